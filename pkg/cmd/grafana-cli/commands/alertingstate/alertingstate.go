@@ -0,0 +1,88 @@
+package alertingstate
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/snapshot"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ExportState writes the alerting state stored in the database to the archive file given as argument.
+func ExportState(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	path := c.Args().First()
+	if path == "" {
+		return errors.New("missing path of the archive file")
+	}
+
+	archive, err := newService(cfg, sqlStore).Export(context.Background())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := snapshot.Write(f, archive); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	logger.Infof("%s Exported the alerting state of %d organizations to %s\n", color.GreenString("✔"), len(archive.Orgs), path)
+	return nil
+}
+
+// ImportState replaces the alerting state stored in the database with the one of the archive file given as argument.
+func ImportState(c utils.CommandLine, cfg *setting.Cfg, sqlStore db.DB) error {
+	path := c.Args().First()
+	if path == "" {
+		return errors.New("missing path of the archive file")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	archive, err := snapshot.Read(f, 0)
+	if err != nil {
+		return err
+	}
+
+	result, err := newService(cfg, sqlStore).Import(context.Background(), archive)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("%s Imported %d alert instances of %d organizations\n", color.GreenString("✔"), result.AlertInstances, result.Orgs)
+	if result.SkippedAlertInstances > 0 {
+		logger.Warnf("Skipped %d alert instances of rules that do not exist\n", result.SkippedAlertInstances)
+	}
+	return nil
+}
+
+func newService(cfg *setting.Cfg, sqlStore db.DB) *snapshot.Service {
+	dbStore := &store.DBstore{
+		Cfg:            cfg.UnifiedAlerting,
+		FeatureToggles: featuremgmt.WithFeatures(),
+		SQLStore:       sqlStore,
+		Logger:         log.New("ngalert.dbstore"),
+	}
+	amStates := notifier.NewAlertmanagerStateStore(kvstore.ProvideService(sqlStore))
+	return snapshot.NewService(dbStore, dbStore, amStates, dbStore, nil, false, log.New("ngalert.snapshot"))
+}
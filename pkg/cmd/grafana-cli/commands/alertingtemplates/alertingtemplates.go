@@ -0,0 +1,161 @@
+package alertingtemplates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
+)
+
+// TestTemplates renders the notification templates of the alerting provisioning directory given as first argument
+// against the JSON fixtures of the directory given as second argument. It fails if a template cannot be rendered or if
+// its output differs from the one expected by a fixture. With the update flag, the rendered output is written to the
+// fixtures as the expected output instead.
+func TestTemplates(c utils.CommandLine) error {
+	provisioningPath, fixturesPath := c.Args().Get(0), c.Args().Get(1)
+	if provisioningPath == "" || fixturesPath == "" {
+		return errors.New("missing path of the provisioning or fixtures directory")
+	}
+	if _, err := os.Stat(provisioningPath); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	orgID := int64(c.Int("org-id"))
+
+	templates, err := alerting.ReadTemplates(ctx, provisioningPath, orgID)
+	if err != nil {
+		return err
+	}
+	suite := definitions.TemplateSuite{
+		Templates: make([]definitions.TemplateSuiteTemplate, 0, len(templates)),
+	}
+	for _, t := range templates {
+		suite.Templates = append(suite.Templates, definitions.TemplateSuiteTemplate{Name: t.Name, Template: t.Template})
+	}
+	if path := c.String("integrations"); path != "" {
+		if err := readJSON(path, &suite.Integrations); err != nil {
+			return err
+		}
+	}
+	paths, err := filepath.Glob(filepath.Join(fixturesPath, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		var fixture definitions.TemplateSuiteFixture
+		if err := readJSON(path, &fixture); err != nil {
+			return err
+		}
+		if fixture.Name == "" {
+			fixture.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		suite.Fixtures = append(suite.Fixtures, fixture)
+	}
+
+	tester, err := notifier.NewTemplateTester(orgID, c.String("external-url"))
+	if err != nil {
+		return err
+	}
+	defer tester.StopAndWait()
+
+	res, err := notifier.RunTemplateSuite(ctx, tester, suite)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("update") && !hasErrors(res) {
+		for i, fixture := range suite.Fixtures {
+			if err := writeJSON(paths[i], golden(fixture, res)); err != nil {
+				return err
+			}
+		}
+		logger.Infof("%s Updated the expected output of %d fixtures\n", color.GreenString("✔"), len(suite.Fixtures))
+		return nil
+	}
+
+	for _, r := range res.Results {
+		if r.Status == definitions.TemplateSuiteOK {
+			continue
+		}
+		name := r.Name
+		if r.Integration != "" {
+			name = r.Integration + " " + r.Name
+		}
+		if r.Fixture != "" {
+			name = fmt.Sprintf("%s (fixture %s)", name, r.Fixture)
+		}
+		switch r.Status {
+		case definitions.TemplateSuiteError:
+			logger.Errorf("%s %s: %s\n", color.RedString("✗"), name, r.Error)
+		case definitions.TemplateSuiteMismatch:
+			logger.Errorf("%s %s: expected %q, got %q\n", color.RedString("✗"), name, r.Expected, r.Text)
+		}
+	}
+	if res.Failed > 0 {
+		return fmt.Errorf("%d of %d template results failed", res.Failed, len(res.Results))
+	}
+	logger.Infof("%s Rendered %d templates against %d fixtures\n", color.GreenString("✔"), len(suite.Templates), len(suite.Fixtures))
+	return nil
+}
+
+func hasErrors(res *definitions.TemplateSuiteResults) bool {
+	for _, r := range res.Results {
+		if r.Status == definitions.TemplateSuiteError {
+			return true
+		}
+	}
+	return false
+}
+
+// golden returns the fixture with the rendered output as the expected output.
+func golden(fixture definitions.TemplateSuiteFixture, res *definitions.TemplateSuiteResults) definitions.TemplateSuiteFixture {
+	fixture.Expected = map[string]string{}
+	fixture.ExpectedFields = map[string]map[string]string{}
+	for _, r := range res.Results {
+		// Results of expected templates that were not rendered have no text and are dropped.
+		if r.Fixture != fixture.Name || r.Error != "" {
+			continue
+		}
+		if r.Integration == "" {
+			fixture.Expected[r.Name] = r.Text
+			continue
+		}
+		if fixture.ExpectedFields[r.Integration] == nil {
+			fixture.ExpectedFields[r.Integration] = map[string]string{}
+		}
+		fixture.ExpectedFields[r.Integration][r.Name] = r.Text
+	}
+	return fixture
+}
+
+func readJSON(path string, v any) error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning because the path is given by the user running the command.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingstate"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingtemplates"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
@@ -167,6 +169,54 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "alerting-state",
+		Usage: "Exports and imports the state of alerts, silences and the notification log",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "export <archive file>. Writes the alerting state of all organizations stored in the database to an archive. Stop Grafana first so that the latest state is persisted.",
+				Action: runDbCommand(alertingstate.ExportState),
+			},
+			{
+				Name:   "import",
+				Usage:  "import <archive file>. Replaces the alerting state of the organizations in the archive. Grafana must not be running. Use the /api/v1/ngalert/state/import API to import into a running Grafana.",
+				Action: runDbCommand(alertingstate.ImportState),
+			},
+		},
+	},
+	{
+		Name:  "alerting-templates",
+		Usage: "Tests notification templates",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "test",
+				Usage:  "test <provisioning directory> <fixtures directory>. Renders the templates of the alerting provisioning files against every JSON fixture for the slack, email and webhook integrations. Fails on template errors and on output that differs from the one expected by the fixtures.",
+				Action: runPluginCommand(alertingtemplates.TestTemplates),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The organization of the provisioned templates",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:  "integrations",
+						Usage: "Path to a JSON file with the integrations and the templates of their fields. Defaults to the default fields of the slack, email and webhook integrations",
+					},
+					&cli.StringFlag{
+						Name:  "external-url",
+						Usage: "The external URL used when rendering the templates",
+						Value: "http://localhost:3000/",
+					},
+					&cli.BoolFlag{
+						Name:  "update",
+						Usage: "Write the rendered output to the fixtures as the expected output",
+						Value: false,
+					},
+				},
+			},
+		},
+	},
 }
 
 var Commands = []*cli.Command{
@@ -0,0 +1,153 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AggregateCommand is an expression command that aggregates across the series or numbers
+// of a query result, grouping them by the values of some of their labels.
+type AggregateCommand struct {
+	VarToAggregate string
+	By             []string
+	Reducer        mathexp.ReducerID
+	Select         *AggregateSelect
+	mapper         mathexp.ReduceMapper
+	refID          string
+}
+
+// NewAggregateCommand creates a new AggregateCommand. Either a reducer or a selection must be given.
+func NewAggregateCommand(refID, varToAggregate string, by []string, reducer mathexp.ReducerID, sel *AggregateSelect, mapper mathexp.ReduceMapper) (*AggregateCommand, error) {
+	switch {
+	case sel != nil && reducer != "":
+		return nil, fmt.Errorf("an aggregation can either reduce or select, not both")
+	case sel != nil:
+		switch sel.Mode {
+		case AggregateSelectTopK, AggregateSelectBottomK:
+		default:
+			return nil, fmt.Errorf("aggregate select mode '%s' is not supported. Supported only: [%s,%s]", sel.Mode, AggregateSelectTopK, AggregateSelectBottomK)
+		}
+		if sel.K < 1 {
+			return nil, fmt.Errorf("the number of values to select must be at least 1, got %d", sel.K)
+		}
+	case reducer == "":
+		return nil, fmt.Errorf("no reducer specified")
+	default:
+		if _, err := mathexp.GetReduceFunc(reducer); err != nil {
+			return nil, err
+		}
+	}
+	return &AggregateCommand{
+		VarToAggregate: varToAggregate,
+		By:             by,
+		Reducer:        reducer,
+		Select:         sel,
+		mapper:         mapper,
+		refID:          refID,
+	}, nil
+}
+
+// UnmarshalAggregateCommand creates an AggregateCommand from Grafana's frontend query.
+func UnmarshalAggregateCommand(rn *rawNode) (*AggregateCommand, error) {
+	q := AggregateQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the aggregate command: %w", err)
+	}
+	return newAggregateCommandFromQuery(rn.RefID, &q)
+}
+
+func newAggregateCommandFromQuery(refID string, q *AggregateQuery) (*AggregateCommand, error) {
+	varToAggregate, err := getReferenceVar(q.Expression, refID)
+	if err != nil {
+		return nil, err
+	}
+	var mapper mathexp.ReduceMapper
+	if q.Settings != nil {
+		switch q.Settings.Mode {
+		case ReduceModeStrict:
+		case ReduceModeDrop:
+			mapper = mathexp.DropNonNumber{}
+		case ReduceModeReplace:
+			if q.Settings.ReplaceWithValue == nil {
+				return nil, fmt.Errorf("setting replaceWithValue must be specified when mode is '%s'", q.Settings.Mode)
+			}
+			mapper = mathexp.ReplaceNonNumberWithValue{Value: *q.Settings.ReplaceWithValue}
+		default:
+			return nil, fmt.Errorf("unsupported reduce mode")
+		}
+	}
+	reducer := mathexp.ReducerID(strings.ToLower(string(q.Reducer)))
+	return NewAggregateCommand(refID, varToAggregate, q.By, reducer, q.Select, mapper)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AggregateCommand) NeedsVars() []string {
+	return []string{ac.VarToAggregate}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AggregateCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAggregate")
+	defer span.End()
+	span.SetAttributes(attribute.String("reducer", string(ac.Reducer)), attribute.StringSlice("by", ac.By))
+
+	var numbers []mathexp.Number
+	var series []mathexp.Series
+	for _, val := range vars[ac.VarToAggregate].Values {
+		switch v := val.(type) {
+		case mathexp.Number:
+			numbers = append(numbers, v)
+		case mathexp.Series:
+			series = append(series, v)
+		case mathexp.NoData:
+		default:
+			return mathexp.Results{}, fmt.Errorf("can only aggregate type series or number, got type %v", val.Type())
+		}
+	}
+	if len(numbers) > 0 && len(series) > 0 {
+		return mathexp.Results{}, fmt.Errorf("can not aggregate a mix of series and numbers")
+	}
+
+	newRes := mathexp.Results{}
+	switch {
+	case len(numbers) > 0 && ac.Select != nil:
+		for _, n := range mathexp.TopKNumbers(ac.refID, numbers, ac.By, ac.Select.K, ac.Select.Mode == AggregateSelectBottomK) {
+			newRes.Values = append(newRes.Values, n)
+		}
+	case len(numbers) > 0:
+		aggregated, err := mathexp.AggregateNumbers(ac.refID, numbers, ac.By, ac.Reducer, ac.mapper)
+		if err != nil {
+			return newRes, err
+		}
+		for _, n := range aggregated {
+			newRes.Values = append(newRes.Values, n)
+		}
+	case len(series) > 0 && ac.Select != nil:
+		return newRes, fmt.Errorf("can only select %s of numbers, reduce the series first", ac.Select.Mode)
+	case len(series) > 0:
+		aggregated, err := mathexp.AggregateSeries(ac.refID, series, ac.By, ac.Reducer, ac.mapper)
+		if err != nil {
+			return newRes, err
+		}
+		for _, s := range aggregated {
+			newRes.Values = append(newRes.Values, s)
+		}
+	default:
+		newRes.Values = append(newRes.Values, mathexp.NewNoData())
+	}
+	return newRes, nil
+}
+
+func (ac *AggregateCommand) Type() string {
+	return TypeAggregate.String()
+}
@@ -0,0 +1,141 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalAggregateCommand(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		expectedErr string
+		expected    *AggregateCommand
+	}{
+		{
+			name:  "reducer with settings",
+			query: `{"expression": "$A", "type": "aggregate", "by": ["cluster"], "reducer": "Sum", "settings": {"mode": "dropNN"}}`,
+			expected: &AggregateCommand{
+				VarToAggregate: "A",
+				By:             []string{"cluster"},
+				Reducer:        mathexp.ReducerSum,
+				mapper:         mathexp.DropNonNumber{},
+				refID:          "B",
+			},
+		},
+		{
+			name:  "topk",
+			query: `{"expression": "A", "type": "aggregate", "select": {"mode": "topk", "k": 3}}`,
+			expected: &AggregateCommand{
+				VarToAggregate: "A",
+				Select:         &AggregateSelect{Mode: AggregateSelectTopK, K: 3},
+				refID:          "B",
+			},
+		},
+		{
+			name:        "missing reducer",
+			query:       `{"expression": "A", "type": "aggregate"}`,
+			expectedErr: "no reducer specified",
+		},
+		{
+			name:        "unknown reducer",
+			query:       `{"expression": "A", "type": "aggregate", "reducer": "foo"}`,
+			expectedErr: "reduction foo not implemented",
+		},
+		{
+			name:        "reducer and select",
+			query:       `{"expression": "A", "type": "aggregate", "reducer": "sum", "select": {"mode": "topk", "k": 3}}`,
+			expectedErr: "either reduce or select",
+		},
+		{
+			name:        "select without k",
+			query:       `{"expression": "A", "type": "aggregate", "select": {"mode": "bottomk"}}`,
+			expectedErr: "must be at least 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := UnmarshalAggregateCommand(&rawNode{RefID: "B", QueryRaw: []byte(tc.query)})
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestAggregateCommand_Execute(t *testing.T) {
+	numbers := mathexp.Vars{"A": newResults(
+		newNumber(data.Labels{"cluster": "a", "pod": "1"}, util.Pointer(1.0)),
+		newNumber(data.Labels{"cluster": "a", "pod": "2"}, util.Pointer(2.0)),
+		newNumber(data.Labels{"cluster": "b", "pod": "1"}, util.Pointer(5.0)),
+	)}
+
+	t.Run("reduce numbers by label", func(t *testing.T) {
+		cmd, err := NewAggregateCommand("B", "A", []string{"cluster"}, mathexp.ReducerSum, nil, nil)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), numbers, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"cluster": "a"}, res.Values[0].GetLabels())
+		require.Equal(t, util.Pointer(3.0), res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Equal(t, data.Labels{"cluster": "b"}, res.Values[1].GetLabels())
+		require.Equal(t, util.Pointer(5.0), res.Values[1].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("select the top numbers", func(t *testing.T) {
+		cmd, err := NewAggregateCommand("B", "A", nil, "", &AggregateSelect{Mode: AggregateSelectTopK, K: 2}, nil)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), numbers, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, data.Labels{"cluster": "b", "pod": "1"}, res.Values[0].GetLabels())
+		require.Equal(t, data.Labels{"cluster": "a", "pod": "2"}, res.Values[1].GetLabels())
+	})
+
+	t.Run("reduce series point by point", func(t *testing.T) {
+		vars := mathexp.Vars{"A": newResults(
+			newSeriesWithLabels(data.Labels{"pod": "1"}, util.Pointer(1.0), util.Pointer(2.0)),
+			newSeriesWithLabels(data.Labels{"pod": "2"}, util.Pointer(3.0), util.Pointer(4.0)),
+		)}
+		cmd, err := NewAggregateCommand("B", "A", nil, mathexp.ReducerMean, nil, nil)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, []float64{2, 3}, seriesValues(res.Values[0].(mathexp.Series)))
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		cmd, err := NewAggregateCommand("B", "A", nil, mathexp.ReducerSum, nil, nil)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": newResults(mathexp.NewNoData())}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, newResults(mathexp.NewNoData()), res)
+	})
+
+	t.Run("topk of series fails", func(t *testing.T) {
+		cmd, err := NewAggregateCommand("B", "A", nil, "", &AggregateSelect{Mode: AggregateSelectTopK, K: 1}, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": newResults(newSeries(1, 2))}, tracing.InitializeTracerForTest())
+		require.ErrorContains(t, err, "reduce the series first")
+	})
+
+	t.Run("mixed series and numbers fail", func(t *testing.T) {
+		cmd, err := NewAggregateCommand("B", "A", nil, mathexp.ReducerSum, nil, nil)
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": newResults(newSeries(1, 2), newNumber(nil, util.Pointer(1.0)))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,172 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// AnomalySeriesLabel is the label that tells apart the bands and the flags
+// when an AnomalyCommand returns more than one series per input series.
+const AnomalySeriesLabel = "anomaly_series"
+
+const (
+	defaultAnomalySensitivity = 3
+	defaultHoltWintersAlpha   = 0.5
+	defaultHoltWintersBeta    = 0.1
+	defaultHoltWintersGamma   = 0.3
+)
+
+// AnomalyCommand is an expression command that detects anomalies in time series
+// using statistical methods, without the need of an external service.
+type AnomalyCommand struct {
+	VarToDetect string
+	Options     mathexp.AnomalyOptions
+	Output      AnomalyOutput
+	refID       string
+}
+
+// NewAnomalyCommand creates a new AnomalyCommand.
+func NewAnomalyCommand(refID, varToDetect string, options mathexp.AnomalyOptions, output AnomalyOutput) (*AnomalyCommand, error) {
+	switch options.Algorithm {
+	case mathexp.AnomalyZScore, mathexp.AnomalyMAD:
+	case mathexp.AnomalyHoltWinters:
+		if options.Season <= 0 {
+			return nil, fmt.Errorf("a season is required for the %s anomaly algorithm", options.Algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("anomaly algorithm '%s' is not supported. Supported only: [%s,%s,%s]", options.Algorithm, mathexp.AnomalyZScore, mathexp.AnomalyMAD, mathexp.AnomalyHoltWinters)
+	}
+	if options.Sensitivity <= 0 {
+		return nil, fmt.Errorf("anomaly sensitivity must be greater than zero, got %v", options.Sensitivity)
+	}
+	switch output {
+	case "":
+		output = AnomalyOutputFlags
+	case AnomalyOutputFlags, AnomalyOutputBands, AnomalyOutputAll:
+	default:
+		return nil, fmt.Errorf("anomaly output '%s' is not supported. Supported only: [%s,%s,%s]", output, AnomalyOutputFlags, AnomalyOutputBands, AnomalyOutputAll)
+	}
+	return &AnomalyCommand{
+		VarToDetect: varToDetect,
+		Options:     options,
+		Output:      output,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalAnomalyCommand creates an AnomalyCommand from Grafana's frontend query.
+func UnmarshalAnomalyCommand(rn *rawNode) (*AnomalyCommand, error) {
+	q := AnomalyQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the anomaly command: %w", err)
+	}
+	return newAnomalyCommandFromQuery(rn.RefID, &q)
+}
+
+func newAnomalyCommandFromQuery(refID string, q *AnomalyQuery) (*AnomalyCommand, error) {
+	varToDetect, err := getReferenceVar(q.Expression, refID)
+	if err != nil {
+		return nil, err
+	}
+	options := mathexp.AnomalyOptions{
+		Algorithm:   q.Algorithm,
+		Sensitivity: defaultAnomalySensitivity,
+	}
+	options.Alpha, options.Beta, options.Gamma = holtWintersFactors(q.HoltWinters)
+	if q.Sensitivity != nil {
+		options.Sensitivity = *q.Sensitivity
+	}
+	if q.Season != "" {
+		options.Season, err = gtime.ParseDuration(q.Season)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse season: %w", err)
+		}
+	}
+	return NewAnomalyCommand(refID, varToDetect, options, q.Output)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (ac *AnomalyCommand) NeedsVars() []string {
+	return []string{ac.VarToDetect}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (ac *AnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteAnomaly")
+	defer span.End()
+	span.SetAttributes(attribute.String("algorithm", string(ac.Options.Algorithm)))
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[ac.VarToDetect].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			lower, upper, err := v.AnomalyBands(ac.refID, ac.Options)
+			if err != nil {
+				return newRes, fmt.Errorf("failed to detect anomalies in series %s: %w", v.GetLabels().String(), err)
+			}
+			switch ac.Output {
+			case AnomalyOutputFlags:
+				newRes.Values = append(newRes.Values, v.AnomalyFlags(ac.refID, lower, upper))
+			case AnomalyOutputBands:
+				newRes.Values = append(newRes.Values,
+					withAnomalySeriesLabel(lower, "lower"),
+					withAnomalySeriesLabel(upper, "upper"),
+				)
+			case AnomalyOutputAll:
+				newRes.Values = append(newRes.Values,
+					withAnomalySeriesLabel(lower, "lower"),
+					withAnomalySeriesLabel(upper, "upper"),
+					withAnomalySeriesLabel(v.AnomalyFlags(ac.refID, lower, upper), "flag"),
+				)
+			}
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (ac *AnomalyCommand) Type() string {
+	return TypeAnomaly.String()
+}
+
+// holtWintersFactors returns the smoothing factors set in hw, or the defaults for the ones that are not set.
+func holtWintersFactors(hw *HoltWintersSettings) (alpha, beta, gamma float64) {
+	alpha, beta, gamma = defaultHoltWintersAlpha, defaultHoltWintersBeta, defaultHoltWintersGamma
+	if hw == nil {
+		return alpha, beta, gamma
+	}
+	if hw.Alpha != nil {
+		alpha = *hw.Alpha
+	}
+	if hw.Beta != nil {
+		beta = *hw.Beta
+	}
+	if hw.Gamma != nil {
+		gamma = *hw.Gamma
+	}
+	return alpha, beta, gamma
+}
+
+func withAnomalySeriesLabel(s mathexp.Series, value string) mathexp.Series {
+	labels := data.Labels{}
+	if s.GetLabels() != nil {
+		labels = s.GetLabels().Copy()
+	}
+	labels[AnomalySeriesLabel] = value
+	s.SetLabels(labels)
+	return s
+}
@@ -0,0 +1,140 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalAnomalyCommand(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		expectedErr string
+		expected    *AnomalyCommand
+	}{
+		{
+			name:  "defaults",
+			query: `{"expression": "$A", "type": "anomaly", "algorithm": "zscore"}`,
+			expected: &AnomalyCommand{
+				VarToDetect: "A",
+				Options: mathexp.AnomalyOptions{
+					Algorithm:   mathexp.AnomalyZScore,
+					Sensitivity: 3,
+					Alpha:       0.5,
+					Beta:        0.1,
+					Gamma:       0.3,
+				},
+				Output: AnomalyOutputFlags,
+				refID:  "B",
+			},
+		},
+		{
+			name:  "holt_winters with settings",
+			query: `{"expression": "A", "type": "anomaly", "algorithm": "holt_winters", "season": "1d", "sensitivity": 2, "holtWinters": {"alpha": 0.2}, "output": "all"}`,
+			expected: &AnomalyCommand{
+				VarToDetect: "A",
+				Options: mathexp.AnomalyOptions{
+					Algorithm:   mathexp.AnomalyHoltWinters,
+					Sensitivity: 2,
+					Season:      24 * time.Hour,
+					Alpha:       0.2,
+					Beta:        0.1,
+					Gamma:       0.3,
+				},
+				Output: AnomalyOutputAll,
+				refID:  "B",
+			},
+		},
+		{
+			name:        "holt_winters without season",
+			query:       `{"expression": "A", "type": "anomaly", "algorithm": "holt_winters"}`,
+			expectedErr: "a season is required",
+		},
+		{
+			name:        "unknown algorithm",
+			query:       `{"expression": "A", "type": "anomaly", "algorithm": "prophet"}`,
+			expectedErr: "anomaly algorithm 'prophet' is not supported",
+		},
+		{
+			name:        "unknown output",
+			query:       `{"expression": "A", "type": "anomaly", "algorithm": "mad", "output": "score"}`,
+			expectedErr: "anomaly output 'score' is not supported",
+		},
+		{
+			name:        "negative sensitivity",
+			query:       `{"expression": "A", "type": "anomaly", "algorithm": "mad", "sensitivity": -1}`,
+			expectedErr: "anomaly sensitivity must be greater than zero",
+		},
+		{
+			name:        "missing expression",
+			query:       `{"type": "anomaly", "algorithm": "mad"}`,
+			expectedErr: "no variable specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := UnmarshalAnomalyCommand(&rawNode{RefID: "B", QueryRaw: []byte(tc.query)})
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestAnomalyCommand_Execute(t *testing.T) {
+	input := newSeriesWithLabels(data.Labels{"host": "a"},
+		util.Pointer(2.0), util.Pointer(4.0), util.Pointer(4.0), util.Pointer(4.0),
+		util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0))
+	vars := mathexp.Vars{"A": newResults(input, mathexp.NewNoData())}
+
+	t.Run("flags keep the labels of the input", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyOptions{Algorithm: mathexp.AnomalyZScore, Sensitivity: 1}, "")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		flags := res.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, flags.GetLabels())
+		require.Equal(t, []float64{1, 0, 0, 0, 0, 0, 0, 1}, seriesValues(flags))
+		require.Equal(t, mathexp.NewNoData(), res.Values[1])
+	})
+
+	t.Run("all outputs are told apart by label", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyOptions{Algorithm: mathexp.AnomalyZScore, Sensitivity: 1}, AnomalyOutputAll)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 4)
+		require.Equal(t, data.Labels{"host": "a", AnomalySeriesLabel: "lower"}, res.Values[0].GetLabels())
+		require.Equal(t, data.Labels{"host": "a", AnomalySeriesLabel: "upper"}, res.Values[1].GetLabels())
+		require.Equal(t, data.Labels{"host": "a", AnomalySeriesLabel: "flag"}, res.Values[2].GetLabels())
+		require.Equal(t, data.Labels{"host": "a"}, input.GetLabels(), "input labels must not be modified")
+	})
+
+	t.Run("numbers are not supported", func(t *testing.T) {
+		cmd, err := NewAnomalyCommand("B", "A", mathexp.AnomalyOptions{Algorithm: mathexp.AnomalyMAD, Sensitivity: 3}, "")
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": newResults(newNumber(nil, util.Pointer(1.0)))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
+
+func seriesValues(s mathexp.Series) []float64 {
+	values := make([]float64, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		values = append(values, *s.GetValue(i))
+	}
+	return values
+}
@@ -15,8 +15,12 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "stddev", "variance", "increase", "rate":
+		return true
 	}
-	return false
+	// percentile reducers such as p95
+	_, ok := mathexp.ParsePercentileReducer(mathexp.ReducerID(cr))
+	return ok
 }
 
 //nolint:gocyclo
@@ -144,6 +148,16 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	default:
+		// the remaining reducers are delegated to mathexp, ignoring null and NaN values
+		n, err := series.Reduce("", mathexp.ReducerID(cr), dropNilOrNaN{})
+		if err != nil {
+			return num
+		}
+		if f := n.GetFloat64Value(); f != nil {
+			value = *f
+			allNull = false
+		}
 	}
 
 	if allNull {
@@ -182,6 +196,24 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// dropNilOrNaN is a mathexp.ReduceMapper that skips null and NaN values,
+// the same way the built-in reducers of classic conditions do.
+type dropNilOrNaN struct{}
+
+func (dropNilOrNaN) MapInput(f *float64) *float64 {
+	if nilOrNaN(f) {
+		return nil
+	}
+	return f
+}
+
+func (dropNilOrNaN) MapOutput(f *float64) *float64 {
+	if nilOrNaN(f) {
+		return nil
+	}
+	return f
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
@@ -115,6 +115,48 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first skips null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(2.0), util.Pointer(3.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(2.0), util.Pointer(4.0), nil, util.Pointer(4.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "variance",
+			reducer:        reducer("variance"),
+			inputSeries:    newSeries(util.Pointer(2.0), util.Pointer(4.0), util.Pointer(4.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0)),
+			expectedNumber: newNumber(util.Pointer(4.0)),
+		},
+		{
+			name:           "p90",
+			reducer:        reducer("p90"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(2.0), util.Pointer(3.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(6.0), util.Pointer(7.0), util.Pointer(8.0), util.Pointer(9.0), util.Pointer(10.0), util.Pointer(11.0)),
+			expectedNumber: newNumber(util.Pointer(10.0)),
+		},
+		{
+			name:           "increase with counter reset",
+			reducer:        reducer("increase"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(5.0), util.Pointer(2.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(8.0)),
+		},
+		{
+			name:           "rate with counter reset",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(5.0), util.Pointer(2.0), util.Pointer(4.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "rate with a single value",
+			reducer:        reducer("rate"),
+			inputSeries:    newSeries(nil, util.Pointer(5.0)),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
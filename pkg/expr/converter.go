@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -121,6 +122,56 @@ func (c *ResultConverter) Convert(ctx context.Context,
 	}, nil
 }
 
+// shiftFrames returns copies of the frames with the values of all time fields moved forward by shift.
+// The frames of the data source response are not modified, as they may be shared, for example with the query cache.
+func shiftFrames(frames data.Frames, shift time.Duration) data.Frames {
+	if shift == 0 {
+		return frames
+	}
+	shifted := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if frame == nil {
+			shifted = append(shifted, frame)
+			continue
+		}
+		f := *frame
+		f.Fields = make([]*data.Field, 0, len(frame.Fields))
+		for _, field := range frame.Fields {
+			f.Fields = append(f.Fields, shiftField(field, shift))
+		}
+		shifted = append(shifted, &f)
+	}
+	return shifted
+}
+
+// shiftField returns a copy of a time field with its values moved forward by shift. Fields of other types are
+// returned as they are.
+func shiftField(field *data.Field, shift time.Duration) *data.Field {
+	var values any
+	switch field.Type() {
+	case data.FieldTypeTime:
+		times := make([]time.Time, field.Len())
+		for i := range times {
+			times[i] = field.At(i).(time.Time).Add(shift)
+		}
+		values = times
+	case data.FieldTypeNullableTime:
+		times := make([]*time.Time, field.Len())
+		for i := range times {
+			if t := field.At(i).(*time.Time); t != nil {
+				shifted := t.Add(shift)
+				times[i] = &shifted
+			}
+		}
+		values = times
+	default:
+		return field
+	}
+	shifted := data.NewField(field.Name, field.Labels, values)
+	shifted.Config = field.Config
+	return shifted
+}
+
 func getResponseFrame(logger *log.ConcreteLogger, resp *backend.QueryDataResponse, refID string) (data.Frames, error) {
 	response, ok := resp.Responses[refID]
 	if !ok {
@@ -47,7 +47,7 @@ func TestPassThroughDataplaneExamples(t *testing.T) {
 
 func framesPassThroughService(t *testing.T, frames data.Frames) (data.Frames, error) {
 	me := &mockEndpoint{
-		map[string]backend.DataResponse{"A": {Frames: frames}},
+		Responses: map[string]backend.DataResponse{"A": {Frames: frames}},
 	}
 
 	features := featuremgmt.WithFeatures()
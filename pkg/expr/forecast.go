@@ -0,0 +1,135 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// ForecastCommand is an expression command that extrapolates time series,
+// for example to predict when a disk will be full.
+type ForecastCommand struct {
+	VarToForecast string
+	Horizon       time.Duration
+	Options       mathexp.ForecastOptions
+	Output        ForecastOutput
+	refID         string
+}
+
+// NewForecastCommand creates a new ForecastCommand.
+func NewForecastCommand(refID, varToForecast string, horizon time.Duration, options mathexp.ForecastOptions, output ForecastOutput) (*ForecastCommand, error) {
+	switch options.Method {
+	case "":
+		options.Method = mathexp.ForecastLinear
+	case mathexp.ForecastLinear, mathexp.ForecastHoltWinters:
+	default:
+		return nil, fmt.Errorf("forecast method '%s' is not supported. Supported only: [%s,%s]", options.Method, mathexp.ForecastLinear, mathexp.ForecastHoltWinters)
+	}
+	if horizon < 0 {
+		return nil, fmt.Errorf("forecast horizon must not be negative, got %v", horizon)
+	}
+	switch output {
+	case "":
+		output = ForecastOutputValue
+	case ForecastOutputValue, ForecastOutputSeries:
+	default:
+		return nil, fmt.Errorf("forecast output '%s' is not supported. Supported only: [%s,%s]", output, ForecastOutputValue, ForecastOutputSeries)
+	}
+	return &ForecastCommand{
+		VarToForecast: varToForecast,
+		Horizon:       horizon,
+		Options:       options,
+		Output:        output,
+		refID:         refID,
+	}, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	q := ForecastQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	return newForecastCommandFromQuery(rn.RefID, &q)
+}
+
+func newForecastCommandFromQuery(refID string, q *ForecastQuery) (*ForecastCommand, error) {
+	varToForecast, err := getReferenceVar(q.Expression, refID)
+	if err != nil {
+		return nil, err
+	}
+	if q.Horizon == "" {
+		return nil, fmt.Errorf("no horizon specified to forecast for refId %v", refID)
+	}
+	horizon, err := gtime.ParseDuration(q.Horizon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse horizon: %w", err)
+	}
+	options := mathexp.ForecastOptions{Method: q.Method}
+	if q.Window != "" {
+		options.Window, err = gtime.ParseDuration(q.Window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse window: %w", err)
+		}
+	}
+	if q.Season != "" {
+		options.Season, err = gtime.ParseDuration(q.Season)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse season: %w", err)
+		}
+	}
+	options.Alpha, options.Beta, options.Gamma = holtWintersFactors(q.HoltWinters)
+	return NewForecastCommand(refID, varToForecast, horizon, options, q.Output)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (fc *ForecastCommand) NeedsVars() []string {
+	return []string{fc.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (fc *ForecastCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	span.SetAttributes(attribute.String("method", string(fc.Options.Method)))
+
+	at := now.Add(fc.Horizon)
+	newRes := mathexp.Results{}
+	for _, val := range vars[fc.VarToForecast].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			var (
+				value mathexp.Value
+				err   error
+			)
+			switch fc.Output {
+			case ForecastOutputValue:
+				value, err = v.Forecast(fc.refID, fc.Options, at)
+			case ForecastOutputSeries:
+				value, err = v.ForecastSeries(fc.refID, fc.Options, at)
+			}
+			if err != nil {
+				return newRes, fmt.Errorf("failed to forecast series %s: %w", v.GetLabels().String(), err)
+			}
+			newRes.Values = append(newRes.Values, value)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (fc *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
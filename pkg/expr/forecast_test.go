@@ -0,0 +1,115 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalForecastCommand(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		expectedErr string
+		expected    *ForecastCommand
+	}{
+		{
+			name:  "defaults",
+			query: `{"expression": "$A", "type": "forecast", "horizon": "4h"}`,
+			expected: &ForecastCommand{
+				VarToForecast: "A",
+				Horizon:       4 * time.Hour,
+				Options: mathexp.ForecastOptions{
+					Method: mathexp.ForecastLinear,
+					Alpha:  0.5,
+					Beta:   0.1,
+					Gamma:  0.3,
+				},
+				Output: ForecastOutputValue,
+				refID:  "B",
+			},
+		},
+		{
+			name:  "holt_winters with settings",
+			query: `{"expression": "A", "type": "forecast", "method": "holt_winters", "horizon": "1d", "window": "1w", "season": "1d", "holtWinters": {"gamma": 0.6}, "output": "series"}`,
+			expected: &ForecastCommand{
+				VarToForecast: "A",
+				Horizon:       24 * time.Hour,
+				Options: mathexp.ForecastOptions{
+					Method: mathexp.ForecastHoltWinters,
+					Window: 7 * 24 * time.Hour,
+					Season: 24 * time.Hour,
+					Alpha:  0.5,
+					Beta:   0.1,
+					Gamma:  0.6,
+				},
+				Output: ForecastOutputSeries,
+				refID:  "B",
+			},
+		},
+		{
+			name:        "missing horizon",
+			query:       `{"expression": "A", "type": "forecast"}`,
+			expectedErr: "no horizon specified",
+		},
+		{
+			name:        "unknown method",
+			query:       `{"expression": "A", "type": "forecast", "horizon": "1h", "method": "arima"}`,
+			expectedErr: "forecast method 'arima' is not supported",
+		},
+		{
+			name:        "unknown output",
+			query:       `{"expression": "A", "type": "forecast", "horizon": "1h", "output": "both"}`,
+			expectedErr: "forecast output 'both' is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := UnmarshalForecastCommand(&rawNode{RefID: "B", QueryRaw: []byte(tc.query)})
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cmd)
+		})
+	}
+}
+
+func TestForecastCommand_Execute(t *testing.T) {
+	// newSeries has a point every second
+	vars := mathexp.Vars{"A": newResults(newSeries(0, 1, 2, 3), mathexp.NewNoData())}
+	now := time.Unix(3, 0)
+
+	t.Run("value at the horizon after now", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", 10*time.Second, mathexp.ForecastOptions{Method: mathexp.ForecastLinear}, "")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), now, vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, newNumber(nil, util.Pointer(13.0)).GetFloat64Value(), res.Values[0].(mathexp.Number).GetFloat64Value())
+		require.Equal(t, mathexp.NewNoData(), res.Values[1])
+	})
+
+	t.Run("projected series", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", 2*time.Second, mathexp.ForecastOptions{Method: mathexp.ForecastLinear}, ForecastOutputSeries)
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), now, vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Equal(t, []float64{4, 5}, seriesValues(res.Values[0].(mathexp.Series)))
+	})
+
+	t.Run("numbers are not supported", func(t *testing.T) {
+		cmd, err := NewForecastCommand("B", "A", time.Hour, mathexp.ForecastOptions{}, "")
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), now, mathexp.Vars{"A": newResults(newNumber(nil, util.Pointer(1.0)))}, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
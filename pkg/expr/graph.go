@@ -62,6 +62,7 @@ type DataPipeline []Node
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	stats := executionStatsFromContext(c)
 
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
 	// Execute datasource nodes first, and grouped by datasource.
@@ -74,7 +75,9 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			dsNodes = append(dsNodes, node.(*DSNode))
 		}
 
+		start := time.Now()
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		stats.observe(TypeDatasourceNode, time.Since(start))
 	}
 
 	s.allowLongFrames = hasSqlExpression(*dp)
@@ -115,7 +118,9 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
+		stats.observe(node.NodeType(), time.Since(start))
 		if err != nil {
 			res.Error = err
 		}
@@ -264,11 +269,19 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 			return nil, err
 		}
 
+		if query.TimeShift < 0 {
+			return nil, fmt.Errorf("time shift of query with refId %v must not be negative", query.RefID)
+		}
+		if query.TimeShift != 0 && NodeTypeFromDatasourceUID(query.DataSource.UID) != TypeDatasourceNode {
+			return nil, fmt.Errorf("time shift can only be set on data source queries, but query with refId %v is an expression", query.RefID)
+		}
+
 		rn := &rawNode{
 			Query:      rawQueryProp,
 			QueryRaw:   query.JSON,
 			RefID:      query.RefID,
 			TimeRange:  query.TimeRange,
+			TimeShift:  query.TimeShift,
 			QueryType:  query.QueryType,
 			DataSource: query.DataSource,
 			idx:        int64(i),
@@ -0,0 +1,149 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// labelGroup is a set of values that share the same values of the grouping labels.
+type labelGroup[T Value] struct {
+	labels data.Labels
+	values []T
+}
+
+// groupByLabels groups the values by the values of the given labels. Labels that a value does not have
+// are left out of its group labels, and if no labels are given all values are put into a single group.
+// The groups are returned in the order in which they first appear.
+func groupByLabels[T Value](values []T, by []string) []*labelGroup[T] {
+	var groups []*labelGroup[T]
+	index := map[string]*labelGroup[T]{}
+	for _, v := range values {
+		labels := data.Labels{}
+		for _, name := range by {
+			if value, ok := v.GetLabels()[name]; ok {
+				labels[name] = value
+			}
+		}
+		key := labels.String()
+		g, ok := index[key]
+		if !ok {
+			if len(labels) == 0 {
+				labels = nil
+			}
+			g = &labelGroup[T]{labels: labels}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.values = append(g.values, v)
+	}
+	return groups
+}
+
+// reduceValues applies the mapper to the values and reduces them, the same way Series.Reduce does for the values of a series.
+func reduceValues(values []*float64, reduceFunc ReducerFunc, mapper ReduceMapper) *float64 {
+	if mapper != nil {
+		mapped := make([]*float64, 0, len(values))
+		for _, v := range values {
+			if v = mapper.MapInput(v); v != nil {
+				mapped = append(mapped, v)
+			}
+		}
+		values = mapped
+	}
+	ff := Float64Field(*data.NewField("", nil, values))
+	f := reduceFunc(&ff)
+	if f != nil && mapper != nil {
+		f = mapper.MapOutput(f)
+	}
+	return f
+}
+
+// AggregateNumbers groups the numbers by the given labels and reduces each group to a single number.
+func AggregateNumbers(refID string, numbers []Number, by []string, rFunc ReducerID, mapper ReduceMapper) ([]Number, error) {
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregation '%s': %w", refID, err)
+	}
+	groups := groupByLabels(numbers, by)
+	result := make([]Number, 0, len(groups))
+	for _, g := range groups {
+		values := make([]*float64, 0, len(g.values))
+		for _, n := range g.values {
+			values = append(values, n.GetFloat64Value())
+		}
+		number := NewNumber(refID, g.labels)
+		number.SetValue(reduceValues(values, reduceFunc, mapper))
+		result = append(result, number)
+	}
+	return result, nil
+}
+
+// AggregateSeries groups the series by the given labels and reduces each group to a single series.
+// The points of the series of a group that share the same time are reduced to a single point.
+func AggregateSeries(refID string, series []Series, by []string, rFunc ReducerID, mapper ReduceMapper) ([]Series, error) {
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregation '%s': %w", refID, err)
+	}
+	groups := groupByLabels(series, by)
+	result := make([]Series, 0, len(groups))
+	for _, g := range groups {
+		var times []time.Time
+		points := map[time.Time][]*float64{}
+		for _, s := range g.values {
+			for i := 0; i < s.Len(); i++ {
+				t, v := s.GetPoint(i)
+				t = t.UTC()
+				if _, ok := points[t]; !ok {
+					times = append(times, t)
+				}
+				points[t] = append(points[t], v)
+			}
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		aggregated := NewSeries(refID, g.labels, len(times))
+		for i, t := range times {
+			aggregated.SetPoint(i, t, reduceValues(points[t], reduceFunc, mapper))
+		}
+		result = append(result, aggregated)
+	}
+	return result, nil
+}
+
+// TopKNumbers returns the k largest numbers of each group of numbers that share the given labels,
+// or the k smallest if bottom is true. The numbers keep their labels. Null and NaN values are never selected.
+func TopKNumbers(refID string, numbers []Number, by []string, k int, bottom bool) []Number {
+	var result []Number
+	for _, g := range groupByLabels(numbers, by) {
+		candidates := make([]Number, 0, len(g.values))
+		for _, n := range g.values {
+			if f := n.GetFloat64Value(); f != nil && !math.IsNaN(*f) {
+				candidates = append(candidates, n)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := *candidates[i].GetFloat64Value(), *candidates[j].GetFloat64Value()
+			if bottom {
+				return a < b
+			}
+			return a > b
+		})
+		if len(candidates) > k {
+			candidates = candidates[:k]
+		}
+		for _, n := range candidates {
+			var labels data.Labels
+			if n.GetLabels() != nil {
+				labels = n.GetLabels().Copy()
+			}
+			selected := NewNumber(refID, labels)
+			selected.SetValue(n.GetFloat64Value())
+			result = append(result, selected)
+		}
+	}
+	return result
+}
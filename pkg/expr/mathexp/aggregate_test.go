@@ -0,0 +1,100 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateNumbers(t *testing.T) {
+	numbers := []Number{
+		makeNumber("", data.Labels{"cluster": "a", "pod": "1"}, float64Pointer(1)),
+		makeNumber("", data.Labels{"cluster": "b", "pod": "1"}, float64Pointer(10)),
+		makeNumber("", data.Labels{"cluster": "a", "pod": "2"}, float64Pointer(2)),
+		makeNumber("", data.Labels{"cluster": "a", "pod": "3"}, nil),
+		makeNumber("", data.Labels{"pod": "4"}, float64Pointer(5)),
+	}
+
+	t.Run("groups by the given labels in order of appearance", func(t *testing.T) {
+		result, err := AggregateNumbers("B", numbers, []string{"cluster"}, ReducerSum, DropNonNumber{})
+		require.NoError(t, err)
+		require.Equal(t, []Number{
+			makeNumber("B", data.Labels{"cluster": "a"}, float64Pointer(3)),
+			makeNumber("B", data.Labels{"cluster": "b"}, float64Pointer(10)),
+			makeNumber("B", nil, float64Pointer(5)),
+		}, result)
+	})
+
+	t.Run("aggregates everything without labels to group by", func(t *testing.T) {
+		result, err := AggregateNumbers("B", numbers, nil, ReducerMax, DropNonNumber{})
+		require.NoError(t, err)
+		require.Equal(t, []Number{makeNumber("B", nil, float64Pointer(10))}, result)
+	})
+
+	t.Run("null values make the group NaN without a mapper", func(t *testing.T) {
+		result, err := AggregateNumbers("B", numbers, []string{"cluster"}, ReducerSum, nil)
+		require.NoError(t, err)
+		require.True(t, math.IsNaN(*result[0].GetFloat64Value()))
+	})
+
+	t.Run("unknown reducer fails", func(t *testing.T) {
+		_, err := AggregateNumbers("B", numbers, nil, "foo", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestAggregateSeries(t *testing.T) {
+	series := []Series{
+		makeSeries("", data.Labels{"cluster": "a", "pod": "1"},
+			tp{time.Unix(60, 0), float64Pointer(1)},
+			tp{time.Unix(120, 0), float64Pointer(2)},
+		),
+		makeSeries("", data.Labels{"cluster": "a", "pod": "2"},
+			tp{time.Unix(0, 0), float64Pointer(5)},
+			tp{time.Unix(60, 0), float64Pointer(3)},
+		),
+		makeSeries("", data.Labels{"cluster": "b", "pod": "1"},
+			tp{time.Unix(60, 0), float64Pointer(7)},
+		),
+	}
+
+	result, err := AggregateSeries("B", series, []string{"cluster"}, ReducerSum, nil)
+	require.NoError(t, err)
+	require.Equal(t, []Series{
+		makeSeries("B", data.Labels{"cluster": "a"},
+			tp{time.Unix(0, 0).UTC(), float64Pointer(5)},
+			tp{time.Unix(60, 0).UTC(), float64Pointer(4)},
+			tp{time.Unix(120, 0).UTC(), float64Pointer(2)},
+		),
+		makeSeries("B", data.Labels{"cluster": "b"},
+			tp{time.Unix(60, 0).UTC(), float64Pointer(7)},
+		),
+	}, result)
+}
+
+func TestTopKNumbers(t *testing.T) {
+	numbers := []Number{
+		makeNumber("", data.Labels{"cluster": "a", "pod": "1"}, float64Pointer(1)),
+		makeNumber("", data.Labels{"cluster": "a", "pod": "2"}, float64Pointer(3)),
+		makeNumber("", data.Labels{"cluster": "a", "pod": "3"}, NaN),
+		makeNumber("", data.Labels{"cluster": "a", "pod": "4"}, float64Pointer(2)),
+		makeNumber("", data.Labels{"cluster": "b", "pod": "1"}, float64Pointer(4)),
+	}
+
+	t.Run("topk keeps the largest numbers of each group", func(t *testing.T) {
+		require.Equal(t, []Number{
+			makeNumber("B", data.Labels{"cluster": "a", "pod": "2"}, float64Pointer(3)),
+			makeNumber("B", data.Labels{"cluster": "a", "pod": "4"}, float64Pointer(2)),
+			makeNumber("B", data.Labels{"cluster": "b", "pod": "1"}, float64Pointer(4)),
+		}, TopKNumbers("B", numbers, []string{"cluster"}, 2, false))
+	})
+
+	t.Run("bottomk keeps the smallest numbers of all", func(t *testing.T) {
+		require.Equal(t, []Number{
+			makeNumber("B", data.Labels{"cluster": "a", "pod": "1"}, float64Pointer(1)),
+		}, TopKNumbers("B", numbers, nil, 1, true))
+	})
+}
@@ -0,0 +1,177 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// The anomaly detection algorithm
+// +enum
+type AnomalyAlgorithm string
+
+const (
+	// Bands at a number of standard deviations around the mean of the series
+	AnomalyZScore AnomalyAlgorithm = "zscore"
+
+	// Bands at a number of median absolute deviations around the median of the series
+	AnomalyMAD AnomalyAlgorithm = "mad"
+
+	// Bands at a number of standard deviations of the forecast error around a seasonal Holt-Winters forecast
+	AnomalyHoltWinters AnomalyAlgorithm = "holt_winters"
+)
+
+// madScale makes the median absolute deviation a consistent estimator of the standard deviation of normally distributed data.
+const madScale = 1.4826
+
+// AnomalyOptions configures the detection of anomalies in a series.
+type AnomalyOptions struct {
+	Algorithm AnomalyAlgorithm
+	// Sensitivity is the width of the bands, in number of deviations.
+	Sensitivity float64
+	// Season is the length of the seasonal cycle. Only used by AnomalyHoltWinters.
+	Season time.Duration
+	// Alpha, Beta and Gamma are the smoothing factors of level, trend and season. Only used by AnomalyHoltWinters.
+	Alpha, Beta, Gamma float64
+}
+
+// AnomalyBands returns the lower and upper bands of the expected values of the series.
+// Points of the bands are null where there is not enough data to determine them.
+func (s Series) AnomalyBands(refID string, opts AnomalyOptions) (lower Series, upper Series, err error) {
+	if opts.Sensitivity <= 0 {
+		return lower, upper, fmt.Errorf("anomaly sensitivity must be greater than zero, got %v", opts.Sensitivity)
+	}
+	lower = NewSeries(refID, s.GetLabels(), s.Len())
+	upper = NewSeries(refID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		lower.SetPoint(i, s.GetTime(i), nil)
+		upper.SetPoint(i, s.GetTime(i), nil)
+	}
+
+	setConstantBands := func(center, deviation float64) {
+		lo, hi := center-opts.Sensitivity*deviation, center+opts.Sensitivity*deviation
+		for i := 0; i < s.Len(); i++ {
+			t := s.GetTime(i)
+			lower.SetPoint(i, t, &lo)
+			upper.SetPoint(i, t, &hi)
+		}
+	}
+
+	switch opts.Algorithm {
+	case AnomalyZScore:
+		values := s.numberValues()
+		if len(values) == 0 {
+			return lower, upper, nil
+		}
+		var mean, variance float64
+		for _, v := range values {
+			mean += v
+		}
+		mean /= float64(len(values))
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(len(values))
+		setConstantBands(mean, math.Sqrt(variance))
+	case AnomalyMAD:
+		values := s.numberValues()
+		if len(values) == 0 {
+			return lower, upper, nil
+		}
+		median := medianOf(values)
+		deviations := make([]float64, len(values))
+		for i, v := range values {
+			deviations[i] = math.Abs(v - median)
+		}
+		setConstantBands(median, madScale*medianOf(deviations))
+	case AnomalyHoltWinters:
+		if opts.Season <= 0 {
+			return lower, upper, fmt.Errorf("a season is required for the %s anomaly algorithm", opts.Algorithm)
+		}
+		step, err := seriesStep(s)
+		if err != nil {
+			return lower, upper, err
+		}
+		seasonLen := int(math.Round(float64(opts.Season) / float64(step)))
+		if seasonLen < 2 {
+			return lower, upper, fmt.Errorf("season %v must span at least two points of the series, which has an interval of %v", opts.Season, step)
+		}
+		values := make([]*float64, s.Len())
+		for i := range values {
+			values[i] = s.GetValue(i)
+		}
+		_, forecasts, err := fitHoltWinters(values, seasonLen, opts.Alpha, opts.Beta, opts.Gamma)
+		if err != nil {
+			return lower, upper, err
+		}
+		var residuals []float64
+		for i, f := range forecasts {
+			if f != nil && values[i] != nil && !math.IsNaN(*values[i]) {
+				residuals = append(residuals, *values[i]-*f)
+			}
+		}
+		var deviation float64
+		for _, r := range residuals {
+			deviation += r * r
+		}
+		if len(residuals) > 0 {
+			deviation = math.Sqrt(deviation / float64(len(residuals)))
+		}
+		for i, f := range forecasts {
+			if f == nil {
+				continue
+			}
+			t := s.GetTime(i)
+			lo, hi := *f-opts.Sensitivity*deviation, *f+opts.Sensitivity*deviation
+			lower.SetPoint(i, t, &lo)
+			upper.SetPoint(i, t, &hi)
+		}
+	default:
+		return lower, upper, fmt.Errorf("anomaly algorithm %v not implemented", opts.Algorithm)
+	}
+	return lower, upper, nil
+}
+
+// AnomalyFlags returns a series that is 1 where the series is outside of the bands and 0 where it is within them.
+// Points are null where the value or either of the bands is null or NaN.
+func (s Series) AnomalyFlags(refID string, lower, upper Series) Series {
+	flags := NewSeries(refID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		lo, hi := lower.GetValue(i), upper.GetValue(i)
+		if nilOrNaN(v) || nilOrNaN(lo) || nilOrNaN(hi) {
+			flags.SetPoint(i, t, nil)
+			continue
+		}
+		f := float64(0)
+		if *v < *lo || *v > *hi {
+			f = 1
+		}
+		flags.SetPoint(i, t, &f)
+	}
+	return flags
+}
+
+// numberValues returns the values of the series that are not null, NaN or Inf.
+func (s Series) numberValues() []float64 {
+	values := make([]float64, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		v := s.GetValue(i)
+		if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+			continue
+		}
+		values = append(values, *v)
+	}
+	return values
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,109 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesAnomalyBands(t *testing.T) {
+	constant := func(v float64, n int) []*float64 {
+		values := make([]*float64, n)
+		for i := range values {
+			values[i] = float64Pointer(v)
+		}
+		return values
+	}
+	seriesOf := func(values ...*float64) Series {
+		s := NewSeries("", nil, len(values))
+		for i, v := range values {
+			s.SetPoint(i, time.Unix(int64(i*60), 0), v)
+		}
+		return s
+	}
+
+	t.Run("zscore bands are centered on the mean", func(t *testing.T) {
+		s := seriesOf(float64Pointer(2), float64Pointer(4), float64Pointer(4), float64Pointer(4), nil, float64Pointer(5), float64Pointer(5), float64Pointer(7), float64Pointer(9))
+		lower, upper, err := s.AnomalyBands("B", AnomalyOptions{Algorithm: AnomalyZScore, Sensitivity: 1})
+		require.NoError(t, err)
+		require.Equal(t, s.Len(), lower.Len())
+		require.Equal(t, 3.0, *lower.GetValue(0))
+		require.Equal(t, 7.0, *upper.GetValue(0))
+
+		flags := s.AnomalyFlags("B", lower, upper)
+		require.Equal(t, 1.0, *flags.GetValue(0))
+		require.Equal(t, 0.0, *flags.GetValue(1))
+		require.Nil(t, flags.GetValue(4))
+		require.Equal(t, 0.0, *flags.GetValue(7))
+		require.Equal(t, 1.0, *flags.GetValue(8))
+	})
+
+	t.Run("mad bands ignore outliers", func(t *testing.T) {
+		s := seriesOf(float64Pointer(1), float64Pointer(2), float64Pointer(3), float64Pointer(4), float64Pointer(1000))
+		lower, upper, err := s.AnomalyBands("B", AnomalyOptions{Algorithm: AnomalyMAD, Sensitivity: 2})
+		require.NoError(t, err)
+		require.InDelta(t, 3-2*madScale, *lower.GetValue(0), 1e-9)
+		require.InDelta(t, 3+2*madScale, *upper.GetValue(0), 1e-9)
+
+		flags := s.AnomalyFlags("B", lower, upper)
+		require.Equal(t, 0.0, *flags.GetValue(0))
+		require.Equal(t, 1.0, *flags.GetValue(4))
+	})
+
+	t.Run("bands of a series without values are null", func(t *testing.T) {
+		s := seriesOf(nil, nil)
+		lower, upper, err := s.AnomalyBands("B", AnomalyOptions{Algorithm: AnomalyZScore, Sensitivity: 3})
+		require.NoError(t, err)
+		require.Nil(t, lower.GetValue(0))
+		require.Nil(t, upper.GetValue(1))
+		require.Equal(t, time.Unix(60, 0), upper.GetTime(1))
+	})
+
+	t.Run("holt_winters follows the season", func(t *testing.T) {
+		pattern := []float64{10, 20, 30, 20}
+		values := make([]*float64, 0, 20)
+		for i := 0; i < 20; i++ {
+			values = append(values, float64Pointer(pattern[i%len(pattern)]))
+		}
+		values[17] = float64Pointer(100)
+		s := seriesOf(values...)
+		lower, upper, err := s.AnomalyBands("B", AnomalyOptions{
+			Algorithm:   AnomalyHoltWinters,
+			Sensitivity: 2,
+			Season:      4 * time.Minute,
+			Alpha:       0.5,
+			Beta:        0.1,
+			Gamma:       0.3,
+		})
+		require.NoError(t, err)
+		for i := 0; i < 4; i++ {
+			require.Nil(t, lower.GetValue(i))
+			require.Nil(t, upper.GetValue(i))
+		}
+
+		flags := s.AnomalyFlags("B", lower, upper)
+		for i := 4; i < 17; i++ {
+			require.Equal(t, 0.0, *flags.GetValue(i), "point %d", i)
+		}
+		require.Equal(t, 1.0, *flags.GetValue(17))
+	})
+
+	t.Run("holt_winters needs two seasons", func(t *testing.T) {
+		s := seriesOf(constant(1, 5)...)
+		_, _, err := s.AnomalyBands("B", AnomalyOptions{Algorithm: AnomalyHoltWinters, Sensitivity: 3, Season: 4 * time.Minute})
+		require.Error(t, err)
+	})
+
+	t.Run("holt_winters season shorter than the interval", func(t *testing.T) {
+		s := seriesOf(constant(1, 10)...)
+		_, _, err := s.AnomalyBands("B", AnomalyOptions{Algorithm: AnomalyHoltWinters, Sensitivity: 3, Season: time.Minute})
+		require.Error(t, err)
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, _, err := seriesOf(float64Pointer(math.Pi)).AnomalyBands("B", AnomalyOptions{Algorithm: "foo", Sensitivity: 3})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,175 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The forecast method
+// +enum
+type ForecastMethod string
+
+const (
+	// Least squares linear regression, like predict_linear in PromQL
+	ForecastLinear ForecastMethod = "linear"
+
+	// Holt-Winters exponential smoothing, seasonal when a season is set
+	ForecastHoltWinters ForecastMethod = "holt_winters"
+)
+
+// ForecastOptions configures how a series is extrapolated.
+type ForecastOptions struct {
+	Method ForecastMethod
+	// Window limits the fitting to the points that are at most Window older than the last point of the series.
+	// All points are used if it is zero.
+	Window time.Duration
+	// Season is the length of the seasonal cycle. Only used by ForecastHoltWinters.
+	Season time.Duration
+	// Alpha, Beta and Gamma are the smoothing factors of level, trend and season. Only used by ForecastHoltWinters.
+	Alpha, Beta, Gamma float64
+}
+
+// MaxForecastPoints is the maximum number of points of a projected series.
+const MaxForecastPoints = 10000
+
+// predictor returns the predicted value of a series at a point in time.
+type predictor func(t time.Time) float64
+
+// Forecast turns the Series into a Number that is the value the series is predicted to have at the given time.
+func (s Series) Forecast(refID string, opts ForecastOptions, at time.Time) (Number, error) {
+	var l data.Labels
+	if s.GetLabels() != nil {
+		l = s.GetLabels().Copy()
+	}
+	number := NewNumber(refID, l)
+	predict, err := s.fitPredictor(opts)
+	if err != nil {
+		return number, err
+	}
+	if predict == nil {
+		number.SetValue(nil)
+		return number, nil
+	}
+	f := predict(at)
+	number.SetValue(&f)
+	return number, nil
+}
+
+// ForecastSeries returns the projection of the Series from its last point until the given time,
+// at the interval of the series. It fails if the projection would have more than MaxForecastPoints points.
+func (s Series) ForecastSeries(refID string, opts ForecastOptions, until time.Time) (Series, error) {
+	projected := NewSeries(refID, s.GetLabels(), 0)
+	predict, err := s.fitPredictor(opts)
+	if err != nil || predict == nil {
+		return projected, err
+	}
+	step, err := seriesStep(s)
+	if err != nil {
+		return projected, err
+	}
+	if points := until.Sub(s.GetTime(s.Len()-1)) / step; points > MaxForecastPoints {
+		return projected, fmt.Errorf("forecast until %s at an interval of %s would produce %d points, more than the maximum of %d: reduce the horizon or increase the interval of the series",
+			until.Format(time.RFC3339), step, points, MaxForecastPoints)
+	}
+	for t := s.GetTime(s.Len() - 1).Add(step); !t.After(until); t = t.Add(step) {
+		f := predict(t)
+		projected.AppendPoint(t, &f)
+	}
+	return projected, nil
+}
+
+// fitPredictor fits the forecast model to the series. It returns a nil predictor if there are no values to fit.
+func (s Series) fitPredictor(opts ForecastOptions) (predictor, error) {
+	if s.Len() == 0 {
+		return nil, nil
+	}
+	fitted := s
+	if opts.Window > 0 {
+		from := s.GetTime(s.Len() - 1).Add(-opts.Window)
+		fitted = NewSeries(s.GetName(), s.GetLabels(), 0)
+		for i := 0; i < s.Len(); i++ {
+			t, v := s.GetPoint(i)
+			if t.After(from) {
+				fitted.AppendPoint(t, v)
+			}
+		}
+	}
+
+	switch opts.Method {
+	case ForecastLinear:
+		return fitted.linearPredictor(), nil
+	case ForecastHoltWinters:
+		return fitted.holtWintersPredictor(opts)
+	default:
+		return nil, fmt.Errorf("forecast method %v not implemented", opts.Method)
+	}
+}
+
+// linearPredictor fits a line through the points of the series with simple linear regression.
+func (s Series) linearPredictor() predictor {
+	var (
+		origin       time.Time
+		n            float64
+		sumX, sumY   float64
+		sumXY, sumXX float64
+	)
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if nilOrNaN(v) {
+			continue
+		}
+		if n == 0 {
+			origin = t
+		}
+		x := t.Sub(origin).Seconds()
+		n++
+		sumX += x
+		sumY += *v
+		sumXY += x * *v
+		sumXX += x * x
+	}
+	if n == 0 {
+		return nil
+	}
+	var slope float64
+	if d := n*sumXX - sumX*sumX; d != 0 {
+		slope = (n*sumXY - sumX*sumY) / d
+	}
+	intercept := (sumY - slope*sumX) / n
+	return func(t time.Time) float64 {
+		return intercept + slope*t.Sub(origin).Seconds()
+	}
+}
+
+func (s Series) holtWintersPredictor(opts ForecastOptions) (predictor, error) {
+	step, err := seriesStep(s)
+	if err != nil {
+		return nil, err
+	}
+	seasonLen := 0
+	if opts.Season > 0 {
+		seasonLen = int(math.Round(float64(opts.Season) / float64(step)))
+		if seasonLen < 2 {
+			return nil, fmt.Errorf("season %v must span at least two points of the series, which has an interval of %v", opts.Season, step)
+		}
+	}
+	values := make([]*float64, s.Len())
+	for i := range values {
+		values[i] = s.GetValue(i)
+	}
+	model, _, err := fitHoltWinters(values, seasonLen, opts.Alpha, opts.Beta, opts.Gamma)
+	if err != nil {
+		return nil, err
+	}
+	last := s.GetTime(s.Len() - 1)
+	return func(t time.Time) float64 {
+		h := int(math.Round(float64(t.Sub(last)) / float64(step)))
+		if h < 0 {
+			h = 0
+		}
+		return model.forecast(h)
+	}, nil
+}
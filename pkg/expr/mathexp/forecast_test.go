@@ -0,0 +1,88 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesForecast(t *testing.T) {
+	// disk usage that grows by 1 per minute
+	linear := makeSeries("disk", data.Labels{"mount": "/"},
+		tp{time.Unix(0, 0), float64Pointer(10)},
+		tp{time.Unix(60, 0), float64Pointer(11)},
+		tp{time.Unix(120, 0), nil},
+		tp{time.Unix(180, 0), float64Pointer(13)},
+		tp{time.Unix(240, 0), float64Pointer(14)},
+	)
+
+	t.Run("linear predicts the value at the given time", func(t *testing.T) {
+		n, err := linear.Forecast("B", ForecastOptions{Method: ForecastLinear}, time.Unix(600, 0))
+		require.NoError(t, err)
+		require.Equal(t, data.Labels{"mount": "/"}, n.GetLabels())
+		require.InDelta(t, 20, *n.GetFloat64Value(), 1e-9)
+	})
+
+	t.Run("linear only fits the points in the window", func(t *testing.T) {
+		s := makeSeries("", nil,
+			tp{time.Unix(0, 0), float64Pointer(100)},
+			tp{time.Unix(60, 0), float64Pointer(1)},
+			tp{time.Unix(120, 0), float64Pointer(2)},
+		)
+		n, err := s.Forecast("B", ForecastOptions{Method: ForecastLinear, Window: 90 * time.Second}, time.Unix(180, 0))
+		require.NoError(t, err)
+		require.InDelta(t, 3, *n.GetFloat64Value(), 1e-9)
+	})
+
+	t.Run("linear projects the series until the given time", func(t *testing.T) {
+		s, err := linear.ForecastSeries("B", ForecastOptions{Method: ForecastLinear}, time.Unix(400, 0))
+		require.NoError(t, err)
+		require.Equal(t, 2, s.Len())
+		require.Equal(t, time.Unix(300, 0), s.GetTime(0))
+		require.InDelta(t, 15, *s.GetValue(0), 1e-9)
+		require.Equal(t, time.Unix(360, 0), s.GetTime(1))
+		require.InDelta(t, 16, *s.GetValue(1), 1e-9)
+	})
+
+	t.Run("projection with too many points fails", func(t *testing.T) {
+		until := time.Unix(240, 0).Add(time.Duration(MaxForecastPoints+1) * time.Minute)
+		_, err := linear.ForecastSeries("B", ForecastOptions{Method: ForecastLinear}, until)
+		require.ErrorContains(t, err, "more than the maximum")
+
+		s, err := linear.ForecastSeries("B", ForecastOptions{Method: ForecastLinear}, time.Unix(240, 0).Add(MaxForecastPoints*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, MaxForecastPoints, s.Len())
+	})
+
+	t.Run("series without values forecasts null", func(t *testing.T) {
+		s := makeSeries("", nil, tp{time.Unix(0, 0), nil})
+		n, err := s.Forecast("B", ForecastOptions{Method: ForecastLinear}, time.Unix(600, 0))
+		require.NoError(t, err)
+		require.Nil(t, n.GetFloat64Value())
+	})
+
+	t.Run("holt_winters follows the trend", func(t *testing.T) {
+		n, err := linear.Forecast("B", ForecastOptions{Method: ForecastHoltWinters, Alpha: 0.5, Beta: 0.5}, time.Unix(600, 0))
+		require.NoError(t, err)
+		require.InDelta(t, 20, *n.GetFloat64Value(), 1e-9)
+	})
+
+	t.Run("holt_winters follows the season", func(t *testing.T) {
+		pattern := []float64{10, 20, 30, 20}
+		s := NewSeries("", nil, 0)
+		for i := 0; i < 16; i++ {
+			s.AppendPoint(time.Unix(int64(i*60), 0), float64Pointer(pattern[i%len(pattern)]))
+		}
+		opts := ForecastOptions{Method: ForecastHoltWinters, Season: 4 * time.Minute, Alpha: 0.5, Beta: 0.1, Gamma: 0.3}
+		n, err := s.Forecast("B", opts, time.Unix(18*60, 0))
+		require.NoError(t, err)
+		require.InDelta(t, 30, *n.GetFloat64Value(), 1e-6)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, err := linear.Forecast("B", ForecastOptions{Method: "foo"}, time.Unix(600, 0))
+		require.Error(t, err)
+	})
+}
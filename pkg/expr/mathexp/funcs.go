@@ -1,7 +1,11 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +72,52 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"clamp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clamp,
+	},
+	"shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		Check:  checkDurationArg(1),
+		F:      shift,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeString},
+		Return: parse.TypeSeriesSet,
+		Check:  checkDurationArg(1),
+		F:      movingAvg,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
+	"timestamp": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      timestamp,
+	},
+}
+
+// checkDurationArg returns a parse time check that the string argument at argIdx is a valid duration.
+func checkDurationArg(argIdx int) func(*parse.Tree, *parse.FuncNode) error {
+	return func(_ *parse.Tree, f *parse.FuncNode) error {
+		s, ok := f.Args[argIdx].(*parse.StringNode)
+		if !ok {
+			return fmt.Errorf("parse: expected a duration string for argument %v of %s", argIdx, f.Name)
+		}
+		if _, err := gtime.ParseDuration(s.Text); err != nil {
+			return fmt.Errorf("parse: invalid duration %s for %s: %w", s.Quoted, f.Name, err)
+		}
+		return nil
+	}
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +357,158 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// clamp limits the value for each result in NumberSet, SeriesSet, or Scalar to the range [min, max].
+func clamp(e *State, varSet Results, minSet Results, maxSet Results) (Results, error) {
+	newRes := Results{}
+	lower := minSet.Values[0].(Scalar).GetFloat64Value()
+	upper := maxSet.Values[0].(Scalar).GetFloat64Value()
+	if lower == nil || upper == nil {
+		return newRes, fmt.Errorf("clamp bounds must not be null")
+	}
+	if *lower > *upper {
+		return newRes, fmt.Errorf("clamp min %v is greater than max %v", *lower, *upper)
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, func(f float64) float64 {
+			return math.Max(*lower, math.Min(*upper, f))
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// shift moves each point of each series in the SeriesSet forward in time by the given duration,
+// so that e.g. shift($A, "1w") can be compared with $A to get the week-over-week change.
+// The labels are kept so the shifted series matches the original one in binary operations.
+func shift(e *State, varSet Results, rawOffset string) (Results, error) {
+	offset, err := gtime.ParseDuration(rawOffset)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, varSet, "shift", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(offset), f)
+		}
+		return newSeries
+	})
+}
+
+// movingAvg returns for each point of each series in the SeriesSet the average of the points
+// in the trailing window (t - window, t]. Null and NaN values are ignored, and the point is null
+// if there are no values in the window.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := gtime.ParseDuration(rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	if window <= 0 {
+		return Results{}, fmt.Errorf("moving_avg window must be greater than zero, got %s", rawWindow)
+	}
+	return perSeries(e, varSet, "moving_avg", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var sum float64
+		count := 0
+		start := 0
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if !nilOrNaN(f) {
+				sum += *f
+				count++
+			}
+			for ; start <= i && !s.GetTime(start).After(t.Add(-window)); start++ {
+				if old := s.GetValue(start); !nilOrNaN(old) {
+					sum -= *old
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			avg := sum / float64(count)
+			newSeries.SetPoint(i, t, &avg)
+		}
+		return newSeries
+	})
+}
+
+// delta returns for each point of each series in the SeriesSet the difference to the previous point.
+// The first point, and points where either value is null, are null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "delta", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var prev *float64
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if f == nil || prev == nil {
+				newSeries.SetPoint(i, t, nil)
+			} else {
+				d := *f - *prev
+				newSeries.SetPoint(i, t, &d)
+			}
+			prev = f
+		}
+		return newSeries
+	})
+}
+
+// cumsum returns for each point of each series in the SeriesSet the sum of all values up to and including the point.
+// Null and NaN values do not contribute to the sum, and are kept as they are.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "cumsum", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		var sum float64
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			if nilOrNaN(f) {
+				newSeries.SetPoint(i, t, f)
+				continue
+			}
+			sum += *f
+			nF := sum
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// timestamp returns for each point of each series in the SeriesSet the time of the point
+// as the number of seconds since the Unix epoch.
+func timestamp(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "timestamp", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t := s.GetTime(i)
+			ts := float64(t.UnixNano()) / float64(time.Second)
+			newSeries.SetPoint(i, t, &ts)
+		}
+		return newSeries
+	})
+}
+
+// perSeries passes each Series of the SeriesSet to seriesF. NoData is passed through as it is,
+// any other type returns an error because the time-aware functions can only work on series.
+func perSeries(e *State, varSet Results, name string, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s can only be applied to a series, got type %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func nilOrNaN(f *float64) bool {
+	return f == nil || math.IsNaN(*f)
+}
@@ -5,8 +5,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
 )
 
 func TestAbsFunc(t *testing.T) {
@@ -140,3 +143,208 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	aSeries := Vars{
+		"A": resultValuesNoErr(
+			makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(60, 0), float64Pointer(3)},
+				tp{time.Unix(120, 0), nil},
+				tp{time.Unix(180, 0), float64Pointer(8)}),
+		),
+	}
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "shift moves points forward in time",
+			expr:      `shift($A, "1h")`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(3600, 0), float64Pointer(1)},
+					tp{time.Unix(3660, 0), float64Pointer(3)},
+					tp{time.Unix(3720, 0), nil},
+					tp{time.Unix(3780, 0), float64Pointer(8)}),
+			),
+		},
+		{
+			name:      "shifted series matches the original in binary operations",
+			expr:      `$A - shift($A, "1m")`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(60, 0), float64Pointer(2)},
+					tp{time.Unix(120, 0), nil},
+					tp{time.Unix(180, 0), nil}),
+			),
+		},
+		{
+			name:     "shift with invalid duration",
+			expr:     `shift($A, "foo")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "shift on scalar",
+			expr:     `shift(1, "1h")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:      "moving_avg over a trailing window",
+			expr:      `moving_avg($A, "2m")`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(60, 0), float64Pointer(2)},
+					tp{time.Unix(120, 0), float64Pointer(3)},
+					tp{time.Unix(180, 0), float64Pointer(8)}),
+			),
+		},
+		{
+			name:      "delta",
+			expr:      `delta($A)`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), nil},
+					tp{time.Unix(60, 0), float64Pointer(2)},
+					tp{time.Unix(120, 0), nil},
+					tp{time.Unix(180, 0), nil}),
+			),
+		},
+		{
+			name:      "cumsum",
+			expr:      `cumsum($A)`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(60, 0), float64Pointer(4)},
+					tp{time.Unix(120, 0), nil},
+					tp{time.Unix(180, 0), float64Pointer(12)}),
+			),
+		},
+		{
+			name:      "timestamp",
+			expr:      `timestamp($A)`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(0)},
+					tp{time.Unix(60, 0), float64Pointer(60)},
+					tp{time.Unix(120, 0), float64Pointer(120)},
+					tp{time.Unix(180, 0), float64Pointer(180)}),
+			),
+		},
+		{
+			name:      "timestamp on number",
+			expr:      `timestamp($A)`,
+			vars:      Vars{"A": resultValuesNoErr(makeNumber("", nil, float64Pointer(1)))},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+			results:   Results{},
+		},
+		{
+			name:      "clamp on series",
+			expr:      `clamp($A, 2, 5)`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0), float64Pointer(2)},
+					tp{time.Unix(60, 0), float64Pointer(3)},
+					tp{time.Unix(120, 0), NaN},
+					tp{time.Unix(180, 0), float64Pointer(5)}),
+			),
+		},
+		{
+			name:      "clamp on scalar with negative bound",
+			expr:      `clamp(-7, -1, 1)`,
+			vars:      Vars{},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   resultValuesNoErr(NewScalar("", float64Pointer(-1))),
+		},
+		{
+			name:      "clamp without commas",
+			expr:      `clamp($A 2 5)`,
+			vars:      aSeries,
+			newErrIs:  require.Error,
+			execErrIs: require.NoError,
+			results:   Results{},
+		},
+		{
+			name:      "clamp with repeated commas",
+			expr:      `clamp($A,, 2, 5)`,
+			vars:      aSeries,
+			newErrIs:  require.Error,
+			execErrIs: require.NoError,
+			results:   Results{},
+		},
+		{
+			name:      "clamp with trailing comma",
+			expr:      `clamp($A, 2, 5,)`,
+			vars:      aSeries,
+			newErrIs:  require.Error,
+			execErrIs: require.NoError,
+			results:   Results{},
+		},
+		{
+			name:      "clamp with leading comma",
+			expr:      `clamp(, $A, 2, 5)`,
+			vars:      aSeries,
+			newErrIs:  require.Error,
+			execErrIs: require.NoError,
+			results:   Results{},
+		},
+		{
+			name:      "clamp with min greater than max",
+			expr:      `clamp($A, 5, 2)`,
+			vars:      aSeries,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+			results:   Results{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if err != nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			opt := cmp.Comparer(func(x, y float64) bool {
+				return (math.IsNaN(x) && math.IsNaN(y)) || x == y
+			})
+			options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+			if diff := cmp.Diff(tt.results, res, options...); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
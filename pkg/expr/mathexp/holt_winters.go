@@ -0,0 +1,152 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// holtWinters is an additive Holt-Winters (triple exponential smoothing) model.
+// When seasonLen is less than 2 the seasonal component is not used and the model
+// is Holt's linear trend method (double exponential smoothing).
+type holtWinters struct {
+	alpha, beta, gamma float64
+	seasonLen          int
+
+	level    float64
+	trend    float64
+	seasonal []float64
+	// next is the index of the next observation the model expects.
+	next int
+}
+
+// fitHoltWinters fits the model to the values and returns it together with the one-step-ahead
+// forecast for every value. Forecasts are nil for the values that were used to initialize the model.
+// Null and NaN values are replaced with the forecast, so they do not disturb the model.
+func fitHoltWinters(values []*float64, seasonLen int, alpha, beta, gamma float64) (*holtWinters, []*float64, error) {
+	for name, v := range map[string]float64{"alpha": alpha, "beta": beta, "gamma": gamma} {
+		if v < 0 || v > 1 {
+			return nil, nil, fmt.Errorf("holt-winters smoothing factor %s must be between 0 and 1, got %v", name, v)
+		}
+	}
+	m := &holtWinters{alpha: alpha, beta: beta, gamma: gamma, seasonLen: seasonLen}
+	forecasts := make([]*float64, len(values))
+
+	if seasonLen < 2 {
+		m.seasonLen = 0
+		if err := m.initLinear(values); err != nil {
+			return nil, nil, err
+		}
+	} else if err := m.initSeasonal(values); err != nil {
+		return nil, nil, err
+	}
+
+	for ; m.next < len(values); m.next++ {
+		f := m.forecast(1)
+		forecasts[m.next] = &f
+		x := f
+		if v := values[m.next]; v != nil && !math.IsNaN(*v) {
+			x = *v
+		}
+		s := m.season(m.next)
+		level := m.alpha*(x-s) + (1-m.alpha)*(m.level+m.trend)
+		m.trend = m.beta*(level-m.level) + (1-m.beta)*m.trend
+		m.level = level
+		if m.seasonLen > 0 {
+			m.seasonal[m.next%m.seasonLen] = m.gamma*(x-level) + (1-m.gamma)*s
+		}
+	}
+	return m, forecasts, nil
+}
+
+// initLinear initializes level and trend from the first two non-null values.
+func (m *holtWinters) initLinear(values []*float64) error {
+	first := -1
+	for i, v := range values {
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		if first < 0 {
+			first = i
+			m.level = *v
+			continue
+		}
+		m.trend = (*v - m.level) / float64(i-first)
+		m.level = *v
+		m.next = i + 1
+		return nil
+	}
+	return fmt.Errorf("at least two values are required to fit a trend")
+}
+
+// initSeasonal initializes level, trend and seasonal components from the first two seasons.
+func (m *holtWinters) initSeasonal(values []*float64) error {
+	if len(values) < 2*m.seasonLen {
+		return fmt.Errorf("at least two seasons of %d points are required, got %d points", m.seasonLen, len(values))
+	}
+	first, ok := meanOf(values[:m.seasonLen])
+	if !ok {
+		return fmt.Errorf("the first season does not have any values")
+	}
+	second, ok := meanOf(values[m.seasonLen : 2*m.seasonLen])
+	if !ok {
+		return fmt.Errorf("the second season does not have any values")
+	}
+	m.trend = (second - first) / float64(m.seasonLen)
+	m.seasonal = make([]float64, m.seasonLen)
+	for i := 0; i < m.seasonLen; i++ {
+		if v := values[i]; v != nil && !math.IsNaN(*v) {
+			m.seasonal[i] = *v - first
+		}
+	}
+	// the level of the first season is its mean, which is centered in the middle of it
+	m.level = first + m.trend*float64(m.seasonLen-1)/2
+	m.next = m.seasonLen
+	return nil
+}
+
+func (m *holtWinters) season(idx int) float64 {
+	if m.seasonLen == 0 {
+		return 0
+	}
+	return m.seasonal[idx%m.seasonLen]
+}
+
+// forecast returns the predicted value h steps after the last observation.
+func (m *holtWinters) forecast(h int) float64 {
+	return m.level + float64(h)*m.trend + m.season(m.next+h-1)
+}
+
+func meanOf(values []*float64) (float64, bool) {
+	var sum float64
+	count := 0
+	for _, v := range values {
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		sum += *v
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// seriesStep returns the median interval between the points of the series.
+func seriesStep(s Series) (time.Duration, error) {
+	if s.Len() < 2 {
+		return 0, fmt.Errorf("at least two points are required to determine the interval of the series")
+	}
+	steps := make([]time.Duration, 0, s.Len()-1)
+	for i := 1; i < s.Len(); i++ {
+		steps = append(steps, s.GetTime(i).Sub(s.GetTime(i-1)))
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i] < steps[j] })
+	step := steps[len(steps)/2]
+	if step <= 0 {
+		return 0, fmt.Errorf("the points of the series must be in ascending order of time")
+	}
+	return step, nil
+}
@@ -103,7 +103,6 @@ func (t *Tree) expect(expected itemType, context string) item {
 }
 
 // expectOneOf consumes the next token and guarantees it has one of the required types.
-// nolint:unused
 func (t *Tree) expectOneOf(expected1, expected2 itemType, context string) item {
 	token := t.next()
 	if token.typ != expected1 && token.typ != expected2 {
@@ -314,8 +313,21 @@ func (t *Tree) Func() (f *FuncNode) {
 	}
 	f = newFunc(token.pos, token.val, funcv)
 	t.expect(itemLeftParen, "func")
+	if t.peek().typ == itemRightParen {
+		t.next()
+		return
+	}
+	// Arguments are separated by exactly one comma, with no comma before the first argument or the closing parenthesis.
 	for {
 		switch token = t.next(); token.typ {
+		case itemString:
+			s, err := strconv.Unquote(token.val)
+			if err != nil {
+				t.errorf("Unquoting error: %s", err)
+			}
+			f.append(newString(token.pos, token.val, s))
+		case itemComma, itemRightParen:
+			t.unexpected(token, "func")
 		default:
 			t.backup()
 			node := t.O()
@@ -323,13 +335,8 @@ func (t *Tree) Func() (f *FuncNode) {
 			if len(f.Args) == 1 && f.F.VariantReturn {
 				f.F.Return = node.Return()
 			}
-		case itemString:
-			s, err := strconv.Unquote(token.val)
-			if err != nil {
-				t.errorf("Unquoting error: %s", err)
-			}
-			f.append(newString(token.pos, token.val, s))
-		case itemRightParen:
+		}
+		if token = t.expectOneOf(itemComma, itemRightParen, "func"); token.typ == itemRightParen {
 			return
 		}
 	}
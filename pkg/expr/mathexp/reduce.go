@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
 type ReducerFunc = func(fv *Float64Field) *float64
 
+// SeriesReducerFunc is a reducer that needs the timestamps of the series in addition to its values.
+type SeriesReducerFunc = func(s Series) *float64
+
 // The reducer function
 // +enum
 type ReducerID string
@@ -22,11 +27,48 @@ const (
 	ReducerCount  ReducerID = "count"
 	ReducerLast   ReducerID = "last"
 	ReducerMedian ReducerID = "median"
+	ReducerFirst  ReducerID = "first"
+	ReducerDiff   ReducerID = "diff"
+	// Population standard deviation
+	ReducerStdDev ReducerID = "stddev"
+	// Population variance
+	ReducerVariance ReducerID = "variance"
+	// Number of points that are neither null nor NaN
+	ReducerCountNonNull ReducerID = "count_non_null"
+	// Increase of a counter over the series, adjusted for counter resets
+	ReducerIncrease ReducerID = "increase"
+	// Per-second rate of a counter over the series, adjusted for counter resets
+	ReducerRate ReducerID = "rate"
 )
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// percentileReducerPrefix is the prefix of the parameterised percentile reducers, e.g. "p95" or "p99.9".
+const percentileReducerPrefix = "p"
+
+// PercentileReducer returns the ID of the reducer that calculates the given percentile.
+func PercentileReducer(percentile float64) ReducerID {
+	return ReducerID(percentileReducerPrefix + strconv.FormatFloat(percentile, 'f', -1, 64))
+}
+
+// ParsePercentileReducer returns the percentile encoded in rFunc, and false if rFunc is not a valid percentile reducer.
+func ParsePercentileReducer(rFunc ReducerID) (float64, bool) {
+	raw, ok := strings.CutPrefix(string(rFunc), percentileReducerPrefix)
+	if !ok || raw == "" {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
+// GetSupportedReduceFuncs returns collection of supported function names.
+// Percentile reducers are parameterised and therefore not part of the list, see PercentileReducer.
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{
+		ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerDiff, ReducerStdDev, ReducerVariance, ReducerCountNonNull, ReducerIncrease, ReducerRate,
+	}
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +168,135 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Diff returns the difference between the last and the first value of the field.
+func Diff(fv *Float64Field) *float64 {
+	nan := math.NaN()
+	if fv.Len() == 0 {
+		return &nan
+	}
+	first, last := fv.GetValue(0), fv.GetValue(fv.Len()-1)
+	if first == nil || last == nil {
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+func Variance(fv *Float64Field) *float64 {
+	nan := math.NaN()
+	if fv.Len() == 0 {
+		return &nan
+	}
+	mean := Avg(fv)
+	if math.IsNaN(*mean) {
+		return mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *mean
+		sum += d * d
+	}
+	f := sum / float64(fv.Len())
+	return &f
+}
+
+func StdDev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+// Percentile returns a reducer that calculates the given percentile (0-100) of the field
+// by linear interpolation between the closest ranks. Percentile(50) is equivalent to Median.
+func Percentile(percentile float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				nan := math.NaN()
+				return &nan
+			}
+			values = append(values, *v)
+		}
+
+		if len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+
+		sort.Float64s(values)
+		rank := percentile / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// Increase returns the increase of a counter over the series.
+// A decrease between two consecutive points is treated as a counter reset,
+// in which case the value after the reset is counted as the increase.
+func Increase(s Series) *float64 {
+	nan := math.NaN()
+	if s.Len() < 2 {
+		return &nan
+	}
+	var increase float64
+	prev := s.GetValue(0)
+	if prev == nil || math.IsNaN(*prev) {
+		return &nan
+	}
+	for i := 1; i < s.Len(); i++ {
+		v := s.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return &nan
+		}
+		if *v < *prev {
+			increase += *v
+		} else {
+			increase += *v - *prev
+		}
+		prev = v
+	}
+	return &increase
+}
+
+// Rate returns the per-second rate of increase of a counter over the time span of the series.
+// Counter resets are handled the same way as in Increase.
+func Rate(s Series) *float64 {
+	increase := Increase(s)
+	if math.IsNaN(*increase) {
+		return increase
+	}
+	seconds := s.GetTime(s.Len() - 1).Sub(s.GetTime(0)).Seconds()
+	if seconds <= 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *increase / seconds
+	return &f
+}
+
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,11 +313,43 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerVariance:
+		return Variance, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
 	default:
+		if p, ok := ParsePercentileReducer(rFunc); ok {
+			return Percentile(p), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
+// GetSeriesReduceFunc returns the reducer for the given ID that operates on a whole series.
+// It supports all reducers of GetReduceFunc as well as the reducers that depend on timestamps, such as rate.
+func GetSeriesReduceFunc(rFunc ReducerID) (SeriesReducerFunc, error) {
+	switch rFunc {
+	case ReducerIncrease:
+		return Increase, nil
+	case ReducerRate:
+		return Rate, nil
+	}
+	reduceFunc, err := GetReduceFunc(rFunc)
+	if err != nil {
+		return nil, err
+	}
+	return func(s Series) *float64 {
+		floatField := Float64Field(*s.Frame.Fields[seriesTypeValIdx])
+		return reduceFunc(&floatField)
+	}, nil
+}
+
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
@@ -161,13 +364,11 @@ func (s Series) Reduce(refID string, rFunc ReducerID, mapper ReduceMapper) (Numb
 	if mapper != nil {
 		series = mapSeries(s, mapper)
 	}
-	fVec := series.Frame.Fields[seriesTypeValIdx]
-	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetSeriesReduceFunc(rFunc)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
-	f = reduceFunc(&floatField)
+	f = reduceFunc(series)
 	if f != nil && mapper != nil {
 		f = mapper.MapOutput(f)
 	}
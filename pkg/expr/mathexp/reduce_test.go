@@ -274,6 +274,131 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(-1))),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.5))),
+		},
+		{
+			name:        "variance series with a nil value",
+			red:         "variance",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "p50 series is the median",
+			red:         "p50",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1.5))),
+		},
+		{
+			name:        "p75 series interpolates between ranks",
+			red:         PercentileReducer(75),
+			varToReduce: "A",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("temp", nil,
+						tp{time.Unix(5, 0), float64Pointer(40)},
+						tp{time.Unix(10, 0), float64Pointer(10)},
+						tp{time.Unix(15, 0), float64Pointer(20)},
+						tp{time.Unix(20, 0), float64Pointer(30)},
+					),
+				),
+			},
+			errIs:     require.NoError,
+			resultsIs: require.Equal,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(32.5))),
+		},
+		{
+			name:        "percentile out of range will error",
+			red:         "p101",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "increase series with counter reset",
+			red:         "increase",
+			varToReduce: "A",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("temp", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(5)},
+						tp{time.Unix(30, 0), float64Pointer(15)},
+					),
+				),
+			},
+			errIs:     require.NoError,
+			resultsIs: require.Equal,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(35))),
+		},
+		{
+			name:        "rate series with counter reset",
+			red:         "rate",
+			varToReduce: "A",
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("temp", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(5)},
+						tp{time.Unix(30, 0), float64Pointer(15)},
+					),
+				),
+			},
+			errIs:     require.NoError,
+			resultsIs: require.Equal,
+			results:   resultValuesNoErr(makeNumber("", nil, float64Pointer(35.0/30))),
+		},
+		{
+			name:        "rate empty series",
+			red:         "rate",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
 	}
 
 	for _, tt := range tests {
@@ -41,6 +41,7 @@ type rawNode struct {
 	QueryRaw   []byte
 	QueryType  string
 	TimeRange  TimeRange
+	TimeShift  time.Duration
 	DataSource *datasources.DataSource
 	// We use this index as the id of the node graph so the order can remain during a the stable sort of the dependency graph execution order.
 	// Some data sources, such as cloud watch, have order dependencies between queries.
@@ -142,6 +143,10 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 			return nil, err
 		}
 		node.Command = q.Command
+		if sqlCmd, ok := node.Command.(*SQLCommand); ok {
+			// the time range is not part of the parsed query, but it is needed to bind the time range macros
+			sqlCmd.timeRange = rn.TimeRange
+		}
 		return node, err
 	}
 
@@ -158,6 +163,12 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 		node.Command, err = UnmarshalThresholdCommand(rn, toggles)
 	case TypeSQL:
 		node.Command, err = UnmarshalSQLCommand(rn)
+	case TypeAnomaly:
+		node.Command, err = UnmarshalAnomalyCommand(rn)
+	case TypeForecast:
+		node.Command, err = UnmarshalForecastCommand(rn)
+	case TypeAggregate:
+		node.Command, err = UnmarshalAggregateCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -182,6 +193,7 @@ type DSNode struct {
 	orgID      int64
 	queryType  string
 	timeRange  TimeRange
+	timeShift  time.Duration
 	intervalMS int64
 	maxDP      int64
 	request    Request
@@ -224,6 +236,7 @@ func (s *Service) buildDSNode(dp *simple.DirectedGraph, rn *rawNode, req *Reques
 		intervalMS: defaultIntervalMS,
 		maxDP:      defaultMaxDP,
 		timeRange:  rn.TimeRange,
+		timeShift:  rn.TimeShift,
 		request:    *req,
 		datasource: rn.DataSource,
 	}
@@ -296,7 +309,7 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					MaxDataPoints: dn.maxDP,
 					Interval:      time.Duration(int64(time.Millisecond) * dn.intervalMS),
 					JSON:          dn.query,
-					TimeRange:     dn.timeRange.AbsoluteTime(now),
+					TimeRange:     dn.absoluteTimeRange(now),
 					QueryType:     dn.queryType,
 				})
 			}
@@ -332,6 +345,8 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					return
 				}
 
+				dataFrames = shiftFrames(dataFrames, dn.timeShift)
+
 				var result mathexp.Results
 				responseType, result, err := s.converter.Convert(ctx, dn.datasource.Type, dataFrames, s.allowLongFrames)
 				if err != nil {
@@ -369,7 +384,7 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 				MaxDataPoints: dn.maxDP,
 				Interval:      time.Duration(int64(time.Millisecond) * dn.intervalMS),
 				JSON:          dn.query,
-				TimeRange:     dn.timeRange.AbsoluteTime(now),
+				TimeRange:     dn.absoluteTimeRange(now),
 				QueryType:     dn.queryType,
 			},
 		},
@@ -400,6 +415,8 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 	}
 
+	dataFrames = shiftFrames(dataFrames, dn.timeShift)
+
 	var result mathexp.Results
 	responseType, result, err = s.converter.Convert(ctx, dn.datasource.Type, dataFrames, s.allowLongFrames)
 	if err != nil {
@@ -407,3 +424,11 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 	}
 	return result, err
 }
+
+// absoluteTimeRange returns the time range of the query, moved back by the time shift of the node.
+func (dn *DSNode) absoluteTimeRange(now time.Time) backend.TimeRange {
+	tr := dn.timeRange.AbsoluteTime(now)
+	tr.From = tr.From.Add(-dn.timeShift)
+	tr.To = tr.To.Add(-dn.timeShift)
+	return tr
+}
@@ -29,6 +29,15 @@ const (
 
 	// SQL query via DuckDB
 	QueryTypeSQL QueryType = "sql"
+
+	// Anomaly detection
+	QueryTypeAnomaly QueryType = "anomaly"
+
+	// Forecast query results
+	QueryTypeForecast QueryType = "forecast"
+
+	// Aggregate query results across series
+	QueryTypeAggregate QueryType = "aggregate"
 )
 
 type MathQuery struct {
@@ -68,6 +77,9 @@ type ThresholdQuery struct {
 
 	// Threshold Conditions
 	Conditions []ThresholdConditionJSON `json:"conditions"`
+
+	// Named levels, used instead of the conditions, from the most to the least severe
+	Levels []ThresholdLevelJSON `json:"levels,omitempty"`
 }
 
 type ClassicQuery struct {
@@ -77,6 +89,87 @@ type ClassicQuery struct {
 // SQLQuery requires the sqlExpression feature flag
 type SQLExpression struct {
 	Expression string `json:"expression" jsonschema:"minLength=1,example=SELECT * FROM A LIMIT 1"`
+
+	// The shape of the result, defaults to table
+	Format SQLFormat `json:"format,omitempty"`
+}
+
+// The shape of the result of a SQL expression
+// +enum
+type SQLFormat string
+
+const (
+	// The result is returned as a table
+	SQLFormatTable SQLFormat = "table"
+
+	// The result is a long time series, with a time column, numeric value columns and string label columns
+	SQLFormatLong SQLFormat = "long"
+
+	// The result is a wide time series, with a time column and numeric value columns
+	SQLFormatWide SQLFormat = "wide"
+
+	// The result is a set of numbers, with one numeric column and string label columns
+	SQLFormatNumeric SQLFormat = "numeric"
+)
+
+type AnomalyQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The anomaly detection algorithm
+	Algorithm mathexp.AnomalyAlgorithm `json:"algorithm"`
+
+	// The width of the bands in number of deviations, defaults to 3
+	Sensitivity *float64 `json:"sensitivity,omitempty"`
+
+	// The length of the seasonal cycle, required for holt_winters
+	Season string `json:"season,omitempty" jsonschema:"example=1d,example=1w"`
+
+	// Holt-Winters smoothing options
+	HoltWinters *HoltWintersSettings `json:"holtWinters,omitempty"`
+
+	// The series to return for each input series
+	Output AnomalyOutput `json:"output,omitempty"`
+}
+
+type ForecastQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The forecast method, defaults to linear
+	Method mathexp.ForecastMethod `json:"method,omitempty"`
+
+	// How far after the evaluation time to forecast
+	Horizon string `json:"horizon" jsonschema:"minLength=1,example=4h,example=1d"`
+
+	// Only fit the model to the points within this duration before the last point of the series
+	Window string `json:"window,omitempty" jsonschema:"example=1h,example=1d"`
+
+	// The length of the seasonal cycle, only used by holt_winters
+	Season string `json:"season,omitempty" jsonschema:"example=1d,example=1w"`
+
+	// Holt-Winters smoothing options
+	HoltWinters *HoltWintersSettings `json:"holtWinters,omitempty"`
+
+	// Whether to return the predicted value or the projected series
+	Output ForecastOutput `json:"output,omitempty"`
+}
+
+type AggregateQuery struct {
+	// Reference to single query result
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The labels to group by, all results are aggregated together when empty
+	By []string `json:"by,omitempty" jsonschema:"example=cluster"`
+
+	// The reducer applied to the values of each group
+	Reducer mathexp.ReducerID `json:"reducer,omitempty"`
+
+	// Select the top or bottom values of each group instead of reducing them
+	Select *AggregateSelect `json:"select,omitempty"`
+
+	// Reducer Options
+	Settings *ReduceSettings `json:"settings,omitempty"`
 }
 
 //-------------------------------
@@ -106,6 +199,64 @@ const (
 	ReduceModeReplace ReduceMode = "replaceNN"
 )
 
+type HoltWintersSettings struct {
+	// Smoothing factor of the level, between 0 and 1
+	Alpha *float64 `json:"alpha,omitempty"`
+
+	// Smoothing factor of the trend, between 0 and 1
+	Beta *float64 `json:"beta,omitempty"`
+
+	// Smoothing factor of the season, between 0 and 1
+	Gamma *float64 `json:"gamma,omitempty"`
+}
+
+// Anomaly detection output
+// +enum
+type AnomalyOutput string
+
+const (
+	// Default output, a series that is 1 where the input is anomalous and 0 otherwise
+	AnomalyOutputFlags AnomalyOutput = "flags"
+
+	// The lower and upper bands of the expected values
+	AnomalyOutputBands AnomalyOutput = "bands"
+
+	// The bands and the flags
+	AnomalyOutputAll AnomalyOutput = "all"
+)
+
+type AggregateSelect struct {
+	// Whether to select the largest or the smallest values
+	Mode AggregateSelectMode `json:"mode"`
+
+	// The number of values to select from each group
+	K int `json:"k" jsonschema:"minimum=1"`
+}
+
+// Aggregate select mode
+// +enum
+type AggregateSelectMode string
+
+const (
+	// Select the numbers with the largest values
+	AggregateSelectTopK AggregateSelectMode = "topk"
+
+	// Select the numbers with the smallest values
+	AggregateSelectBottomK AggregateSelectMode = "bottomk"
+)
+
+// Forecast output
+// +enum
+type ForecastOutput string
+
+const (
+	// Default output, the predicted value at the horizon
+	ForecastOutputValue ForecastOutput = "value"
+
+	// The series projected from the last point until the horizon
+	ForecastOutputSeries ForecastOutput = "series"
+)
+
 //go:embed query.types.json
 var f embed.FS
 
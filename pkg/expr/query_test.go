@@ -27,6 +27,12 @@ func TestQueryTypeDefinitions(t *testing.T) {
 				reflect.TypeOf(ReduceModeDrop),       // pick an example value (not the root)
 				reflect.TypeOf(ThresholdIsAbove),
 				reflect.TypeOf(classic.ConditionOperatorAnd),
+				reflect.TypeOf(mathexp.AnomalyZScore),
+				reflect.TypeOf(AnomalyOutputFlags),
+				reflect.TypeOf(mathexp.ForecastLinear),
+				reflect.TypeOf(ForecastOutputValue),
+				reflect.TypeOf(AggregateSelectTopK),
+				reflect.TypeOf(SQLFormatTable),
 			},
 		})
 	require.NoError(t, err)
@@ -90,6 +96,13 @@ func TestQueryTypeDefinitions(t *testing.T) {
 						Expression: "SELECT * FROM A limit 1",
 					}),
 				},
+				{
+					Name: "Join the series of A with the owners in B, as long time series",
+					SaveModel: data.AsUnstructured(SQLExpression{
+						Expression: "SELECT A.time, A.value, B.team FROM A JOIN B ON A.host = B.host WHERE $__timeFilter(A.time) ORDER BY A.time",
+						Format:     SQLFormatLong,
+					}),
+				},
 			},
 		},
 		schemabuilder.QueryTypeInfo{
@@ -159,6 +172,91 @@ func TestQueryTypeDefinitions(t *testing.T) {
 						]
 					  }`),
 				},
+				{
+					Name: "Critical when A >= 95, warning when A >= 80",
+					SaveModel: data.AsUnstructured(ThresholdQuery{
+						Expression: "A",
+						Conditions: []ThresholdConditionJSON{},
+						Levels: []ThresholdLevelJSON{
+							{
+								Name: "critical",
+								Evaluator: ConditionEvalJSON{
+									Type:   ThresholdIsAboveOrEqual,
+									Params: []float64{95},
+								},
+							},
+							{
+								Name: "warning",
+								Evaluator: ConditionEvalJSON{
+									Type:   ThresholdIsAboveOrEqual,
+									Params: []float64{80},
+								},
+							},
+						},
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAnomaly),
+			GoType:         reflect.TypeOf(&AnomalyQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "flag values of A more than 3 standard deviations from the mean",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Algorithm:  mathexp.AnomalyZScore,
+					}),
+				},
+				{
+					Name: "bands around a daily seasonal forecast of A",
+					SaveModel: data.AsUnstructured(AnomalyQuery{
+						Expression: "$A",
+						Algorithm:  mathexp.AnomalyHoltWinters,
+						Season:     "1d",
+						Output:     AnomalyOutputAll,
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeForecast),
+			GoType:         reflect.TypeOf(&ForecastQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "predict the value of A in 4 hours from the last hour",
+					SaveModel: data.AsUnstructured(ForecastQuery{
+						Expression: "$A",
+						Method:     mathexp.ForecastLinear,
+						Horizon:    "4h",
+						Window:     "1h",
+					}),
+				},
+			},
+		},
+		schemabuilder.QueryTypeInfo{
+			Discriminators: data.NewDiscriminators("type", QueryTypeAggregate),
+			GoType:         reflect.TypeOf(&AggregateQuery{}),
+			Examples: []data.QueryExample{
+				{
+					Name: "sum the series of A by cluster",
+					SaveModel: data.AsUnstructured(AggregateQuery{
+						Expression: "$A",
+						By:         []string{"cluster"},
+						Reducer:    mathexp.ReducerSum,
+					}),
+				},
+				{
+					Name: "the three largest numbers of A in each cluster",
+					SaveModel: data.AsUnstructured(AggregateQuery{
+						Expression: "$A",
+						By:         []string{"cluster"},
+						Select: &AggregateSelect{
+							Mode: AggregateSelectTopK,
+							K:    3,
+						},
+					}),
+				},
 			},
 		},
 	)
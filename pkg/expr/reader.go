@@ -134,7 +134,31 @@ func (h *ExpressionQueryReader) ReadQuery(
 		err = iter.ReadVal(q)
 		if err == nil {
 			eq.Properties = q
-			eq.Command, err = NewSQLCommand(common.RefID, q.Expression)
+			eq.Command, err = NewSQLCommand(common.RefID, q.Expression, q.Format, nil)
+		}
+
+	case QueryTypeAnomaly:
+		q := &AnomalyQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = newAnomalyCommandFromQuery(common.RefID, q)
+		}
+
+	case QueryTypeForecast:
+		q := &ForecastQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = newForecastCommandFromQuery(common.RefID, q)
+		}
+
+	case QueryTypeAggregate:
+		q := &AggregateQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = newAggregateCommandFromQuery(common.RefID, q)
 		}
 
 	case QueryTypeThreshold:
@@ -143,7 +167,16 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
 		}
-		if err == nil {
+		if err == nil && len(q.Levels) > 0 {
+			if len(q.Conditions) > 0 {
+				return eq, fmt.Errorf("threshold expression can have either conditions or levels, not both")
+			}
+			if err := checkLevelsHaveNoUnloadEvaluator(q.Levels); err != nil {
+				return eq, err
+			}
+			eq.Command, err = NewMultiLevelThresholdCommandFromJSON(common.RefID, referenceVar, q.Levels)
+			eq.Properties = q
+		} else if err == nil {
 			// we only support one condition for now, we might want to turn this in to "OR" expressions later
 			if len(q.Conditions) != 1 {
 				return eq, fmt.Errorf("threshold expression requires exactly one condition")
@@ -118,6 +118,14 @@ func TestService(t *testing.T) {
 	if diff := cmp.Diff(expect, res, options...); diff != "" {
 		t.Errorf("Result mismatch (-want +got):\n%s", diff)
 	}
+
+	t.Run("should record the execution time of data source queries and expressions", func(t *testing.T) {
+		stats := &ExecutionStats{}
+		_, err := s.ExecutePipeline(WithExecutionStats(context.Background(), stats), time.Now(), pl)
+		require.NoError(t, err)
+		require.Positive(t, stats.DatasourceDuration)
+		require.Positive(t, stats.ExpressionDuration)
+	})
 }
 
 func TestDSQueryError(t *testing.T) {
@@ -184,16 +192,106 @@ func TestDSQueryError(t *testing.T) {
 	require.Equal(t, fp(42), resp.Responses["C"].Frames[0].Fields[0].At(0))
 }
 
+func TestServiceTimeShift(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	now := time.Unix(1000000, 0)
+
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{data.NewFrame("",
+				data.NewField("time", nil, []time.Time{now}),
+				data.NewField("value", nil, []*float64{fp(6)}))}},
+			"B": {Frames: data.Frames{data.NewFrame("",
+				data.NewField("time", nil, []time.Time{now.Add(-week)}),
+				data.NewField("value", nil, []*float64{fp(3)}))}},
+		},
+	}
+
+	pCtxProvider := plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test"}},
+		},
+	}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, pluginconfig.NewFakePluginRequestConfigProvider())
+
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:          setting.NewCfg(),
+		dataService:  me,
+		pCtxProvider: pCtxProvider,
+		features:     features,
+		tracer:       tracing.InitializeTracerForTest(),
+		metrics:      newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+
+	ds := &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"}
+	queries := []Query{
+		{
+			RefID:      "A",
+			DataSource: ds,
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" } }`),
+			TimeRange:  RelativeTimeRange{From: -time.Hour},
+		},
+		{
+			RefID:      "B",
+			DataSource: ds,
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" } }`),
+			TimeRange:  RelativeTimeRange{From: -time.Hour},
+			TimeShift:  week,
+		},
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A / $B" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}})
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), now, pl)
+	require.NoError(t, err)
+
+	require.Len(t, me.Queries, 2)
+	for _, q := range me.Queries {
+		shift := time.Duration(0)
+		if q.RefID == "B" {
+			shift = week
+		}
+		require.Equal(t, now.Add(-time.Hour-shift), q.TimeRange.From)
+		require.Equal(t, now.Add(-shift), q.TimeRange.To)
+	}
+
+	require.NoError(t, res.Responses["C"].Error)
+	c := res.Responses["C"].Frames[0]
+	require.Equal(t, now, c.Fields[0].At(0))
+	require.Equal(t, fp(2), c.Fields[1].At(0))
+	// the frames of the data source response are copied, not shifted in place
+	require.Equal(t, now.Add(-week), me.Responses["B"].Frames[0].Fields[0].At(0))
+
+	t.Run("expressions can not be shifted", func(t *testing.T) {
+		queries[2].TimeShift = week
+		_, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}})
+		require.ErrorContains(t, err, "time shift can only be set on data source queries")
+	})
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
 
 type mockEndpoint struct {
 	Responses map[string]backend.DataResponse
+	// Queries are all the queries the endpoint received.
+	Queries []backend.DataQuery
 }
 
 func (me *mockEndpoint) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	resp := backend.NewQueryDataResponse()
+	me.Queries = append(me.Queries, req.Queries...)
 	for _, ref := range req.Queries {
 		resp.Responses[ref.RefID] = me.Responses[ref.RefID]
 	}
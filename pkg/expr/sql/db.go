@@ -2,21 +2,82 @@ package sql
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
+// ColumnTypeError is returned when a field of a frame can not be converted to a column of a table.
+type ColumnTypeError struct {
+	Table  string
+	Column string
+	Type   data.FieldType
+}
+
+func (e *ColumnTypeError) Error() string {
+	return fmt.Sprintf("can not convert column '%s' of table '%s': type %s is not supported", e.Column, e.Table, e.Type.ItemTypeString())
+}
+
+// ErrEngineNotAvailable is returned when a SQL statement is run, as no SQL engine is part of this build.
+var ErrEngineNotAvailable = errors.New("not implemented: no SQL engine is available to run SQL expressions")
+
+// DB is the database SQL expressions are run against. It has no engine in this build: statements are not run and
+// return ErrEngineNotAvailable.
 type DB struct {
 }
 
 func (db *DB) RunCommands(commands []string) (string, error) {
-	return "", errors.New("not implemented")
+	return "", ErrEngineNotAvailable
 }
 
-func (db *DB) QueryFramesInto(name string, query string, frames []*data.Frame, f *data.Frame) error {
-	return errors.New("not implemented")
+// QueryFramesInto checks that the frames can be converted to tables, and returns a ColumnTypeError for the first
+// field that cannot. The query itself is not run, see ErrEngineNotAvailable, so f is left empty and args, the values
+// of the parameter placeholders of the query, are not used.
+func (db *DB) QueryFramesInto(name string, query string, frames []*data.Frame, f *data.Frame, args ...any) error {
+	for _, frame := range frames {
+		if err := validateFrame(frame); err != nil {
+			return err
+		}
+	}
+	return ErrEngineNotAvailable
 }
 
 func NewInMemoryDB() *DB {
 	return &DB{}
 }
+
+// validateFrame checks that all the fields of the frame can be converted to columns of a table.
+func validateFrame(frame *data.Frame) error {
+	for i, field := range frame.Fields {
+		if _, ok := ColumnType(field.Type()); ok {
+			continue
+		}
+		column := field.Name
+		if column == "" {
+			column = fmt.Sprintf("#%d", i)
+		}
+		return &ColumnTypeError{Table: frame.RefID, Column: column, Type: field.Type()}
+	}
+	return nil
+}
+
+// ColumnType returns the SQL type of the column that holds the values of a field of the given type.
+func ColumnType(t data.FieldType) (string, bool) {
+	switch t.NonNullableType() {
+	case data.FieldTypeInt8, data.FieldTypeInt16, data.FieldTypeInt32, data.FieldTypeInt64,
+		data.FieldTypeUint8, data.FieldTypeUint16, data.FieldTypeUint32:
+		return "BIGINT", true
+	case data.FieldTypeUint64:
+		return "UBIGINT", true
+	case data.FieldTypeFloat32, data.FieldTypeFloat64:
+		return "DOUBLE", true
+	case data.FieldTypeString:
+		return "VARCHAR", true
+	case data.FieldTypeBool:
+		return "BOOLEAN", true
+	case data.FieldTypeTime:
+		return "TIMESTAMP", true
+	default:
+		return "", false
+	}
+}
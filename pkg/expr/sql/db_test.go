@@ -0,0 +1,36 @@
+package sql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFramesIntoUnsupportedColumn(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("host", nil, []string{"a"}),
+		data.NewField("meta", nil, []json.RawMessage{json.RawMessage(`{}`)}),
+	)
+	frame.RefID = "B"
+
+	err := NewInMemoryDB().QueryFramesInto("C", "SELECT * FROM B", []*data.Frame{frame}, &data.Frame{})
+
+	var columnErr *ColumnTypeError
+	require.ErrorAs(t, err, &columnErr)
+	require.Equal(t, "B", columnErr.Table)
+	require.Equal(t, "meta", columnErr.Column)
+	require.Equal(t, data.FieldTypeJSON, columnErr.Type)
+	require.EqualError(t, err, "can not convert column 'meta' of table 'B': type json.RawMessage is not supported")
+}
+
+func TestQueryFramesIntoWithoutEngine(t *testing.T) {
+	frame := data.NewFrame("", data.NewField("host", nil, []string{"a"}))
+	frame.RefID = "B"
+
+	result := &data.Frame{}
+	err := NewInMemoryDB().QueryFramesInto("C", "SELECT * FROM B", []*data.Frame{frame}, result)
+	require.ErrorIs(t, err, ErrEngineNotAvailable)
+	require.Equal(t, 0, result.Rows())
+}
@@ -0,0 +1,49 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// macroRegexp matches the time range macros, with or without parentheses. String literals, quoted identifiers and
+// comments are matched as a whole so that the macros they contain are left as they are.
+var macroRegexp = regexp.MustCompile(`(?s)'(?:[^']|'')*'|"(?:[^"]|"")*"|--[^\n]*|/\*.*?\*/|\$__(timeFrom|timeTo|timeFilter)\b(?:\(([^)]*)\))?`)
+
+// ExpandMacros replaces the time range macros of the query with parameter placeholders and returns
+// the values to bind to them, in order of appearance. Binding the values instead of formatting them
+// into the query keeps their type and leaves no room for injection. Supported macros are:
+//   - $__timeFrom() the start of the time range
+//   - $__timeTo() the end of the time range
+//   - $__timeFilter(column) a condition that column is within the time range
+func ExpandMacros(rawSQL string, from, to time.Time) (string, []any, error) {
+	var args []any
+	var err error
+	expanded := macroRegexp.ReplaceAllStringFunc(rawSQL, func(macro string) string {
+		match := macroRegexp.FindStringSubmatch(macro)
+		name, arg := match[1], strings.TrimSpace(match[2])
+		switch name {
+		case "":
+			// a string literal, quoted identifier or comment
+			return macro
+		case "timeFrom":
+			args = append(args, from.UTC())
+			return "?"
+		case "timeTo":
+			args = append(args, to.UTC())
+			return "?"
+		default:
+			if arg == "" {
+				err = fmt.Errorf("macro $__%s requires the name of a time column", name)
+				return macro
+			}
+			args = append(args, from.UTC(), to.UTC())
+			return fmt.Sprintf("%s BETWEEN ? AND ?", arg)
+		}
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return expanded, args, nil
+}
@@ -0,0 +1,63 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandMacros(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	testCases := []struct {
+		name        string
+		sql         string
+		expected    string
+		args        []any
+		expectedErr string
+	}{
+		{
+			name:     "no macros",
+			sql:      "SELECT * FROM A",
+			expected: "SELECT * FROM A",
+		},
+		{
+			name:     "time range",
+			sql:      "SELECT * FROM A WHERE time > $__timeFrom() AND time < $__timeTo",
+			expected: "SELECT * FROM A WHERE time > ? AND time < ?",
+			args:     []any{from, to},
+		},
+		{
+			name:     "time filter",
+			sql:      "SELECT * FROM A WHERE $__timeFilter(A.time) AND value > 0",
+			expected: "SELECT * FROM A WHERE A.time BETWEEN ? AND ? AND value > 0",
+			args:     []any{from, to},
+		},
+		{
+			name:     "macros in string literals, quoted identifiers and comments",
+			sql:      "SELECT '$__timeFrom()', 'it''s $__timeTo' AS \"$__timeFilter(x)\" FROM A -- $__timeTo\nWHERE /* $__timeFrom */ time > $__timeFrom()",
+			expected: "SELECT '$__timeFrom()', 'it''s $__timeTo' AS \"$__timeFilter(x)\" FROM A -- $__timeTo\nWHERE /* $__timeFrom */ time > ?",
+			args:     []any{from},
+		},
+		{
+			name:        "time filter without a column",
+			sql:         "SELECT * FROM A WHERE $__timeFilter()",
+			expectedErr: "requires the name of a time column",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expanded, args, err := ExpandMacros(tc.sql, from, to)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, expanded)
+			require.Equal(t, tc.args, args)
+		})
+	}
+}
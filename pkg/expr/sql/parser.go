@@ -3,6 +3,7 @@ package sql
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -18,6 +19,9 @@ const (
 
 var logger = log.New("sql_expr")
 
+// cteNameRegexp matches the keys of the flattened ast that hold the names of common table expressions.
+var cteNameRegexp = regexp.MustCompile(`cte_map\.map\.\d+\.key$`)
+
 // TablesList returns a list of tables for the sql statement
 func TablesList(rawSQL string) ([]string, error) {
 	db := NewInMemoryDB()
@@ -39,7 +43,8 @@ func TablesList(rawSQL string) ([]string, error) {
 	return tablesFromAST(ast)
 }
 
-// tablesFromAST returns a list of tables from the ast
+// tablesFromAST returns a list of tables from the ast. Common table expressions
+// are named intermediate tables of the query itself, so they are not in the list.
 func tablesFromAST(ast []map[string]any) ([]string, error) {
 	flat, err := flatten.Flatten(ast[0], "", flatten.DotStyle)
 	if err != nil {
@@ -47,6 +52,13 @@ func tablesFromAST(ast []map[string]any) ([]string, error) {
 		return nil, fmt.Errorf("error flattening ast: %s", err.Error())
 	}
 
+	ctes := []string{}
+	for k, v := range flat {
+		if name, ok := v.(string); ok && cteNameRegexp.MatchString(k) {
+			ctes = append(ctes, name)
+		}
+	}
+
 	tables := []string{}
 	for k, v := range flat {
 		if strings.HasSuffix(k, ERROR) {
@@ -58,7 +70,7 @@ func tablesFromAST(ast []map[string]any) ([]string, error) {
 		}
 		if strings.Contains(k, TABLE_NAME) {
 			table, ok := v.(string)
-			if ok && !existsInList(table, tables) {
+			if ok && !existsInList(table, tables) && !existsInList(table, ctes) {
 				tables = append(tables, v.(string))
 			}
 		}
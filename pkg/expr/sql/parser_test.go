@@ -191,7 +191,7 @@ func TestWith(t *testing.T) {
 	tables, err := TablesList((sql))
 	assert.Nil(t, err)
 
-	assert.Equal(t, 5, len(tables))
+	assert.Equal(t, 3, len(tables))
 	assert.Equal(t, "A", tables[0])
 	assert.Equal(t, "B", tables[1])
 	assert.Equal(t, "BEE", tables[2])
@@ -214,3 +214,45 @@ func TestWithQuote2(t *testing.T) {
 
 	assert.Equal(t, 0, len(tables))
 }
+
+func TestTablesFromASTWithCTE(t *testing.T) {
+	// the relevant parts of json_serialize_sql of
+	// WITH joined AS (SELECT * FROM A JOIN B ON A.host = B.host) SELECT * FROM joined
+	ast := []map[string]any{{
+		"error": false,
+		"statements": []any{map[string]any{
+			"node": map[string]any{
+				"cte_map": map[string]any{
+					"map": []any{map[string]any{
+						"key": "joined",
+						"value": map[string]any{
+							"query": map[string]any{
+								"node": map[string]any{
+									"from_table": map[string]any{
+										"type": "JOIN",
+										"left": map[string]any{
+											"type":       "BASE_TABLE",
+											"table_name": "A",
+										},
+										"right": map[string]any{
+											"type":       "BASE_TABLE",
+											"table_name": "B",
+										},
+									},
+								},
+							},
+						},
+					}},
+				},
+				"from_table": map[string]any{
+					"type":       "BASE_TABLE",
+					"table_name": "joined",
+				},
+			},
+		}},
+	}}
+	tables, err := tablesFromAST(ast)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"A", "B"}, tables)
+}
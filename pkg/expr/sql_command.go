@@ -18,16 +18,33 @@ import (
 type SQLCommand struct {
 	query       string
 	varsToQuery []string
+	format      SQLFormat
+	timeRange   TimeRange
 	refID       string
 }
 
-// NewSQLCommand creates a new SQLCommand.
-func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
+// NewSQLCommand creates a new SQLCommand. The time range is used to bind the values of
+// the time range macros of the query and may be nil if the query does not use them.
+func NewSQLCommand(refID, rawSQL string, format SQLFormat, timeRange TimeRange) (*SQLCommand, error) {
 	if rawSQL == "" {
 		return nil, errutil.BadRequest("sql-missing-query",
 			errutil.WithPublicMessage("missing SQL query"))
 	}
-	tables, err := sql.TablesList(rawSQL)
+	switch format {
+	case "", SQLFormatTable, SQLFormatLong, SQLFormatWide, SQLFormatNumeric:
+	default:
+		return nil, errutil.BadRequest("sql-invalid-format",
+			errutil.WithPublicMessage("unsupported SQL format"),
+		).Errorf("SQL format '%s' is not supported. Supported only: [%s,%s,%s,%s]", format, SQLFormatTable, SQLFormatLong, SQLFormatWide, SQLFormatNumeric)
+	}
+	// the macros are replaced with placeholders so the query can be parsed without a time range
+	parsedSQL, _, err := sql.ExpandMacros(rawSQL, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, errutil.BadRequest("sql-invalid-macro",
+			errutil.WithPublicMessage("error reading SQL macros"),
+		).Errorf("%w", err)
+	}
+	tables, err := sql.TablesList(parsedSQL)
 	if err != nil {
 		logger.Warn("invalid sql query", "sql", rawSQL, "error", err)
 		return nil, errutil.BadRequest("sql-invalid-sql",
@@ -43,6 +60,8 @@ func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
 	return &SQLCommand{
 		query:       rawSQL,
 		varsToQuery: tables,
+		format:      format,
+		timeRange:   timeRange,
 		refID:       refID,
 	}, nil
 }
@@ -65,7 +84,16 @@ func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
 		return nil, fmt.Errorf("expected sql expression to be type string, but got type %T", expressionRaw)
 	}
 
-	return NewSQLCommand(rn.RefID, expression)
+	var format SQLFormat
+	if formatRaw, ok := rn.Query["format"]; ok {
+		formatStr, ok := formatRaw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected sql format to be type string, but got type %T", formatRaw)
+		}
+		format = SQLFormat(formatStr)
+	}
+
+	return NewSQLCommand(rn.RefID, expression, format, rn.TimeRange)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -93,17 +121,32 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 
 	rsp := mathexp.Results{}
 
+	var from, to time.Time
+	if gr.timeRange != nil {
+		tr := gr.timeRange.AbsoluteTime(now)
+		from, to = tr.From, tr.To
+	}
+	query, args, err := sql.ExpandMacros(gr.query, from, to)
+	if err != nil {
+		rsp.Error = err
+		return rsp, nil
+	}
+	if len(args) > 0 && gr.timeRange == nil {
+		rsp.Error = fmt.Errorf("time range macros can not be used in SQL expression %s without a time range", gr.refID)
+		return rsp, nil
+	}
+
 	db := sql.NewInMemoryDB()
 	var frame = &data.Frame{}
 
-	logger.Debug("Executing query", "query", gr.query, "frames", len(allFrames))
-	err := db.QueryFramesInto(gr.refID, gr.query, allFrames, frame)
+	logger.Debug("Executing query", "query", query, "frames", len(allFrames))
+	err = db.QueryFramesInto(gr.refID, query, allFrames, frame, args...)
 	if err != nil {
 		logger.Error("Failed to query frames", "error", err.Error())
 		rsp.Error = err
 		return rsp, nil
 	}
-	logger.Debug("Done Executing query", "query", gr.query, "rows", frame.Rows())
+	logger.Debug("Done Executing query", "query", query, "rows", frame.Rows())
 
 	frame.RefID = gr.refID
 
@@ -111,15 +154,92 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 		rsp.Values = mathexp.Values{
 			mathexp.NoData{Frame: frame},
 		}
+		return rsp, nil
 	}
 
-	rsp.Values = mathexp.Values{
-		mathexp.TableData{Frame: frame},
-	}
-
+	rsp.Values, rsp.Error = sqlFrameToValues(frame, gr.format)
 	return rsp, nil
 }
 
 func (gr *SQLCommand) Type() string {
 	return TypeSQL.String()
 }
+
+// sqlFrameToValues converts the result of a SQL query to values of the given shape.
+func sqlFrameToValues(frame *data.Frame, format SQLFormat) (mathexp.Values, error) {
+	switch format {
+	case SQLFormatLong, SQLFormatWide:
+		if err := checkSQLTimeSeriesColumns(frame, format); err != nil {
+			return nil, err
+		}
+		wide := frame
+		if format == SQLFormatLong {
+			var err error
+			if wide, err = data.LongToWide(frame, nil); err != nil {
+				return nil, fmt.Errorf("failed to convert the result of SQL expression %s to time series: %w", frame.RefID, err)
+			}
+		}
+		series, err := WideToMany(wide, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the result of SQL expression %s to time series: %w", frame.RefID, err)
+		}
+		vals := make(mathexp.Values, 0, len(series))
+		for _, s := range series {
+			vals = append(vals, s)
+		}
+		return vals, nil
+	case SQLFormatNumeric:
+		numericCount := 0
+		for _, field := range frame.Fields {
+			fType := field.Type()
+			switch {
+			case fType.Numeric():
+				numericCount++
+			case fType == data.FieldTypeString || fType == data.FieldTypeNullableString:
+			default:
+				return nil, sqlColumnError(frame, field, "only numeric and string columns can be converted to numbers")
+			}
+		}
+		if numericCount != 1 {
+			return nil, fmt.Errorf("the result of SQL expression %s must have exactly one numeric column to be converted to numbers, got %d", frame.RefID, numericCount)
+		}
+		numbers, err := extractNumberSet(frame)
+		if err != nil {
+			return nil, err
+		}
+		vals := make(mathexp.Values, 0, len(numbers))
+		for _, n := range numbers {
+			vals = append(vals, n)
+		}
+		return vals, nil
+	default:
+		return mathexp.Values{mathexp.TableData{Frame: frame}}, nil
+	}
+}
+
+// checkSQLTimeSeriesColumns checks that the result of a SQL query has a single time column, numeric value
+// columns and, in long format only, string label columns.
+func checkSQLTimeSeriesColumns(frame *data.Frame, format SQLFormat) error {
+	timeCount := 0
+	for _, field := range frame.Fields {
+		fType := field.Type()
+		switch {
+		case fType.Time():
+			timeCount++
+		case fType.Numeric():
+		case format == SQLFormatLong && (fType == data.FieldTypeString || fType == data.FieldTypeNullableString):
+		case format == SQLFormatLong:
+			return sqlColumnError(frame, field, "only time, numeric and string columns can be converted to long time series")
+		default:
+			return sqlColumnError(frame, field, "only time and numeric columns can be converted to wide time series")
+		}
+	}
+	if timeCount != 1 {
+		return fmt.Errorf("the result of SQL expression %s must have exactly one time column to be converted to time series, got %d", frame.RefID, timeCount)
+	}
+	return nil
+}
+
+func sqlColumnError(frame *data.Frame, field *data.Field, reason string) error {
+	return fmt.Errorf("column '%s' of the result of SQL expression %s has type %s: %s", field.Name, frame.RefID, field.Type().ItemTypeString(), reason)
+}
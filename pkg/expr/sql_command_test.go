@@ -3,11 +3,17 @@ package expr
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
 func TestNewCommand(t *testing.T) {
 	t.Skip()
-	cmd, err := NewSQLCommand("a", "select a from foo, bar")
+	cmd, err := NewSQLCommand("a", "select a from foo, bar", "", nil)
 	if err != nil && strings.Contains(err.Error(), "feature is not enabled") {
 		return
 	}
@@ -25,3 +31,78 @@ func TestNewCommand(t *testing.T) {
 		return
 	}
 }
+
+func TestNewCommandInvalidFormat(t *testing.T) {
+	_, err := NewSQLCommand("a", "select a from foo", "matrix", nil)
+	require.ErrorContains(t, err, "SQL format 'matrix' is not supported")
+}
+
+func TestSQLFrameToValues(t *testing.T) {
+	t0 := time.Unix(0, 0)
+	t1 := time.Unix(60, 0)
+
+	t.Run("table", func(t *testing.T) {
+		frame := data.NewFrame("", data.NewField("host", nil, []string{"a"}))
+		vals, err := sqlFrameToValues(frame, "")
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{mathexp.TableData{Frame: frame}}, vals)
+	})
+
+	t.Run("long", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t0, t0, t1, t1}),
+			data.NewField("team", nil, []string{"a", "b", "a", "b"}),
+			data.NewField("value", nil, []float64{1, 2, 3, 4}),
+		)
+		vals, err := sqlFrameToValues(frame, SQLFormatLong)
+		require.NoError(t, err)
+		require.Len(t, vals, 2)
+		require.Equal(t, data.Labels{"team": "a"}, vals[0].GetLabels())
+		require.Equal(t, []float64{1, 3}, seriesValues(vals[0].(mathexp.Series)))
+		require.Equal(t, data.Labels{"team": "b"}, vals[1].GetLabels())
+		require.Equal(t, []float64{2, 4}, seriesValues(vals[1].(mathexp.Series)))
+	})
+
+	t.Run("wide", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t0, t1}),
+			data.NewField("value", data.Labels{"team": "a"}, []float64{1, 3}),
+		)
+		vals, err := sqlFrameToValues(frame, SQLFormatWide)
+		require.NoError(t, err)
+		require.Len(t, vals, 1)
+		require.Equal(t, []float64{1, 3}, seriesValues(vals[0].(mathexp.Series)))
+	})
+
+	t.Run("wide with a string column", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t0}),
+			data.NewField("team", nil, []string{"a"}),
+		)
+		frame.RefID = "C"
+		_, err := sqlFrameToValues(frame, SQLFormatWide)
+		require.EqualError(t, err, "column 'team' of the result of SQL expression C has type string: only time and numeric columns can be converted to wide time series")
+	})
+
+	t.Run("numeric", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("team", nil, []string{"a", "b"}),
+			data.NewField("value", nil, []float64{1, 2}),
+		)
+		vals, err := sqlFrameToValues(frame, SQLFormatNumeric)
+		require.NoError(t, err)
+		require.Len(t, vals, 2)
+		require.Equal(t, data.Labels{"team": "b"}, vals[1].GetLabels())
+		require.Equal(t, 2.0, *vals[1].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("numeric with a time column", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{t0}),
+			data.NewField("value", nil, []float64{1}),
+		)
+		frame.RefID = "C"
+		_, err := sqlFrameToValues(frame, SQLFormatNumeric)
+		require.EqualError(t, err, "column 'time' of the result of SQL expression C has type time.Time: only numeric and string columns can be converted to numbers")
+	})
+}
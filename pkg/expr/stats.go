@@ -0,0 +1,37 @@
+package expr
+
+import (
+	"context"
+	"time"
+)
+
+// ExecutionStats collects how long the nodes of a pipeline take to execute. It is not safe for concurrent use.
+type ExecutionStats struct {
+	// DatasourceDuration is the time spent querying data sources, including machine learning queries.
+	DatasourceDuration time.Duration
+	// ExpressionDuration is the time spent executing expression commands.
+	ExpressionDuration time.Duration
+}
+
+type executionStatsKey struct{}
+
+// WithExecutionStats returns a context that makes the pipelines executed with it record their execution time in stats.
+func WithExecutionStats(ctx context.Context, stats *ExecutionStats) context.Context {
+	return context.WithValue(ctx, executionStatsKey{}, stats)
+}
+
+func executionStatsFromContext(ctx context.Context) *ExecutionStats {
+	stats, _ := ctx.Value(executionStatsKey{}).(*ExecutionStats)
+	return stats
+}
+
+func (s *ExecutionStats) observe(nodeType NodeType, d time.Duration) {
+	if s == nil {
+		return
+	}
+	if nodeType == TypeCMDNode {
+		s.ExpressionDuration += d
+		return
+	}
+	s.DatasourceDuration += d
+}
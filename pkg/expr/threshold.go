@@ -34,14 +34,25 @@ type ThresholdType string
 const (
 	ThresholdIsAbove        ThresholdType = "gt"
 	ThresholdIsBelow        ThresholdType = "lt"
+	ThresholdIsAboveOrEqual ThresholdType = "gte"
+	ThresholdIsBelowOrEqual ThresholdType = "lte"
+	ThresholdIsEqual        ThresholdType = "eq"
+	ThresholdIsNotEqual     ThresholdType = "ne"
 	ThresholdIsWithinRange  ThresholdType = "within_range"
 	ThresholdIsOutsideRange ThresholdType = "outside_range"
 )
 
+// ThresholdSeverityLabel is the label that holds the name of the level of the results of a multi-level threshold.
+const ThresholdSeverityLabel = "severity"
+
 var (
 	supportedThresholdFuncs = []string{
 		string(ThresholdIsAbove),
 		string(ThresholdIsBelow),
+		string(ThresholdIsAboveOrEqual),
+		string(ThresholdIsBelowOrEqual),
+		string(ThresholdIsEqual),
+		string(ThresholdIsNotEqual),
 		string(ThresholdIsWithinRange),
 		string(ThresholdIsOutsideRange),
 	}
@@ -70,6 +81,26 @@ func NewThresholdCommand(refID, referenceVar string, thresholdFunc ThresholdType
 			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
 		}
 		predicate = lessThanPredicate{value: conditions[0]}
+	case ThresholdIsAboveOrEqual:
+		if len(conditions) < 1 {
+			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		}
+		predicate = greaterThanOrEqualPredicate{value: conditions[0]}
+	case ThresholdIsBelowOrEqual:
+		if len(conditions) < 1 {
+			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		}
+		predicate = lessThanOrEqualPredicate{value: conditions[0]}
+	case ThresholdIsEqual:
+		if len(conditions) < 1 {
+			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		}
+		predicate = equalPredicate{value: conditions[0]}
+	case ThresholdIsNotEqual:
+		if len(conditions) < 1 {
+			return nil, fmt.Errorf("incorrect number of arguments for threshold function '%s': got %d but need 1", thresholdFunc, len(conditions))
+		}
+		predicate = notEqualPredicate{value: conditions[0]}
 	default:
 		return nil, fmt.Errorf("expected threshold function to be one of [%s], got %s", strings.Join(supportedThresholdFuncs, ", "), thresholdFunc)
 	}
@@ -98,6 +129,16 @@ func UnmarshalThresholdCommand(rn *rawNode, features featuremgmt.FeatureToggles)
 	}
 	referenceVar := cmdConfig.Expression
 
+	if len(cmdConfig.Levels) > 0 {
+		if len(cmdConfig.Conditions) > 0 {
+			return nil, fmt.Errorf("threshold expression can have either conditions or levels, not both")
+		}
+		if err := checkLevelsHaveNoUnloadEvaluator(cmdConfig.Levels); err != nil {
+			return nil, err
+		}
+		return NewMultiLevelThresholdCommandFromJSON(rn.RefID, referenceVar, cmdConfig.Levels)
+	}
+
 	// we only support one condition for now, we might want to turn this in to "OR" expressions later
 	if len(cmdConfig.Conditions) != 1 {
 		return nil, fmt.Errorf("threshold expression requires exactly one condition")
@@ -126,6 +167,17 @@ func UnmarshalThresholdCommand(rn *rawNode, features featuremgmt.FeatureToggles)
 	return threshold, nil
 }
 
+// checkLevelsHaveNoUnloadEvaluator returns an error if a level of the threshold query has an unload evaluator.
+// Multi-level thresholds do not support recovery thresholds, and the field would otherwise be silently dropped.
+func checkLevelsHaveNoUnloadEvaluator(levels []ThresholdLevelJSON) error {
+	for _, l := range levels {
+		if l.UnloadEvaluator != nil {
+			return fmt.Errorf("threshold level '%s' has an unload evaluator, but recovery thresholds are not supported with levels", l.Name)
+		}
+	}
+	return nil
+}
+
 // NeedsVars returns the variable names (refIds) that are dependencies
 // to execute the command and allows the command to fulfill the Command interface.
 func (tc *ThresholdCommand) NeedsVars() []string {
@@ -133,47 +185,56 @@ func (tc *ThresholdCommand) NeedsVars() []string {
 }
 
 func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer) (mathexp.Results, error) {
-	eval := func(maybeValue *float64) *float64 {
-		if maybeValue == nil {
-			return nil
-		}
-		result := tc.predicate.Eval(*maybeValue)
-		if tc.Invert {
-			result = !result
-		}
-		if result {
-			return util.Pointer(float64(1))
-		}
-		return util.Pointer(float64(0))
-	}
-
 	refVarResult := vars[tc.ReferenceVar]
 	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
 	for _, val := range refVarResult.Values {
-		switch v := val.(type) {
-		case mathexp.Series:
-			s := mathexp.NewSeries(tc.RefID, v.GetLabels(), v.Len())
-			for i := 0; i < v.Len(); i++ {
-				t, value := v.GetPoint(i)
-				s.SetPoint(i, t, eval(value))
-			}
-			newRes.Values = append(newRes.Values, s)
-		case mathexp.Number:
-			copyV := mathexp.NewNumber(tc.RefID, v.GetLabels())
-			copyV.SetValue(eval(v.GetFloat64Value()))
-			newRes.Values = append(newRes.Values, copyV)
-		case mathexp.Scalar:
-			copyV := mathexp.NewScalar(tc.RefID, eval(v.GetFloat64Value()))
-			newRes.Values = append(newRes.Values, copyV)
-		case mathexp.NoData:
-			newRes.Values = append(newRes.Values, mathexp.NewNoData())
-		default:
-			return newRes, fmt.Errorf("unsupported format of the input data, got type %v", val.Type())
+		v, err := mapThresholdValue(tc.RefID, val, val.GetLabels(), tc.eval)
+		if err != nil {
+			return newRes, err
 		}
+		newRes.Values = append(newRes.Values, v)
 	}
 	return newRes, nil
 }
 
+// eval returns 1 if the value meets the condition, 0 if it does not and nil if there is no value.
+func (tc *ThresholdCommand) eval(maybeValue *float64) *float64 {
+	if maybeValue == nil {
+		return nil
+	}
+	result := tc.predicate.Eval(*maybeValue)
+	if tc.Invert {
+		result = !result
+	}
+	if result {
+		return util.Pointer(float64(1))
+	}
+	return util.Pointer(float64(0))
+}
+
+// mapThresholdValue returns a copy of the value with the given labels, where every value is mapped with fn.
+func mapThresholdValue(refID string, val mathexp.Value, labels data.Labels, fn func(*float64) *float64) (mathexp.Value, error) {
+	switch v := val.(type) {
+	case mathexp.Series:
+		s := mathexp.NewSeries(refID, labels, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			t, value := v.GetPoint(i)
+			s.SetPoint(i, t, fn(value))
+		}
+		return s, nil
+	case mathexp.Number:
+		copyV := mathexp.NewNumber(refID, labels)
+		copyV.SetValue(fn(v.GetFloat64Value()))
+		return copyV, nil
+	case mathexp.Scalar:
+		return mathexp.NewScalar(refID, fn(v.GetFloat64Value())), nil
+	case mathexp.NoData:
+		return mathexp.NewNoData(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format of the input data, got type %v", val.Type())
+	}
+}
+
 func (tc *ThresholdCommand) Type() string {
 	return TypeThreshold.String()
 }
@@ -192,6 +253,15 @@ func IsSupportedThresholdFunc(name string) bool {
 type ThresholdCommandConfig struct {
 	Expression string                   `json:"expression"`
 	Conditions []ThresholdConditionJSON `json:"conditions"`
+	Levels     []ThresholdLevelJSON     `json:"levels,omitempty"`
+}
+
+type ThresholdLevelJSON struct {
+	// The name of the level, which is the value of the severity label of its results
+	Name      string            `json:"name"`
+	Evaluator ConditionEvalJSON `json:"evaluator"`
+	// The unload evaluator is not supported with levels, it is only read to reject it
+	UnloadEvaluator *ConditionEvalJSON `json:"unloadEvaluator,omitempty" jsonschema:"-"`
 }
 
 type ThresholdConditionJSON struct {
@@ -294,3 +364,35 @@ type greaterThanPredicate struct {
 func (r greaterThanPredicate) Eval(f float64) bool {
 	return f > r.value
 }
+
+type lessThanOrEqualPredicate struct {
+	value float64
+}
+
+func (r lessThanOrEqualPredicate) Eval(f float64) bool {
+	return f <= r.value
+}
+
+type greaterThanOrEqualPredicate struct {
+	value float64
+}
+
+func (r greaterThanOrEqualPredicate) Eval(f float64) bool {
+	return f >= r.value
+}
+
+type equalPredicate struct {
+	value float64
+}
+
+func (r equalPredicate) Eval(f float64) bool {
+	return f == r.value
+}
+
+type notEqualPredicate struct {
+	value float64
+}
+
+func (r notEqualPredicate) Eval(f float64) bool {
+	return f != r.value
+}
@@ -0,0 +1,119 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ThresholdLevel is a named threshold of a MultiLevelThresholdCommand.
+type ThresholdLevel struct {
+	Name      string
+	Threshold ThresholdCommand
+}
+
+// MultiLevelThresholdCommand checks the input against several thresholds, for example "warning" above 80 and
+// "critical" above 95. It returns a result for every level of every input value, with the name of the level
+// in the severity label. Levels are checked in order and only the first level whose condition is met is 1,
+// so the levels must be ordered from the most to the least severe.
+type MultiLevelThresholdCommand struct {
+	ReferenceVar string
+	RefID        string
+	Levels       []ThresholdLevel
+}
+
+// NewMultiLevelThresholdCommand creates a new MultiLevelThresholdCommand.
+func NewMultiLevelThresholdCommand(refID, referenceVar string, levels []ThresholdLevel) (*MultiLevelThresholdCommand, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("multi-level threshold expression requires at least one level")
+	}
+	names := make(map[string]struct{}, len(levels))
+	for _, l := range levels {
+		if l.Name == "" {
+			return nil, fmt.Errorf("threshold levels must have a name")
+		}
+		if _, ok := names[l.Name]; ok {
+			return nil, fmt.Errorf("threshold level '%s' is defined more than once", l.Name)
+		}
+		names[l.Name] = struct{}{}
+	}
+	return &MultiLevelThresholdCommand{
+		ReferenceVar: referenceVar,
+		RefID:        refID,
+		Levels:       levels,
+	}, nil
+}
+
+// NewMultiLevelThresholdCommandFromJSON creates a new MultiLevelThresholdCommand from the levels of a threshold query.
+func NewMultiLevelThresholdCommandFromJSON(refID, referenceVar string, levelsJSON []ThresholdLevelJSON) (*MultiLevelThresholdCommand, error) {
+	levels := make([]ThresholdLevel, 0, len(levelsJSON))
+	for _, l := range levelsJSON {
+		threshold, err := NewThresholdCommand(refID, referenceVar, l.Evaluator.Type, l.Evaluator.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition of level '%s': %w", l.Name, err)
+		}
+		levels = append(levels, ThresholdLevel{Name: l.Name, Threshold: *threshold})
+	}
+	return NewMultiLevelThresholdCommand(refID, referenceVar, levels)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (tc *MultiLevelThresholdCommand) NeedsVars() []string {
+	return []string{tc.ReferenceVar}
+}
+
+func (tc *MultiLevelThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer) (mathexp.Results, error) {
+	refVarResult := vars[tc.ReferenceVar]
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values)*len(tc.Levels))}
+	for _, val := range refVarResult.Values {
+		if _, ok := val.(mathexp.NoData); ok {
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+			continue
+		}
+		if scalar, ok := val.(mathexp.Scalar); ok {
+			// scalars can not have labels, so they are told apart as numbers
+			n := mathexp.NewNumber("", nil)
+			n.SetValue(scalar.GetFloat64Value())
+			val = n
+		}
+		for i, level := range tc.Levels {
+			labels := data.Labels{}
+			if val.GetLabels() != nil {
+				labels = val.GetLabels().Copy()
+			}
+			labels[ThresholdSeverityLabel] = level.Name
+			v, err := mapThresholdValue(tc.RefID, val, labels, tc.levelEval(i))
+			if err != nil {
+				return newRes, err
+			}
+			newRes.Values = append(newRes.Values, v)
+		}
+	}
+	return newRes, nil
+}
+
+// levelEval returns a function that is 1 if the level with the given index is the first level whose condition is met.
+func (tc *MultiLevelThresholdCommand) levelEval(idx int) func(*float64) *float64 {
+	return func(maybeValue *float64) *float64 {
+		if maybeValue == nil {
+			return nil
+		}
+		for i := range tc.Levels[:idx] {
+			if *tc.Levels[i].Threshold.eval(maybeValue) == 1 {
+				return util.Pointer(float64(0))
+			}
+		}
+		return tc.Levels[idx].Threshold.eval(maybeValue)
+	}
+}
+
+func (tc *MultiLevelThresholdCommand) Type() string {
+	return TypeThreshold.String()
+}
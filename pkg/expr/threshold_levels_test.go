@@ -0,0 +1,118 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestNewMultiLevelThresholdCommand(t *testing.T) {
+	gt, err := NewThresholdCommand("B", "A", ThresholdIsAbove, []float64{1})
+	require.NoError(t, err)
+
+	_, err = NewMultiLevelThresholdCommand("B", "A", nil)
+	require.ErrorContains(t, err, "at least one level")
+
+	_, err = NewMultiLevelThresholdCommand("B", "A", []ThresholdLevel{{Threshold: *gt}})
+	require.ErrorContains(t, err, "must have a name")
+
+	_, err = NewMultiLevelThresholdCommand("B", "A", []ThresholdLevel{{Name: "warning", Threshold: *gt}, {Name: "warning", Threshold: *gt}})
+	require.ErrorContains(t, err, "defined more than once")
+
+	_, err = NewMultiLevelThresholdCommandFromJSON("B", "A", []ThresholdLevelJSON{{Name: "warning", Evaluator: ConditionEvalJSON{Type: "gte"}}})
+	require.ErrorContains(t, err, "invalid condition of level 'warning'")
+}
+
+func TestMultiLevelThresholdExecute(t *testing.T) {
+	cmd, err := NewMultiLevelThresholdCommandFromJSON("B", "A", []ThresholdLevelJSON{
+		{Name: "critical", Evaluator: ConditionEvalJSON{Type: ThresholdIsAboveOrEqual, Params: []float64{95}}},
+		{Name: "warning", Evaluator: ConditionEvalJSON{Type: ThresholdIsAboveOrEqual, Params: []float64{80}}},
+	})
+	require.NoError(t, err)
+
+	critical := data.Labels{"host": "a", ThresholdSeverityLabel: "critical"}
+	warning := data.Labels{"host": "a", ThresholdSeverityLabel: "warning"}
+
+	cases := []struct {
+		name     string
+		input    mathexp.Value
+		expected mathexp.Values
+	}{
+		{
+			name:  "number below all levels",
+			input: newNumber(data.Labels{"host": "a"}, util.Pointer(50.0)),
+			expected: mathexp.Values{
+				newNumberWithRefID("B", critical, util.Pointer(0.0)),
+				newNumberWithRefID("B", warning, util.Pointer(0.0)),
+			},
+		},
+		{
+			name:  "number above the least severe level",
+			input: newNumber(data.Labels{"host": "a"}, util.Pointer(80.0)),
+			expected: mathexp.Values{
+				newNumberWithRefID("B", critical, util.Pointer(0.0)),
+				newNumberWithRefID("B", warning, util.Pointer(1.0)),
+			},
+		},
+		{
+			name:  "only the most severe level fires",
+			input: newNumber(data.Labels{"host": "a"}, util.Pointer(99.0)),
+			expected: mathexp.Values{
+				newNumberWithRefID("B", critical, util.Pointer(1.0)),
+				newNumberWithRefID("B", warning, util.Pointer(0.0)),
+			},
+		},
+		{
+			name:  "number without value",
+			input: newNumber(data.Labels{"host": "a"}, nil),
+			expected: mathexp.Values{
+				newNumberWithRefID("B", critical, nil),
+				newNumberWithRefID("B", warning, nil),
+			},
+		},
+		{
+			name:  "scalar",
+			input: newScalar(util.Pointer(90.0)),
+			expected: mathexp.Values{
+				newNumberWithRefID("B", data.Labels{ThresholdSeverityLabel: "critical"}, util.Pointer(0.0)),
+				newNumberWithRefID("B", data.Labels{ThresholdSeverityLabel: "warning"}, util.Pointer(1.0)),
+			},
+		},
+		{
+			name:     "no data",
+			input:    mathexp.NewNoData(),
+			expected: mathexp.Values{mathexp.NewNoData()},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": newResults(tc.input)}, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res.Values)
+		})
+	}
+
+	t.Run("series", func(t *testing.T) {
+		input := newSeriesWithLabels(data.Labels{"host": "a"}, util.Pointer(50.0), util.Pointer(85.0), util.Pointer(97.0))
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": newResults(input)}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		require.Equal(t, critical, res.Values[0].GetLabels())
+		require.Equal(t, []float64{0, 0, 1}, seriesValues(res.Values[0].(mathexp.Series)))
+		require.Equal(t, warning, res.Values[1].GetLabels())
+		require.Equal(t, []float64{0, 1, 0}, seriesValues(res.Values[1].(mathexp.Series)))
+	})
+}
+
+func newNumberWithRefID(refID string, labels data.Labels, value *float64) mathexp.Number {
+	n := mathexp.NewNumber(refID, labels)
+	n.SetValue(value)
+	return n
+}
@@ -38,6 +38,26 @@ func TestNewThresholdCommand(t *testing.T) {
 			args:        []float64{0},
 			shouldError: false,
 		},
+		{
+			fn:          "gte",
+			args:        []float64{0},
+			shouldError: false,
+		},
+		{
+			fn:          "lte",
+			args:        []float64{0},
+			shouldError: false,
+		},
+		{
+			fn:          "eq",
+			args:        []float64{0},
+			shouldError: false,
+		},
+		{
+			fn:          "ne",
+			args:        []float64{0},
+			shouldError: false,
+		},
 		{
 			fn:          "within_range",
 			args:        []float64{0, 1},
@@ -60,6 +80,18 @@ func TestNewThresholdCommand(t *testing.T) {
 			shouldError:   true,
 			expectedError: "incorrect number of arguments",
 		},
+		{
+			fn:            "gte",
+			args:          []float64{},
+			shouldError:   true,
+			expectedError: "incorrect number of arguments",
+		},
+		{
+			fn:            "ne",
+			args:          []float64{},
+			shouldError:   true,
+			expectedError: "incorrect number of arguments",
+		},
 		{
 			fn:            "within_range",
 			args:          []float64{0},
@@ -143,6 +175,52 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 			shouldError:   true,
 			expectedError: "expected threshold function to be one of",
 		},
+		{
+			description: "unmarshal levels as multi-level threshold",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [],
+				"levels": [
+					{"name": "critical", "evaluator": {"type": "gte", "params": [95]}},
+					{"name": "warning", "evaluator": {"type": "gte", "params": [80]}}
+				]
+			}`,
+			assert: func(t *testing.T, command Command) {
+				require.IsType(t, &MultiLevelThresholdCommand{}, command)
+				cmd := command.(*MultiLevelThresholdCommand)
+				require.Equal(t, []string{"A"}, cmd.NeedsVars())
+				require.Len(t, cmd.Levels, 2)
+				require.Equal(t, "critical", cmd.Levels[0].Name)
+				require.Equal(t, greaterThanOrEqualPredicate{95.0}, cmd.Levels[0].Threshold.predicate)
+				require.Equal(t, "warning", cmd.Levels[1].Name)
+				require.Equal(t, greaterThanOrEqualPredicate{80.0}, cmd.Levels[1].Threshold.predicate)
+			},
+		},
+		{
+			description: "unmarshal with both conditions and levels should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{"evaluator": {"type": "gt", "params": [20]}}],
+				"levels": [{"name": "warning", "evaluator": {"type": "gt", "params": [80]}}]
+			}`,
+			shouldError:   true,
+			expectedError: "either conditions or levels",
+		},
+		{
+			description: "unmarshal levels with an unload evaluator should error",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"levels": [
+					{"name": "critical", "evaluator": {"type": "gte", "params": [95]}},
+					{"name": "warning", "evaluator": {"type": "gte", "params": [80]}, "unloadEvaluator": {"type": "lt", "params": [70]}}
+				]
+			}`,
+			shouldError:   true,
+			expectedError: "threshold level 'warning' has an unload evaluator",
+		},
 		{
 			description: "unmarshal with bad expression",
 			query: `{
@@ -257,6 +335,22 @@ func TestIsSupportedThresholdFunc(t *testing.T) {
 			function:  ThresholdIsOutsideRange,
 			supported: true,
 		},
+		{
+			function:  ThresholdIsAboveOrEqual,
+			supported: true,
+		},
+		{
+			function:  ThresholdIsBelowOrEqual,
+			supported: true,
+		},
+		{
+			function:  ThresholdIsEqual,
+			supported: true,
+		},
+		{
+			function:  ThresholdIsNotEqual,
+			supported: true,
+		},
 		{
 			function:  "foo",
 			supported: false,
@@ -557,3 +651,24 @@ func TestThresholdExecute(t *testing.T) {
 		})
 	}
 }
+
+func TestThresholdPredicates(t *testing.T) {
+	cases := []struct {
+		pred     predicate
+		value    float64
+		expected bool
+	}{
+		{pred: greaterThanOrEqualPredicate{10}, value: 9, expected: false},
+		{pred: greaterThanOrEqualPredicate{10}, value: 10, expected: true},
+		{pred: greaterThanOrEqualPredicate{10}, value: math.NaN(), expected: false},
+		{pred: lessThanOrEqualPredicate{10}, value: 10, expected: true},
+		{pred: lessThanOrEqualPredicate{10}, value: 11, expected: false},
+		{pred: equalPredicate{10}, value: 10, expected: true},
+		{pred: equalPredicate{10}, value: 10.5, expected: false},
+		{pred: notEqualPredicate{10}, value: 10, expected: false},
+		{pred: notEqualPredicate{10}, value: 0, expected: true},
+	}
+	for _, tc := range cases {
+		require.Equalf(t, tc.expected, tc.pred.Eval(tc.value), "%#v of %v", tc.pred, tc.value)
+	}
+}
@@ -31,6 +31,12 @@ type Query struct {
 	Interval      time.Duration
 	QueryType     string
 	MaxDataPoints int64
+	// TimeShift moves the time range of a data source query back, for example to compare
+	// with the same time last week. The times of the results are moved forward by the same
+	// amount, so they line up with the results of the queries that are not shifted.
+	// The time shift is only applied by the expression pipeline: /api/ds/query only reads it
+	// from requests with expressions, queries without expressions are sent to the data source as they are.
+	TimeShift time.Duration
 }
 
 // TimeRange is a time.Time based TimeRange.
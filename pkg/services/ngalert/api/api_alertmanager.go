@@ -304,6 +304,20 @@ func (srv AlertmanagerSrv) RoutePostTestTemplates(c *contextmodel.ReqContext, bo
 	return response.JSON(http.StatusOK, newTestTemplateResult(res))
 }
 
+func (srv AlertmanagerSrv) RoutePostTestTemplateSuite(c *contextmodel.ReqContext, body apimodels.TemplateSuite) response.Response {
+	am, errResp := srv.AlertmanagerFor(c.SignedInUser.GetOrgID())
+	if errResp != nil {
+		return errResp
+	}
+
+	res, err := notifier.RunTemplateSuite(c.Req.Context(), am, body)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to run the template suite", err)
+	}
+
+	return response.JSON(http.StatusOK, res)
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
@@ -555,6 +555,52 @@ func TestRoutePostTestTemplates(t *testing.T) {
 	})
 }
 
+func TestRoutePostTestTemplateSuite(t *testing.T) {
+	sut := createSut(t)
+	suite := apimodels.TemplateSuite{
+		Templates: []apimodels.TemplateSuiteTemplate{{
+			Name:     "slack",
+			Template: `{{ define "slack.title" }}{{ len .Alerts }} alerts{{ end }}`,
+		}},
+		Fixtures: []apimodels.TemplateSuiteFixture{{
+			Name:     "empty",
+			Expected: map[string]string{"slack.title": "0 alerts"},
+		}},
+	}
+
+	t.Run("assert 404 when no alertmanager found", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(10)
+
+		response := sut.RoutePostTestTemplateSuite(rc, suite)
+		require.Equal(tt, 404, response.Status())
+	})
+
+	t.Run("assert 409 when alertmanager not ready", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(3)
+
+		response := sut.RoutePostTestTemplateSuite(rc, suite)
+		require.Equal(tt, 409, response.Status())
+	})
+
+	t.Run("assert 400 when the suite has no fixtures", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+
+		response := sut.RoutePostTestTemplateSuite(rc, apimodels.TemplateSuite{})
+		require.Equal(tt, 400, response.Status())
+	})
+
+	t.Run("assert 200 for a valid alertmanager", func(tt *testing.T) {
+		rc := createRequestCtxInOrg(1)
+
+		response := sut.RoutePostTestTemplateSuite(rc, suite)
+		require.Equal(tt, 200, response.Status())
+
+		var res apimodels.TemplateSuiteResults
+		require.NoError(tt, json.Unmarshal(response.Body(), &res))
+		require.Zero(tt, res.Failed)
+	})
+}
+
 func createSut(t *testing.T) AlertmanagerSrv {
 	t.Helper()
 
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/snapshot"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/util"
@@ -26,6 +29,25 @@ type ConfigSrv struct {
 	store                store.AdminConfigurationStore
 	log                  log.Logger
 	featureManager       featuremgmt.FeatureToggles
+	snapshots            *snapshot.Service
+	ruleStore            ListAlertRulesStore
+	scheduler            StatusReader
+	// ruleSharding is set when the evaluation of alert rules is sharded across the instances of the HA cluster.
+	ruleSharding bool
+}
+
+const (
+	defaultExpensiveRulesLimit = 10
+	// maxAlertingStateArchiveSize limits the size of the decompressed archives that can be imported.
+	maxAlertingStateArchiveSize = 256 << 20
+)
+
+// expensiveRuleMeasures returns the measure of the last evaluation of a rule that each sort order ranks rules by.
+var expensiveRuleMeasures = map[apimodels.ExpensiveRulesSortBy]func(apimodels.ExpensiveRule) float64{
+	apimodels.ExpensiveRulesByDuration:   func(r apimodels.ExpensiveRule) float64 { return r.EvaluationTime },
+	apimodels.ExpensiveRulesByQuery:      func(r apimodels.ExpensiveRule) float64 { return r.QueryTime },
+	apimodels.ExpensiveRulesByExpression: func(r apimodels.ExpensiveRule) float64 { return r.ExpressionTime },
+	apimodels.ExpensiveRulesBySeries:     func(r apimodels.ExpensiveRule) float64 { return float64(r.SeriesCount) },
 }
 
 func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.Response {
@@ -120,6 +142,99 @@ func (srv ConfigSrv) RouteDeleteNGalertConfig(c *contextmodel.ReqContext) respon
 	return response.JSON(http.StatusOK, util.DynMap{"message": "admin configuration deleted"})
 }
 
+func (srv ConfigSrv) RouteGetAlertingStateExport(c *contextmodel.ReqContext) response.Response {
+	archive, err := srv.snapshots.Export(c.Req.Context())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to export alerting state")
+	}
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf, archive); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to write alerting state archive")
+	}
+	filename := fmt.Sprintf("alerting-state-%s.json.gz", archive.CreatedAt.Format("20060102-150405"))
+	return response.Respond(http.StatusOK, buf.Bytes()).
+		SetHeader("Content-Type", "application/gzip").
+		SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename=%s`, filename))
+}
+
+func (srv ConfigSrv) RoutePostAlertingStateImport(c *contextmodel.ReqContext) response.Response {
+	archive, err := snapshot.Read(c.Req.Body, maxAlertingStateArchiveSize)
+	if err != nil {
+		if errors.Is(err, snapshot.ErrArchiveTooLarge) {
+			return ErrResp(http.StatusRequestEntityTooLarge, err, "invalid alerting state archive")
+		}
+		return ErrResp(http.StatusBadRequest, err, "invalid alerting state archive")
+	}
+	result, err := srv.snapshots.Import(c.Req.Context(), archive)
+	if err != nil {
+		if errors.Is(err, snapshot.ErrImportWithHA) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to import alerting state")
+	}
+	return response.JSON(http.StatusOK, apimodels.AlertingStateImportResult{
+		Orgs:                  result.Orgs,
+		AlertInstances:        result.AlertInstances,
+		SkippedAlertInstances: result.SkippedAlertInstances,
+	})
+}
+
+func (srv ConfigSrv) RouteGetExpensiveRules(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt("limit")
+	if limit < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit must be greater than or equal to 0"), "")
+	}
+	if limit == 0 {
+		limit = defaultExpensiveRulesLimit
+	}
+	sortBy := apimodels.ExpensiveRulesSortBy(c.Query("sortBy"))
+	if sortBy == "" {
+		sortBy = apimodels.ExpensiveRulesByDuration
+	}
+	measure, ok := expensiveRuleMeasures[sortBy]
+	if !ok {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unsupported sortBy value %q", sortBy), "")
+	}
+
+	rules, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to list alert rules")
+	}
+
+	result := make([]apimodels.ExpensiveRule, 0, len(rules))
+	for _, rule := range rules {
+		status, ok := srv.scheduler.Status(rule.GetKey())
+		// Rules that are not scheduled by this instance or that were not evaluated yet have no cost.
+		if !ok || status.EvaluationTimestamp.IsZero() {
+			continue
+		}
+		result = append(result, apimodels.ExpensiveRule{
+			UID:                 rule.UID,
+			Title:               rule.Title,
+			FolderUID:           rule.NamespaceUID,
+			RuleGroup:           rule.RuleGroup,
+			Type:                rule.Type().String(),
+			Health:              status.Health,
+			LastError:           errorOrEmpty(status.LastError),
+			LastEvaluation:      status.EvaluationTimestamp,
+			EvaluationTime:      status.EvaluationDuration.Seconds(),
+			QueryTime:           status.Cost.QueryDuration.Seconds(),
+			ExpressionTime:      status.Cost.ExpressionDuration.Seconds(),
+			SeriesCount:         status.Cost.SeriesCount,
+			MaxSeries:           rule.MaxSeries,
+			SeriesLimitExceeded: status.SeriesLimitExceeded,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return measure(result[i]) > measure(result[j])
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return response.JSON(http.StatusOK, apimodels.ExpensiveRules{Rules: result, Partial: srv.ruleSharding})
+}
+
 // externalAlertmanagers returns the URL of any external alertmanager that is
 // configured as datasource. The URL does not contain any auth.
 func (srv ConfigSrv) externalAlertmanagers(ctx context.Context, orgID int64) ([]string, error) {
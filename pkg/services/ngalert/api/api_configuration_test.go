@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -12,7 +14,9 @@ import (
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
 )
 
@@ -133,3 +137,98 @@ func createAPIAdminSut(t *testing.T,
 		featureManager: features,
 	}
 }
+
+type fakeRuleStatuses map[ngmodels.AlertRuleKey]ngmodels.RuleStatus
+
+func (f fakeRuleStatuses) Status(key ngmodels.AlertRuleKey) (ngmodels.RuleStatus, bool) {
+	status, ok := f[key]
+	return status, ok
+}
+
+func TestRouteGetExpensiveRules(t *testing.T) {
+	orgID := int64(1)
+	gen := ngmodels.RuleGen
+	ruleStore := fakes.NewRuleStore(t)
+	slow := gen.With(gen.WithOrgID(orgID), gen.WithMaxSeries(100)).GenerateRef()
+	large := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+	notEvaluated := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+	otherOrg := gen.With(gen.WithOrgID(orgID + 1)).GenerateRef()
+	ruleStore.PutRule(context.Background(), slow, large, notEvaluated, otherOrg)
+
+	now := time.Now()
+	statuses := fakeRuleStatuses{
+		slow.GetKey(): {
+			Health:              "ok",
+			EvaluationTimestamp: now,
+			EvaluationDuration:  3 * time.Second,
+			Cost:                ngmodels.EvaluationCost{QueryDuration: 2 * time.Second, ExpressionDuration: time.Second, SeriesCount: 10},
+		},
+		large.GetKey(): {
+			Health:              "ok",
+			EvaluationTimestamp: now,
+			EvaluationDuration:  time.Second,
+			Cost:                ngmodels.EvaluationCost{QueryDuration: time.Second, SeriesCount: 1000},
+		},
+		notEvaluated.GetKey(): {Health: "ok"},
+		otherOrg.GetKey(): {
+			Health:              "ok",
+			EvaluationTimestamp: now,
+			EvaluationDuration:  time.Minute,
+		},
+	}
+	sut := ConfigSrv{ruleStore: ruleStore, scheduler: statuses}
+
+	get := func(t *testing.T, query string) (int, definitions.ExpensiveRules) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/ngalert/rules/expensive?"+query, nil)
+		require.NoError(t, err)
+		ctx := createRequestCtxInOrg(orgID)
+		ctx.Req = req
+		resp := sut.RouteGetExpensiveRules(ctx)
+		var result definitions.ExpensiveRules
+		if resp.Status() == http.StatusOK {
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		}
+		return resp.Status(), result
+	}
+	uids := func(result definitions.ExpensiveRules) []string {
+		var uids []string
+		for _, r := range result.Rules {
+			uids = append(uids, r.UID)
+		}
+		return uids
+	}
+
+	t.Run("should sort the evaluated rules of the organization by evaluation duration by default", func(t *testing.T) {
+		status, result := get(t, "")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{slow.UID, large.UID}, uids(result))
+		require.Equal(t, 3.0, result.Rules[0].EvaluationTime)
+		require.Equal(t, 2.0, result.Rules[0].QueryTime)
+		require.Equal(t, 1.0, result.Rules[0].ExpressionTime)
+		require.Equal(t, 10, result.Rules[0].SeriesCount)
+		require.EqualValues(t, 100, result.Rules[0].MaxSeries)
+		require.False(t, result.Partial)
+	})
+
+	t.Run("should mark the result as partial when the evaluation of rules is sharded", func(t *testing.T) {
+		sut.ruleSharding = true
+		t.Cleanup(func() { sut.ruleSharding = false })
+		status, result := get(t, "")
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, result.Partial)
+	})
+
+	t.Run("should sort by the requested measure and limit the number of rules", func(t *testing.T) {
+		status, result := get(t, "sortBy=series&limit=1")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{large.UID}, uids(result))
+	})
+
+	t.Run("should fail for invalid parameters", func(t *testing.T) {
+		status, _ := get(t, "sortBy=memory")
+		require.Equal(t, http.StatusBadRequest, status)
+		status, _ = get(t, "limit=-1")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -2,13 +2,18 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 )
 
 type NotificationSrv struct {
@@ -20,6 +25,8 @@ type NotificationSrv struct {
 type ReceiverService interface {
 	GetReceiver(ctx context.Context, q models.GetReceiverQuery, u identity.Requester) (*models.Receiver, error)
 	ListReceivers(ctx context.Context, q models.ListReceiversQuery, user identity.Requester) ([]*models.Receiver, error)
+	ListFailedNotifications(ctx context.Context, q models.ListQueuedNotificationsQuery, user identity.Requester) ([]*models.QueuedNotification, models.NotificationQueueHealth, error)
+	ReplayFailedNotifications(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd, user identity.Requester) (int64, error)
 }
 
 func (srv *NotificationSrv) RouteGetTimeInterval(c *contextmodel.ReqContext, name string) response.Response {
@@ -78,3 +85,90 @@ func (srv *NotificationSrv) RouteGetReceivers(c *contextmodel.ReqContext) respon
 
 	return response.JSON(http.StatusOK, gettables)
 }
+
+func (srv *NotificationSrv) RouteGetReceiverFailedNotifications(c *contextmodel.ReqContext, name string) response.Response {
+	q := models.ListQueuedNotificationsQuery{
+		OrgID:    c.SignedInUser.OrgID,
+		Receiver: name,
+		Status:   models.QueuedNotificationStatus(c.Query("status")),
+		Limit:    c.QueryInt("limit"),
+	}
+	switch q.Status {
+	case "", models.QueuedNotificationPending, models.QueuedNotificationDead:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be %s or %s", q.Status, models.QueuedNotificationPending, models.QueuedNotificationDead), "")
+	}
+	if q.Limit < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit must not be negative"), "")
+	}
+
+	notifications, health, err := srv.receiverService.ListFailedNotifications(c.Req.Context(), q, c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get failed notifications", err)
+	}
+
+	result := apimodels.GettableFailedNotifications{
+		Health: apimodels.NotificationQueueHealth{
+			Pending:       health.Pending,
+			Dead:          health.Dead,
+			OldestPending: timeOrNil(health.OldestPending),
+			LastAttempt:   timeOrNil(health.LastAttempt),
+		},
+		Notifications: make([]apimodels.FailedNotification, 0, len(notifications)),
+	}
+	for _, n := range notifications {
+		alerts, err := notifier.DecodeQueuedNotificationAlerts(n)
+		if err != nil {
+			srv.logger.Warn("Failed to decode alerts of queued notification", "id", n.ID, "error", err)
+		}
+		failed := apimodels.FailedNotification{
+			ID:               n.ID,
+			Integration:      n.IntegrationType,
+			IntegrationIndex: n.IntegrationIndex,
+			GroupKey:         n.GroupKey,
+			Status:           string(n.Status),
+			Attempts:         n.Attempts,
+			LastError:        n.LastError,
+			Created:          n.CreatedAt,
+			Updated:          n.UpdatedAt,
+			Alerts:           make([]apimodels.FailedNotificationAlert, 0, len(alerts)),
+		}
+		if n.Status == models.QueuedNotificationPending {
+			failed.NextAttempt = timeOrNil(n.NextAttemptAt)
+		}
+		for _, a := range alerts {
+			lbls := make(map[string]string, len(a.Labels))
+			for k, v := range a.Labels {
+				lbls[string(k)] = string(v)
+			}
+			failed.Alerts = append(failed.Alerts, apimodels.FailedNotificationAlert{
+				Labels:   lbls,
+				StartsAt: a.StartsAt,
+				EndsAt:   a.EndsAt,
+			})
+		}
+		result.Notifications = append(result.Notifications, failed)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *NotificationSrv) RoutePostReceiverReplayFailedNotifications(c *contextmodel.ReqContext, name string, body apimodels.ReplayFailedNotificationsBody) response.Response {
+	cmd := models.ReplayQueuedNotificationsCmd{
+		OrgID:    c.SignedInUser.OrgID,
+		Receiver: name,
+		IDs:      body.IDs,
+		Now:      time.Now(),
+	}
+	replayed, err := srv.receiverService.ReplayFailedNotifications(c.Req.Context(), cmd, c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to replay failed notifications", err)
+	}
+	return response.JSON(http.StatusOK, apimodels.ReplayedFailedNotifications{Replayed: replayed})
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -165,6 +165,83 @@ func TestRouteGetReceivers(t *testing.T) {
 	})
 }
 
+func TestRouteGetReceiverFailedNotifications(t *testing.T) {
+	fakeReceiverSvc := fakes.NewFakeReceiverService()
+
+	t.Run("builds query from request context and url param", func(t *testing.T) {
+		fakeReceiverSvc.ListFailedNotificationsFn = func(ctx context.Context, q models.ListQueuedNotificationsQuery, u identity.Requester) ([]*models.QueuedNotification, models.NotificationQueueHealth, error) {
+			return nil, models.NotificationQueueHealth{Pending: 1}, nil
+		}
+		handler := NewNotificationsApi(newNotificationSrv(fakeReceiverSvc))
+		rc := testReqCtx("GET")
+		rc.Context.Req.Form.Set("status", "dead")
+		rc.Context.Req.Form.Set("limit", "10")
+		resp := handler.handleRouteGetReceiverFailedNotifications(&rc, "receiver1")
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.JSONEq(t, `{"health":{"pending":1,"dead":0},"notifications":[]}`, string(resp.Body()))
+
+		call := fakeReceiverSvc.PopMethodCall()
+		require.Equal(t, "ListFailedNotifications", call.Method)
+		expectedQ := models.ListQueuedNotificationsQuery{
+			OrgID:    1,
+			Receiver: "receiver1",
+			Status:   models.QueuedNotificationDead,
+			Limit:    10,
+		}
+		require.Equal(t, expectedQ, call.Args[1])
+	})
+
+	t.Run("returns 400 if status is invalid", func(t *testing.T) {
+		handler := NewNotificationsApi(newNotificationSrv(fakeReceiverSvc))
+		rc := testReqCtx("GET")
+		rc.Context.Req.Form.Set("status", "unknown")
+		resp := handler.handleRouteGetReceiverFailedNotifications(&rc, "receiver1")
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should pass along not found response", func(t *testing.T) {
+		fakeReceiverSvc.ListFailedNotificationsFn = func(ctx context.Context, q models.ListQueuedNotificationsQuery, u identity.Requester) ([]*models.QueuedNotification, models.NotificationQueueHealth, error) {
+			return nil, models.NotificationQueueHealth{}, legacy_storage.ErrReceiverNotFound.Errorf("")
+		}
+		handler := NewNotificationsApi(newNotificationSrv(fakeReceiverSvc))
+		rc := testReqCtx("GET")
+		resp := handler.handleRouteGetReceiverFailedNotifications(&rc, "receiver1")
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
+
+func TestRoutePostReceiverReplayFailedNotifications(t *testing.T) {
+	fakeReceiverSvc := fakes.NewFakeReceiverService()
+
+	t.Run("replays notifications of the receiver", func(t *testing.T) {
+		fakeReceiverSvc.ReplayFailedNotificationsFn = func(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd, u identity.Requester) (int64, error) {
+			return int64(len(cmd.IDs)), nil
+		}
+		handler := NewNotificationsApi(newNotificationSrv(fakeReceiverSvc))
+		rc := testReqCtx("POST")
+		resp := handler.handleRoutePostReceiverReplayFailedNotifications(&rc, definitions.ReplayFailedNotificationsBody{IDs: []int64{1, 2}}, "receiver1")
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.JSONEq(t, `{"replayed":2}`, string(resp.Body()))
+
+		call := fakeReceiverSvc.PopMethodCall()
+		require.Equal(t, "ReplayFailedNotifications", call.Method)
+		cmd := call.Args[1].(models.ReplayQueuedNotificationsCmd)
+		require.Equal(t, int64(1), cmd.OrgID)
+		require.Equal(t, "receiver1", cmd.Receiver)
+		require.Equal(t, []int64{1, 2}, cmd.IDs)
+	})
+
+	t.Run("should pass along permission denied response", func(t *testing.T) {
+		fakeReceiverSvc.ReplayFailedNotificationsFn = func(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd, u identity.Requester) (int64, error) {
+			return 0, ac.ErrAuthorizationBase.Errorf("")
+		}
+		handler := NewNotificationsApi(newNotificationSrv(fakeReceiverSvc))
+		rc := testReqCtx("POST")
+		resp := handler.handleRoutePostReceiverReplayFailedNotifications(&rc, definitions.ReplayFailedNotificationsBody{}, "receiver1")
+		require.Equal(t, http.StatusForbidden, resp.Status())
+	})
+}
+
 func TestRouteGetReceiversResponses(t *testing.T) {
 	createTestEnv := func(t *testing.T, testConfig string) testEnvironment {
 		env := createTestEnv(t, testConfig)
@@ -372,6 +449,7 @@ func createNotificationSrvSutFromEnv(t *testing.T, env *testEnvironment) Notific
 		legacy_storage.NewAlertmanagerConfigStore(env.configs),
 		env.prov,
 		env.store,
+		env.store,
 		env.secrets,
 		env.xact,
 		env.log,
@@ -29,12 +29,19 @@ type StatusReader interface {
 	Status(key ngmodels.AlertRuleKey) (ngmodels.RuleStatus, bool)
 }
 
+// RemoteStateLoader loads the states of the alert rules that are evaluated by other instances of the HA cluster.
+type RemoteStateLoader interface {
+	LoadRemoteStates(ctx context.Context, orgID int64)
+}
+
 type PrometheusSrv struct {
 	log     log.Logger
 	manager state.AlertInstanceManager
-	status  StatusReader
-	store   RuleStore
-	authz   RuleAccessControlService
+	// remote loads the states of the rules evaluated by other instances into the manager, it is optional.
+	remote RemoteStateLoader
+	status StatusReader
+	store  RuleStore
+	authz  RuleAccessControlService
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -66,6 +73,9 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 	// As we are using req.Form directly, this triggers a call to ParseForm() if needed.
 	c.Query("")
 
+	if srv.remote != nil {
+		srv.remote.LoadRemoteStates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	}
 	resp := PrepareAlertStatuses(srv.manager, AlertStatusesOptions{
 		OrgID: c.SignedInUser.GetOrgID(),
 		Query: c.Req.Form,
@@ -228,6 +238,9 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		namespaces[namespaceUID] = folder.Fullpath
 	}
 
+	if srv.remote != nil {
+		srv.remote.LoadRemoteStates(c.Req.Context(), c.OrgID)
+	}
 	ruleResponse = PrepareRuleGroupStatuses(srv.log, srv.manager, srv.status, srv.store, RuleGroupStatusesOptions{
 		Ctx:        c.Req.Context(),
 		OrgID:      c.OrgID,
@@ -512,15 +525,30 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, sr StatusRe
 			Duration:    rule.For.Seconds(),
 			Annotations: apimodels.LabelsFromMap(rule.Annotations),
 		}
+		for _, dependency := range state.CheckRuleDependencies(manager, rule, nil) {
+			status := apimodels.RuleDependencyStatus{
+				RuleUID: dependency.RuleUID,
+				State:   string(dependency.State),
+				Met:     dependency.Met,
+			}
+			if dependency.ParentState != nil {
+				status.ParentState = dependency.ParentState.String()
+			}
+			alertingRule.Dependencies = append(alertingRule.Dependencies, status)
+		}
 
 		newRule := apimodels.Rule{
-			Name:           rule.Title,
-			Labels:         apimodels.LabelsFromMap(rule.GetLabels(labelOptions...)),
-			Health:         status.Health,
-			LastError:      errorOrEmpty(status.LastError),
-			Type:           rule.Type().String(),
-			LastEvaluation: status.EvaluationTimestamp,
-			EvaluationTime: status.EvaluationDuration.Seconds(),
+			Name:                rule.Title,
+			Labels:              apimodels.LabelsFromMap(rule.GetLabels(labelOptions...)),
+			Health:              status.Health,
+			LastError:           errorOrEmpty(status.LastError),
+			Type:                rule.Type().String(),
+			LastEvaluation:      status.EvaluationTimestamp,
+			EvaluationTime:      status.EvaluationDuration.Seconds(),
+			QueryTime:           status.Cost.QueryDuration.Seconds(),
+			ExpressionTime:      status.Cost.ExpressionDuration.Seconds(),
+			SeriesCount:         status.Cost.SeriesCount,
+			SeriesLimitExceeded: status.SeriesLimitExceeded,
 		}
 
 		states := manager.GetStatesForRuleUID(rule.OrgID, rule.UID)
@@ -1502,6 +1502,37 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 	})
 }
 
+func TestRouteGetRuleStatusesDependencies(t *testing.T) {
+	orgID := int64(1)
+	gen := ngmodels.RuleGen
+	fakeStore, fakeAIM, api := setupAPI(t)
+
+	parent := gen.With(gen.WithOrgID(orgID), gen.WithGroupKey(ngmodels.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "folder", RuleGroup: "group"}), gen.WithGroupIndex(1)).GenerateRef()
+	fakeAIM.GenerateAlertInstances(orgID, parent.UID, 1, withAlertingState())
+	child := gen.With(gen.WithOrgID(orgID), gen.WithGroupKey(parent.GetGroupKey()), gen.WithGroupIndex(2), gen.WithDependencies(
+		ngmodels.RuleDependency{RuleUID: parent.UID, State: ngmodels.RuleDependencyStateNormal},
+		ngmodels.RuleDependency{RuleUID: "unknown", State: ngmodels.RuleDependencyStateAlerting},
+	)).GenerateRef()
+	fakeStore.PutRule(context.Background(), parent, child)
+
+	req, err := http.NewRequest("GET", "/api/v1/rules", nil)
+	require.NoError(t, err)
+	c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+
+	resp := api.RouteGetRuleStatuses(c)
+	require.Equal(t, http.StatusOK, resp.Status())
+	result := &apimodels.RuleResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body(), result))
+	require.Len(t, result.Data.RuleGroups, 1)
+	rules := result.Data.RuleGroups[0].Rules
+	require.Len(t, rules, 2)
+	require.Empty(t, rules[0].Dependencies)
+	require.Equal(t, []apimodels.RuleDependencyStatus{
+		{RuleUID: parent.UID, State: "Normal", ParentState: "Alerting", Met: false},
+		{RuleUID: "unknown", State: "Alerting", Met: false},
+	}, rules[1].Dependencies)
+}
+
 func setupAPI(t *testing.T) (*fakes.RuleStore, *fakeAlertInstanceManager, PrometheusSrv) {
 	fakeStore := fakes.NewRuleStore(t)
 	fakeAIM := NewFakeAlertInstanceManager(t)
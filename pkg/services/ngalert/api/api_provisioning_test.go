@@ -1942,6 +1942,7 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 		configStore,
 		env.prov,
 		env.store,
+		env.store,
 		env.secrets,
 		env.xact,
 		env.log,
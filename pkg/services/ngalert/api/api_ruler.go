@@ -154,6 +154,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 			rulesToDelete = append(rulesToDelete, uid...)
 		}
 		if len(rulesToDelete) > 0 {
+			if err := store.VerifyNoDependentRules(ctx, srv.store, c.SignedInUser.GetOrgID(), rulesToDelete, nil); err != nil {
+				return err
+			}
 			err := srv.store.DeleteAlertRulesByUID(ctx, c.SignedInUser.GetOrgID(), rulesToDelete...)
 			if err != nil {
 				return err
@@ -181,7 +184,7 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 		if errors.As(err, &errutil.Error{}) {
 			return response.Err(err)
 		}
-		if errors.Is(err, errProvisionedResource) {
+		if errors.Is(err, errProvisionedResource) || errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
 			return ErrResp(http.StatusBadRequest, err, "failed to delete rule group")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
@@ -366,160 +369,196 @@ func (srv RulerSrv) checkGroupLimits(group apimodels.PostableRuleGroupConfig) er
 
 // updateAlertRulesInGroup calculates changes (rules to add,update,delete), verifies that the user is authorized to do the calculated changes and updates database.
 // All operations are performed in a single transaction
-//
-//nolint:gocyclo
 func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) response.Response {
 	var finalChanges *store.GroupDelta
 	var dbConfig *ngmodels.AlertConfiguration
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
-		id, _ := c.SignedInUser.GetInternalID()
-		userNamespace := c.SignedInUser.GetIdentityType()
+		var err error
+		finalChanges, dbConfig, err = srv.saveRuleGroupChanges(tranCtx, c, groupKey, rules)
+		return err
+	})
+	if err != nil {
+		return ruleGroupUpdateErrorResponse(err)
+	}
 
-		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
-			groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
-		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
-		if err != nil {
-			return err
-		}
+	if dbConfig != nil {
+		srv.refreshAlertmanagerConfig(c, dbConfig)
+	}
 
-		if groupChanges.IsEmpty() {
-			finalChanges = groupChanges
-			logger.Info("No changes detected in the request. Do nothing")
-			return nil
-		}
+	return changesToResponse(finalChanges)
+}
+
+// refreshAlertmanagerConfig applies the configuration to the Alertmanager of the organization after a change in notification settings of rules.
+func (srv RulerSrv) refreshAlertmanagerConfig(c *contextmodel.ReqContext, dbConfig *ngmodels.AlertConfiguration) {
+	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingSimplifiedRouting) {
+		return
+	}
+	// This isn't strictly necessary since the alertmanager config is periodically synced.
+	err := srv.amRefresher.ApplyConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), dbConfig)
+	if err != nil {
+		srv.log.Warn("Failed to refresh Alertmanager config for org after change in notification settings", "org", c.SignedInUser.GetOrgID(), "error", err)
+	}
+}
+
+// saveRuleGroupChanges calculates changes of the group, verifies that the user is authorized to do them and writes them to the database.
+// It must be called in a transaction. It returns the Alertmanager configuration if the changes affect notification settings.
+//
+//nolint:gocyclo
+func (srv RulerSrv) saveRuleGroupChanges(tranCtx context.Context, c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRuleWithOptionals) (*store.GroupDelta, *ngmodels.AlertConfiguration, error) {
+	id, _ := c.SignedInUser.GetInternalID()
+	userNamespace := c.SignedInUser.GetIdentityType()
+
+	logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
+		groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
+	groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if groupChanges.IsEmpty() {
+		logger.Info("No changes detected in the request. Do nothing")
+		return groupChanges, nil, nil
+	}
+
+	err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
+		return nil, nil, err
+	}
 
-		err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
+	var dbConfig *ngmodels.AlertConfiguration
+	newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
+	if len(newOrUpdatedNotificationSettings) > 0 {
+		dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
 		if err != nil {
-			return err
+			return nil, nil, fmt.Errorf("failed to get latest configuration: %w", err)
 		}
-
-		if err := validateQueries(c.Req.Context(), groupChanges, srv.conditionValidator, c.SignedInUser); err != nil {
-			return err
+		cfg, err := notifier.Load([]byte(dbConfig.AlertmanagerConfiguration))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse configuration: %w", err)
 		}
-
-		newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
-		if len(newOrUpdatedNotificationSettings) > 0 {
-			dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
-			if err != nil {
-				return fmt.Errorf("failed to get latest configuration: %w", err)
-			}
-			cfg, err := notifier.Load([]byte(dbConfig.AlertmanagerConfiguration))
-			if err != nil {
-				return fmt.Errorf("failed to parse configuration: %w", err)
-			}
-			validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
-			for _, s := range newOrUpdatedNotificationSettings {
-				if err := validator.Validate(s); err != nil {
-					return errors.Join(ngmodels.ErrAlertRuleFailedValidation, err)
-				}
+		validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
+		for _, s := range newOrUpdatedNotificationSettings {
+			if err := validator.Validate(s); err != nil {
+				return nil, nil, errors.Join(ngmodels.ErrAlertRuleFailedValidation, err)
 			}
 		}
+	}
 
-		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
-			return err
-		}
+	if err := validateRuleDependencyParents(tranCtx, srv.store, groupChanges); err != nil {
+		return nil, nil, err
+	}
 
-		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
-		logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
+	if err := verifyDeletedRulesHaveNoDependents(tranCtx, srv.store, groupChanges); err != nil {
+		return nil, nil, err
+	}
 
-		// Delete first as this could prevent future unique constraint violations.
-		if len(finalChanges.Delete) > 0 {
-			UIDs := make([]string, 0, len(finalChanges.Delete))
-			for _, rule := range finalChanges.Delete {
-				UIDs = append(UIDs, rule.UID)
-			}
+	if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
+		return nil, nil, err
+	}
 
-			if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
-				return fmt.Errorf("failed to delete rules: %w", err)
-			}
-		}
+	finalChanges := store.UpdateCalculatedRuleFields(groupChanges)
+	logger.Debug("Updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
 
-		if len(finalChanges.Update) > 0 {
-			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
-			for _, update := range finalChanges.Update {
-				logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
-				updates = append(updates, ngmodels.UpdateRule{
-					Existing: update.Existing,
-					New:      *update.New,
-				})
-			}
-			err = srv.store.UpdateAlertRules(tranCtx, updates)
-			if err != nil {
-				return fmt.Errorf("failed to update rules: %w", err)
-			}
+	// Delete first as this could prevent future unique constraint violations.
+	if len(finalChanges.Delete) > 0 {
+		UIDs := make([]string, 0, len(finalChanges.Delete))
+		for _, rule := range finalChanges.Delete {
+			UIDs = append(UIDs, rule.UID)
 		}
 
-		if len(finalChanges.New) > 0 {
-			inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
-			for _, rule := range finalChanges.New {
-				inserts = append(inserts, *rule)
-			}
-			added, err := srv.store.InsertAlertRules(tranCtx, inserts)
-			if err != nil {
-				return fmt.Errorf("failed to add rules: %w", err)
-			}
-			if len(added) != len(finalChanges.New) {
-				logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
-			} else {
-				for i, newRule := range finalChanges.New {
-					newRule.ID = added[i].ID
-					newRule.UID = added[i].UID
-				}
-			}
+		if err = srv.store.DeleteAlertRulesByUID(tranCtx, c.SignedInUser.GetOrgID(), UIDs...); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete rules: %w", err)
 		}
+	}
 
-		if len(finalChanges.New) > 0 {
-			userID, _ := identity.UserIdentifier(c.SignedInUser.GetID())
-			limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
-				OrgID:  c.SignedInUser.GetOrgID(),
-				UserID: userID,
-			}) // alert rule is table name
-			if err != nil {
-				return fmt.Errorf("failed to get alert rules quota: %w", err)
-			}
-			if limitReached {
-				return ngmodels.ErrQuotaReached
-			}
+	if len(finalChanges.Update) > 0 {
+		updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+		for _, update := range finalChanges.Update {
+			logger.Debug("Updating rule", "rule_uid", update.New.UID, "diff", update.Diff.String())
+			updates = append(updates, ngmodels.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
 		}
-		return nil
-	})
+		err = srv.store.UpdateAlertRules(tranCtx, updates)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update rules: %w", err)
+		}
+	}
 
-	if err != nil {
-		if errors.As(err, &errutil.Error{}) {
-			return response.Err(err)
-		} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
-			return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
-		} else if errors.Is(err, ngmodels.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		} else if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+	if len(finalChanges.New) > 0 {
+		inserts := make([]ngmodels.AlertRule, 0, len(finalChanges.New))
+		for _, rule := range finalChanges.New {
+			inserts = append(inserts, *rule)
+		}
+		added, err := srv.store.InsertAlertRules(tranCtx, inserts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add rules: %w", err)
+		}
+		if len(added) != len(finalChanges.New) {
+			logger.Error("Cannot match inserted rules with final changes", "insertedCount", len(added), "changes", len(finalChanges.New))
+		} else {
+			for i, newRule := range finalChanges.New {
+				newRule.ID = added[i].ID
+				newRule.UID = added[i].UID
+			}
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 	}
 
-	if srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingSimplifiedRouting) && dbConfig != nil {
-		// This isn't strictly necessary since the alertmanager config is periodically synced.
-		err := srv.amRefresher.ApplyConfig(c.Req.Context(), groupKey.OrgID, dbConfig)
+	if len(finalChanges.New) > 0 {
+		userID, _ := identity.UserIdentifier(c.SignedInUser.GetID())
+		limitReached, err := srv.QuotaService.CheckQuotaReached(tranCtx, ngmodels.QuotaTargetSrv, &quota.ScopeParameters{
+			OrgID:  c.SignedInUser.GetOrgID(),
+			UserID: userID,
+		}) // alert rule is table name
 		if err != nil {
-			srv.log.Warn("Failed to refresh Alertmanager config for org after change in notification settings", "org", c.SignedInUser.GetOrgID(), "error", err)
+			return nil, nil, fmt.Errorf("failed to get alert rules quota: %w", err)
+		}
+		if limitReached {
+			return nil, nil, ngmodels.ErrQuotaReached
 		}
 	}
+	return finalChanges, dbConfig, nil
+}
 
-	return changesToResponse(finalChanges)
+func ruleGroupUpdateErrorResponse(err error) response.Response {
+	if errors.As(err, &errutil.Error{}) {
+		return response.Err(err)
+	} else if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) || errors.Is(err, errProvisionedResource) {
+		return ErrResp(http.StatusBadRequest, err, "failed to update rule group")
+	} else if errors.Is(err, ngmodels.ErrQuotaReached) {
+		return ErrResp(http.StatusForbidden, err, "")
+	} else if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "failed to update rule group")
 }
 
-func changesToResponse(finalChanges *store.GroupDelta) response.Response {
+func changesToResponse(changes ...*store.GroupDelta) response.Response {
+	var created, updated, deleted int
+	for _, finalChanges := range changes {
+		created += len(finalChanges.New)
+		updated += len(finalChanges.Update)
+		deleted += len(finalChanges.Delete)
+	}
 	body := apimodels.UpdateRuleGroupResponse{
 		Message: "rule group updated successfully",
-		Created: make([]string, 0, len(finalChanges.New)),
-		Updated: make([]string, 0, len(finalChanges.Update)),
-		Deleted: make([]string, 0, len(finalChanges.Delete)),
+		Created: make([]string, 0, created),
+		Updated: make([]string, 0, updated),
+		Deleted: make([]string, 0, deleted),
 	}
-	if finalChanges.IsEmpty() {
-		body.Message = "no changes detected in the rule group"
-	} else {
+	empty := true
+	for _, finalChanges := range changes {
+		if finalChanges.IsEmpty() {
+			continue
+		}
+		empty = false
 		for _, r := range finalChanges.New {
 			body.Created = append(body.Created, r.UID)
 		}
@@ -530,6 +569,9 @@ func changesToResponse(finalChanges *store.GroupDelta) response.Response {
 			body.Deleted = append(body.Deleted, r.UID)
 		}
 	}
+	if empty {
+		body.Message = "no changes detected in the rule group"
+	}
 	return response.JSON(http.StatusAccepted, body)
 }
 
@@ -575,6 +617,8 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			IsPaused:             r.IsPaused,
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
 			Record:               ApiRecordFromModelRecord(r.Record),
+			Dependencies:         ApiRuleDependenciesFromModelRuleDependencies(r.Dependencies),
+			MaxSeries:            r.MaxSeries,
 			Metadata:             AlertRuleMetadataFromModelMetadata(r.Metadata),
 		},
 	}
@@ -643,6 +687,58 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 	return nil
 }
 
+// validateRuleDependencyParents checks that the parent rules of new or updated dependencies exist and are not deleted by the changes.
+func validateRuleDependencyParents(ctx context.Context, ruleStore RuleStore, groupChanges *store.GroupDelta) error {
+	dependencies := groupChanges.NewOrUpdatedDependencies()
+	if len(dependencies) == 0 {
+		return nil
+	}
+	deleted := make(map[string]struct{}, len(groupChanges.Delete))
+	for _, rule := range groupChanges.Delete {
+		deleted[rule.UID] = struct{}{}
+	}
+	parents := make(map[string]struct{}, len(dependencies))
+	for _, d := range dependencies {
+		parents[d.RuleUID] = struct{}{}
+	}
+	uids := make([]string, 0, len(parents))
+	for uid := range parents {
+		uids = append(uids, uid)
+	}
+	existing, err := ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: groupChanges.GroupKey.OrgID, RuleUIDs: uids})
+	if err != nil {
+		return fmt.Errorf("failed to fetch parent rules of dependencies: %w", err)
+	}
+	for _, rule := range existing {
+		if _, ok := deleted[rule.UID]; !ok {
+			delete(parents, rule.UID)
+		}
+	}
+	for _, uid := range uids {
+		if _, ok := parents[uid]; ok {
+			return fmt.Errorf("%w: parent rule %s of a dependency does not exist", ngmodels.ErrAlertRuleFailedValidation, uid)
+		}
+	}
+	return nil
+}
+
+// verifyDeletedRulesHaveNoDependents checks that no rule depends on the rules deleted by the changes, unless it is
+// deleted as well or its dependency is removed by the same changes.
+func verifyDeletedRulesHaveNoDependents(ctx context.Context, ruleStore RuleStore, groupChanges *store.GroupDelta) error {
+	if len(groupChanges.Delete) == 0 {
+		return nil
+	}
+	deleted := make([]string, 0, len(groupChanges.Delete))
+	for _, rule := range groupChanges.Delete {
+		deleted = append(deleted, rule.UID)
+	}
+	updated := make([]*ngmodels.AlertRule, 0, len(groupChanges.Update))
+	for _, delta := range groupChanges.Update {
+		updated = append(updated, delta.New)
+	}
+	return store.VerifyNoDependentRules(ctx, ruleStore, groupChanges.GroupKey.OrgID, deleted, updated)
+}
+
 // shouldValidate returns true if the rule is not paused and there are changes in the rule that are not ignored
 func shouldValidate(delta store.RuleDelta) bool {
 	for _, diff := range delta.Diff {
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ImportPrometheusRules converts the Prometheus rule groups from the request body to Grafana-managed rules that query the data source `ds`
+// and saves them in the folder `namespaceUID`. Every imported group replaces the group with the same name in the folder.
+// All groups are saved in a single transaction. If the query parameter `dry_run` is true, the converted rules are returned in the
+// export format and nothing is saved.
+// Can return 403 StatusForbidden if user is not authorized to read folder `namespaceUID`
+func (srv RulerSrv) ImportPrometheusRules(c *contextmodel.ReqContext, ds *datasources.DataSource, namespaceUID string) response.Response {
+	namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), namespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	var file prom.PrometheusRulesFile
+	decoder := yaml.NewDecoder(c.Req.Body)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse Prometheus rules")
+	}
+	if len(file.Groups) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("no rule groups to import"), "")
+	}
+
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:   ds.UID,
+		DatasourceType:  ds.Type,
+		DefaultInterval: srv.cfg.DefaultRuleEvaluationInterval,
+	})
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	groups, err := converter.PrometheusRulesToGrafana(c.SignedInUser.GetOrgID(), namespace.UID, file.Groups)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to convert Prometheus rules")
+	}
+	if err := srv.validateImportedGroups(groups); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	if c.QueryBool("dry_run") {
+		export := make([]ngmodels.AlertRuleGroupWithFolderFullpath, 0, len(groups))
+		for _, g := range groups {
+			key := ngmodels.AlertRuleGroupKey{OrgID: c.SignedInUser.GetOrgID(), NamespaceUID: namespace.UID, RuleGroup: g.Title}
+			export = append(export, ngmodels.NewAlertRuleGroupWithFolderFullpath(key, g.Rules, namespace.Fullpath))
+		}
+		e, err := AlertingFileExportFromAlertRuleGroupWithFolderFullpath(export)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		}
+		return exportResponse(c, e)
+	}
+
+	changes := make([]*store.GroupDelta, 0, len(groups))
+	var dbConfig *ngmodels.AlertConfiguration
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		if err := srv.matchExistingRules(tranCtx, c.SignedInUser.GetOrgID(), namespace.UID, groups); err != nil {
+			return err
+		}
+		for _, g := range groups {
+			groupKey := ngmodels.AlertRuleGroupKey{
+				OrgID:        c.SignedInUser.GetOrgID(),
+				NamespaceUID: namespace.UID,
+				RuleGroup:    g.Title,
+			}
+			rules := make([]*ngmodels.AlertRuleWithOptionals, 0, len(g.Rules))
+			for _, r := range g.Rules {
+				rules = append(rules, &ngmodels.AlertRuleWithOptionals{AlertRule: r})
+			}
+			delta, groupConfig, err := srv.saveRuleGroupChanges(tranCtx, c, groupKey, rules)
+			if err != nil {
+				return fmt.Errorf("failed to import rule group %s: %w", g.Title, err)
+			}
+			if groupConfig != nil {
+				dbConfig = groupConfig
+			}
+			changes = append(changes, delta)
+		}
+		return nil
+	})
+	if err != nil {
+		return ruleGroupUpdateErrorResponse(err)
+	}
+
+	if dbConfig != nil {
+		srv.refreshAlertmanagerConfig(c, dbConfig)
+	}
+
+	return changesToResponse(changes...)
+}
+
+func (srv RulerSrv) validateImportedGroups(groups []ngmodels.AlertRuleGroup) error {
+	limits := RuleLimitsFromConfig(srv.cfg, srv.featureManager)
+	for _, g := range groups {
+		if len(g.Title) > store.AlertRuleMaxRuleGroupNameLength {
+			return fmt.Errorf("rule group name %s is too long. Max length is %d", g.Title, store.AlertRuleMaxRuleGroupNameLength)
+		}
+		for _, r := range g.Rules {
+			if r.Type() == ngmodels.RuleTypeRecording && !limits.RecordingRulesAllowed {
+				return fmt.Errorf("%w: recording rule %s cannot be imported because recording rules are not enabled", ngmodels.ErrAlertRuleFailedValidation, r.Title)
+			}
+			if err := r.ValidateAlertRule(*srv.cfg); err != nil {
+				return fmt.Errorf("invalid rule %s in group %s: %w", r.Title, g.Title, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchExistingRules sets UIDs of the converted rules to UIDs of the rules in the folder that they replace.
+// A converted rule replaces the rule with the same UID, or, if there is none, the rule with the same title. UIDs of the other rules
+// are cleared, so they are created as new rules.
+func (srv RulerSrv) matchExistingRules(ctx context.Context, orgID int64, namespaceUID string, groups []ngmodels.AlertRuleGroup) error {
+	existing, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespaceUID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list rules in the folder: %w", err)
+	}
+	byUID := make(map[string]*ngmodels.AlertRule, len(existing))
+	byTitle := make(map[string]*ngmodels.AlertRule, len(existing))
+	for _, r := range existing {
+		byUID[r.UID] = r
+		byTitle[r.Title] = r
+	}
+	for _, g := range groups {
+		for i := range g.Rules {
+			r := &g.Rules[i]
+			if _, ok := byUID[r.UID]; ok {
+				continue
+			}
+			r.UID = ""
+			if e, ok := byTitle[r.Title]; ok {
+				r.UID = e.UID
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	folder2 "github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+)
+
+const prometheusRulesYAML = `
+groups:
+  - name: node
+    interval: 1m
+    rules:
+      - alert: HighLoad
+        expr: node_load1 > 10
+        for: 5m
+        labels:
+          severity: warning
+      - record: instance:node_load1:avg
+        expr: avg by (instance) (node_load1)
+`
+
+func TestImportPrometheusRules(t *testing.T) {
+	orgID := int64(1)
+	folder := &folder2.Folder{
+		UID:      "folder-uid",
+		Title:    "Imported",
+		Fullpath: "Imported",
+	}
+	ds := &datasources.DataSource{UID: "prom-uid", Type: datasources.DS_PROMETHEUS}
+
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)
+	perms := map[int64]map[string][]string{
+		orgID: {
+			dashboards.ActionFoldersRead: {scope},
+			ac.ActionAlertingRuleRead:    {scope},
+			ac.ActionAlertingRuleCreate:  {scope},
+			ac.ActionAlertingRuleUpdate:  {scope},
+			ac.ActionAlertingRuleDelete:  {scope},
+			datasources.ActionQuery:      {datasources.ScopeProvider.GetResourceScopeUID(ds.UID)},
+		},
+	}
+
+	createRequest := func(body string) *contextmodel.ReqContext {
+		rc := createRequestContextWithPerms(orgID, perms, nil)
+		rc.Req.Body = io.NopCloser(strings.NewReader(body))
+		return rc
+	}
+
+	initService := func(t *testing.T) (*RulerSrv, *fakes.RuleStore) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		svc.QuotaService = quotatest.New(false, nil)
+		svc.cfg.DefaultRuleEvaluationInterval = time.Minute
+		return svc, ruleStore
+	}
+
+	insertedRules := func(ruleStore *fakes.RuleStore) []models.AlertRule {
+		var result []models.AlertRule
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			rules, ok := cmd.([]models.AlertRule)
+			return rules, ok
+		}) {
+			result = append(result, cmd.([]models.AlertRule)...)
+		}
+		return result
+	}
+
+	t.Run("should create rules from Prometheus rule groups", func(t *testing.T) {
+		svc, ruleStore := initService(t)
+
+		resp := svc.ImportPrometheusRules(createRequest(prometheusRulesYAML), ds, folder.UID)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+
+		inserted := insertedRules(ruleStore)
+		require.Len(t, inserted, 2)
+		for _, r := range inserted {
+			require.Equal(t, folder.UID, r.NamespaceUID)
+			require.Equal(t, "node", r.RuleGroup)
+			require.Empty(t, r.UID)
+		}
+		require.Equal(t, "HighLoad", inserted[0].Title)
+		require.Equal(t, "instance:node_load1:avg", inserted[1].Title)
+		require.NotNil(t, inserted[1].Record)
+	})
+
+	t.Run("should replace existing rules with the same title", func(t *testing.T) {
+		svc, ruleStore := initService(t)
+		existing := models.RuleGen.With(
+			models.RuleGen.WithOrgID(orgID),
+			models.RuleGen.WithNamespaceUID(folder.UID),
+			models.RuleGen.WithGroupName("old"),
+			models.RuleGen.WithTitle("HighLoad"),
+		).GenerateRef()
+		ruleStore.PutRule(context.Background(), existing)
+
+		resp := svc.ImportPrometheusRules(createRequest(prometheusRulesYAML), ds, folder.UID)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+
+		var result apimodels.UpdateRuleGroupResponse
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, []string{existing.UID}, result.Updated)
+		require.Len(t, insertedRules(ruleStore), 1)
+	})
+
+	t.Run("should return rules in export format when dry_run is true", func(t *testing.T) {
+		svc, ruleStore := initService(t)
+
+		rc := createRequest(prometheusRulesYAML)
+		rc.Req.Form.Set("dry_run", "true")
+		rc.Req.Form.Set("format", "json")
+		resp := svc.ImportPrometheusRules(rc, ds, folder.UID)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var export apimodels.AlertingFileExport
+		require.NoError(t, json.Unmarshal(resp.Body(), &export))
+		require.Len(t, export.Groups, 1)
+		require.Equal(t, "node", export.Groups[0].Name)
+		require.Equal(t, folder.Fullpath, export.Groups[0].Folder)
+		require.Len(t, export.Groups[0].Rules, 2)
+		require.Empty(t, insertedRules(ruleStore))
+	})
+
+	t.Run("should return 400 when rules are invalid", func(t *testing.T) {
+		svc, ruleStore := initService(t)
+
+		testCases := map[string]string{
+			"invalid YAML":   "groups: [",
+			"unknown fields": "groups:\n  - name: test\n    unknown: true\n",
+			"no groups":      "groups: []\n",
+			"invalid rule":   "groups:\n  - name: test\n    rules:\n      - alert: test\n        expr: sum(up\n",
+		}
+		for name, body := range testCases {
+			t.Run(name, func(t *testing.T) {
+				resp := svc.ImportPrometheusRules(createRequest(body), ds, folder.UID)
+				require.Equal(t, http.StatusBadRequest, resp.Status())
+			})
+		}
+		require.Empty(t, insertedRules(ruleStore))
+	})
+}
@@ -640,6 +640,112 @@ func TestValidateQueries(t *testing.T) {
 	})
 }
 
+func TestValidateRuleDependencyParents(t *testing.T) {
+	gen := models.RuleGen
+	orgID := rand.Int63()
+	parent := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), parent)
+
+	dependsOn := func(uid string) *models.AlertRule {
+		return gen.With(gen.WithOrgID(orgID), gen.WithDependencies(models.RuleDependency{RuleUID: uid, State: models.RuleDependencyStateNormal})).GenerateRef()
+	}
+
+	t.Run("should pass if the parent rule exists", func(t *testing.T) {
+		delta := &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+			New:      []*models.AlertRule{dependsOn(parent.UID)},
+		}
+		require.NoError(t, validateRuleDependencyParents(context.Background(), ruleStore, delta))
+	})
+
+	t.Run("should fail if the parent rule does not exist", func(t *testing.T) {
+		delta := &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+			New:      []*models.AlertRule{dependsOn("missing")},
+		}
+		err := validateRuleDependencyParents(context.Background(), ruleStore, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "parent rule missing of a dependency does not exist")
+	})
+
+	t.Run("should fail if the parent rule is deleted by the same change", func(t *testing.T) {
+		delta := &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+			Update: []store.RuleDelta{
+				{
+					Existing: parent,
+					New:      dependsOn(parent.UID),
+					Diff:     cmputil.DiffReport{cmputil.Diff{Path: "Dependencies"}},
+				},
+			},
+			Delete: []*models.AlertRule{parent},
+		}
+		err := validateRuleDependencyParents(context.Background(), ruleStore, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should not check updated rules whose dependencies did not change", func(t *testing.T) {
+		delta := &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+			Update: []store.RuleDelta{
+				{
+					New:  dependsOn("missing"),
+					Diff: cmputil.DiffReport{cmputil.Diff{Path: "Title"}},
+				},
+			},
+		}
+		require.NoError(t, validateRuleDependencyParents(context.Background(), ruleStore, delta))
+	})
+}
+
+func TestVerifyDeletedRulesHaveNoDependents(t *testing.T) {
+	gen := models.RuleGen
+	orgID := rand.Int63()
+	parent := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+	child := gen.With(gen.WithOrgID(orgID), gen.WithDependencies(models.RuleDependency{RuleUID: parent.UID, State: models.RuleDependencyStateNormal})).GenerateRef()
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), parent, child)
+
+	t.Run("should fail if another rule depends on a deleted rule", func(t *testing.T) {
+		delta := &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+			Delete:   []*models.AlertRule{parent},
+		}
+		err := verifyDeletedRulesHaveNoDependents(context.Background(), ruleStore, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "depends on it")
+	})
+
+	t.Run("should pass if the dependency is removed by the same change", func(t *testing.T) {
+		updated := models.CopyRule(child)
+		updated.Dependencies = nil
+		delta := &store.GroupDelta{
+			GroupKey: models.AlertRuleGroupKey{OrgID: orgID},
+			Update:   []store.RuleDelta{{Existing: child, New: updated}},
+			Delete:   []*models.AlertRule{parent},
+		}
+		require.NoError(t, verifyDeletedRulesHaveNoDependents(context.Background(), ruleStore, delta))
+	})
+}
+
+func TestRouteDeleteAlertRulesWithDependents(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID))
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+	parent := gen.With(gen.WithNamespace(folder)).GenerateRef()
+	child := gen.With(gen.WithDependencies(models.RuleDependency{RuleUID: parent.UID, State: models.RuleDependencyStateNormal})).GenerateRef()
+	ruleStore.PutRule(context.Background(), parent, child)
+
+	request := createRequestContextWithPerms(orgID, createPermissionsForRules([]*models.AlertRule{parent}, orgID), nil)
+
+	response := createService(ruleStore).RouteDeleteAlertRules(request, folder.UID, parent.RuleGroup)
+	require.Equalf(t, http.StatusBadRequest, response.Status(), "Expected 400 but got %d: %v", response.Status(), string(response.Body()))
+	require.Contains(t, string(response.Body()), "depends on it")
+}
+
 func createServiceWithProvenanceStore(store *fakes.RuleStore, provenanceStore provisioning.ProvisioningStore) *RulerSrv {
 	svc := createService(store)
 	svc.provenanceStore = provenanceStore
@@ -719,3 +825,22 @@ func createPermissionsForRules(rules []*models.AlertRule, orgID int64) map[int64
 	}
 	return map[int64]map[string][]string{orgID: permissions}
 }
+
+func TestChangesToResponse(t *testing.T) {
+	t.Run("returns a message when there are no changes", func(t *testing.T) {
+		response := changesToResponse(&store.GroupDelta{})
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.JSONEq(t, `{"message": "no changes detected in the rule group"}`, string(response.Body()))
+	})
+
+	t.Run("returns the changes of all groups", func(t *testing.T) {
+		gen := models.RuleGen
+		first := &store.GroupDelta{New: gen.GenerateManyRef(2)}
+		second := &store.GroupDelta{Delete: gen.GenerateManyRef(1)}
+		var body apimodels.UpdateRuleGroupResponse
+		require.NoError(t, json.Unmarshal(changesToResponse(first, second).Body(), &body))
+		require.Equal(t, []string{first.New[0].UID, first.New[1].UID}, body.Created)
+		require.Empty(t, body.Updated)
+		require.Equal(t, []string{second.Delete[0].UID}, body.Deleted)
+	})
+}
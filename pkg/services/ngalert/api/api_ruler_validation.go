@@ -61,6 +61,10 @@ func validateRuleNode(
 		return nil, fmt.Errorf("alert rule title is too long. Max length is %d", store.AlertRuleMaxTitleLength)
 	}
 
+	if ruleNode.GrafanaManagedAlert.MaxSeries < 0 {
+		return nil, fmt.Errorf("%w: max series must be greater than or equal to 0", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
 	queries := AlertQueriesFromApiAlertQueries(ruleNode.GrafanaManagedAlert.Data)
 
 	newAlertRule := ngmodels.AlertRule{
@@ -72,6 +76,7 @@ func validateRuleNode(
 		IntervalSeconds: intervalSeconds,
 		NamespaceUID:    namespaceUID,
 		RuleGroup:       groupName,
+		MaxSeries:       ruleNode.GrafanaManagedAlert.MaxSeries,
 	}
 
 	if isRecordingRule {
@@ -144,6 +149,13 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		}
 	}
 
+	if len(in.GrafanaManagedAlert.Dependencies) > 0 {
+		newRule.Dependencies, err = validateRuleDependencies(in.GrafanaManagedAlert.Dependencies)
+		if err != nil {
+			return ngmodels.AlertRule{}, err
+		}
+	}
+
 	if in.GrafanaManagedAlert.Metadata != nil {
 		newRule.Metadata.EditorSettings = ngmodels.EditorSettings{
 			SimplifiedQueryAndExpressionsSection: in.GrafanaManagedAlert.Metadata.EditorSettings.SimplifiedQueryAndExpressionsSection,
@@ -189,6 +201,18 @@ func validateRecordingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 	return newRule, nil
 }
 
+func validateRuleDependencies(dependencies []apimodels.RuleDependency) ([]ngmodels.RuleDependency, error) {
+	result := make([]ngmodels.RuleDependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		state, err := ngmodels.RuleDependencyStateFromString(d.State)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid dependency on rule %s: %s", ngmodels.ErrAlertRuleFailedValidation, d.RuleUID, err.Error())
+		}
+		result = append(result, ngmodels.RuleDependency{RuleUID: d.RuleUID, State: state})
+	}
+	return result, nil
+}
+
 func validateLabels(l map[string]string) error {
 	for key := range l {
 		if _, ok := ngmodels.LabelsUserCannotSpecify[key]; ok {
@@ -1106,6 +1106,47 @@ func TestValidateRuleNodeEditorSettings(t *testing.T) {
 	}
 }
 
+func TestValidateRuleNodeDependencies(t *testing.T) {
+	cfg := config(t)
+	limits := makeLimits(cfg)
+
+	t.Run("valid dependencies", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.Dependencies = []apimodels.RuleDependency{{RuleUID: "parent", State: "Alerting"}}
+		newRule, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval*time.Duration(rand.Int63n(10)+1), rand.Int63(), randFolder().UID, limits)
+		require.NoError(t, err)
+		require.Equal(t, []models.RuleDependency{{RuleUID: "parent", State: models.RuleDependencyStateAlerting}}, newRule.Dependencies)
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.Dependencies = []apimodels.RuleDependency{{RuleUID: "parent", State: "firing"}}
+		_, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval*time.Duration(rand.Int63n(10)+1), rand.Int63(), randFolder().UID, limits)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "unknown rule dependency state 'firing'")
+	})
+}
+
+func TestValidateRuleNodeMaxSeries(t *testing.T) {
+	cfg := config(t)
+	limits := makeLimits(cfg)
+
+	t.Run("valid max series", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.MaxSeries = 100
+		newRule, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval*time.Duration(rand.Int63n(10)+1), rand.Int63(), randFolder().UID, limits)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, newRule.MaxSeries)
+	})
+
+	t.Run("negative max series", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.MaxSeries = -1
+		_, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval*time.Duration(rand.Int63n(10)+1), rand.Int63(), randFolder().UID, limits)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
 func TestValidateRuleNodeReservedLabels(t *testing.T) {
 	cfg := config(t)
 	limits := makeLimits(cfg)
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -39,6 +41,10 @@ type folderService interface {
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 }
 
+type ruleReader interface {
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) (*ngmodels.AlertRule, error)
+}
+
 type TestingApiSrv struct {
 	*AlertingProxy
 	DatasourceCache datasources.CacheService
@@ -51,6 +57,7 @@ type TestingApiSrv struct {
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	ruleStore       ruleReader
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -94,6 +101,19 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
 	}
 
+	transitions := srv.processEvalResults(c.Req.Context(), now, rule, results, folder.Fullpath)
+
+	alerts := make([]*amv2.PostableAlert, 0, len(transitions))
+	for _, alertState := range transitions {
+		alerts = append(alerts, state.StateToPostableAlert(alertState, srv.appUrl))
+	}
+
+	return response.JSON(http.StatusOK, alerts)
+}
+
+// processEvalResults returns the states that the results of the rule lead to from an empty state, as the state manager
+// of the ruler would compute them: with the labels of the rule and its no data and execution error states applied.
+func (srv TestingApiSrv) processEvalResults(ctx context.Context, now time.Time, rule *ngmodels.AlertRule, results eval.Results, folderFullpath string) state.StateTransitions {
 	cfg := state.ManagerCfg{
 		Metrics:       nil,
 		ExternalURL:   srv.appUrl,
@@ -106,21 +126,172 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 	}
 	manager := state.NewManager(cfg, state.NewNoopPersister())
 	includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel)
-	transitions := manager.ProcessEvalResults(
-		c.Req.Context(),
+	return manager.ProcessEvalResults(
+		ctx,
 		now,
 		rule,
 		results,
-		state.GetRuleExtraLabels(log.New("testing"), rule, folder.Fullpath, includeFolder),
+		state.GetRuleExtraLabels(log.New("testing"), rule, folderFullpath, includeFolder),
 		nil,
 	)
+}
 
-	alerts := make([]*amv2.PostableAlert, 0, len(transitions))
-	for _, alertState := range transitions {
-		alerts = append(alerts, state.StateToPostableAlert(alertState, srv.appUrl))
+// RouteTestGrafanaRuleDiff evaluates the current and the proposed version of a rule at the same time and returns
+// which alert instances would appear, disappear or change state if the proposed version was saved. The current version
+// is the saved rule with the UID of the proposed version. A rule that does not exist yet has no alert instances.
+func (srv TestingApiSrv) RouteTestGrafanaRuleDiff(c *contextmodel.ReqContext, body apimodels.PostableExtendedRuleNodeExtended) response.Response {
+	ctx := c.Req.Context()
+	folder, err := srv.folderService.GetNamespaceByUID(ctx, body.NamespaceUID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(dashboards.ErrFolderAccessDenied)
+	}
+	proposed, err := validateRuleNode(
+		&body.Rule,
+		body.RuleGroup,
+		srv.cfg.BaseInterval,
+		c.SignedInUser.GetOrgID(),
+		folder.UID,
+		RuleLimitsFromConfig(srv.cfg, srv.featureManager),
+	)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
 	}
 
-	return response.JSON(http.StatusOK, alerts)
+	var current *ngmodels.AlertRule
+	if proposed.UID != "" {
+		current, err = srv.ruleStore.GetAlertRuleByUID(ctx, &ngmodels.GetAlertRuleByUIDQuery{OrgID: c.SignedInUser.GetOrgID(), UID: proposed.UID})
+		if err != nil && !errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusInternalServerError, err, "Failed to get the current version of the rule")
+		}
+	}
+	if current != nil {
+		if err := srv.authz.AuthorizeAccessInFolder(ctx, c.SignedInUser, current); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to rule", err)
+		}
+		if err := srv.authz.AuthorizeDatasourceAccessForRule(ctx, c.SignedInUser, current); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to rule", err)
+		}
+		// the proposed version can omit the fields that do not change, like an update of the rule.
+		patched := ngmodels.AlertRuleWithOptionals{AlertRule: *proposed}
+		ngmodels.PatchPartialAlertRule(current, &patched)
+		proposed = &patched.AlertRule
+	}
+	if err := srv.authz.AuthorizeDatasourceAccessForRule(ctx, c.SignedInUser, proposed); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to rule", err)
+	}
+
+	now := time.Now()
+	evaluate := func(rule *ngmodels.AlertRule) (eval.Results, response.Response) {
+		if srv.featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingQueryOptimization) {
+			if _, err := store.OptimizeAlertQueries(rule.Data); err != nil {
+				return nil, ErrResp(http.StatusInternalServerError, err, "Failed to optimize query")
+			}
+		}
+		evaluator, err := srv.evaluator.Create(eval.NewContext(ctx, c.SignedInUser), rule.GetEvalCondition().WithSource("preview"))
+		if err != nil {
+			return nil, ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
+		}
+		results, err := evaluator.Evaluate(ctx, now)
+		if err != nil {
+			return nil, ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
+		}
+		return results, nil
+	}
+
+	var currentResults eval.Results
+	if current != nil {
+		var errResp response.Response
+		if currentResults, errResp = evaluate(current); errResp != nil {
+			return errResp
+		}
+	}
+	proposedResults, errResp := evaluate(proposed)
+	if errResp != nil {
+		return errResp
+	}
+
+	// the pending period is not applied, the states of both versions would otherwise depend on the previous evaluations.
+	statesOf := func(rule *ngmodels.AlertRule, results eval.Results) state.StateTransitions {
+		withoutFor := *rule
+		withoutFor.For = 0
+		return srv.processEvalResults(ctx, now, &withoutFor, results, folder.Fullpath)
+	}
+	var currentStates state.StateTransitions
+	if current != nil {
+		currentStates = statesOf(current, currentResults)
+	}
+	diff := diffEvalStates(currentStates, statesOf(proposed, proposedResults))
+	diff.EvaluatedAt = now
+	if current != nil {
+		diff.CurrentVersion = current.Version
+	}
+	return response.JSON(http.StatusOK, diff)
+}
+
+// diffEvalStates compares the states of the alert instances of two versions of a rule by their labels, which include
+// the labels of the rule.
+func diffEvalStates(current, proposed state.StateTransitions) apimodels.RuleEvalDiff {
+	diff := apimodels.RuleEvalDiff{
+		Summary: apimodels.RuleEvalDiffStats{
+			Current:  len(current),
+			Proposed: len(proposed),
+		},
+		Instances: []apimodels.RuleEvalInstanceDiff{},
+	}
+
+	byLabels := make(map[data.Fingerprint]*state.State, len(current))
+	for _, s := range current {
+		byLabels[s.Labels.Fingerprint()] = s.State
+	}
+	for _, s := range proposed {
+		fp := s.Labels.Fingerprint()
+		instance := apimodels.RuleEvalInstanceDiff{
+			Labels:        withoutPrivateLabels(s.Labels),
+			ProposedState: state.FormatStateAndReason(s.State.State, s.StateReason),
+		}
+		if s.Error != nil {
+			instance.Error = s.Error.Error()
+		}
+		old, ok := byLabels[fp]
+		delete(byLabels, fp)
+		switch {
+		case !ok:
+			instance.Change = apimodels.RuleEvalInstanceAdded
+			diff.Summary.Added++
+		case old.State != s.State.State || old.StateReason != s.StateReason:
+			instance.Change = apimodels.RuleEvalInstanceChanged
+			instance.CurrentState = state.FormatStateAndReason(old.State, old.StateReason)
+			diff.Summary.Changed++
+		default:
+			diff.Summary.Unchanged++
+			continue
+		}
+		diff.Instances = append(diff.Instances, instance)
+	}
+	for _, s := range byLabels {
+		diff.Instances = append(diff.Instances, apimodels.RuleEvalInstanceDiff{
+			Labels:       withoutPrivateLabels(s.Labels),
+			Change:       apimodels.RuleEvalInstanceRemoved,
+			CurrentState: state.FormatStateAndReason(s.State, s.StateReason),
+		})
+		diff.Summary.Removed++
+	}
+
+	sort.Slice(diff.Instances, func(i, j int) bool {
+		return data.Labels(diff.Instances[i].Labels).String() < data.Labels(diff.Instances[j].Labels).String()
+	})
+	return diff
+}
+
+// withoutPrivateLabels returns the labels without the private labels that the ruler adds to alert instances.
+func withoutPrivateLabels(labels data.Labels) data.Labels {
+	result := make(data.Labels, len(labels))
+	for k, v := range labels {
+		if !strings.HasPrefix(k, "__") || !strings.HasSuffix(k, "__") {
+			result[k] = v
+		}
+	}
+	return result
 }
 
 func (srv TestingApiSrv) RouteTestRuleConfig(c *contextmodel.ReqContext, body apimodels.TestRulePayload, datasourceUID string) response.Response {
@@ -272,6 +443,10 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		Labels:          cmd.Labels,
 	}
 
+	if cmd.SimulateNotifications {
+		return srv.backtestAlertRuleNotifications(c, cmd, rule)
+	}
+
 	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
 	if err != nil {
 		if errors.Is(err, backtesting.ErrInvalidInputData) {
@@ -286,3 +461,46 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// backtestAlertRuleNotifications tests the rule and simulates the notifications that the Alertmanager of the organization
+// would send for its alerts.
+func (srv TestingApiSrv) backtestAlertRuleNotifications(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig, rule *ngmodels.AlertRule) response.Response {
+	if cmd.NotificationSettings != nil {
+		settings, err := validateNotificationSettings(cmd.NotificationSettings)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		rule.NotificationSettings = settings
+	}
+
+	folderTitle := ""
+	includeFolder := false
+	if cmd.NamespaceUID != "" {
+		folder, err := srv.folderService.GetNamespaceByUID(c.Req.Context(), cmd.NamespaceUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(dashboards.ErrFolderAccessDenied)
+		}
+		rule.NamespaceUID = folder.UID
+		folderTitle = folder.Fullpath
+		includeFolder = !srv.cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel)
+	}
+	extraLabels := state.GetRuleExtraLabels(log.New("backtesting"), rule, folderTitle, includeFolder)
+	for k, v := range extraLabels {
+		if v == "" {
+			delete(extraLabels, k)
+		}
+	}
+
+	result, notifications, err := srv.backtesting.TestNotifications(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, extraLabels)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	return response.JSON(http.StatusOK, apimodels.BacktestNotificationsResult{
+		States:        result,
+		Notifications: BacktestNotificationsFromNotifications(notifications),
+	})
+}
@@ -1,14 +1,17 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -243,6 +246,165 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 	})
 }
 
+func TestRouteTestGrafanaRuleDiff(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	gen := models.RuleGen
+	query := gen.GenerateQuery()
+	f := randFolder()
+	current := gen.With(
+		gen.WithOrgID(1),
+		gen.WithNamespaceUID(f.UID),
+		gen.WithQuery(query),
+		gen.WithTitle("cpu"),
+		gen.WithLabels(data.Labels{"team": "a"}),
+		gen.WithFor(0),
+		gen.WithNoDataExecAs(models.NoData),
+		gen.WithErrorExecAs(models.ErrorErrState),
+		gen.WithNoNotificationSettings(),
+		func(r *models.AlertRule) { r.Version = 3 },
+	).GenerateRef()
+
+	result := func(state eval.State, labels data.Labels) eval.Result {
+		return eval.Result{Instance: labels, State: state}
+	}
+	currentResults := eval.Results{
+		result(eval.Normal, data.Labels{"instance": "a"}),
+		result(eval.Normal, data.Labels{"instance": "b"}),
+		result(eval.Alerting, data.Labels{"instance": "c"}),
+	}
+	proposedResults := eval.Results{
+		result(eval.Normal, data.Labels{"instance": "a"}),
+		result(eval.Alerting, data.Labels{"instance": "b"}),
+		result(eval.Alerting, data.Labels{"instance": "d"}),
+	}
+	// instanceLabels returns the labels of an alert instance of the rule, which include the labels of the rule.
+	instanceLabels := func(instance string) map[string]string {
+		labels := map[string]string{"alertname": "cpu", "team": "a", models.FolderTitleLabel: f.Fullpath}
+		if instance != "" {
+			labels["instance"] = instance
+		}
+		return labels
+	}
+
+	setup := func(t *testing.T, permissions []ac.Permission) (*TestingApiSrv, *eval_mocks.ConditionEvaluatorMock) {
+		ruleStore := fakes2.NewRuleStore(t)
+		ruleStore.Folders[rc.OrgID] = []*folder.Folder{f}
+		ruleStore.PutRule(rc.Req.Context(), current)
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{{UID: query.DatasourceUID}}}
+		return createTestingApiSrv(t, ds, acMock.New().WithPermissions(permissions), eval_mocks.NewEvaluatorFactory(evaluator), featuremgmt.WithFeatures(), ruleStore), evaluator
+	}
+	// expectEvaluation makes the evaluator return the results once, evaluated at the time of the evaluation.
+	expectEvaluation := func(evaluator *eval_mocks.ConditionEvaluatorMock, results eval.Results) {
+		results = slices.Clone(results)
+		evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Run(func(_ context.Context, now time.Time) {
+			for i := range results {
+				results[i].EvaluatedAt = now
+			}
+		}).Return(results, nil).Once()
+	}
+	proposedRule := func(uid string) definitions.PostableExtendedRuleNodeExtended {
+		rule := validRule()
+		forDuration := model.Duration(0)
+		rule.For = &forDuration
+		rule.Labels = map[string]string{"team": "a"}
+		rule.GrafanaManagedAlert.Title = "cpu"
+		rule.GrafanaManagedAlert.UID = uid
+		rule.GrafanaManagedAlert.Data = ApiAlertQueriesFromAlertQueries([]models.AlertQuery{query})
+		rule.GrafanaManagedAlert.Condition = query.RefID
+		rule.GrafanaManagedAlert.NoDataState = definitions.NoDataState(models.NoData)
+		rule.GrafanaManagedAlert.ExecErrState = definitions.ExecutionErrorState(models.ErrorErrState)
+		return definitions.PostableExtendedRuleNodeExtended{Rule: rule, NamespaceUID: f.UID, NamespaceTitle: f.Title, RuleGroup: current.RuleGroup}
+	}
+	allPermissions := []ac.Permission{
+		{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID)},
+		{Action: ac.ActionAlertingRuleRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+		{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+	}
+
+	t.Run("should return the difference between the current and the proposed version", func(t *testing.T) {
+		srv, evaluator := setup(t, allPermissions)
+		expectEvaluation(evaluator, currentResults)
+		expectEvaluation(evaluator, proposedResults)
+
+		response := srv.RouteTestGrafanaRuleDiff(rc, proposedRule(current.UID))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var diff definitions.RuleEvalDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.EqualValues(t, 3, diff.CurrentVersion)
+		require.Equal(t, definitions.RuleEvalDiffStats{Current: 3, Proposed: 3, Added: 1, Removed: 1, Changed: 1, Unchanged: 1}, diff.Summary)
+		require.Equal(t, []definitions.RuleEvalInstanceDiff{
+			{Labels: instanceLabels("b"), Change: definitions.RuleEvalInstanceChanged, CurrentState: "Normal", ProposedState: "Alerting"},
+			{Labels: instanceLabels("c"), Change: definitions.RuleEvalInstanceRemoved, CurrentState: "Alerting"},
+			{Labels: instanceLabels("d"), Change: definitions.RuleEvalInstanceAdded, ProposedState: "Alerting"},
+		}, diff.Instances)
+		evaluator.AssertNumberOfCalls(t, "Evaluate", 2)
+	})
+
+	t.Run("should apply the no data state and the labels of each version", func(t *testing.T) {
+		srv, evaluator := setup(t, allPermissions)
+		noData := eval.Results{result(eval.NoData, nil)}
+		expectEvaluation(evaluator, noData)
+		expectEvaluation(evaluator, noData)
+
+		proposed := proposedRule(current.UID)
+		proposed.Rule.GrafanaManagedAlert.NoDataState = definitions.NoDataState(models.OK)
+		forDuration := model.Duration(time.Hour)
+		proposed.Rule.For = &forDuration
+		response := srv.RouteTestGrafanaRuleDiff(rc, proposed)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var diff definitions.RuleEvalDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Equal(t, []definitions.RuleEvalInstanceDiff{
+			{Labels: instanceLabels(""), Change: definitions.RuleEvalInstanceChanged, CurrentState: "NoData", ProposedState: "Normal (NoData)"},
+		}, diff.Instances)
+
+		srv, evaluator = setup(t, allPermissions)
+		expectEvaluation(evaluator, currentResults)
+		expectEvaluation(evaluator, currentResults)
+
+		proposed = proposedRule(current.UID)
+		proposed.Rule.Labels = map[string]string{"team": "b"}
+		response = srv.RouteTestGrafanaRuleDiff(rc, proposed)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Equal(t, definitions.RuleEvalDiffStats{Current: 3, Proposed: 3, Added: 3, Removed: 3}, diff.Summary, "instances with other labels are other alerts")
+	})
+
+	t.Run("should return all instances as added when the rule does not exist", func(t *testing.T) {
+		srv, evaluator := setup(t, allPermissions)
+		expectEvaluation(evaluator, proposedResults)
+
+		response := srv.RouteTestGrafanaRuleDiff(rc, proposedRule(""))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var diff definitions.RuleEvalDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &diff))
+		require.Zero(t, diff.CurrentVersion)
+		require.Equal(t, definitions.RuleEvalDiffStats{Proposed: 3, Added: 3}, diff.Summary)
+		evaluator.AssertNumberOfCalls(t, "Evaluate", 1)
+	})
+
+	t.Run("should return Forbidden if user cannot read the current version", func(t *testing.T) {
+		srv, evaluator := setup(t, allPermissions[:1])
+
+		response := srv.RouteTestGrafanaRuleDiff(rc, proposedRule(current.UID))
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+		evaluator.AssertNotCalled(t, "Evaluate", mock.Anything, mock.Anything)
+	})
+}
+
 func TestRouteEvalQueries(t *testing.T) {
 	t.Run("when fine-grained access is enabled", func(t *testing.T) {
 		rc := &contextmodel.ReqContext{
@@ -404,5 +566,6 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		tracer:          tracing.InitializeTracerForTest(),
 		featureManager:  featureManager,
 		folderService:   ruleStore,
+		ruleStore:       ruleStore,
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -34,6 +35,8 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		Dependencies:         ModelRuleDependenciesFromApiRuleDependencies(a.Dependencies),
+		MaxSeries:            a.MaxSeries,
 	}
 
 	if rule.Type() == models.RuleTypeRecording {
@@ -64,6 +67,8 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		Dependencies:         ApiRuleDependenciesFromModelRuleDependencies(rule.Dependencies),
+		MaxSeries:            rule.MaxSeries,
 	}
 }
 
@@ -87,6 +92,7 @@ func AlertQueriesFromApiAlertQueries(queries []definitions.AlertQuery) []models.
 				From: models.Duration(q.RelativeTimeRange.From),
 				To:   models.Duration(q.RelativeTimeRange.To),
 			},
+			TimeShift:     models.Duration(q.TimeShift),
 			DatasourceUID: q.DatasourceUID,
 			Model:         q.Model,
 		})
@@ -105,6 +111,7 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 				From: definitions.Duration(q.RelativeTimeRange.From),
 				To:   definitions.Duration(q.RelativeTimeRange.To),
 			},
+			TimeShift:     definitions.Duration(q.TimeShift),
 			DatasourceUID: q.DatasourceUID,
 			Model:         q.Model,
 		})
@@ -215,6 +222,7 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
 		Record:               AlertRuleRecordExportFromRecord(rule.Record),
+		Dependencies:         AlertRuleDependencyExportFromRuleDependencies(rule.Dependencies),
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
@@ -225,6 +233,9 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	if rule.Labels != nil {
 		result.Labels = &rule.Labels
 	}
+	if rule.MaxSeries > 0 {
+		result.MaxSeries = util.Pointer(rule.MaxSeries)
+	}
 	return result, nil
 }
 
@@ -251,6 +262,11 @@ func AlertQueryExportFromAlertQuery(query models.AlertQuery) (definitions.AlertQ
 	if query.QueryType != "" {
 		queryType = &query.QueryType
 	}
+	var timeShift *int64
+	if query.TimeShift != 0 {
+		seconds := int64(time.Duration(query.TimeShift).Seconds())
+		timeShift = &seconds
+	}
 
 	modelString, err := encodeQueryModel(mdl)
 	if err != nil {
@@ -264,9 +280,10 @@ func AlertQueryExportFromAlertQuery(query models.AlertQuery) (definitions.AlertQ
 			FromSeconds: int64(time.Duration(query.RelativeTimeRange.From).Seconds()),
 			ToSeconds:   int64(time.Duration(query.RelativeTimeRange.To).Seconds()),
 		},
-		DatasourceUID: query.DatasourceUID,
-		Model:         mdl,
-		ModelString:   modelString,
+		TimeShiftSeconds: timeShift,
+		DatasourceUID:    query.DatasourceUID,
+		Model:            mdl,
+		ModelString:      modelString,
 	}, nil
 }
 
@@ -576,3 +593,81 @@ func GettableApiReceiversFromReceivers(recvs []*models.Receiver) ([]*definitions
 	}
 	return out, nil
 }
+
+func ModelRuleDependenciesFromApiRuleDependencies(dependencies []definitions.RuleDependency) []models.RuleDependency {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	result := make([]models.RuleDependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		result = append(result, models.RuleDependency{
+			RuleUID: d.RuleUID,
+			State:   models.RuleDependencyState(d.State),
+		})
+	}
+	return result
+}
+
+func ApiRuleDependenciesFromModelRuleDependencies(dependencies []models.RuleDependency) []definitions.RuleDependency {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	result := make([]definitions.RuleDependency, 0, len(dependencies))
+	for _, d := range dependencies {
+		result = append(result, definitions.RuleDependency{
+			RuleUID: d.RuleUID,
+			State:   string(d.State),
+		})
+	}
+	return result
+}
+
+func AlertRuleDependencyExportFromRuleDependencies(dependencies []models.RuleDependency) []definitions.AlertRuleDependencyExport {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleDependencyExport, 0, len(dependencies))
+	for _, d := range dependencies {
+		result = append(result, definitions.AlertRuleDependencyExport{
+			RuleUID: d.RuleUID,
+			State:   string(d.State),
+		})
+	}
+	return result
+}
+
+// BacktestNotificationsFromNotifications converts []backtesting.Notification to []definitions.BacktestNotification
+func BacktestNotificationsFromNotifications(notifications []backtesting.Notification) []definitions.BacktestNotification {
+	result := make([]definitions.BacktestNotification, 0, len(notifications))
+	for _, n := range notifications {
+		result = append(result, definitions.BacktestNotification{
+			Time:        n.Time,
+			Receiver:    n.Receiver,
+			GroupKey:    n.GroupKey,
+			GroupLabels: n.GroupLabels,
+			Sent:        n.Sent,
+			MutedBy:     n.MutedBy,
+			Alerts:      backtestNotificationAlertsFromNotificationAlerts(n.Alerts),
+			Silenced:    backtestNotificationAlertsFromNotificationAlerts(n.Silenced),
+		})
+	}
+	return result
+}
+
+func backtestNotificationAlertsFromNotificationAlerts(alerts []backtesting.NotificationAlert) []definitions.BacktestNotificationAlert {
+	result := make([]definitions.BacktestNotificationAlert, 0, len(alerts))
+	for _, a := range alerts {
+		status := "firing"
+		if a.Resolved {
+			status = "resolved"
+		}
+		result = append(result, definitions.BacktestNotificationAlert{
+			Labels:     a.Labels,
+			Status:     status,
+			StartsAt:   a.StartsAt,
+			EndsAt:     a.EndsAt,
+			SilencedBy: a.SilencedBy,
+		})
+	}
+	return result
+}
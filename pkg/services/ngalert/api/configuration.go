@@ -36,3 +36,15 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
+
+func (f *ConfigurationApiHandler) handleRouteGetAlertingStateExport(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetAlertingStateExport(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostAlertingStateImport(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RoutePostAlertingStateImport(c)
+}
+
+func (f *ConfigurationApiHandler) handleRouteGetExpensiveRules(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetExpensiveRules(c)
+}
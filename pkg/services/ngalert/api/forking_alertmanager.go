@@ -190,6 +190,10 @@ func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contex
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplateSuite(ctx *contextmodel.ReqContext, conf apimodels.TemplateSuite) response.Response {
+	return f.GrafanaSvc.RoutePostTestTemplateSuite(ctx, conf)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaTemplates(ctx *contextmodel.ReqContext, conf apimodels.TestTemplatesConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestTemplates(ctx, conf)
 }
@@ -14,15 +14,18 @@ import (
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type NotificationsApi interface {
 	RouteGetReceiver(*contextmodel.ReqContext) response.Response
+	RouteGetReceiverFailedNotifications(*contextmodel.ReqContext) response.Response
 	RouteGetReceivers(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeInterval(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeIntervals(*contextmodel.ReqContext) response.Response
+	RoutePostReceiverReplayFailedNotifications(*contextmodel.ReqContext) response.Response
 }
 
 func (f *NotificationsApiHandler) RouteGetReceiver(ctx *contextmodel.ReqContext) response.Response {
@@ -30,6 +33,11 @@ func (f *NotificationsApiHandler) RouteGetReceiver(ctx *contextmodel.ReqContext)
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteGetReceiver(ctx, nameParam)
 }
+func (f *NotificationsApiHandler) RouteGetReceiverFailedNotifications(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteGetReceiverFailedNotifications(ctx, nameParam)
+}
 func (f *NotificationsApiHandler) RouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetReceivers(ctx)
 }
@@ -41,6 +49,16 @@ func (f *NotificationsApiHandler) RouteNotificationsGetTimeInterval(ctx *context
 func (f *NotificationsApiHandler) RouteNotificationsGetTimeIntervals(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteNotificationsGetTimeIntervals(ctx)
 }
+func (f *NotificationsApiHandler) RoutePostReceiverReplayFailedNotifications(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	// Parse Request Body
+	conf := apimodels.ReplayFailedNotificationsBody{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostReceiverReplayFailedNotifications(ctx, conf, nameParam)
+}
 
 func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -56,6 +74,18 @@ func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/notifications/receivers/{Name}/failed-notifications"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/receivers/{Name}/failed-notifications"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/receivers/{Name}/failed-notifications",
+				api.Hooks.Wrap(srv.RouteGetReceiverFailedNotifications),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/notifications/receivers"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -92,5 +122,17 @@ func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/notifications/receivers/{Name}/failed-notifications/replay"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/notifications/receivers/{Name}/failed-notifications/replay"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/notifications/receivers/{Name}/failed-notifications/replay",
+				api.Hooks.Wrap(srv.RoutePostReceiverReplayFailedNotifications),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
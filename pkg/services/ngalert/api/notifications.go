@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
 type NotificationsApiHandler struct {
@@ -30,3 +31,11 @@ func (f *NotificationsApiHandler) handleRouteGetReceiver(ctx *contextmodel.ReqCo
 func (f *NotificationsApiHandler) handleRouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.notificationSrv.RouteGetReceivers(ctx)
 }
+
+func (f *NotificationsApiHandler) handleRouteGetReceiverFailedNotifications(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.notificationSrv.RouteGetReceiverFailedNotifications(ctx, name)
+}
+
+func (f *NotificationsApiHandler) handleRoutePostReceiverReplayFailedNotifications(ctx *contextmodel.ReqContext, body apimodels.ReplayFailedNotificationsBody, name string) response.Response {
+	return f.notificationSrv.RoutePostReceiverReplayFailedNotifications(ctx, name, body)
+}
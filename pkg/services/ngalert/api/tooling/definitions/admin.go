@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -58,6 +60,71 @@ import (
 //       200: Ack
 //       500: Failure
 
+// swagger:route GET /v1/ngalert/state/export configuration RouteGetAlertingStateExport
+//
+// Exports the alert instances of all organizations and the silences and notification log of their Alertmanagers to a gzip compressed archive that can be imported into another Grafana instance.
+//
+//     Produces:
+//     - application/gzip
+//
+//     Responses:
+//       200: file
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/state/import configuration RoutePostAlertingStateImport
+//
+// Imports an archive created by the export endpoint. The state of every organization in the archive replaces its current state. Alert instances of rules that do not exist are skipped.
+// The import is refused when high availability is configured, because the other instances would keep their previous state. The decompressed archive cannot be larger than 256 MiB.
+//
+//     Consumes:
+//     - application/gzip
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertingStateImportResult
+//       400: ValidationError
+//       409: Failure
+//       413: Failure
+//       500: Failure
+
+// swagger:route GET /v1/ngalert/rules/expensive configuration RouteGetExpensiveRules
+//
+// Lists the rules of the user's organization with the most expensive last evaluation. Only rules evaluated by this Grafana instance are listed, the response is marked as partial when the evaluation of alert rules is sharded across the instances of the HA cluster.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: ExpensiveRules
+//       400: ValidationError
+
+// swagger:parameters RouteGetExpensiveRules
+type ExpensiveRulesParams struct {
+	// Maximum number of rules to return.
+	// in:query
+	// required: false
+	// default: 10
+	Limit int `json:"limit"`
+	// Measure of the last evaluation the rules are sorted by, in descending order.
+	// in:query
+	// required: false
+	// enum: duration,query,expression,series
+	// default: duration
+	SortBy ExpensiveRulesSortBy `json:"sortBy"`
+}
+
+// swagger:enum ExpensiveRulesSortBy
+type ExpensiveRulesSortBy string
+
+const (
+	ExpensiveRulesByDuration   ExpensiveRulesSortBy = "duration"
+	ExpensiveRulesByQuery      ExpensiveRulesSortBy = "query"
+	ExpensiveRulesByExpression ExpensiveRulesSortBy = "expression"
+	ExpensiveRulesBySeries     ExpensiveRulesSortBy = "series"
+)
+
 // swagger:parameters RoutePostNGalertConfig
 type NGalertConfig struct {
 	// in:body
@@ -95,3 +162,49 @@ type AlertingStatus struct {
 	AlertmanagersChoice      AlertmanagersChoice `json:"alertmanagersChoice"`
 	NumExternalAlertmanagers int                 `json:"numExternalAlertmanagers"`
 }
+
+// swagger:model
+type AlertingStateImportResult struct {
+	// Number of organizations whose state was imported.
+	Orgs int `json:"orgs"`
+	// Number of imported alert instances.
+	AlertInstances int `json:"alertInstances"`
+	// Number of alert instances that were skipped because their rule does not exist.
+	SkippedAlertInstances int `json:"skippedAlertInstances"`
+}
+
+// swagger:model
+type ExpensiveRules struct {
+	Rules []ExpensiveRule `json:"rules"`
+	// True when the evaluation of alert rules is sharded across the instances of the HA cluster. The cost of an
+	// evaluation is only known by the instance that evaluates the rule, so the rules evaluated by other instances
+	// are missing.
+	Partial bool `json:"partial"`
+}
+
+// swagger:model
+type ExpensiveRule struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// enum: alerting,recording
+	Type   string `json:"type"`
+	Health string `json:"health"`
+	// Error of the last evaluation, including the error returned when the rule exceeded its series limit.
+	LastError      string    `json:"lastError,omitempty"`
+	LastEvaluation time.Time `json:"lastEvaluation"`
+	// Duration in seconds of the last evaluation.
+	EvaluationTime float64 `json:"evaluationTime"`
+	// Time in seconds spent querying data sources during the last evaluation.
+	QueryTime float64 `json:"queryTime"`
+	// Time in seconds spent executing server-side expressions during the last evaluation.
+	ExpressionTime float64 `json:"expressionTime"`
+	// Number of series produced by the last evaluation.
+	SeriesCount int `json:"seriesCount"`
+	// Number of series the rule can produce before it is paused. 0 means unlimited.
+	MaxSeries int64 `json:"maxSeries,omitempty"`
+	// True when the rule exceeded its series limit and is not evaluated until it is updated. The pause is lifted
+	// when Grafana restarts.
+	SeriesLimitExceeded bool `json:"seriesLimitExceeded,omitempty"`
+}
@@ -178,6 +178,19 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /alertmanager/grafana/config/api/v1/templates/test-suite alertmanager RoutePostTestGrafanaTemplateSuite
+//
+// Test a set of Grafana managed templates against fixture alerts for each integration type and compare the results with the expected output.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: TemplateSuiteResults
+//       400: ValidationError
+//       403: PermissionDenied
+//       409: AlertManagerNotReady
+
 // swagger:route GET /alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -366,6 +379,91 @@ type TestTemplatesErrorResult struct {
 	Message string `json:"message"`
 }
 
+// swagger:parameters RoutePostTestGrafanaTemplateSuite
+type TemplateSuiteParams struct {
+	// in:body
+	Body TemplateSuite
+}
+
+type TemplateSuite struct {
+	// Templates to test. Their definitions take precedence over the ones of the saved templates.
+	Templates []TemplateSuiteTemplate `json:"templates"`
+
+	// Integrations whose templated fields are rendered for every fixture. Defaults to slack, email and webhook with their default fields.
+	Integrations []TemplateSuiteIntegration `json:"integrations,omitempty"`
+
+	// Fixtures to render the templates against.
+	Fixtures []TemplateSuiteFixture `json:"fixtures"`
+}
+
+type TemplateSuiteTemplate struct {
+	// Name of the template file.
+	Name string `json:"name"`
+
+	// Template string to test.
+	Template string `json:"template"`
+}
+
+type TemplateSuiteIntegration struct {
+	// Type of the integration, one of slack, email or webhook.
+	Type string `json:"type"`
+
+	// Fields maps the templated fields of the integration to the template used to render them. Missing fields use the default template of the integration.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+type TemplateSuiteFixture struct {
+	// Name of the fixture.
+	Name string `json:"name"`
+
+	// Alerts to use as data when rendering the templates.
+	Alerts []*amv2.PostableAlert `json:"alerts"`
+
+	// Expected output of template definitions, keyed by the name of the definition.
+	Expected map[string]string `json:"expected,omitempty"`
+
+	// Expected output of integration fields, keyed by integration type and field name.
+	ExpectedFields map[string]map[string]string `json:"expectedFields,omitempty"`
+}
+
+// swagger:model
+type TemplateSuiteResults struct {
+	// Number of results that are not ok.
+	Failed  int                   `json:"failed"`
+	Results []TemplateSuiteResult `json:"results"`
+}
+
+type TemplateSuiteResult struct {
+	// Name of the fixture used to render the template. Empty for errors that do not depend on the fixture.
+	Fixture string `json:"fixture,omitempty"`
+
+	// Integration type of the rendered field. Empty for template definitions.
+	Integration string `json:"integration,omitempty"`
+
+	// Name of the template definition or of the integration field.
+	Name string `json:"name"`
+
+	Status TemplateSuiteStatus `json:"status"`
+
+	// Interpolated value of the template.
+	Text string `json:"text,omitempty"`
+
+	// Expected value of the template if the status is "mismatch".
+	Expected string `json:"expected,omitempty"`
+
+	// Error message if the template could not be rendered.
+	Error string `json:"error,omitempty"`
+}
+
+// swagger:enum TemplateSuiteStatus
+type TemplateSuiteStatus string
+
+const (
+	TemplateSuiteOK       TemplateSuiteStatus = "ok"
+	TemplateSuiteError    TemplateSuiteStatus = "error"
+	TemplateSuiteMismatch TemplateSuiteStatus = "mismatch"
+)
+
 // swagger:enum TemplateErrorKind
 type TemplateErrorKind string
 
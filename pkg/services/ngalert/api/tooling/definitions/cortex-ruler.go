@@ -48,6 +48,25 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route POST /ruler/grafana/api/v1/import/prometheus/{Namespace} ruler RouteImportPrometheusRules
+//
+// Converts Prometheus rule groups to Grafana-managed rules and creates or updates them in the folder
+//
+//     Consumes:
+//     - application/yaml
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: NotFound
+
 // swagger:route Get /ruler/{DatasourceUID}/api/v1/rules ruler RouteGetRulesConfig
 //
 // List rule groups
@@ -194,6 +213,39 @@ type NamespaceConfig struct {
 	Body PostableRuleGroupConfig
 }
 
+// swagger:parameters RouteImportPrometheusRules
+type ImportPrometheusRulesParams struct {
+	// The UID of the rule folder
+	// in:path
+	Namespace string
+	// The UID of the Prometheus or Loki data source that the imported rules query
+	// in:query
+	// required:true
+	DatasourceUID string `json:"datasource_uid"`
+	// If true, the converted rules are returned in the export format and nothing is saved
+	// in:query
+	// required:false
+	DryRun bool `json:"dry_run"`
+	// Prometheus rule file with rule groups
+	// in:body
+	Body PrometheusRulesFile
+}
+
+// PrometheusRulesFile is a rule file in the format used by Prometheus and Mimir.
+// swagger:model
+type PrometheusRulesFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// swagger:model
+type PrometheusRuleGroup struct {
+	Name        string          `yaml:"name" json:"name"`
+	Interval    model.Duration  `yaml:"interval,omitempty" json:"interval,omitempty"`
+	QueryOffset *model.Duration `yaml:"query_offset,omitempty" json:"query_offset,omitempty"`
+	Limit       int             `yaml:"limit,omitempty" json:"limit,omitempty"`
+	Rules       []ApiRuleNode   `yaml:"rules" json:"rules"`
+}
+
 // swagger:parameters RouteGetNamespaceRulesConfig RouteDeleteNamespaceRulesConfig RouteGetNamespaceGrafanaRulesConfig RouteDeleteNamespaceGrafanaRulesConfig
 type PathNamespaceConfig struct {
 	// The UID of the rule folder
@@ -519,6 +571,19 @@ type Record struct {
 	From string `json:"from" yaml:"from"`
 }
 
+// swagger:model
+type RuleDependency struct {
+	// UID of the parent rule of the same organization that the rule depends on.
+	// required: true
+	// example: datacenter-reachable
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+	// State the parent rule must be in for the rule to be evaluated. A rule is Normal only if all its alert
+	// instances are Normal, and Alerting if at least one of them is Alerting.
+	// required: true
+	// enum: Normal,Alerting
+	State string `json:"state" yaml:"state"`
+}
+
 // swagger:model
 type PostableGrafanaRule struct {
 	Title                string                         `json:"title" yaml:"title"`
@@ -530,6 +595,8 @@ type PostableGrafanaRule struct {
 	IsPaused             *bool                          `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
+	Dependencies         []RuleDependency               `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	MaxSeries            int64                          `json:"max_series,omitempty" yaml:"max_series,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
@@ -552,6 +619,8 @@ type GettableGrafanaRule struct {
 	IsPaused             bool                           `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
+	Dependencies         []RuleDependency               `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	MaxSeries            int64                          `json:"max_series,omitempty" yaml:"max_series,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
@@ -564,6 +633,9 @@ type AlertQuery struct {
 	QueryType string `json:"queryType"`
 	// RelativeTimeRange is the relative Start and End of the query as sent by the frontend.
 	RelativeTimeRange RelativeTimeRange `json:"relativeTimeRange"`
+	// TimeShift moves the relative time range of the query back, in seconds. The times of the results
+	// are moved forward by the same amount so they line up with the results of the other queries.
+	TimeShift Duration `json:"timeShift,omitempty"`
 
 	// Grafana data source unique identifier; it should be '__expr__' for a Server Side Expression operation.
 	DatasourceUID string `json:"datasourceUid"`
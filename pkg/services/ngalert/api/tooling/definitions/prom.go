@@ -159,9 +159,26 @@ type AlertingRule struct {
 	Alerts         []Alert          `json:"alerts,omitempty"`
 	Totals         map[string]int64 `json:"totals,omitempty"`
 	TotalsFiltered map[string]int64 `json:"totalsFiltered,omitempty"`
+	// Dependencies of the rule on the state of other rules. The rule is not evaluated while any of them is not met.
+	Dependencies []RuleDependencyStatus `json:"dependencies,omitempty"`
 	Rule
 }
 
+// RuleDependencyStatus is the current status of a dependency of a rule on the state of another rule.
+// swagger:model
+type RuleDependencyStatus struct {
+	// UID of the parent rule.
+	// required: true
+	RuleUID string `json:"ruleUid"`
+	// State the parent rule must be in for the rule to be evaluated.
+	// required: true
+	State string `json:"state"`
+	// Current state of the parent rule. It is empty if the parent rule has no state.
+	ParentState string `json:"parentState,omitempty"`
+	// required: true
+	Met bool `json:"met"`
+}
+
 // adapted from cortex
 // swagger:model
 type Rule struct {
@@ -177,6 +194,15 @@ type Rule struct {
 	Type           string    `json:"type"`
 	LastEvaluation time.Time `json:"lastEvaluation"`
 	EvaluationTime float64   `json:"evaluationTime"`
+	// Time in seconds spent querying data sources during the last evaluation.
+	QueryTime float64 `json:"queryTime,omitempty"`
+	// Time in seconds spent executing server-side expressions during the last evaluation.
+	ExpressionTime float64 `json:"expressionTime,omitempty"`
+	// Number of series produced by the last evaluation.
+	SeriesCount int `json:"seriesCount,omitempty"`
+	// True when the rule exceeded its series limit and is not evaluated until it is updated. The pause is lifted
+	// when Grafana restarts.
+	SeriesLimitExceeded bool `json:"seriesLimitExceeded,omitempty"`
 }
 
 // Alert has info for an alert.
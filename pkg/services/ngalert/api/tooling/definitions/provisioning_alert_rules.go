@@ -170,6 +170,11 @@ type ProvisionedAlertRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
 	//example: {"metric":"grafana_alerts_ratio", "from":"A"}
 	Record *Record `json:"record"`
+	// example: [{"rule_uid":"datacenter-reachable","state":"Normal"}]
+	Dependencies []RuleDependency `json:"dependencies,omitempty"`
+	// Number of series the rule can produce in a single evaluation before it is paused. 0 means unlimited.
+	// example: 1000
+	MaxSeries int64 `json:"max_series,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -277,6 +282,8 @@ type AlertRuleExport struct {
 	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	Dependencies         []AlertRuleDependencyExport          `json:"dependencies,omitempty" yaml:"dependencies,omitempty" hcl:"dependency,block"`
+	MaxSeries            *int64                               `json:"maxSeries,omitempty" yaml:"maxSeries,omitempty" hcl:"max_series"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -284,6 +291,7 @@ type AlertQueryExport struct {
 	RefID             string                  `json:"refId" yaml:"refId" hcl:"ref_id"`
 	QueryType         *string                 `json:"queryType,omitempty" yaml:"queryType,omitempty" hcl:"query_type"`
 	RelativeTimeRange RelativeTimeRangeExport `json:"relativeTimeRange,omitempty" yaml:"relativeTimeRange,omitempty" hcl:"relative_time_range,block"`
+	TimeShiftSeconds  *int64                  `json:"timeShift,omitempty" yaml:"timeShift,omitempty" hcl:"time_shift"`
 	DatasourceUID     string                  `json:"datasourceUid" yaml:"datasourceUid" hcl:"datasource_uid"`
 	Model             map[string]any          `json:"model" yaml:"model"`
 	ModelString       string                  `json:"-" yaml:"-" hcl:"model"`
@@ -311,3 +319,9 @@ type AlertRuleRecordExport struct {
 	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
 	From   string `json:"from" yaml:"from" hcl:"from"`
 }
+
+// AlertRuleDependencyExport is the provisioned export of models.RuleDependency.
+type AlertRuleDependencyExport struct {
+	RuleUID string `json:"rule_uid" yaml:"rule_uid" hcl:"rule_uid"`
+	State   string `json:"state" yaml:"state" hcl:"state"`
+}
@@ -1,5 +1,7 @@
 package definitions
 
+import "time"
+
 // swagger:route GET /v1/notifications/receivers/{Name} notifications RouteGetReceiver
 //
 // Get a receiver by name.
@@ -17,6 +19,28 @@ package definitions
 //      200: GetReceiversResponse
 //      403: PermissionDenied
 
+// swagger:route GET /v1/notifications/receivers/{Name}/failed-notifications notifications RouteGetReceiverFailedNotifications
+//
+// Get the notifications that the receiver failed to deliver. Pending notifications are retried, dead notifications
+// are not retried anymore unless they are replayed. Requires the notification queue to be enabled.
+//
+//    Responses:
+//      200: GetReceiverFailedNotificationsResponse
+//      400: ValidationError
+//      403: PermissionDenied
+//      404: NotFound
+
+// swagger:route POST /v1/notifications/receivers/{Name}/failed-notifications/replay notifications RoutePostReceiverReplayFailedNotifications
+//
+// Retry dead notifications of the receiver immediately. If no IDs are provided, all dead notifications are replayed.
+// Notifications that are still pending are not replayed.
+//
+//    Responses:
+//      200: ReplayFailedNotificationsResponse
+//      400: ValidationError
+//      403: PermissionDenied
+//      404: NotFound
+
 // swagger:parameters RouteGetReceiver
 type GetReceiverParams struct {
 	// in:path
@@ -54,3 +78,85 @@ type GetReceiversResponse struct {
 	// in:body
 	Body []GettableApiReceiver
 }
+
+// swagger:parameters RouteGetReceiverFailedNotifications
+type GetReceiverFailedNotificationsParams struct {
+	// in:path
+	// required: true
+	Name string `json:"name"`
+	// Filter by status, either pending or dead.
+	// in:query
+	// required: false
+	Status string `json:"status"`
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RoutePostReceiverReplayFailedNotifications
+type ReplayFailedNotificationsParams struct {
+	// in:path
+	// required: true
+	Name string `json:"name"`
+	// in:body
+	Body ReplayFailedNotificationsBody
+}
+
+// swagger:model
+type ReplayFailedNotificationsBody struct {
+	IDs []int64 `json:"ids,omitempty"`
+}
+
+// swagger:response GetReceiverFailedNotificationsResponse
+type GetReceiverFailedNotificationsResponse struct {
+	// in:body
+	Body GettableFailedNotifications
+}
+
+// swagger:response ReplayFailedNotificationsResponse
+type ReplayFailedNotificationsResponse struct {
+	// in:body
+	Body ReplayedFailedNotifications
+}
+
+// swagger:model
+type GettableFailedNotifications struct {
+	Health        NotificationQueueHealth `json:"health"`
+	Notifications []FailedNotification    `json:"notifications"`
+}
+
+// swagger:model
+type NotificationQueueHealth struct {
+	Pending       int64      `json:"pending"`
+	Dead          int64      `json:"dead"`
+	OldestPending *time.Time `json:"oldestPending,omitempty"`
+	LastAttempt   *time.Time `json:"lastAttempt,omitempty"`
+}
+
+// swagger:model
+type FailedNotification struct {
+	ID int64 `json:"id"`
+	// Integration is the type of the integration, for example webhook.
+	Integration      string                    `json:"integration"`
+	IntegrationIndex int                       `json:"integrationIndex"`
+	GroupKey         string                    `json:"groupKey"`
+	Status           string                    `json:"status"`
+	Attempts         int                       `json:"attempts"`
+	LastError        string                    `json:"lastError,omitempty"`
+	NextAttempt      *time.Time                `json:"nextAttempt,omitempty"`
+	Created          time.Time                 `json:"created"`
+	Updated          time.Time                 `json:"updated"`
+	Alerts           []FailedNotificationAlert `json:"alerts"`
+}
+
+// swagger:model
+type FailedNotificationAlert struct {
+	Labels   map[string]string `json:"labels"`
+	StartsAt time.Time         `json:"startsAt"`
+	EndsAt   time.Time         `json:"endsAt,omitempty"`
+}
+
+// swagger:model
+type ReplayedFailedNotifications struct {
+	Replayed int64 `json:"replayed"`
+}
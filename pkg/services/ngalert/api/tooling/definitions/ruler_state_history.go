@@ -9,6 +9,8 @@ import "github.com/grafana/grafana-plugin-sdk-go/data"
 // Allows to query alerting state history.
 // In addition to defined query parameters it accepts filter by labels. The query parameter name must start with 'labels_'
 //   Example: /v1/rules/history?labels_myKey1=myValue1&labels_myKey2=myValue2
+// Labels can also be filtered by matchers, which support regular expressions and negative matches.
+//   Example: /v1/rules/history?matcher={"name":"severity","value":"crit.*","isRegex":true,"isEqual":true}
 //
 //     Produces:
 //     - application/json
@@ -50,4 +52,9 @@ type StateHistoryParams struct {
 	DashboardUID string
 	// Filter by dashboard's panel ID. Requires Dashboard UID to be specified.
 	PanelID int64
+	// Filter by labels of alert instances. Each matcher is a JSON-encoded Alertmanager matcher with the fields name, value, isRegex and isEqual.
+	// Not supported when the state history is configured to use annotations for storage.
+	// in:query
+	// required: false
+	Matcher []string `json:"matcher"`
 }
@@ -28,6 +28,21 @@ import (
 //       400: ValidationError
 //       404: NotFound
 
+// swagger:route Post /v1/rule/test/grafana/diff testing RouteTestRuleGrafanaDiff
+//
+// Evaluate the current and a proposed version of a Grafana rule and compare their alert instances
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: TestGrafanaRuleDiffResponse
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:route Post /v1/rule/test/{DatasourceUID} testing RouteTestRuleConfig
 //
 // Test a rule against external data source ruler
@@ -100,6 +115,67 @@ type TestGrafanaRuleRequest struct {
 	Body PostableExtendedRuleNodeExtended
 }
 
+// swagger:parameters RouteTestRuleGrafanaDiff
+type TestGrafanaRuleDiffRequest struct {
+	// in:body
+	Body PostableExtendedRuleNodeExtended
+}
+
+// swagger:response TestGrafanaRuleDiffResponse
+type TestGrafanaRuleDiffResponse struct {
+	// in:body
+	Body RuleEvalDiff
+}
+
+// RuleEvalDiff compares the alert instances of the current and a proposed version of a rule evaluated at the same
+// time. The states are the states the ruler would set from the results, with the labels, the no data state and the
+// execution error state of each version applied, but without the pending period of the rule.
+//
+// swagger:model
+type RuleEvalDiff struct {
+	// EvaluatedAt is the time both versions of the rule were evaluated at.
+	EvaluatedAt time.Time `json:"evaluatedAt"`
+	// CurrentVersion is the version of the saved rule. It is 0 when the rule does not exist.
+	CurrentVersion int64             `json:"currentVersion"`
+	Summary        RuleEvalDiffStats `json:"summary"`
+	// Instances are the alert instances that appear, disappear or change state.
+	Instances []RuleEvalInstanceDiff `json:"instances"`
+}
+
+// swagger:model
+type RuleEvalDiffStats struct {
+	// Current is the number of alert instances of the current version.
+	Current int `json:"current"`
+	// Proposed is the number of alert instances of the proposed version.
+	Proposed  int `json:"proposed"`
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// swagger:enum RuleEvalInstanceChange
+type RuleEvalInstanceChange string
+
+const (
+	RuleEvalInstanceAdded   RuleEvalInstanceChange = "added"
+	RuleEvalInstanceRemoved RuleEvalInstanceChange = "removed"
+	RuleEvalInstanceChanged RuleEvalInstanceChange = "changed"
+)
+
+// swagger:model
+type RuleEvalInstanceDiff struct {
+	// example: {"instance": "server-1"}
+	Labels map[string]string      `json:"labels"`
+	Change RuleEvalInstanceChange `json:"change"`
+	// example: Normal
+	CurrentState string `json:"currentState,omitempty"`
+	// example: Alerting
+	ProposedState string `json:"proposedState,omitempty"`
+	// Error is the evaluation error of the proposed version for this instance.
+	Error string `json:"error,omitempty"`
+}
+
 // swagger:model
 type PostableExtendedRuleNodeExtended struct {
 	// required: true
@@ -230,7 +306,50 @@ type BacktestConfig struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 
 	NoDataState NoDataState `json:"no_data_state"`
+
+	// NamespaceUID is the UID of the folder of the rule. It is used to add the folder label to alerts when notifications are simulated.
+	NamespaceUID string `json:"namespace_uid,omitempty"`
+	// NotificationSettings are the simplified routing settings of the rule.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty"`
+	// SimulateNotifications enables simulation of the notifications that the Alertmanager of the organization would send for the alerts of the rule.
+	// If enabled, the response is BacktestNotificationsResult.
+	SimulateNotifications bool `json:"simulate_notifications,omitempty"`
 }
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:model
+type BacktestNotificationsResult struct {
+	// States is the same frame that is returned as BacktestResult.
+	States *data.Frame `json:"states"`
+	// Notifications is the timeline of notifications that the Alertmanager would have sent.
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// swagger:model
+type BacktestNotification struct {
+	// Time when the Alertmanager would have flushed the alert group.
+	Time        time.Time         `json:"time"`
+	Receiver    string            `json:"receiver"`
+	GroupKey    string            `json:"group_key"`
+	GroupLabels map[string]string `json:"group_labels"`
+	// Sent is false if the notification was suppressed by time intervals or silences.
+	Sent bool `json:"sent"`
+	// MutedBy contains names of the time intervals that muted the notification.
+	MutedBy []string `json:"muted_by,omitempty"`
+	// Alerts are the alerts of the group that are not silenced.
+	Alerts []BacktestNotificationAlert `json:"alerts"`
+	// Silenced are the alerts of the group that are silenced.
+	Silenced []BacktestNotificationAlert `json:"silenced,omitempty"`
+}
+
+// swagger:model
+type BacktestNotificationAlert struct {
+	Labels   map[string]string `json:"labels"`
+	Status   string            `json:"status"`
+	StartsAt time.Time         `json:"starts_at"`
+	EndsAt   time.Time         `json:"ends_at"`
+	// SilencedBy contains IDs of the silences that matched the alert.
+	SilencedBy []string `json:"silenced_by,omitempty"`
+}
@@ -42,11 +42,18 @@ type stateManager interface {
 type Engine struct {
 	evalFactory        eval.EvaluatorFactory
 	createStateManager func() stateManager
+	loadRoutingConfig  func(ctx context.Context, rule *models.AlertRule) (*RoutingConfig, error)
 }
 
-func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer) *Engine {
+func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracing.Tracer, configStore alertmanagerConfigStore, settingsStore notificationSettingsStore, silences silenceStore) *Engine {
+	routing := &routingConfigLoader{
+		configStore:   configStore,
+		settingsStore: settingsStore,
+		silences:      silences,
+	}
 	return &Engine{
-		evalFactory: evalFactory,
+		evalFactory:       evalFactory,
+		loadRoutingConfig: routing.Load,
 		createStateManager: func() stateManager {
 			cfg := state.ManagerCfg{
 				Metrics:       nil,
@@ -64,16 +71,47 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
-	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
-	logger := logger.FromContext(ctx)
+	return e.test(ctx, user, rule, from, to, nil, nil)
+}
+
+// TestNotifications tests the rule like Test, and replays the alerts through the notification policies, time intervals
+// and silences of the Alertmanager of the organization. In addition to the states, it returns the timeline of
+// notifications that the Alertmanager would have sent. extraLabels are added to the labels of alerts, like the scheduler does.
+func (e *Engine) TestNotifications(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels) (*data.Frame, []Notification, error) {
+	if _, err := evaluationsCount(rule, from, to); err != nil {
+		return nil, nil, err
+	}
+	routing, err := e.loadRoutingConfig(ctx, rule)
+	if err != nil {
+		return nil, nil, err
+	}
+	simulator := newNotificationSimulator(routing)
+	result, err := e.test(ctx, user, rule, from, to, extraLabels, simulator.Process)
+	if err != nil {
+		return nil, nil, err
+	}
+	simulator.FlushUntil(to)
+	return result, simulator.Notifications(), nil
+}
 
+func evaluationsCount(rule *models.AlertRule, from, to time.Time) (int, error) {
 	if !from.Before(to) {
-		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+		return 0, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
 	}
 	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
-		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+		return 0, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	}
+	return int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds), nil
+}
+
+func (e *Engine) test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels, onStates func(now time.Time, states state.StateTransitions)) (*data.Frame, error) {
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
+
+	length, err := evaluationsCount(rule, from, to)
+	if err != nil {
+		return nil, err
 	}
-	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
 
 	stateManager := e.createStateManager()
 
@@ -97,7 +135,10 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil, nil)
+		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, nil)
+		if onStates != nil {
+			onStates(currentTime, states)
+		}
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
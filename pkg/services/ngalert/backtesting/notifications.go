@@ -0,0 +1,310 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// NotificationAlert is an alert that is part of a simulated notification.
+type NotificationAlert struct {
+	Labels   data.Labels
+	StartsAt time.Time
+	EndsAt   time.Time
+	Resolved bool
+	// SilencedBy contains IDs of silences that matched the alert at the time of the notification.
+	SilencedBy []string
+}
+
+// Notification is a notification that the Alertmanager would have sent to a contact point for a group of alerts.
+type Notification struct {
+	Time        time.Time
+	Receiver    string
+	GroupKey    string
+	GroupLabels data.Labels
+	// Sent is false if the notification was suppressed by time intervals or silences.
+	Sent bool
+	// MutedBy contains names of the time intervals that muted the notification.
+	MutedBy []string
+	// Alerts are the alerts of the group that are not silenced.
+	Alerts []NotificationAlert
+	// Silenced are the alerts of the group that are silenced.
+	Silenced []NotificationAlert
+}
+
+type simulatedAlert struct {
+	labels      data.Labels
+	lset        model.LabelSet
+	fingerprint model.Fingerprint
+	startsAt    time.Time
+	endsAt      time.Time
+}
+
+func (a *simulatedAlert) resolved(now time.Time) bool {
+	return !a.endsAt.After(now)
+}
+
+type aggregationGroup struct {
+	key       string
+	route     *dispatch.Route
+	labels    data.Labels
+	alerts    map[model.Fingerprint]*simulatedAlert
+	nextFlush time.Time
+}
+
+type notificationLogEntry struct {
+	firing    map[model.Fingerprint]struct{}
+	resolved  map[model.Fingerprint]struct{}
+	timestamp time.Time
+}
+
+// notificationSimulator replays alerts through the routing tree of the Alertmanager. It mimics how the Alertmanager
+// dispatches alerts to aggregation groups, and how the notification pipeline mutes and deduplicates notifications.
+// Inhibition rules are not simulated.
+type notificationSimulator struct {
+	cfg        *RoutingConfig
+	intervener *timeinterval.Intervener
+	groups     map[string]*aggregationGroup
+	// log is the notification log keyed by receiver and group key. It outlives groups, like in the Alertmanager.
+	log           map[string]*notificationLogEntry
+	notifications []Notification
+}
+
+func newNotificationSimulator(cfg *RoutingConfig) *notificationSimulator {
+	return &notificationSimulator{
+		cfg:        cfg,
+		intervener: timeinterval.NewIntervener(cfg.TimeIntervals),
+		groups:     make(map[string]*aggregationGroup),
+		log:        make(map[string]*notificationLogEntry),
+	}
+}
+
+// Process flushes all alert groups that are due before the evaluation, and then dispatches the alerts that Grafana
+// sends to the Alertmanager after the evaluation.
+func (s *notificationSimulator) Process(now time.Time, transitions state.StateTransitions) {
+	s.FlushUntil(now)
+	for _, t := range transitions {
+		if !needsSending(now, t) {
+			continue
+		}
+		postable := state.StateToPostableAlert(t, nil)
+		lbls := data.Labels(postable.Labels)
+		lset := make(model.LabelSet, len(lbls))
+		for k, v := range lbls {
+			lset[model.LabelName(k)] = model.LabelValue(v)
+		}
+		alert := &simulatedAlert{
+			labels:      lbls,
+			lset:        lset,
+			fingerprint: lset.Fingerprint(),
+			startsAt:    time.Time(postable.StartsAt),
+			endsAt:      time.Time(postable.EndsAt),
+		}
+		for _, route := range s.cfg.Route.Match(lset) {
+			s.dispatch(now, route, alert)
+		}
+	}
+}
+
+// FlushUntil flushes all alert groups that are due at or before the given time, in chronological order.
+func (s *notificationSimulator) FlushUntil(until time.Time) {
+	for {
+		var next *aggregationGroup
+		for _, g := range s.groups {
+			if g.nextFlush.After(until) {
+				continue
+			}
+			if next == nil || g.nextFlush.Before(next.nextFlush) || (g.nextFlush.Equal(next.nextFlush) && g.key < next.key) {
+				next = g
+			}
+		}
+		if next == nil {
+			return
+		}
+		s.flush(next)
+	}
+}
+
+// Notifications returns the timeline of notifications.
+func (s *notificationSimulator) Notifications() []Notification {
+	return s.notifications
+}
+
+func (s *notificationSimulator) dispatch(now time.Time, route *dispatch.Route, alert *simulatedAlert) {
+	groupLabels := getGroupLabels(alert.lset, route)
+	key := route.Key() + ":" + groupLabels.String()
+	g, ok := s.groups[key]
+	if !ok {
+		// Resolved alerts that are not known to the group do not create a new group.
+		if alert.resolved(now) {
+			return
+		}
+		g = &aggregationGroup{
+			key:       key,
+			route:     route,
+			labels:    make(data.Labels, len(groupLabels)),
+			alerts:    make(map[model.Fingerprint]*simulatedAlert),
+			nextFlush: now.Add(route.RouteOpts.GroupWait),
+		}
+		for k, v := range groupLabels {
+			g.labels[string(k)] = string(v)
+		}
+		s.groups[key] = g
+	}
+	a := *alert
+	g.alerts[alert.fingerprint] = &a
+}
+
+func (s *notificationSimulator) flush(g *aggregationGroup) {
+	now := g.nextFlush
+	opts := g.route.RouteOpts
+
+	var mutedBy []string
+	for _, name := range opts.MuteTimeIntervals {
+		if muted, _ := s.intervener.Mutes([]string{name}, now); muted {
+			mutedBy = append(mutedBy, name)
+		}
+	}
+	if len(opts.ActiveTimeIntervals) > 0 {
+		if active, _ := s.intervener.Mutes(opts.ActiveTimeIntervals, now); !active {
+			mutedBy = append(mutedBy, opts.ActiveTimeIntervals...)
+		}
+	}
+
+	alerts := make([]*simulatedAlert, 0, len(g.alerts))
+	for _, a := range g.alerts {
+		alerts = append(alerts, a)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].labels.String() < alerts[j].labels.String()
+	})
+
+	var notified, silenced []NotificationAlert
+	allFiring, allResolved := map[model.Fingerprint]struct{}{}, map[model.Fingerprint]struct{}{}
+	firing, resolved := map[model.Fingerprint]struct{}{}, map[model.Fingerprint]struct{}{}
+	for _, a := range alerts {
+		isResolved := a.resolved(now)
+		silencedBy := s.silencedBy(a.lset, now)
+		n := NotificationAlert{
+			Labels:     a.labels,
+			StartsAt:   a.startsAt,
+			EndsAt:     a.endsAt,
+			Resolved:   isResolved,
+			SilencedBy: silencedBy,
+		}
+		all, notifiable := allFiring, firing
+		if isResolved {
+			all, notifiable = allResolved, resolved
+		}
+		all[a.fingerprint] = struct{}{}
+		if len(silencedBy) > 0 {
+			silenced = append(silenced, n)
+			continue
+		}
+		notifiable[a.fingerprint] = struct{}{}
+		notified = append(notified, n)
+	}
+
+	logKey := opts.Receiver + "/" + g.key
+	entry := s.log[logKey]
+	shouldNotify := needsUpdate(entry, firing, resolved, opts.RepeatInterval, now)
+	// Report notifications that would be sent if there were no silences to show their effect.
+	if shouldNotify || needsUpdate(entry, allFiring, allResolved, opts.RepeatInterval, now) {
+		sent := shouldNotify && len(mutedBy) == 0
+		s.notifications = append(s.notifications, Notification{
+			Time:        now,
+			Receiver:    opts.Receiver,
+			GroupKey:    g.key,
+			GroupLabels: g.labels,
+			Sent:        sent,
+			MutedBy:     mutedBy,
+			Alerts:      notified,
+			Silenced:    silenced,
+		})
+		if sent {
+			s.log[logKey] = &notificationLogEntry{firing: firing, resolved: resolved, timestamp: now}
+		}
+	}
+
+	// The Alertmanager removes resolved alerts from the group after the group is flushed, and the group itself once it has no alerts.
+	for fp, a := range g.alerts {
+		if a.resolved(now) {
+			delete(g.alerts, fp)
+		}
+	}
+	if len(g.alerts) == 0 {
+		delete(s.groups, g.key)
+		return
+	}
+	interval := opts.GroupInterval
+	if interval <= 0 {
+		interval = dispatch.DefaultRouteOpts.GroupInterval
+	}
+	g.nextFlush = now.Add(interval)
+}
+
+func (s *notificationSimulator) silencedBy(lset model.LabelSet, now time.Time) []string {
+	var result []string
+	for _, silence := range s.cfg.Silences {
+		if !silence.StartsAt.After(now) && silence.EndsAt.After(now) && silence.Matchers.Matches(lset) {
+			result = append(result, silence.ID)
+		}
+	}
+	return result
+}
+
+// needsSending returns true if Grafana sends the alert of the state transition to the Alertmanager after the evaluation.
+func needsSending(now time.Time, t state.StateTransition) bool {
+	switch t.State.State {
+	case eval.Alerting, eval.NoData, eval.Error:
+		return true
+	case eval.Normal:
+		return t.State.ResolvedAt != nil && t.State.ResolvedAt.Equal(now)
+	default:
+		return false
+	}
+}
+
+// needsUpdate follows the logic of the deduplication stage of the notification pipeline of the Alertmanager.
+// It assumes that the contact point sends notifications about resolved alerts.
+func needsUpdate(entry *notificationLogEntry, firing, resolved map[model.Fingerprint]struct{}, repeat time.Duration, now time.Time) bool {
+	if entry == nil {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, entry.firing) {
+		return true
+	}
+	if len(firing) == 0 {
+		return len(entry.firing) > 0
+	}
+	if !isSubset(resolved, entry.resolved) {
+		return true
+	}
+	return entry.timestamp.Before(now.Add(-repeat))
+}
+
+func isSubset(set, of map[model.Fingerprint]struct{}) bool {
+	for fp := range set {
+		if _, ok := of[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func getGroupLabels(lset model.LabelSet, route *dispatch.Route) model.LabelSet {
+	groupLabels := model.LabelSet{}
+	for ln, lv := range lset {
+		if _, ok := route.RouteOpts.GroupBy[ln]; ok || route.RouteOpts.GroupByAll {
+			groupLabels[ln] = lv
+		}
+	}
+	return groupLabels
+}
@@ -0,0 +1,174 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestNotificationSimulator(t *testing.T) {
+	evalInterval := time.Minute
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newRoute := func(muteTimeIntervals ...string) *dispatch.Route {
+		groupWait := model.Duration(30 * time.Second)
+		groupInterval := model.Duration(5 * time.Minute)
+		repeatInterval := model.Duration(time.Hour)
+		return dispatch.NewRoute(&config.Route{
+			Receiver:          "default",
+			GroupBy:           []model.LabelName{"alertname"},
+			GroupWait:         &groupWait,
+			GroupInterval:     &groupInterval,
+			RepeatInterval:    &repeatInterval,
+			MuteTimeIntervals: muteTimeIntervals,
+		}, nil)
+	}
+
+	firing := func(now time.Time, series string) state.StateTransition {
+		return state.StateTransition{
+			State: &state.State{
+				State:    eval.Alerting,
+				Labels:   data.Labels{"alertname": "test", "series": series},
+				StartsAt: now,
+				EndsAt:   now.Add(4 * evalInterval),
+			},
+			PreviousState: eval.Alerting,
+		}
+	}
+
+	resolved := func(now time.Time, series string) state.StateTransition {
+		return state.StateTransition{
+			State: &state.State{
+				State:      eval.Normal,
+				Labels:     data.Labels{"alertname": "test", "series": series},
+				StartsAt:   start,
+				EndsAt:     now,
+				ResolvedAt: &now,
+			},
+			PreviousState: eval.Alerting,
+		}
+	}
+
+	pending := func(now time.Time, series string) state.StateTransition {
+		return state.StateTransition{
+			State: &state.State{
+				State:  eval.Pending,
+				Labels: data.Labels{"alertname": "test", "series": series},
+			},
+			PreviousState: eval.Normal,
+		}
+	}
+
+	// run evaluates the rule every minute for 15 minutes. The series 1 fires from the start and resolves at the 10th minute,
+	// the series 2 fires from the 2nd minute till the end, and the series 3 is always pending.
+	run := func(cfg *RoutingConfig) []Notification {
+		simulator := newNotificationSimulator(cfg)
+		end := start.Add(15 * evalInterval)
+		for now := start; now.Before(end); now = now.Add(evalInterval) {
+			transitions := state.StateTransitions{pending(now, "3")}
+			if now.Before(start.Add(10 * evalInterval)) {
+				transitions = append(transitions, firing(now, "1"))
+			} else {
+				transitions = append(transitions, resolved(now, "1"))
+			}
+			if !now.Before(start.Add(2 * evalInterval)) {
+				transitions = append(transitions, firing(now, "2"))
+			}
+			simulator.Process(now, transitions)
+		}
+		simulator.FlushUntil(end)
+		return simulator.Notifications()
+	}
+
+	alertSeries := func(alerts []NotificationAlert) []string {
+		result := make([]string, 0, len(alerts))
+		for _, a := range alerts {
+			s := a.Labels["series"]
+			if a.Resolved {
+				s += " resolved"
+			}
+			result = append(result, s)
+		}
+		return result
+	}
+
+	t.Run("should group alerts and deduplicate notifications", func(t *testing.T) {
+		notifications := run(&RoutingConfig{Route: newRoute()})
+
+		require.Len(t, notifications, 3)
+		for _, n := range notifications {
+			require.True(t, n.Sent)
+			require.Equal(t, "default", n.Receiver)
+			require.Equal(t, data.Labels{"alertname": "test"}, n.GroupLabels)
+			require.Empty(t, n.Silenced)
+		}
+
+		// The first notification is sent after group wait and contains only the series 1.
+		require.Equal(t, start.Add(30*time.Second), notifications[0].Time)
+		require.Equal(t, []string{"1"}, alertSeries(notifications[0].Alerts))
+
+		// The next notification is sent after group interval because the series 2 started firing.
+		require.Equal(t, start.Add(5*time.Minute+30*time.Second), notifications[1].Time)
+		require.Equal(t, []string{"1", "2"}, alertSeries(notifications[1].Alerts))
+
+		// The series 1 is resolved at the 10th minute.
+		require.Equal(t, start.Add(10*time.Minute+30*time.Second), notifications[2].Time)
+		require.Equal(t, []string{"1 resolved", "2"}, alertSeries(notifications[2].Alerts))
+	})
+
+	t.Run("should mute notifications by time intervals", func(t *testing.T) {
+		notifications := run(&RoutingConfig{
+			Route: newRoute("always"),
+			TimeIntervals: map[string][]timeinterval.TimeInterval{
+				"always": {{}},
+			},
+		})
+
+		require.Len(t, notifications, 3)
+		for _, n := range notifications {
+			require.False(t, n.Sent)
+			require.Equal(t, []string{"always"}, n.MutedBy)
+		}
+	})
+
+	t.Run("should not send notifications for silenced alerts", func(t *testing.T) {
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "series", "2")
+		require.NoError(t, err)
+		notifications := run(&RoutingConfig{
+			Route: newRoute(),
+			Silences: []Silence{
+				{
+					ID:       "silence-1",
+					Matchers: labels.Matchers{matcher},
+					StartsAt: start,
+					EndsAt:   start.Add(time.Hour),
+				},
+			},
+		})
+
+		require.Len(t, notifications, 3)
+
+		require.True(t, notifications[0].Sent)
+		require.Equal(t, []string{"1"}, alertSeries(notifications[0].Alerts))
+
+		// The series 2 would trigger a notification if it was not silenced.
+		require.False(t, notifications[1].Sent)
+		require.Equal(t, []string{"1"}, alertSeries(notifications[1].Alerts))
+		require.Equal(t, []string{"2"}, alertSeries(notifications[1].Silenced))
+		require.Equal(t, []string{"silence-1"}, notifications[1].Silenced[0].SilencedBy)
+
+		require.True(t, notifications[2].Sent)
+		require.Equal(t, []string{"1 resolved"}, alertSeries(notifications[2].Alerts))
+		require.Equal(t, []string{"2"}, alertSeries(notifications[2].Silenced))
+	})
+}
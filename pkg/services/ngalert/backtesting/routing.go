@@ -0,0 +1,162 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+// RoutingConfig is the part of the Alertmanager configuration of an organization that determines which notifications
+// are sent for alerts.
+type RoutingConfig struct {
+	Route         *dispatch.Route
+	TimeIntervals map[string][]timeinterval.TimeInterval
+	Silences      []Silence
+}
+
+// Silence is a silence of the Alertmanager.
+type Silence struct {
+	ID       string
+	Matchers labels.Matchers
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+type alertmanagerConfigStore interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, orgID int64) (*models.AlertConfiguration, error)
+}
+
+type notificationSettingsStore interface {
+	ListNotificationSettings(ctx context.Context, q models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error)
+}
+
+type silenceStore interface {
+	ListSilences(ctx context.Context, orgID int64, filter []string) ([]*models.Silence, error)
+}
+
+// routingConfigLoader builds the routing configuration from the latest Alertmanager configuration of the organization,
+// including the routes that are autogenerated from notification settings of alert rules, and the silences of the
+// Alertmanager.
+type routingConfigLoader struct {
+	configStore   alertmanagerConfigStore
+	settingsStore notificationSettingsStore
+	silences      silenceStore
+}
+
+func (l *routingConfigLoader) Load(ctx context.Context, rule *models.AlertRule) (*RoutingConfig, error) {
+	amConfig, err := l.configStore.GetLatestAlertmanagerConfiguration(ctx, rule.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest Alertmanager configuration: %w", err)
+	}
+	cfg, err := notifier.Load([]byte(amConfig.AlertmanagerConfiguration))
+	if err != nil {
+		return nil, err
+	}
+
+	validator := notifier.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
+	for _, s := range rule.NotificationSettings {
+		if err := validator.Validate(s); err != nil {
+			return nil, errors.Join(ErrInvalidInputData, err)
+		}
+	}
+	store := &withRuleNotificationSettings{notificationSettingsStore: l.settingsStore, rule: rule}
+	if err := notifier.AddAutogenConfig(ctx, logger, store, rule.OrgID, &cfg.AlertmanagerConfig, true); err != nil {
+		return nil, fmt.Errorf("failed to add autogenerated routes: %w", err)
+	}
+
+	intervals := make(map[string][]timeinterval.TimeInterval, len(cfg.AlertmanagerConfig.MuteTimeIntervals)+len(cfg.AlertmanagerConfig.TimeIntervals))
+	for _, ti := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+	for _, ti := range cfg.AlertmanagerConfig.TimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+
+	silences, err := l.silences.ListSilences(ctx, rule.OrgID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	result := &RoutingConfig{
+		Route:         dispatch.NewRoute(cfg.AlertmanagerConfig.Route.AsAMRoute(), nil),
+		TimeIntervals: intervals,
+		Silences:      make([]Silence, 0, len(silences)),
+	}
+	for _, s := range silences {
+		silence, err := silenceFromModel(s)
+		if err != nil {
+			logger.FromContext(ctx).Warn("Failed to parse silence. Skipping", "error", err)
+			continue
+		}
+		result.Silences = append(result.Silences, silence)
+	}
+	return result, nil
+}
+
+// withRuleNotificationSettings adds the notification settings of the tested rule to the stored ones, so the
+// autogenerated routes include a route for the rule even if no other rule uses the same settings.
+type withRuleNotificationSettings struct {
+	notificationSettingsStore
+	rule *models.AlertRule
+}
+
+func (s *withRuleNotificationSettings) ListNotificationSettings(ctx context.Context, q models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error) {
+	result, err := s.notificationSettingsStore.ListNotificationSettings(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.rule.NotificationSettings) == 0 {
+		return result, nil
+	}
+	if result == nil {
+		result = make(map[models.AlertRuleKey][]models.NotificationSettings, 1)
+	}
+	result[s.rule.GetKey()] = s.rule.NotificationSettings
+	return result, nil
+}
+
+func silenceFromModel(s *models.Silence) (Silence, error) {
+	if s.ID == nil || s.StartsAt == nil || s.EndsAt == nil {
+		return Silence{}, errors.New("silence must have ID, start and end time")
+	}
+	matchers := make(labels.Matchers, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		matcher, err := matcherFromModel(m)
+		if err != nil {
+			return Silence{}, fmt.Errorf("invalid matcher in silence %s: %w", *s.ID, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return Silence{
+		ID:       *s.ID,
+		Matchers: matchers,
+		StartsAt: time.Time(*s.StartsAt),
+		EndsAt:   time.Time(*s.EndsAt),
+	}, nil
+}
+
+func matcherFromModel(m *amv2.Matcher) (*labels.Matcher, error) {
+	if m == nil || m.Name == nil || m.Value == nil {
+		return nil, errors.New("matcher must have name and value")
+	}
+	isEqual := m.IsEqual == nil || *m.IsEqual
+	isRegex := m.IsRegex != nil && *m.IsRegex
+	t := labels.MatchEqual
+	switch {
+	case isEqual && isRegex:
+		t = labels.MatchRegexp
+	case !isEqual && isRegex:
+		t = labels.MatchNotRegexp
+	case !isEqual:
+		t = labels.MatchNotEqual
+	}
+	return labels.NewMatcher(t, *m.Name, *m.Value)
+}
@@ -0,0 +1,45 @@
+package backtesting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSilenceFromModel(t *testing.T) {
+	startsAt := strfmt.DateTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	endsAt := strfmt.DateTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	silence := &models.Silence{
+		ID: util.Pointer("silence-1"),
+		Silence: amv2.Silence{
+			StartsAt: &startsAt,
+			EndsAt:   &endsAt,
+			Matchers: amv2.Matchers{
+				{Name: util.Pointer("team"), Value: util.Pointer("a"), IsEqual: util.Pointer(true), IsRegex: util.Pointer(false)},
+				{Name: util.Pointer("env"), Value: util.Pointer("prod|staging"), IsRegex: util.Pointer(true)},
+				{Name: util.Pointer("region"), Value: util.Pointer("eu"), IsEqual: util.Pointer(false), IsRegex: util.Pointer(false)},
+			},
+		},
+	}
+
+	s, err := silenceFromModel(silence)
+	require.NoError(t, err)
+	require.Equal(t, "silence-1", s.ID)
+	require.Equal(t, time.Time(startsAt), s.StartsAt)
+	require.Equal(t, time.Time(endsAt), s.EndsAt)
+
+	require.True(t, s.Matchers.Matches(model.LabelSet{"team": "a", "env": "prod", "region": "us"}))
+	require.False(t, s.Matchers.Matches(model.LabelSet{"team": "a", "env": "dev", "region": "us"}))
+	require.False(t, s.Matchers.Matches(model.LabelSet{"team": "a", "env": "prod", "region": "eu"}))
+
+	silence.Matchers = append(silence.Matchers, &amv2.Matcher{Name: util.Pointer("team")})
+	_, err = silenceFromModel(silence)
+	require.ErrorContains(t, err, "invalid matcher in silence silence-1")
+}
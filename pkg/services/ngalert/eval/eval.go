@@ -26,6 +26,12 @@ import (
 
 var logger = log.New("ngalert.eval")
 
+// ErrSeriesLimitExceeded is returned when an evaluation produces more series than the rule allows.
+var ErrSeriesLimitExceeded = errors.New("series limit exceeded")
+
+// ErrRuleDependencyParentMissing is returned when a rule depends on a rule that does not exist.
+var ErrRuleDependencyParentMissing = errors.New("parent rule of a dependency is missing")
+
 type EvaluatorFactory interface {
 	// Create builds an evaluator pipeline ready to evaluate a rule's query
 	Create(ctx EvaluationContext, condition models.Condition) (ConditionEvaluator, error)
@@ -192,7 +198,8 @@ func (evalResults Results) HasNonRetryableErrors() bool {
 }
 
 // IsNonRetryableError indicates whether an error is considered persistent and not worth performing evaluation retries.
-// Currently it is true if err is `&invalidEvalResultFormatError` or `ErrSeriesMustBeWide`
+// Currently it is true if err is `&invalidEvalResultFormatError`, `ErrSeriesMustBeWide`, `ErrSeriesLimitExceeded`
+// or `ErrRuleDependencyParentMissing`
 func IsNonRetryableError(err error) bool {
 	var nonRetryableError *invalidEvalResultFormatError
 	if errors.As(err, &nonRetryableError) {
@@ -201,6 +208,12 @@ func IsNonRetryableError(err error) bool {
 	if errors.Is(err, expr.ErrSeriesMustBeWide) {
 		return true
 	}
+	if errors.Is(err, ErrSeriesLimitExceeded) {
+		return true
+	}
+	if errors.Is(err, ErrRuleDependencyParentMissing) {
+		return true
+	}
 	return false
 }
 
@@ -415,6 +428,7 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 
 		req.Queries = append(req.Queries, expr.Query{
 			TimeRange:     q.RelativeTimeRange.ToTimeRange(),
+			TimeShift:     time.Duration(q.TimeShift),
 			DataSource:    ds,
 			JSON:          model,
 			Interval:      interval,
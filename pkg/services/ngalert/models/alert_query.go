@@ -90,6 +90,10 @@ type AlertQuery struct {
 	// RelativeTimeRange is the relative Start and End of the query as sent by the frontend.
 	RelativeTimeRange RelativeTimeRange `json:"relativeTimeRange"`
 
+	// TimeShift moves the relative time range of the query back, for example to compare with the same time last week.
+	// The times of the results are moved forward by the same amount so they line up with the results of the other queries.
+	TimeShift Duration `json:"timeShift,omitempty"`
+
 	// Grafana data source unique identifier; it should be '__expr__' for a Server Side Expression operation.
 	DatasourceUID string `json:"datasourceUid"`
 
@@ -305,5 +309,11 @@ func (aq *AlertQuery) PreSave() error {
 	if ok := isExpression || aq.RelativeTimeRange.isValid(); !ok {
 		return ErrInvalidRelativeTimeRange(aq.RefID, aq.RelativeTimeRange)
 	}
+	if aq.TimeShift < 0 {
+		return ErrInvalidTimeShift(aq.RefID, fmt.Sprintf("time shift %s must not be negative", aq.TimeShift))
+	}
+	if isExpression && aq.TimeShift != 0 {
+		return ErrInvalidTimeShift(aq.RefID, "time shift can only be set on data source queries")
+	}
 	return nil
 }
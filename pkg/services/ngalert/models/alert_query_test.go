@@ -355,6 +355,41 @@ func TestAlertQuery_PreSave(t *testing.T) {
 			}`,
 			errContains: "Invalid alert rule query B: invalid relative time range [From: 16m40s, To: 16m40s]",
 		},
+		{
+			desc: "no error when time shift is set on a query",
+			blob: `{
+				"refId": "B",
+				"relativeTimeRange": {
+					"from": 2000,
+					"to": 1000
+				},
+				"timeShift": 604800,
+				"model": {}
+			}`,
+		},
+		{
+			desc: "expected error when time shift is negative",
+			blob: `{
+				"refId": "B",
+				"relativeTimeRange": {
+					"from": 2000,
+					"to": 1000
+				},
+				"timeShift": -60,
+				"model": {}
+			}`,
+			errContains: "Invalid alert rule query B: time shift -1m0s must not be negative",
+		},
+		{
+			desc: "expected error when time shift is set on an expression",
+			blob: `{
+				"refId": "C",
+				"datasourceUid": "__expr__",
+				"timeShift": 60,
+				"model": {"type": "math", "expression": "$B"}
+			}`,
+			errContains: "Invalid alert rule query C: time shift can only be set on data source queries",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -157,13 +157,14 @@ const (
 )
 
 const (
-	StateReasonMissingSeries = "MissingSeries"
-	StateReasonNoData        = "NoData"
-	StateReasonError         = "Error"
-	StateReasonPaused        = "Paused"
-	StateReasonUpdated       = "Updated"
-	StateReasonRuleDeleted   = "RuleDeleted"
-	StateReasonKeepLast      = "KeepLast"
+	StateReasonMissingSeries    = "MissingSeries"
+	StateReasonNoData           = "NoData"
+	StateReasonError            = "Error"
+	StateReasonPaused           = "Paused"
+	StateReasonUpdated          = "Updated"
+	StateReasonRuleDeleted      = "RuleDeleted"
+	StateReasonKeepLast         = "KeepLast"
+	StateReasonDependencyNotMet = "DependencyNotMet"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -269,7 +270,12 @@ type AlertRule struct {
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings
-	Metadata             AlertRuleMetadata
+	Dependencies         []RuleDependency
+	// MaxSeries is the number of series the rule can produce in a single evaluation before it is paused. 0 means unlimited.
+	// The pause is kept in memory by the instance that evaluates the rule, it is lifted when the rule is updated or
+	// Grafana restarts.
+	MaxSeries int64
+	Metadata  AlertRuleMetadata
 }
 
 type AlertRuleMetadata struct {
@@ -599,6 +605,14 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 			return errors.Join(ErrAlertRuleFailedValidation, fmt.Errorf("invalid notification settings: %w", err))
 		}
 	}
+
+	if err := validateRuleDependencies(alertRule); err != nil {
+		return err
+	}
+
+	if alertRule.MaxSeries < 0 {
+		return fmt.Errorf("%w: max series must be greater than or equal to 0", ErrAlertRuleFailedValidation)
+	}
 	return nil
 }
 
@@ -660,6 +674,7 @@ func ClearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.Condition = ""
 	rule.For = 0
 	rule.NotificationSettings = nil
+	rule.Dependencies = nil
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -695,6 +710,10 @@ type ListAlertRulesQuery struct {
 
 	ReceiverName     string
 	TimeIntervalName string
+
+	// DependsOnRuleUIDs is optional and allows filtering rules
+	// to return just those that depend on one of the rules.
+	DependsOnRuleUIDs []string
 }
 
 // CountAlertRulesQuery is the query for counting alert rules
@@ -905,4 +924,18 @@ type RuleStatus struct {
 	LastError           error
 	EvaluationTimestamp time.Time
 	EvaluationDuration  time.Duration
+	Cost                EvaluationCost
+	// SeriesLimitExceeded is set when the rule exceeded its series limit and is not evaluated until it is updated.
+	// The pause is kept in memory by the instance that evaluates the rule, it is lifted when Grafana restarts.
+	SeriesLimitExceeded bool
+}
+
+// EvaluationCost describes the resources used by the last evaluation of a rule.
+type EvaluationCost struct {
+	// QueryDuration is the time spent querying data sources.
+	QueryDuration time.Duration
+	// ExpressionDuration is the time spent executing server-side expressions.
+	ExpressionDuration time.Duration
+	// SeriesCount is the number of series produced by the evaluation.
+	SeriesCount int
 }
@@ -385,6 +385,38 @@ func TestPatchPartialAlertRule(t *testing.T) {
 	})
 }
 
+func TestCopyRule(t *testing.T) {
+	dashboardUID := "dashboard"
+	panelID := int64(1)
+	rule := RuleGen.With(
+		RuleMuts.WithLabel("label", "value"),
+		RuleMuts.WithAnnotation("annotation", "value"),
+		RuleMuts.WithGroupIndex(2),
+		RuleMuts.WithDashboardAndPanel(&dashboardUID, &panelID),
+		RuleMuts.WithNotificationSettings(NotificationSettings{Receiver: "receiver"}),
+		RuleMuts.WithDependencies(RuleDependency{RuleUID: "parent", State: RuleDependencyStateNormal}),
+		RuleMuts.WithMaxSeries(100),
+		RuleMuts.WithFor(time.Minute),
+		RuleMuts.WithIsPaused(true),
+		RuleMuts.WithEditorSettingsSimplifiedNotificationsSection(true),
+	).GenerateRef()
+	rule.ID = 1
+	rule.Record = &Record{From: "A", Metric: "metric"}
+
+	// every field is set, so that a field that is not copied makes the copy differ from the rule.
+	v := reflect.ValueOf(*rule)
+	for i := 0; i < v.NumField(); i++ {
+		require.Falsef(t, v.Field(i).IsZero(), "field %s of the rule should be set", v.Type().Field(i).Name)
+	}
+
+	copied := CopyRule(rule)
+	require.Equal(t, rule, copied)
+	require.NotSame(t, rule.DashboardUID, copied.DashboardUID)
+	require.NotSame(t, rule.Record, copied.Record)
+	copied.Labels["copied"] = "true"
+	require.NotContains(t, rule.Labels, "copied")
+}
+
 func TestDiff(t *testing.T) {
 	t.Run("should return nil if there is no diff", func(t *testing.T) {
 		rule1 := RuleGen.GenerateRef()
@@ -702,6 +734,7 @@ func TestDiff(t *testing.T) {
 			query2.QueryType = "test"
 			query2.RefID = "test"
 			query2.DatasourceUID = "test"
+			query2.TimeShift = Duration(time.Hour)
 			query2.Model = json.RawMessage(`{ "test": "da2ta"}`)
 
 			rule2.Data = []AlertQuery{query2}
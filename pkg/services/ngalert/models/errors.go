@@ -15,6 +15,7 @@ var (
 					MustTemplate(errAlertRuleConflictMsgVerbose, errutil.WithPublic(errAlertRuleConflictMsgVerbose))
 	ErrAlertRuleGroupNotFound       = errutil.NotFound("alerting.alert-rule.notFound")
 	ErrInvalidRelativeTimeRangeBase = errutil.BadRequest("alerting.alert-rule.invalidRelativeTime").MustTemplate("Invalid alert rule query {{ .Public.RefID }}: invalid relative time range [From: {{ .Public.From }}, To: {{ .Public.To }}]")
+	ErrInvalidTimeShiftBase         = errutil.BadRequest("alerting.alert-rule.invalidTimeShift").MustTemplate("Invalid alert rule query {{ .Public.RefID }}: {{ .Public.Reason }}")
 	ErrConditionNotExistBase        = errutil.BadRequest("alerting.alert-rule.conditionNotExist").MustTemplate("Condition {{ .Public.Given }} does not exist, must be one of {{ .Public.Existing }}")
 )
 
@@ -42,6 +43,10 @@ func ErrInvalidRelativeTimeRange(refID string, rtr RelativeTimeRange) error {
 	return ErrInvalidRelativeTimeRangeBase.Build(errutil.TemplateData{Public: map[string]any{"RefID": refID, "From": rtr.From, "To": rtr.To}})
 }
 
+func ErrInvalidTimeShift(refID string, reason string) error {
+	return ErrInvalidTimeShiftBase.Build(errutil.TemplateData{Public: map[string]any{"RefID": refID, "Reason": reason}})
+}
+
 func ErrConditionNotExist(given string, existing []string) error {
 	return ErrConditionNotExistBase.Build(errutil.TemplateData{Public: map[string]any{"Given": given, "Existing": fmt.Sprintf("%v", existing)}})
 }
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
+
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 )
 
@@ -13,6 +15,8 @@ type HistoryQuery struct {
 	DashboardUID string
 	PanelID      int64
 	Labels       map[string]string
+	// Matchers filters the history by labels of alert instances. Unlike Labels, it supports regular expressions and negative matches.
+	Matchers     labels.Matchers
 	From         time.Time
 	To           time.Time
 	Limit        int
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrQueuedNotificationNotFound is returned when the queued notification does not exist.
+var ErrQueuedNotificationNotFound = errors.New("queued notification not found")
+
+// QueuedNotificationStatus is the status of a notification in the notification queue.
+type QueuedNotificationStatus string
+
+const (
+	// QueuedNotificationPending is the status of a notification that is retried.
+	QueuedNotificationPending QueuedNotificationStatus = "pending"
+	// QueuedNotificationDead is the status of a notification that is not retried anymore, either because it failed too
+	// many times or because the error is not retryable. It can be replayed.
+	QueuedNotificationDead QueuedNotificationStatus = "dead"
+)
+
+// QueuedNotification is a notification that an integration of a receiver failed to deliver.
+type QueuedNotification struct {
+	ID    int64
+	OrgID int64
+	// Receiver is the name of the receiver.
+	Receiver string
+	// IntegrationType and IntegrationIndex identify the integration of the receiver. The index is the position of the
+	// integration among the integrations of the same type.
+	IntegrationType  string
+	IntegrationIndex int
+	GroupKey         string
+	// Payload contains the alerts and the notification context needed to send the notification again.
+	Payload       []byte
+	Status        QueuedNotificationStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// ListQueuedNotificationsQuery is the query for notifications in the notification queue of a receiver.
+type ListQueuedNotificationsQuery struct {
+	OrgID    int64
+	Receiver string
+	// Status filters notifications by status. Empty returns notifications with all statuses.
+	Status QueuedNotificationStatus
+	Limit  int
+}
+
+// ReplayQueuedNotificationsCmd schedules notifications of a receiver to be retried immediately.
+type ReplayQueuedNotificationsCmd struct {
+	OrgID    int64
+	Receiver string
+	// IDs are the dead notifications to replay, other notifications are ignored. If empty, all dead notifications of the
+	// receiver are replayed.
+	IDs []int64
+	Now time.Time
+}
+
+// NotificationQueueHealth summarizes the notifications of a receiver in the notification queue.
+type NotificationQueueHealth struct {
+	Pending int64
+	Dead    int64
+	// OldestPending is when the oldest pending notification was queued. It is zero if there are no pending notifications.
+	OldestPending time.Time
+	// LastAttempt is the time of the latest failed attempt to deliver a queued notification.
+	LastAttempt time.Time
+}
+
+// Healthy returns true if there are no failed notifications.
+func (h NotificationQueueHealth) Healthy() bool {
+	return h.Pending == 0 && h.Dead == 0
+}
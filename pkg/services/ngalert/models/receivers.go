@@ -15,6 +15,7 @@ import (
 	alertingNotify "github.com/grafana/alerting/notify"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
 )
 
 // GetReceiverQuery represents a query for a single receiver.
@@ -544,6 +545,10 @@ func ValidateIntegration(ctx context.Context, integration alertingNotify.Grafana
 		return fmt.Errorf("settings should not be empty")
 	}
 
+	if integrations.IsSupported(integration.Type) {
+		return integrations.Validate(ctx, &integration, decryptFunc)
+	}
+
 	_, err := alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
 		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
 			Integrations: []*alertingNotify.GrafanaIntegrationConfig{&integration},
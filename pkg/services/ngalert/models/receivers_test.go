@@ -1,6 +1,8 @@
 package models
 
 import (
+	"context"
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -408,3 +410,17 @@ func TestReceiver_Fingerprint(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateIntegration_GrafanaIntegrations(t *testing.T) {
+	valid := alertingNotify.GrafanaIntegrationConfig{
+		UID:      "uid",
+		Name:     "matrix",
+		Type:     "matrix",
+		Settings: json.RawMessage(`{"homeserver_url": "https://matrix.example.com", "room_id": "!room:example.com", "access_token": "token"}`),
+	}
+	require.NoError(t, ValidateIntegration(context.Background(), valid, alertingNotify.NoopDecrypt))
+
+	invalid := valid
+	invalid.Settings = json.RawMessage(`{"homeserver_url": "https://matrix.example.com", "access_token": "token"}`)
+	require.ErrorContains(t, ValidateIntegration(context.Background(), invalid, alertingNotify.NoopDecrypt), "could not find room_id property in settings")
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"unsafe"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// RuleDependencyState is the state that the parent rule of a RuleDependency must be in.
+type RuleDependencyState string
+
+const (
+	// RuleDependencyStateNormal is met when all alert instances of the parent rule are Normal.
+	RuleDependencyStateNormal RuleDependencyState = "Normal"
+	// RuleDependencyStateAlerting is met when at least one alert instance of the parent rule is Alerting.
+	RuleDependencyStateAlerting RuleDependencyState = "Alerting"
+)
+
+func RuleDependencyStateFromString(state string) (RuleDependencyState, error) {
+	switch state {
+	case string(RuleDependencyStateNormal):
+		return RuleDependencyStateNormal, nil
+	case string(RuleDependencyStateAlerting):
+		return RuleDependencyStateAlerting, nil
+	default:
+		return "", fmt.Errorf("unknown rule dependency state '%s', must be one of %s or %s", state, RuleDependencyStateNormal, RuleDependencyStateAlerting)
+	}
+}
+
+// RuleDependency makes the evaluation of an alert rule conditional on the state of another alert rule of the same
+// organization, called the parent rule. For example, the rules that check the services of a data center can depend
+// on the rule that checks that the data center is reachable being Normal, so that an outage of the data center
+// fires a single alert instead of one for every service. While a dependency is not met, the rule is not evaluated
+// and its alerts are resolved. A dependency on a parent rule that has no state yet is not met, and a rule whose
+// parent rule does not exist is evaluated to an error. A rule that other rules depend on cannot be deleted.
+type RuleDependency struct {
+	// RuleUID is the UID of the parent rule.
+	RuleUID string `json:"rule_uid"`
+	// State is the state the parent rule must be in for the rule to be evaluated.
+	State RuleDependencyState `json:"state"`
+}
+
+func (d RuleDependency) Validate() error {
+	if d.RuleUID == "" {
+		return errors.New("UID of the parent rule must not be empty")
+	}
+	if _, err := RuleDependencyStateFromString(string(d.State)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d RuleDependency) Fingerprint() data.Fingerprint {
+	h := fnv.New64()
+
+	writeString := func(s string) {
+		// save on extra slice allocation when string is converted to bytes.
+		_, _ = h.Write(unsafe.Slice(unsafe.StringData(s), len(s))) //nolint:gosec
+		// ignore errors returned by Write method because fnv never returns them.
+		_, _ = h.Write([]byte{255}) // use an invalid utf-8 sequence as separator
+	}
+
+	writeString(d.RuleUID)
+	writeString(string(d.State))
+	return data.Fingerprint(h.Sum64())
+}
+
+// validateRuleDependencies checks that the dependencies of the rule are valid, that there is at most one dependency
+// on every parent rule and that the rule does not depend on itself.
+func validateRuleDependencies(rule *AlertRule) error {
+	parents := make(map[string]struct{}, len(rule.Dependencies))
+	for _, d := range rule.Dependencies {
+		if err := d.Validate(); err != nil {
+			return errors.Join(ErrAlertRuleFailedValidation, fmt.Errorf("invalid dependency: %w", err))
+		}
+		if rule.UID != "" && d.RuleUID == rule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := parents[d.RuleUID]; ok {
+			return fmt.Errorf("%w: rule depends on rule %s more than once", ErrAlertRuleFailedValidation, d.RuleUID)
+		}
+		parents[d.RuleUID] = struct{}{}
+	}
+	return nil
+}
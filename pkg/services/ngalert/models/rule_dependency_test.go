@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleDependencyStateFromString(t *testing.T) {
+	s, err := RuleDependencyStateFromString("Normal")
+	require.NoError(t, err)
+	require.Equal(t, RuleDependencyStateNormal, s)
+
+	s, err = RuleDependencyStateFromString("Alerting")
+	require.NoError(t, err)
+	require.Equal(t, RuleDependencyStateAlerting, s)
+
+	_, err = RuleDependencyStateFromString("Pending")
+	require.ErrorContains(t, err, "unknown rule dependency state 'Pending'")
+}
+
+func TestValidateRuleDependencies(t *testing.T) {
+	testCases := []struct {
+		name         string
+		dependencies []RuleDependency
+		expectedErr  string
+	}{
+		{
+			name: "valid dependencies",
+			dependencies: []RuleDependency{
+				{RuleUID: "parent-1", State: RuleDependencyStateNormal},
+				{RuleUID: "parent-2", State: RuleDependencyStateAlerting},
+			},
+		},
+		{
+			name:         "missing parent UID",
+			dependencies: []RuleDependency{{State: RuleDependencyStateNormal}},
+			expectedErr:  "UID of the parent rule must not be empty",
+		},
+		{
+			name:         "invalid state",
+			dependencies: []RuleDependency{{RuleUID: "parent-1", State: "NoData"}},
+			expectedErr:  "unknown rule dependency state 'NoData'",
+		},
+		{
+			name:         "dependency on itself",
+			dependencies: []RuleDependency{{RuleUID: "rule", State: RuleDependencyStateNormal}},
+			expectedErr:  "rule cannot depend on itself",
+		},
+		{
+			name: "duplicate parent",
+			dependencies: []RuleDependency{
+				{RuleUID: "parent-1", State: RuleDependencyStateNormal},
+				{RuleUID: "parent-1", State: RuleDependencyStateAlerting},
+			},
+			expectedErr: "rule depends on rule parent-1 more than once",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateRuleDependencies(&AlertRule{UID: "rule", Dependencies: tc.dependencies})
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestRuleDependencyFingerprint(t *testing.T) {
+	d := RuleDependency{RuleUID: "parent", State: RuleDependencyStateNormal}
+	require.Equal(t, d.Fingerprint(), RuleDependency{RuleUID: "parent", State: RuleDependencyStateNormal}.Fingerprint())
+	require.NotEqual(t, d.Fingerprint(), RuleDependency{RuleUID: "parent", State: RuleDependencyStateAlerting}.Fingerprint())
+	require.NotEqual(t, d.Fingerprint(), RuleDependency{RuleUID: "parent-2", State: RuleDependencyStateNormal}.Fingerprint())
+}
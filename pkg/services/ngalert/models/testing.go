@@ -499,6 +499,18 @@ func (a *AlertRuleMutators) WithNoNotificationSettings() AlertRuleMutator {
 	}
 }
 
+func (a *AlertRuleMutators) WithDependencies(dependencies ...RuleDependency) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Dependencies = dependencies
+	}
+}
+
+func (a *AlertRuleMutators) WithMaxSeries(maxSeries int64) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.MaxSeries = maxSeries
+	}
+}
+
 func (a *AlertRuleMutators) WithIsPaused(paused bool) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.IsPaused = paused
@@ -629,6 +641,8 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		For:             r.For,
 		Record:          r.Record,
 		IsPaused:        r.IsPaused,
+		MaxSeries:       r.MaxSeries,
+		Metadata:        r.Metadata,
 	}
 
 	if r.DashboardUID != nil {
@@ -645,6 +659,7 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 			RefID:             d.RefID,
 			QueryType:         d.QueryType,
 			RelativeTimeRange: d.RelativeTimeRange,
+			TimeShift:         d.TimeShift,
 			DatasourceUID:     d.DatasourceUID,
 		}
 		q.Model = make([]byte, 0, cap(d.Model))
@@ -677,6 +692,11 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	if r.Dependencies != nil {
+		result.Dependencies = make([]RuleDependency, len(r.Dependencies))
+		copy(result.Dependencies, r.Dependencies)
+	}
+
 	if len(mutators) > 0 {
 		for _, mutator := range mutators {
 			mutator(&result)
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/alerting/receivers"
 	alertingTemplates "github.com/grafana/alerting/templates"
@@ -20,6 +21,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/setting"
@@ -36,6 +38,7 @@ const (
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.NotificationQueueStore
 	autogenRuleStore
 }
 
@@ -60,6 +63,9 @@ type alertmanager struct {
 	orgID     int64
 
 	withAutogen bool
+
+	// queue retries notifications that integrations failed to deliver. It is nil if the notification queue is disabled.
+	queue *notificationQueue
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -155,6 +161,11 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		withAutogen: withAutogen,
 	}
 
+	if cfg.UnifiedAlerting.NotificationQueue.Enabled {
+		am.queue = newNotificationQueue(orgID, store, cfg.UnifiedAlerting.NotificationQueue, clock.New(), l.New("component", "notification-queue"))
+		am.queue.run()
+	}
+
 	return am, nil
 }
 
@@ -167,6 +178,9 @@ func (am *alertmanager) Ready() bool {
 
 func (am *alertmanager) StopAndWait() {
 	am.Base.StopAndWait()
+	if am.queue != nil {
+		am.queue.stopAndWait()
+	}
 }
 
 // SaveAndApplyDefaultConfig saves the default configuration to the database and applies it to the Alertmanager.
@@ -342,6 +356,18 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 	}
 
 	am.logger.Info("Applying new configuration to Alertmanager", "configHash", fmt.Sprintf("%x", configHash))
+	receiverIntegrationsFunc := am.buildReceiverIntegrations
+	var queued *queuedIntegrations
+	if am.queue != nil {
+		queued = newQueuedIntegrations()
+		receiverIntegrationsFunc = func(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+			integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+			if err != nil {
+				return nil, err
+			}
+			return am.queue.wrapIntegrations(queued, receiver.Name, integrations), nil
+		}
+	}
 	err = am.Base.ApplyConfig(AlertingConfiguration{
 		rawAlertmanagerConfig:    rawConfig,
 		configHash:               configHash,
@@ -351,11 +377,14 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 		timeIntervals:            cfg.AlertmanagerConfig.TimeIntervals,
 		templates:                ToTemplateDefinitions(cfg),
 		receivers:                PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig),
-		receiverIntegrationsFunc: am.buildReceiverIntegrations,
+		receiverIntegrationsFunc: receiverIntegrationsFunc,
 	})
 	if err != nil {
 		return false, err
 	}
+	if am.queue != nil {
+		am.queue.setIntegrations(queued)
+	}
 
 	am.updateConfigMetrics(cfg, len(rawConfig))
 	return true, nil
@@ -385,13 +414,14 @@ func (am *alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+	receiver, grafanaIntegrations := integrations.Split(receiver)
 	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
 	s := &sender{am.NotificationService}
 	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
-	integrations, err := alertingNotify.BuildReceiverIntegrations(
+	result, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
 		tmpl,
 		img,
@@ -408,7 +438,14 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
-	return integrations, nil
+	if len(grafanaIntegrations) == 0 {
+		return result, nil
+	}
+	built, err := integrations.BuildIntegrations(context.Background(), grafanaIntegrations, am.decryptFn, tmpl, s, LoggerFactory)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, built...), nil
 }
 
 // PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
@@ -1699,6 +1699,259 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{ // Since Grafana 11.5
+			Type:        "jira",
+			Name:        "Jira",
+			Description: "Creates Jira issues for firing alerts and transitions them when the alerts are resolved",
+			Heading:     "Jira settings",
+			Info:        "One issue is created per alert group. The issue is labelled with a key derived from the group, which is used to find it again.",
+			Options: []NotifierOption{
+				{
+					Label:        "URL",
+					Description:  "The URL of the Jira instance.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://example.atlassian.net",
+					PropertyName: "api_url",
+					Required:     true,
+				},
+				{
+					Label:        "Project",
+					Description:  "The key of the project in which issues are created.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "OPS",
+					PropertyName: "project",
+					Required:     true,
+				},
+				{
+					Label:        "Issue type",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "Bug",
+					PropertyName: "issue_type",
+				},
+				{
+					Label:        "Username",
+					Description:  "The user, or the email address of the user in Jira Cloud. Required if no API token is provided.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "user",
+				},
+				{
+					Label:        "Password",
+					Description:  "The password, or the API token of the user in Jira Cloud.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Personal access token",
+					Description:  "A personal access token of Jira Data Center, used instead of the username and password.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "api_token",
+					Secure:       true,
+				},
+				{
+					Label:        "Summary",
+					Description:  "Templated summary of the issue.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+					PropertyName: "summary",
+				},
+				{
+					Label:        "Description",
+					Description:  "Templated description of the issue.",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					PropertyName: "description",
+				},
+				{
+					Label:        "Labels",
+					Description:  "Comma-separated labels added to the issue.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "labels",
+				},
+				{
+					Label:        "Priority",
+					Description:  "Templated name of the priority of the issue.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "priority",
+				},
+				{
+					Label:        "Resolve transition",
+					Description:  "The name of the transition applied to the issue when the alerts are resolved.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "Done",
+					PropertyName: "resolve_transition",
+				},
+			},
+		},
+		{ // Since Grafana 11.5
+			Type:        "servicenow",
+			Name:        "ServiceNow",
+			Description: "Creates ServiceNow incidents for firing alerts and resolves them when the alerts are resolved",
+			Heading:     "ServiceNow settings",
+			Info:        "One incident is created per alert group. The correlation ID of the incident is derived from the group and is used to find it again.",
+			Options: []NotifierOption{
+				{
+					Label:        "URL",
+					Description:  "The URL of the ServiceNow instance.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://example.service-now.com",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "user",
+					Required:     true,
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:        "Table",
+					Description:  "The table in which incidents are created.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "incident",
+					PropertyName: "table",
+				},
+				{
+					Label:        "Short description",
+					Description:  "Templated short description of the incident.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+					PropertyName: "short_description",
+				},
+				{
+					Label:        "Description",
+					Description:  "Templated description of the incident.",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					PropertyName: "description",
+				},
+				{
+					Label:        "Assignment group",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "assignment_group",
+				},
+				{
+					Label:        "Caller",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "caller_id",
+				},
+				{
+					Label:        "Impact",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "2",
+					PropertyName: "impact",
+				},
+				{
+					Label:        "Urgency",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "2",
+					PropertyName: "urgency",
+				},
+				{
+					Label:        "Resolve state",
+					Description:  "The state of the incident when the alerts are resolved.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "6",
+					PropertyName: "resolve_state",
+				},
+				{
+					Label:        "Close code",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "Solution provided",
+					PropertyName: "close_code",
+				},
+				{
+					Label:        "Close notes",
+					Description:  "Templated notes added to the incident when the alerts are resolved.",
+					Element:      ElementTypeTextArea,
+					Placeholder:  "The alerts were resolved in Grafana.",
+					PropertyName: "close_notes",
+				},
+			},
+		},
+		{ // Since Grafana 11.5
+			Type:        "matrix",
+			Name:        "Matrix",
+			Description: "Sends notifications to a Matrix room",
+			Heading:     "Matrix settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Homeserver URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://matrix.example.com",
+					PropertyName: "homeserver_url",
+					Required:     true,
+				},
+				{
+					Label:        "Room ID",
+					Description:  "The ID of the room, which the user of the access token must have joined.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "!abcdefgh:example.com",
+					PropertyName: "room_id",
+					Required:     true,
+				},
+				{
+					Label:        "Access token",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "access_token",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:   "Message type",
+					Element: ElementTypeSelect,
+					SelectOptions: []SelectOption{
+						{
+							Value: "m.text",
+							Label: "Text",
+						},
+						{
+							Value: "m.notice",
+							Label: "Notice",
+						},
+					},
+					PropertyName: "msgtype",
+				},
+				{
+					Label:        "Message",
+					Description:  "Templated message.",
+					Element:      ElementTypeTextArea,
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+					PropertyName: "message",
+				},
+			},
+		},
 	}
 }
 
@@ -32,6 +32,9 @@ func TestGetSecretKeysForContactPointType(t *testing.T) {
 		{receiverType: "opsgenie", expectedSecretFields: []string{"apiKey"}},
 		{receiverType: "webex", expectedSecretFields: []string{"bot_token"}},
 		{receiverType: "sns", expectedSecretFields: []string{"sigv4.access_key", "sigv4.secret_key"}},
+		{receiverType: "jira", expectedSecretFields: []string{"password", "api_token"}},
+		{receiverType: "servicenow", expectedSecretFields: []string{"password"}},
+		{receiverType: "matrix", expectedSecretFields: []string{"access_token"}},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.receiverType, func(t *testing.T) {
@@ -90,6 +90,83 @@ func (fileStore *FileStore) persist(ctx context.Context, filename string, st ale
 	return int64(len(bytes)), err
 }
 
+// AlertmanagerState is the persisted state of the Alertmanager of an organization.
+type AlertmanagerState struct {
+	// Silences is the binary snapshot of the silences.
+	Silences []byte
+	// NotificationLog is the binary snapshot of the notification log.
+	NotificationLog []byte
+}
+
+// rawState is a snapshot of Alertmanager state that is already in its binary representation.
+type rawState []byte
+
+func (s rawState) MarshalBinary() ([]byte, error) {
+	return s, nil
+}
+
+// SaveState saves the given snapshots to the database. Empty snapshots are not saved.
+func (fileStore *FileStore) SaveState(ctx context.Context, st AlertmanagerState) error {
+	if len(st.Silences) > 0 {
+		if _, err := fileStore.persist(ctx, SilencesFilename, rawState(st.Silences)); err != nil {
+			return fmt.Errorf("failed to save silences: %w", err)
+		}
+	}
+	if len(st.NotificationLog) > 0 {
+		if _, err := fileStore.persist(ctx, NotificationLogFilename, rawState(st.NotificationLog)); err != nil {
+			return fmt.Errorf("failed to save notification log: %w", err)
+		}
+	}
+	return nil
+}
+
+// AlertmanagerStateStore reads and writes the persisted state of the Alertmanagers of all organizations
+// without running them. It is meant to be used when the Alertmanagers are not running, for example from the CLI.
+type AlertmanagerStateStore struct {
+	kv kvstore.KVStore
+}
+
+func NewAlertmanagerStateStore(kv kvstore.KVStore) *AlertmanagerStateStore {
+	return &AlertmanagerStateStore{kv: kv}
+}
+
+// GetAlertmanagerStates returns the persisted state of the Alertmanagers of all organizations.
+func (s *AlertmanagerStateStore) GetAlertmanagerStates(ctx context.Context) (map[int64]AlertmanagerState, error) {
+	all, err := s.kv.GetAll(ctx, kvstore.AllOrganizations, KVNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Alertmanager state from database: %w", err)
+	}
+	result := make(map[int64]AlertmanagerState, len(all))
+	for orgID, files := range all {
+		var st AlertmanagerState
+		for filename, content := range files {
+			var target *[]byte
+			switch filename {
+			case SilencesFilename:
+				target = &st.Silences
+			case NotificationLogFilename:
+				target = &st.NotificationLog
+			default:
+				continue
+			}
+			b, err := decode(content)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding file '%s' of organization %d: %w", filename, orgID, err)
+			}
+			*target = b
+		}
+		if len(st.Silences) > 0 || len(st.NotificationLog) > 0 {
+			result[orgID] = st
+		}
+	}
+	return result, nil
+}
+
+// RestoreAlertmanagerState replaces the persisted state of the Alertmanager of the organization.
+func (s *AlertmanagerStateStore) RestoreAlertmanagerState(ctx context.Context, orgID int64, st AlertmanagerState) error {
+	return NewFileStore(orgID, s.kv).SaveState(ctx, st)
+}
+
 func decode(s string) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(s)
 }
@@ -0,0 +1,146 @@
+// Package integrations contains contact point integrations that are implemented in Grafana rather than in the
+// alerting package. The Alertmanager builds them next to the integrations of the alerting package.
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations/jira"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations/matrix"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/integrations/servicenow"
+)
+
+const (
+	JiraType       = "jira"
+	ServiceNowType = "servicenow"
+	MatrixType     = "matrix"
+)
+
+type notificationChannel interface {
+	notify.Notifier
+	notify.ResolvedSender
+}
+
+// IsSupported returns true if integrations of the given type are implemented in this package.
+func IsSupported(integrationType string) bool {
+	switch strings.ToLower(integrationType) {
+	case JiraType, ServiceNowType, MatrixType:
+		return true
+	}
+	return false
+}
+
+// Split returns a copy of the receiver without the integrations that are implemented in this package, and these
+// integrations.
+func Split(receiver *alertingNotify.APIReceiver) (*alertingNotify.APIReceiver, []*alertingNotify.GrafanaIntegrationConfig) {
+	var supported []*alertingNotify.GrafanaIntegrationConfig
+	others := make([]*alertingNotify.GrafanaIntegrationConfig, 0, len(receiver.Integrations))
+	for _, integration := range receiver.Integrations {
+		if IsSupported(integration.Type) {
+			supported = append(supported, integration)
+			continue
+		}
+		others = append(others, integration)
+	}
+	if len(supported) == 0 {
+		return receiver, nil
+	}
+	result := *receiver
+	result.Integrations = others
+	return &result, supported
+}
+
+// Validate returns an error if the settings of the integration are not valid.
+func Validate(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) error {
+	if _, err := parseConfig(ctx, integration, decrypt); err != nil {
+		return alertingNotify.IntegrationValidationError{Integration: integration, Err: err}
+	}
+	return nil
+}
+
+// BuildIntegrations builds the integrations from their configuration. All of them must be supported by this package.
+func BuildIntegrations(
+	ctx context.Context,
+	configs []*alertingNotify.GrafanaIntegrationConfig,
+	decrypt alertingNotify.GetDecryptedValueFn,
+	tmpl *alertingTemplates.Template,
+	sender receivers.WebhookSender,
+	logger alertingLogging.LoggerFactory,
+) ([]*alertingNotify.Integration, error) {
+	integrations := make([]*alertingNotify.Integration, 0, len(configs))
+	// The index of an integration is its position among the integrations of the same type.
+	indexes := make(map[string]int, len(configs))
+	for _, cfg := range configs {
+		settings, err := parseConfig(ctx, cfg, decrypt)
+		if err != nil {
+			return nil, alertingNotify.IntegrationValidationError{Integration: cfg, Err: err}
+		}
+		meta := receivers.Metadata{
+			UID:                   cfg.UID,
+			Name:                  cfg.Name,
+			Type:                  cfg.Type,
+			DisableResolveMessage: cfg.DisableResolveMessage,
+		}
+		l := logger("ngalert.notifier."+meta.Type, "notifierUID", meta.UID)
+
+		var n notificationChannel
+		switch s := settings.(type) {
+		case jira.Config:
+			n = jira.New(s, meta, tmpl, sender, l)
+		case servicenow.Config:
+			n = servicenow.New(s, meta, tmpl, sender, l)
+		case matrix.Config:
+			n = matrix.New(s, meta, tmpl, sender, l)
+		}
+
+		integrationType := strings.ToLower(cfg.Type)
+		integrations = append(integrations, alertingNotify.NewIntegration(n, n, cfg.Type, indexes[integrationType], cfg.Name))
+		indexes[integrationType]++
+	}
+	return integrations, nil
+}
+
+func parseConfig(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) (any, error) {
+	secureSettings, err := decodeSecureSettings(integration.SecureSettings)
+	if err != nil {
+		// An error means that the secure settings are not base-64 encoded.
+		secureSettings = make(map[string][]byte, len(integration.SecureSettings))
+		for k, v := range integration.SecureSettings {
+			secureSettings[k] = []byte(v)
+		}
+	}
+	decryptFn := func(key string, fallback string) string {
+		return decrypt(ctx, secureSettings, key, fallback)
+	}
+
+	switch strings.ToLower(integration.Type) {
+	case JiraType:
+		return jira.NewConfig(integration.Settings, decryptFn)
+	case ServiceNowType:
+		return servicenow.NewConfig(integration.Settings, decryptFn)
+	case MatrixType:
+		return matrix.NewConfig(integration.Settings, decryptFn)
+	}
+	return nil, fmt.Errorf("notifier %s is not supported", integration.Type)
+}
+
+func decodeSecureSettings(secrets map[string]string) (map[string][]byte, error) {
+	secureSettings := make(map[string][]byte, len(secrets))
+	for k, v := range secrets {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secure settings key %s: %w", k, err)
+		}
+		secureSettings[k] = d
+	}
+	return secureSettings, nil
+}
@@ -0,0 +1,430 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// httpSender sends webhooks like the notification service of Grafana does.
+type httpSender struct{}
+
+func (httpSender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSettings) error {
+	req, err := http.NewRequestWithContext(ctx, cmd.HTTPMethod, cmd.URL, bytes.NewReader([]byte(cmd.Body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cmd.User != "" && cmd.Password != "" {
+		req.SetBasicAuth(cmd.User, cmd.Password)
+	}
+	for k, v := range cmd.HTTPHeader {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if cmd.Validation != nil {
+		if err := cmd.Validation(body, resp.StatusCode); err != nil {
+			return fmt.Errorf("webhook failed validation: %w", err)
+		}
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook response status %v", resp.Status)
+	}
+	return nil
+}
+
+func nopLoggerFactory(string, ...any) alertingLogging.Logger {
+	return alertingLogging.FakeLogger{}
+}
+
+func noopDecrypt(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+	if v, ok := sjd[key]; ok {
+		return string(v)
+	}
+	return fallback
+}
+
+func buildIntegration(t *testing.T, integrationType string, settings string, secure map[string]string) *alertingNotify.Integration {
+	t.Helper()
+	tmpl := alertingTemplates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	encoded := make(map[string]string, len(secure))
+	for k, v := range secure {
+		encoded[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+	result, err := BuildIntegrations(context.Background(), []*alertingNotify.GrafanaIntegrationConfig{{
+		UID:            "uid",
+		Name:           "test",
+		Type:           integrationType,
+		Settings:       json.RawMessage(settings),
+		SecureSettings: encoded,
+	}}, noopDecrypt, tmpl, httpSender{}, nopLoggerFactory)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	return result[0]
+}
+
+func notifyCtx(groupKey string) context.Context {
+	ctx := notify.WithGroupKey(context.Background(), groupKey)
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+	return notify.WithReceiverName(ctx, "test")
+}
+
+var (
+	firing = &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+		EndsAt:   time.Now().Add(time.Hour),
+	}}
+	resolved = &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(-time.Minute),
+	}}
+)
+
+type jiraIssue struct {
+	key    string
+	fields map[string]any
+	done   bool
+}
+
+// fakeJira is a stand-in for the parts of the Jira REST API that the notifier uses.
+type fakeJira struct {
+	mtx    sync.Mutex
+	issues []*jiraIssue
+}
+
+var jqlLabel = regexp.MustCompile(`labels = "([^"]+)"`)
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if user, pass, _ := r.BasicAuth(); user != "grafana" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/search":
+		label := jqlLabel.FindStringSubmatch(body["jql"].(string))[1]
+		issues := []map[string]any{}
+		for _, i := range f.issues {
+			if !i.done && containsLabel(i.fields["labels"], label) {
+				issues = append(issues, map[string]any{
+					"key":         i.key,
+					"transitions": []map[string]string{{"id": "11", "name": "In Progress"}, {"id": "31", "name": "Done"}},
+				})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"issues": issues})
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		i := &jiraIssue{key: fmt.Sprintf("OPS-%d", len(f.issues)+1), fields: body["fields"].(map[string]any)}
+		f.issues = append(f.issues, i)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"key": i.key})
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/"):
+		i := f.find(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"))
+		for k, v := range body["fields"].(map[string]any) {
+			i.fields[k] = v
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/transitions"):
+		i := f.find(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/transitions"))
+		if body["transition"].(map[string]any)["id"] != "31" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		i.done = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeJira) find(key string) *jiraIssue {
+	for _, i := range f.issues {
+		if i.key == key {
+			return i
+		}
+	}
+	return nil
+}
+
+func containsLabel(labels any, label string) bool {
+	for _, l := range labels.([]any) {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func TestJira(t *testing.T) {
+	jira := &fakeJira{}
+	srv := httptest.NewServer(jira)
+	t.Cleanup(srv.Close)
+
+	integration := buildIntegration(t, JiraType,
+		fmt.Sprintf(`{"api_url": %q, "project": "OPS", "user": "grafana", "labels": "grafana,alerts", "summary": "{{ .CommonLabels.alertname }} is {{ .Status }}"}`, srv.URL),
+		map[string]string{"password": "secret"})
+	ctx := notifyCtx("{}:{alertname=\"test\"}")
+
+	_, err := integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	require.Len(t, jira.issues, 1)
+	issue := jira.issues[0]
+	require.Equal(t, "test is firing", issue.fields["summary"])
+	require.Equal(t, map[string]any{"key": "OPS"}, issue.fields["project"])
+	require.Equal(t, map[string]any{"name": "Bug"}, issue.fields["issuetype"])
+	require.Len(t, issue.fields["labels"], 3)
+	require.Regexp(t, `^ALERT\{[0-9a-f]{64}\}$`, issue.fields["labels"].([]any)[2])
+
+	// Notifications of the same group update the issue.
+	_, err = integration.Notify(ctx, firing, firing)
+	require.NoError(t, err)
+	require.Len(t, jira.issues, 1)
+
+	// Notifications of other groups create a new issue.
+	_, err = integration.Notify(notifyCtx("{}:{alertname=\"other\"}"), firing)
+	require.NoError(t, err)
+	require.Len(t, jira.issues, 2)
+
+	_, err = integration.Notify(ctx, resolved)
+	require.NoError(t, err)
+	require.True(t, issue.done)
+	require.False(t, jira.issues[1].done)
+	require.Equal(t, "test is firing", issue.fields["summary"], "resolving an issue should not update it")
+
+	// The group fires again after its issue was resolved.
+	_, err = integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	require.Len(t, jira.issues, 3)
+
+	t.Run("does not retry requests that Jira rejected", func(t *testing.T) {
+		integration := buildIntegration(t, JiraType, fmt.Sprintf(`{"api_url": %q, "project": "OPS", "user": "grafana"}`, srv.URL),
+			map[string]string{"password": "wrong"})
+		retry, err := integration.Notify(ctx, firing)
+		require.ErrorContains(t, err, "unexpected status code 401 from Jira")
+		require.False(t, retry)
+	})
+}
+
+type serviceNowRecord struct {
+	fields map[string]string
+}
+
+// fakeServiceNow is a stand-in for the parts of the ServiceNow Table API that the notifier uses.
+type fakeServiceNow struct {
+	mtx     sync.Mutex
+	records map[string]*serviceNowRecord
+}
+
+var correlationQuery = regexp.MustCompile(`^correlation_id=([0-9a-f]+)\^active=true$`)
+
+func (f *fakeServiceNow) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if user, pass, _ := r.BasicAuth(); user != "grafana" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/incident":
+		m := correlationQuery.FindStringSubmatch(r.URL.Query().Get("sysparm_query"))
+		if m == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		result := []map[string]string{}
+		for id, rec := range f.records {
+			if rec.fields["correlation_id"] == m[1] && rec.fields["state"] != "6" {
+				result = append(result, map[string]string{"sys_id": id, "number": rec.fields["number"]})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/incident":
+		var fields map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&fields)
+		id := fmt.Sprintf("sys%d", len(f.records)+1)
+		fields["number"] = fmt.Sprintf("INC%07d", len(f.records)+1)
+		f.records[id] = &serviceNowRecord{fields: fields}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]string{"sys_id": id, "number": fields["number"]}})
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/now/table/incident/"):
+		rec, ok := f.records[strings.TrimPrefix(r.URL.Path, "/api/now/table/incident/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var fields map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&fields)
+		for k, v := range fields {
+			rec.fields[k] = v
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": map[string]string{}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestServiceNow(t *testing.T) {
+	sn := &fakeServiceNow{records: map[string]*serviceNowRecord{}}
+	srv := httptest.NewServer(sn)
+	t.Cleanup(srv.Close)
+
+	integration := buildIntegration(t, ServiceNowType,
+		fmt.Sprintf(`{"url": %q, "user": "grafana", "assignment_group": "ops", "short_description": "{{ .CommonLabels.alertname }}"}`, srv.URL),
+		map[string]string{"password": "secret"})
+	ctx := notifyCtx("{}:{alertname=\"test\"}")
+
+	_, err := integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	require.Len(t, sn.records, 1)
+	rec := sn.records["sys1"]
+	assert.Equal(t, "test", rec.fields["short_description"])
+	assert.Equal(t, "ops", rec.fields["assignment_group"])
+	assert.Equal(t, "Grafana", rec.fields["correlation_display"])
+	assert.Len(t, rec.fields["correlation_id"], 64)
+
+	_, err = integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	require.Len(t, sn.records, 1, "notifications of the same group should update the incident")
+
+	_, err = integration.Notify(ctx, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, "6", rec.fields["state"])
+	assert.Equal(t, "Solution provided", rec.fields["close_code"])
+	assert.Equal(t, "The alerts were resolved in Grafana.", rec.fields["close_notes"])
+
+	_, err = integration.Notify(ctx, resolved)
+	require.NoError(t, err, "resolving a group without an active incident should succeed")
+
+	_, err = integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	require.Len(t, sn.records, 2)
+}
+
+func TestMatrix(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		requests []*http.Request
+		bodies   []map[string]string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"event_id": "$event"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	integration := buildIntegration(t, MatrixType,
+		fmt.Sprintf(`{"homeserver_url": %q, "room_id": "!room:example.com", "msgtype": "m.notice", "message": "{{ .CommonLabels.alertname }} is {{ .Status }}"}`, srv.URL),
+		map[string]string{"access_token": "token"})
+	ctx := notify.WithNow(notifyCtx("{}:{alertname=\"test\"}"), time.Now())
+
+	_, err := integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	_, err = integration.Notify(ctx, firing)
+	require.NoError(t, err)
+	_, err = integration.Notify(ctx, resolved)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, http.MethodPut, requests[0].Method)
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+	assert.True(t, strings.HasPrefix(requests[0].URL.EscapedPath(), "/_matrix/client/v3/rooms/%21room:example.com/send/m.room.message/grafana-"), requests[0].URL.EscapedPath())
+	assert.Equal(t, map[string]string{"msgtype": "m.notice", "body": "test is firing"}, bodies[0])
+	assert.Equal(t, "test is resolved", bodies[2]["body"])
+	assert.Equal(t, requests[0].URL.Path, requests[1].URL.Path, "retries of a notification should use the same transaction ID")
+	assert.NotEqual(t, requests[0].URL.Path, requests[2].URL.Path)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		typ      string
+		settings string
+		secure   map[string]string
+		expErr   string
+	}{
+		{name: "valid jira", typ: "jira", settings: `{"api_url": "https://example.atlassian.net", "project": "OPS"}`, secure: map[string]string{"api_token": "token"}},
+		{name: "jira without project", typ: "jira", settings: `{"api_url": "https://example.atlassian.net"}`, secure: map[string]string{"api_token": "token"}, expErr: "could not find project property in settings"},
+		{name: "jira without credentials", typ: "jira", settings: `{"api_url": "https://example.atlassian.net", "project": "OPS", "user": "grafana"}`, expErr: "either an API token or a user and password must be provided"},
+		{name: "jira with invalid URL", typ: "jira", settings: `{"api_url": "example", "project": "OPS"}`, secure: map[string]string{"api_token": "token"}, expErr: `invalid URL "example"`},
+		{name: "valid servicenow", typ: "servicenow", settings: `{"url": "https://example.service-now.com", "user": "grafana"}`, secure: map[string]string{"password": "secret"}},
+		{name: "servicenow without password", typ: "servicenow", settings: `{"url": "https://example.service-now.com", "user": "grafana"}`, expErr: "user and password must be provided"},
+		{name: "servicenow with invalid table", typ: "servicenow", settings: `{"url": "https://example.service-now.com", "user": "grafana", "table": "../sys_user"}`, secure: map[string]string{"password": "secret"}, expErr: `invalid table "../sys_user"`},
+		{name: "valid matrix", typ: "matrix", settings: `{"homeserver_url": "https://matrix.example.com", "room_id": "!room:example.com"}`, secure: map[string]string{"access_token": "token"}},
+		{name: "matrix without access token", typ: "matrix", settings: `{"homeserver_url": "https://matrix.example.com", "room_id": "!room:example.com"}`, expErr: "could not find access_token property in settings"},
+		{name: "matrix with invalid message type", typ: "matrix", settings: `{"homeserver_url": "https://matrix.example.com", "room_id": "!room:example.com", "msgtype": "m.image"}`, secure: map[string]string{"access_token": "token"}, expErr: `invalid msgtype "m.image"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &alertingNotify.GrafanaIntegrationConfig{UID: "uid", Name: "test", Type: tc.typ, Settings: json.RawMessage(tc.settings), SecureSettings: tc.secure}
+			err := Validate(context.Background(), cfg, noopDecrypt)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+			require.ErrorAs(t, err, &alertingNotify.IntegrationValidationError{})
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{Type: "webhook"}, {Type: "Jira"}, {Type: "email"}, {Type: "matrix"},
+		}},
+	}
+	others, supported := Split(receiver)
+	require.Len(t, others.Integrations, 2)
+	require.Equal(t, "webhook", others.Integrations[0].Type)
+	require.Equal(t, "email", others.Integrations[1].Type)
+	require.Len(t, supported, 2)
+	require.Len(t, receiver.Integrations, 4, "the receiver should not be changed")
+
+	receiver = &alertingNotify.APIReceiver{}
+	others, supported = Split(receiver)
+	require.Same(t, receiver, others)
+	require.Empty(t, supported)
+}
@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	DefaultIssueType         = "Bug"
+	DefaultResolveTransition = "Done"
+)
+
+type Config struct {
+	// URL is the base URL of the Jira instance, for example https://example.atlassian.net.
+	URL               string                          `json:"api_url,omitempty" yaml:"api_url,omitempty"`
+	Project           string                          `json:"project,omitempty" yaml:"project,omitempty"`
+	IssueType         string                          `json:"issue_type,omitempty" yaml:"issue_type,omitempty"`
+	Summary           string                          `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description       string                          `json:"description,omitempty" yaml:"description,omitempty"`
+	Labels            receivers.CommaSeparatedStrings `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Priority          string                          `json:"priority,omitempty" yaml:"priority,omitempty"`
+	ResolveTransition string                          `json:"resolve_transition,omitempty" yaml:"resolve_transition,omitempty"`
+	User              string                          `json:"user,omitempty" yaml:"user,omitempty"`
+	Password          string                          `json:"password,omitempty" yaml:"password,omitempty"`
+	// Token is a personal access token that is sent as a bearer token. It is used instead of the user and password.
+	Token string `json:"api_token,omitempty" yaml:"api_token,omitempty"`
+}
+
+// NewConfig is the constructor for the Jira notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	settings := Config{}
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if settings.URL == "" {
+		return Config{}, errors.New("could not find api_url property in settings")
+	}
+	u, err := url.Parse(settings.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return Config{}, fmt.Errorf("invalid URL %q", settings.URL)
+	}
+	settings.URL = strings.TrimSuffix(u.String(), "/")
+
+	if settings.Project == "" {
+		return Config{}, errors.New("could not find project property in settings")
+	}
+	if settings.IssueType == "" {
+		settings.IssueType = DefaultIssueType
+	}
+	if settings.Summary == "" {
+		settings.Summary = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Description == "" {
+		settings.Description = templates.DefaultMessageEmbed
+	}
+	if settings.ResolveTransition == "" {
+		settings.ResolveTransition = DefaultResolveTransition
+	}
+
+	settings.Password = decryptFn("password", settings.Password)
+	settings.Token = decryptFn("api_token", settings.Token)
+	if settings.Token == "" && (settings.User == "" || settings.Password == "") {
+		return Config{}, errors.New("either an API token or a user and password must be provided")
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,235 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	// Jira limits the summary of issues to 255 characters and text fields to 32767 characters.
+	maxSummaryLenRunes     = 255
+	maxDescriptionLenRunes = 32767
+	// maxErrorBodyLen limits how much of the response of Jira is included in errors.
+	maxErrorBodyLen = 1024
+)
+
+// Notifier creates a Jira issue for each alert group when the group starts firing and transitions the issue when the
+// group is resolved. The issue is found again by a label derived from the group key.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+// New is the constructor for the Jira notifier.
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+// DeduplicationLabel returns the label that identifies the issue of the alert group.
+func DeduplicationLabel(key notify.Key) string {
+	return fmt.Sprintf("ALERT{%s}", key.Hash())
+}
+
+type issue struct {
+	Key         string       `json:"key"`
+	Transitions []transition `json:"transitions,omitempty"`
+}
+
+type transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type searchRequest struct {
+	JQL        string   `json:"jql"`
+	MaxResults int      `json:"maxResults"`
+	Fields     []string `json:"fields"`
+	Expand     []string `json:"expand"`
+}
+
+type searchResponse struct {
+	Issues []issue `json:"issues"`
+}
+
+type issueRequest struct {
+	Fields map[string]any `json:"fields"`
+}
+
+type transitionRequest struct {
+	Transition transition `json:"transition"`
+}
+
+// apiError is returned when Jira responds with a status code that is not successful.
+type apiError struct {
+	statusCode int
+	body       string
+}
+
+func (e apiError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from Jira: %s", e.statusCode, e.body)
+}
+
+// Notify creates or updates the issue of the alert group when it is firing, and resolves the issue when it is resolved.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	label := DeduplicationLabel(key)
+
+	existing, err := n.searchIssue(ctx, label)
+	if err != nil {
+		return shouldRetry(err), fmt.Errorf("failed to search for the Jira issue: %w", err)
+	}
+
+	if types.Alerts(as...).Status() == model.AlertResolved {
+		if existing == nil {
+			n.log.Debug("No open Jira issue found for resolved alerts", "label", label)
+			return true, nil
+		}
+		return n.resolveIssue(ctx, existing)
+	}
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+	summary, truncated := receivers.TruncateInRunes(tmpl(n.settings.Summary), maxSummaryLenRunes)
+	if truncated {
+		n.log.Warn("Truncated summary", "key", key, "max_runes", maxSummaryLenRunes)
+	}
+	description, truncated := receivers.TruncateInRunes(tmpl(n.settings.Description), maxDescriptionLenRunes)
+	if truncated {
+		n.log.Warn("Truncated description", "key", key, "max_runes", maxDescriptionLenRunes)
+	}
+	if tmplErr != nil {
+		n.log.Warn("Failed to template Jira message", "error", tmplErr.Error())
+	}
+
+	if existing != nil {
+		n.log.Debug("Updating Jira issue", "issue", existing.Key)
+		req := issueRequest{Fields: map[string]any{
+			"summary":     summary,
+			"description": description,
+		}}
+		if err := n.do(ctx, http.MethodPut, "/rest/api/2/issue/"+existing.Key, req, nil); err != nil {
+			return shouldRetry(err), fmt.Errorf("failed to update Jira issue %s: %w", existing.Key, err)
+		}
+		return true, nil
+	}
+
+	fields := map[string]any{
+		"project":     map[string]string{"key": n.settings.Project},
+		"issuetype":   map[string]string{"name": n.settings.IssueType},
+		"summary":     summary,
+		"description": description,
+		"labels":      append(append([]string{}, n.settings.Labels...), label),
+	}
+	if n.settings.Priority != "" {
+		fields["priority"] = map[string]string{"name": tmpl(n.settings.Priority)}
+	}
+	var created issue
+	if err := n.do(ctx, http.MethodPost, "/rest/api/2/issue", issueRequest{Fields: fields}, &created); err != nil {
+		return shouldRetry(err), fmt.Errorf("failed to create Jira issue: %w", err)
+	}
+	n.log.Debug("Created Jira issue", "issue", created.Key)
+	return true, nil
+}
+
+// searchIssue returns the open issue with the label, or nil if there is none.
+func (n *Notifier) searchIssue(ctx context.Context, label string) (*issue, error) {
+	req := searchRequest{
+		JQL:        fmt.Sprintf("project = %q AND labels = %q AND statusCategory != Done ORDER BY created DESC", n.settings.Project, label),
+		MaxResults: 1,
+		Fields:     []string{"status"},
+		Expand:     []string{"transitions"},
+	}
+	var res searchResponse
+	if err := n.do(ctx, http.MethodPost, "/rest/api/2/search", req, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Issues) == 0 {
+		return nil, nil
+	}
+	return &res.Issues[0], nil
+}
+
+func (n *Notifier) resolveIssue(ctx context.Context, existing *issue) (bool, error) {
+	for _, t := range existing.Transitions {
+		if !strings.EqualFold(t.Name, n.settings.ResolveTransition) {
+			continue
+		}
+		if err := n.do(ctx, http.MethodPost, "/rest/api/2/issue/"+existing.Key+"/transitions", transitionRequest{Transition: transition{ID: t.ID}}, nil); err != nil {
+			return shouldRetry(err), fmt.Errorf("failed to transition Jira issue %s: %w", existing.Key, err)
+		}
+		n.log.Debug("Resolved Jira issue", "issue", existing.Key, "transition", t.Name)
+		return true, nil
+	}
+	return false, fmt.Errorf("transition %q is not available for Jira issue %s", n.settings.ResolveTransition, existing.Key)
+}
+
+// do sends a request to the Jira REST API and decodes the response into res if it is not nil.
+func (n *Notifier) do(ctx context.Context, method, path string, req any, res any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	cmd := &receivers.SendWebhookSettings{
+		URL:        n.settings.URL + path,
+		Body:       string(body),
+		HTTPMethod: method,
+		HTTPHeader: map[string]string{"Accept": "application/json"},
+		Validation: func(body []byte, statusCode int) error {
+			if statusCode/100 != 2 {
+				b, _ := receivers.TruncateInBytes(string(body), maxErrorBodyLen)
+				return apiError{statusCode: statusCode, body: b}
+			}
+			if res == nil || len(body) == 0 {
+				return nil
+			}
+			if err := json.Unmarshal(body, res); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			return nil
+		},
+	}
+	if n.settings.Token != "" {
+		cmd.HTTPHeader["Authorization"] = "Bearer " + n.settings.Token
+	} else {
+		cmd.User = n.settings.User
+		cmd.Password = n.settings.Password
+	}
+	return n.ns.SendWebhook(ctx, cmd)
+}
+
+// shouldRetry returns false if Jira rejected the request, because sending it again would fail the same way.
+func shouldRetry(err error) bool {
+	var e apiError
+	if errors.As(err, &e) {
+		return e.statusCode == http.StatusTooManyRequests || e.statusCode/100 == 5
+	}
+	return true
+}
+
+func (n *Notifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
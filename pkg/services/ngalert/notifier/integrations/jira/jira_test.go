@@ -0,0 +1,180 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+type response struct {
+	status int
+	body   string
+}
+
+// fakeSender responds to the requests of the notifier by method and path.
+type fakeSender struct {
+	responses map[string]response
+	requests  []*receivers.SendWebhookSettings
+}
+
+func (s *fakeSender) SendWebhook(_ context.Context, cmd *receivers.SendWebhookSettings) error {
+	s.requests = append(s.requests, cmd)
+	res, ok := s.responses[cmd.HTTPMethod+" "+strings.TrimPrefix(cmd.URL, "https://jira.example.com")]
+	if !ok {
+		res = response{status: http.StatusNotFound}
+	}
+	if cmd.Validation != nil {
+		return cmd.Validation([]byte(res.body), res.status)
+	}
+	return nil
+}
+
+func (s *fakeSender) calls() []string {
+	result := make([]string, 0, len(s.requests))
+	for _, r := range s.requests {
+		result = append(result, r.HTTPMethod+" "+strings.TrimPrefix(r.URL, "https://jira.example.com"))
+	}
+	return result
+}
+
+func TestNotify(t *testing.T) {
+	key := notify.Key("{}:{alertname=\"test\"}")
+	label := DeduplicationLabel(key)
+	ctx := notify.WithGroupKey(context.Background(), string(key))
+	firing := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+	resolved := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(-time.Minute),
+	}}
+	cfg := Config{
+		URL:               "https://jira.example.com",
+		Project:           "OPS",
+		IssueType:         DefaultIssueType,
+		Summary:           "{{ .CommonLabels.alertname }} is {{ .Status }}",
+		Description:       "description",
+		Labels:            []string{"grafana"},
+		ResolveTransition: DefaultResolveTransition,
+		Token:             "token",
+	}
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	newNotifier := func(responses map[string]response) (*Notifier, *fakeSender) {
+		sender := &fakeSender{responses: responses}
+		return New(cfg, receivers.Metadata{}, tmpl, sender, &logging.FakeLogger{}), sender
+	}
+	body := func(t *testing.T, cmd *receivers.SendWebhookSettings) map[string]any {
+		t.Helper()
+		var result map[string]any
+		require.NoError(t, json.Unmarshal([]byte(cmd.Body), &result))
+		return result
+	}
+
+	t.Run("searches the issue by the label of the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusOK, body: `{"issues": []}`},
+			"POST /rest/api/2/issue":  {status: http.StatusCreated, body: `{"key": "OPS-1"}`},
+		})
+		_, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+
+		search := sender.requests[0]
+		require.Equal(t, "Bearer token", search.HTTPHeader["Authorization"])
+		require.Contains(t, body(t, search)["jql"], `labels = "`+label+`"`)
+		require.Contains(t, body(t, search)["jql"], `project = "OPS"`)
+	})
+
+	t.Run("creates an issue if there is none for the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusOK, body: `{"issues": []}`},
+			"POST /rest/api/2/issue":  {status: http.StatusCreated, body: `{"key": "OPS-1"}`},
+		})
+		retry, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.True(t, retry)
+		require.Equal(t, []string{"POST /rest/api/2/search", "POST /rest/api/2/issue"}, sender.calls())
+
+		fields := body(t, sender.requests[1])["fields"].(map[string]any)
+		require.Equal(t, "test is firing", fields["summary"])
+		require.Equal(t, map[string]any{"key": "OPS"}, fields["project"])
+		require.Equal(t, []any{"grafana", label}, fields["labels"])
+	})
+
+	t.Run("updates the issue of the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"POST /rest/api/2/search":     {status: http.StatusOK, body: `{"issues": [{"key": "OPS-1"}]}`},
+			"PUT /rest/api/2/issue/OPS-1": {status: http.StatusNoContent},
+		})
+		_, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.Equal(t, []string{"POST /rest/api/2/search", "PUT /rest/api/2/issue/OPS-1"}, sender.calls())
+		require.Equal(t, map[string]any{"summary": "test is firing", "description": "description"}, body(t, sender.requests[1])["fields"])
+	})
+
+	t.Run("resolves the issue of the group with the resolve transition", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusOK, body: `{"issues": [{"key": "OPS-1", "transitions": [
+				{"id": "11", "name": "In Progress"},
+				{"id": "31", "name": "done"}
+			]}]}`},
+			"POST /rest/api/2/issue/OPS-1/transitions": {status: http.StatusNoContent},
+		})
+		_, err := n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		require.Equal(t, []string{"POST /rest/api/2/search", "POST /rest/api/2/issue/OPS-1/transitions"}, sender.calls())
+		require.Equal(t, map[string]any{"transition": map[string]any{"id": "31", "name": ""}}, body(t, sender.requests[1]))
+	})
+
+	t.Run("fails to resolve the issue if the transition is not available", func(t *testing.T) {
+		n, _ := newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusOK, body: `{"issues": [{"key": "OPS-1", "transitions": [{"id": "11", "name": "In Progress"}]}]}`},
+		})
+		retry, err := n.Notify(ctx, resolved)
+		require.ErrorContains(t, err, `transition "Done" is not available for Jira issue OPS-1`)
+		require.False(t, retry)
+	})
+
+	t.Run("does nothing for resolved alerts without an issue", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusOK, body: `{"issues": []}`},
+		})
+		_, err := n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		require.Equal(t, []string{"POST /rest/api/2/search"}, sender.calls())
+	})
+
+	t.Run("retries only errors that are not caused by the request", func(t *testing.T) {
+		n, _ := newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusServiceUnavailable},
+		})
+		retry, err := n.Notify(ctx, firing)
+		require.ErrorContains(t, err, "unexpected status code 503 from Jira")
+		require.True(t, retry)
+
+		n, _ = newNotifier(map[string]response{
+			"POST /rest/api/2/search": {status: http.StatusOK, body: `{"issues": []}`},
+			"POST /rest/api/2/issue":  {status: http.StatusBadRequest, body: `{"errors": {"project": "invalid"}}`},
+		})
+		retry, err = n.Notify(ctx, firing)
+		require.ErrorContains(t, err, "unexpected status code 400 from Jira")
+		require.False(t, retry)
+	})
+}
@@ -0,0 +1,64 @@
+package matrix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	MessageTypeText   = "m.text"
+	MessageTypeNotice = "m.notice"
+)
+
+type Config struct {
+	// HomeserverURL is the URL of the Matrix homeserver, for example https://matrix.example.com.
+	HomeserverURL string `json:"homeserver_url,omitempty" yaml:"homeserver_url,omitempty"`
+	RoomID        string `json:"room_id,omitempty" yaml:"room_id,omitempty"`
+	AccessToken   string `json:"access_token,omitempty" yaml:"access_token,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+	MessageType   string `json:"msgtype,omitempty" yaml:"msgtype,omitempty"`
+}
+
+// NewConfig is the constructor for the Matrix notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	settings := Config{}
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if settings.HomeserverURL == "" {
+		return Config{}, errors.New("could not find homeserver_url property in settings")
+	}
+	u, err := url.Parse(settings.HomeserverURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return Config{}, fmt.Errorf("invalid URL %q", settings.HomeserverURL)
+	}
+	settings.HomeserverURL = strings.TrimSuffix(u.String(), "/")
+
+	if settings.RoomID == "" {
+		return Config{}, errors.New("could not find room_id property in settings")
+	}
+
+	settings.AccessToken = decryptFn("access_token", settings.AccessToken)
+	if settings.AccessToken == "" {
+		return Config{}, errors.New("could not find access_token property in settings")
+	}
+
+	if settings.Message == "" {
+		settings.Message = templates.DefaultMessageEmbed
+	}
+	switch settings.MessageType {
+	case "":
+		settings.MessageType = MessageTypeText
+	case MessageTypeText, MessageTypeNotice:
+	default:
+		return Config{}, fmt.Errorf("invalid msgtype %q, must be %s or %s", settings.MessageType, MessageTypeText, MessageTypeNotice)
+	}
+	return settings, nil
+}
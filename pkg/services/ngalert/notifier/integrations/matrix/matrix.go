@@ -0,0 +1,109 @@
+package matrix
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+var (
+	// Provides current time. Can be overwritten in tests.
+	timeNow = time.Now
+)
+
+// Matrix limits events to 65536 bytes. The limit of the message leaves room for the rest of the event.
+const maxMessageLenBytes = 60000
+
+// Notifier sends messages to a Matrix room.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+// New is the constructor for the Matrix notifier.
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+type message struct {
+	MsgType string `json:"msgtype"`
+	Body    string `json:"body"`
+}
+
+// Notify sends a message to the Matrix room.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+	body, truncated := receivers.TruncateInBytes(tmpl(n.settings.Message), maxMessageLenBytes)
+	if truncated {
+		n.log.Warn("Truncated message", "key", key, "max_bytes", maxMessageLenBytes)
+	}
+	if tmplErr != nil {
+		n.log.Warn("Failed to template Matrix message", "error", tmplErr.Error())
+	}
+
+	b, err := json.Marshal(message{MsgType: n.settings.MessageType, Body: body})
+	if err != nil {
+		return false, err
+	}
+
+	cmd := &receivers.SendWebhookSettings{
+		URL: fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+			n.settings.HomeserverURL, url.PathEscape(n.settings.RoomID), transactionID(ctx, key, as)),
+		Body:       string(b),
+		HTTPMethod: http.MethodPut,
+		HTTPHeader: map[string]string{
+			"Authorization": "Bearer " + n.settings.AccessToken,
+		},
+	}
+	if err := n.ns.SendWebhook(ctx, cmd); err != nil {
+		n.log.Error("Failed to send Matrix message", "error", err)
+		return true, err
+	}
+	return true, nil
+}
+
+// transactionID returns the same ID when the same notification is sent again, so that the homeserver does not post
+// the message twice.
+func transactionID(ctx context.Context, key notify.Key, as []*types.Alert) string {
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = timeNow()
+	}
+	h := sha256.New()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte(types.Alerts(as...).Status()))
+	_, _ = h.Write([]byte(strconv.FormatInt(now.UnixNano(), 10)))
+	return "grafana-" + hex.EncodeToString(h.Sum(nil))
+}
+
+func (n *Notifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
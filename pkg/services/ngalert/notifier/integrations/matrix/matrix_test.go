@@ -0,0 +1,98 @@
+package matrix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+func TestNotify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := notify.Key("{}:{alertname=\"test\"}")
+	ctx := notify.WithNow(notify.WithGroupKey(context.Background(), string(key)), now)
+	firing := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: now.Add(-time.Minute),
+	}}
+	resolved := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(-time.Minute),
+	}}
+	cfg := Config{
+		HomeserverURL: "https://matrix.example.com",
+		RoomID:        "!room:example.com",
+		AccessToken:   "token",
+		Message:       "{{ .CommonLabels.alertname }} is {{ .Status }}",
+		MessageType:   MessageTypeNotice,
+	}
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	newNotifier := func() (*Notifier, *receivers.NotificationServiceMock) {
+		sender := receivers.MockNotificationService()
+		return New(cfg, receivers.Metadata{}, tmpl, sender, &logging.FakeLogger{}), sender
+	}
+	transaction := func(t *testing.T, cmd receivers.SendWebhookSettings) string {
+		t.Helper()
+		prefix := "https://matrix.example.com/_matrix/client/v3/rooms/" + url.PathEscape(cfg.RoomID) + "/send/m.room.message/"
+		require.True(t, strings.HasPrefix(cmd.URL, prefix), cmd.URL)
+		return strings.TrimPrefix(cmd.URL, prefix)
+	}
+
+	t.Run("sends a message to the room", func(t *testing.T) {
+		n, sender := newNotifier()
+		retry, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.True(t, retry)
+
+		require.Equal(t, http.MethodPut, sender.Webhook.HTTPMethod)
+		require.Equal(t, "Bearer token", sender.Webhook.HTTPHeader["Authorization"])
+		require.JSONEq(t, `{"msgtype": "m.notice", "body": "test is firing"}`, sender.Webhook.Body)
+		require.NotEmpty(t, transaction(t, sender.Webhook))
+	})
+
+	t.Run("sends the same notification with the same transaction ID", func(t *testing.T) {
+		n, sender := newNotifier()
+		_, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		_, err = n.Notify(ctx, firing)
+		require.NoError(t, err)
+		_, err = n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		_, err = n.Notify(notify.WithNow(ctx, now.Add(time.Minute)), firing)
+		require.NoError(t, err)
+		_, err = n.Notify(notify.WithGroupKey(ctx, "{}:{alertname=\"other\"}"), firing)
+		require.NoError(t, err)
+
+		require.Len(t, sender.WebhookCalls, 5)
+		first := transaction(t, sender.WebhookCalls[0])
+		require.Equal(t, first, transaction(t, sender.WebhookCalls[1]), "a retry of the notification should not post the message twice")
+		for _, call := range sender.WebhookCalls[2:] {
+			require.NotEqual(t, first, transaction(t, call))
+		}
+		require.JSONEq(t, `{"msgtype": "m.notice", "body": "test is resolved"}`, sender.WebhookCalls[2].Body)
+	})
+
+	t.Run("retries failed messages", func(t *testing.T) {
+		n, sender := newNotifier()
+		sender.ShouldError = errors.New("connection refused")
+		retry, err := n.Notify(ctx, firing)
+		require.ErrorContains(t, err, "connection refused")
+		require.True(t, retry)
+	})
+}
@@ -0,0 +1,83 @@
+package servicenow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	DefaultTable = "incident"
+	// DefaultResolveState is the state of resolved incidents in ServiceNow.
+	DefaultResolveState = "6"
+	DefaultCloseCode    = "Solution provided"
+	DefaultCloseNotes   = "The alerts were resolved in Grafana."
+)
+
+type Config struct {
+	// URL is the URL of the ServiceNow instance, for example https://example.service-now.com.
+	URL              string `json:"url,omitempty" yaml:"url,omitempty"`
+	Table            string `json:"table,omitempty" yaml:"table,omitempty"`
+	User             string `json:"user,omitempty" yaml:"user,omitempty"`
+	Password         string `json:"password,omitempty" yaml:"password,omitempty"`
+	ShortDescription string `json:"short_description,omitempty" yaml:"short_description,omitempty"`
+	Description      string `json:"description,omitempty" yaml:"description,omitempty"`
+	AssignmentGroup  string `json:"assignment_group,omitempty" yaml:"assignment_group,omitempty"`
+	CallerID         string `json:"caller_id,omitempty" yaml:"caller_id,omitempty"`
+	Impact           string `json:"impact,omitempty" yaml:"impact,omitempty"`
+	Urgency          string `json:"urgency,omitempty" yaml:"urgency,omitempty"`
+	ResolveState     string `json:"resolve_state,omitempty" yaml:"resolve_state,omitempty"`
+	CloseCode        string `json:"close_code,omitempty" yaml:"close_code,omitempty"`
+	CloseNotes       string `json:"close_notes,omitempty" yaml:"close_notes,omitempty"`
+}
+
+// NewConfig is the constructor for the ServiceNow notifier.
+func NewConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (Config, error) {
+	settings := Config{}
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return Config{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if settings.URL == "" {
+		return Config{}, errors.New("could not find url property in settings")
+	}
+	u, err := url.Parse(settings.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return Config{}, fmt.Errorf("invalid URL %q", settings.URL)
+	}
+	settings.URL = strings.TrimSuffix(u.String(), "/")
+
+	if settings.Table == "" {
+		settings.Table = DefaultTable
+	}
+	if strings.ContainsAny(settings.Table, "/?#") {
+		return Config{}, fmt.Errorf("invalid table %q", settings.Table)
+	}
+
+	settings.Password = decryptFn("password", settings.Password)
+	if settings.User == "" || settings.Password == "" {
+		return Config{}, errors.New("user and password must be provided")
+	}
+
+	if settings.ShortDescription == "" {
+		settings.ShortDescription = templates.DefaultMessageTitleEmbed
+	}
+	if settings.Description == "" {
+		settings.Description = templates.DefaultMessageEmbed
+	}
+	if settings.ResolveState == "" {
+		settings.ResolveState = DefaultResolveState
+	}
+	if settings.CloseCode == "" {
+		settings.CloseCode = DefaultCloseCode
+	}
+	if settings.CloseNotes == "" {
+		settings.CloseNotes = DefaultCloseNotes
+	}
+	return settings, nil
+}
@@ -0,0 +1,226 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+const (
+	// ServiceNow limits the short description of incidents to 160 characters.
+	maxShortDescriptionLenRunes = 160
+	maxDescriptionLenRunes      = 4000
+	// maxErrorBodyLen limits how much of the response of ServiceNow is included in errors.
+	maxErrorBodyLen = 1024
+)
+
+// Notifier creates a ServiceNow incident for each alert group when the group starts firing and resolves the incident
+// when the group is resolved. The incident is found again by its correlation ID, which is derived from the group key.
+type Notifier struct {
+	*receivers.Base
+	log      logging.Logger
+	ns       receivers.WebhookSender
+	tmpl     *templates.Template
+	settings Config
+}
+
+// New is the constructor for the ServiceNow notifier.
+func New(cfg Config, meta receivers.Metadata, template *templates.Template, sender receivers.WebhookSender, logger logging.Logger) *Notifier {
+	return &Notifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		ns:       sender,
+		tmpl:     template,
+		settings: cfg,
+	}
+}
+
+// CorrelationID returns the correlation ID of the incident of the alert group.
+func CorrelationID(key notify.Key) string {
+	return key.Hash()
+}
+
+type record struct {
+	SysID  string `json:"sys_id"`
+	Number string `json:"number"`
+}
+
+type listResponse struct {
+	Result []record `json:"result"`
+}
+
+type recordResponse struct {
+	Result record `json:"result"`
+}
+
+// apiError is returned when ServiceNow responds with a status code that is not successful.
+type apiError struct {
+	statusCode int
+	body       string
+}
+
+func (e apiError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from ServiceNow: %s", e.statusCode, e.body)
+}
+
+// Notify creates or updates the incident of the alert group when it is firing, and resolves the incident when it is
+// resolved.
+func (n *Notifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	key, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return false, err
+	}
+	correlationID := CorrelationID(key)
+
+	existing, err := n.findIncident(ctx, correlationID)
+	if err != nil {
+		return shouldRetry(err), fmt.Errorf("failed to search for the ServiceNow incident: %w", err)
+	}
+
+	var tmplErr error
+	tmpl, _ := templates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+	defer func() {
+		if tmplErr != nil {
+			n.log.Warn("Failed to template ServiceNow message", "error", tmplErr.Error())
+		}
+	}()
+
+	if types.Alerts(as...).Status() == model.AlertResolved {
+		if existing == nil {
+			n.log.Debug("No active ServiceNow incident found for resolved alerts", "correlation_id", correlationID)
+			return true, nil
+		}
+		fields := map[string]string{
+			"state":       n.settings.ResolveState,
+			"close_code":  n.settings.CloseCode,
+			"close_notes": tmpl(n.settings.CloseNotes),
+		}
+		if err := n.do(ctx, http.MethodPut, n.recordPath(existing.SysID), fields, nil); err != nil {
+			return shouldRetry(err), fmt.Errorf("failed to resolve ServiceNow incident %s: %w", existing.Number, err)
+		}
+		n.log.Debug("Resolved ServiceNow incident", "incident", existing.Number)
+		return true, nil
+	}
+
+	shortDescription, truncated := receivers.TruncateInRunes(tmpl(n.settings.ShortDescription), maxShortDescriptionLenRunes)
+	if truncated {
+		n.log.Warn("Truncated short description", "key", key, "max_runes", maxShortDescriptionLenRunes)
+	}
+	description, truncated := receivers.TruncateInRunes(tmpl(n.settings.Description), maxDescriptionLenRunes)
+	if truncated {
+		n.log.Warn("Truncated description", "key", key, "max_runes", maxDescriptionLenRunes)
+	}
+
+	if existing != nil {
+		fields := map[string]string{
+			"short_description": shortDescription,
+			"description":       description,
+		}
+		if err := n.do(ctx, http.MethodPut, n.recordPath(existing.SysID), fields, nil); err != nil {
+			return shouldRetry(err), fmt.Errorf("failed to update ServiceNow incident %s: %w", existing.Number, err)
+		}
+		n.log.Debug("Updated ServiceNow incident", "incident", existing.Number)
+		return true, nil
+	}
+
+	fields := map[string]string{
+		"short_description":   shortDescription,
+		"description":         description,
+		"correlation_id":      correlationID,
+		"correlation_display": "Grafana",
+	}
+	optional := map[string]string{
+		"assignment_group": n.settings.AssignmentGroup,
+		"caller_id":        n.settings.CallerID,
+		"impact":           n.settings.Impact,
+		"urgency":          n.settings.Urgency,
+	}
+	for k, v := range optional {
+		if v != "" {
+			fields[k] = tmpl(v)
+		}
+	}
+	var created recordResponse
+	if err := n.do(ctx, http.MethodPost, "/api/now/table/"+n.settings.Table, fields, &created); err != nil {
+		return shouldRetry(err), fmt.Errorf("failed to create ServiceNow incident: %w", err)
+	}
+	n.log.Debug("Created ServiceNow incident", "incident", created.Result.Number)
+	return true, nil
+}
+
+// findIncident returns the active incident with the correlation ID, or nil if there is none.
+func (n *Notifier) findIncident(ctx context.Context, correlationID string) (*record, error) {
+	q := url.Values{}
+	q.Set("sysparm_query", "correlation_id="+correlationID+"^active=true")
+	q.Set("sysparm_fields", "sys_id,number")
+	q.Set("sysparm_limit", "1")
+	var res listResponse
+	if err := n.do(ctx, http.MethodGet, "/api/now/table/"+n.settings.Table+"?"+q.Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Result) == 0 {
+		return nil, nil
+	}
+	return &res.Result[0], nil
+}
+
+func (n *Notifier) recordPath(sysID string) string {
+	return "/api/now/table/" + n.settings.Table + "/" + url.PathEscape(sysID)
+}
+
+// do sends a request to the ServiceNow Table API and decodes the response into res if it is not nil.
+func (n *Notifier) do(ctx context.Context, method, path string, req any, res any) error {
+	var body []byte
+	if req != nil {
+		var err error
+		if body, err = json.Marshal(req); err != nil {
+			return err
+		}
+	}
+	cmd := &receivers.SendWebhookSettings{
+		URL:        n.settings.URL + path,
+		User:       n.settings.User,
+		Password:   n.settings.Password,
+		Body:       string(body),
+		HTTPMethod: method,
+		HTTPHeader: map[string]string{"Accept": "application/json"},
+		Validation: func(body []byte, statusCode int) error {
+			if statusCode/100 != 2 {
+				b, _ := receivers.TruncateInBytes(string(body), maxErrorBodyLen)
+				return apiError{statusCode: statusCode, body: b}
+			}
+			if res == nil || len(body) == 0 {
+				return nil
+			}
+			if err := json.Unmarshal(body, res); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			return nil
+		},
+	}
+	return n.ns.SendWebhook(ctx, cmd)
+}
+
+// shouldRetry returns false if ServiceNow rejected the request, because sending it again would fail the same way.
+func shouldRetry(err error) bool {
+	var e apiError
+	if errors.As(err, &e) {
+		return e.statusCode == http.StatusTooManyRequests || e.statusCode/100 == 5
+	}
+	return true
+}
+
+func (n *Notifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
@@ -0,0 +1,181 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+)
+
+type response struct {
+	status int
+	body   string
+}
+
+// fakeSender responds to the requests of the notifier by method and path, without the query.
+type fakeSender struct {
+	responses map[string]response
+	requests  []*receivers.SendWebhookSettings
+}
+
+func (s *fakeSender) SendWebhook(_ context.Context, cmd *receivers.SendWebhookSettings) error {
+	s.requests = append(s.requests, cmd)
+	res, ok := s.responses[requestPath(cmd)]
+	if !ok {
+		res = response{status: http.StatusNotFound}
+	}
+	if cmd.Validation != nil {
+		return cmd.Validation([]byte(res.body), res.status)
+	}
+	return nil
+}
+
+func (s *fakeSender) calls() []string {
+	result := make([]string, 0, len(s.requests))
+	for _, r := range s.requests {
+		result = append(result, requestPath(r))
+	}
+	return result
+}
+
+func requestPath(cmd *receivers.SendWebhookSettings) string {
+	path, _, _ := strings.Cut(strings.TrimPrefix(cmd.URL, "https://example.service-now.com"), "?")
+	return cmd.HTTPMethod + " " + path
+}
+
+func TestNotify(t *testing.T) {
+	key := notify.Key("{}:{alertname=\"test\"}")
+	ctx := notify.WithGroupKey(context.Background(), string(key))
+	firing := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+	resolved := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(-time.Minute),
+	}}
+	cfg := Config{
+		URL:              "https://example.service-now.com",
+		Table:            DefaultTable,
+		User:             "grafana",
+		Password:         "secret",
+		ShortDescription: "{{ .CommonLabels.alertname }} is {{ .Status }}",
+		Description:      "description",
+		Urgency:          "1",
+		ResolveState:     DefaultResolveState,
+		CloseCode:        DefaultCloseCode,
+		CloseNotes:       DefaultCloseNotes,
+	}
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+	newNotifier := func(responses map[string]response) (*Notifier, *fakeSender) {
+		sender := &fakeSender{responses: responses}
+		return New(cfg, receivers.Metadata{}, tmpl, sender, &logging.FakeLogger{}), sender
+	}
+	body := func(t *testing.T, cmd *receivers.SendWebhookSettings) map[string]any {
+		t.Helper()
+		var result map[string]any
+		require.NoError(t, json.Unmarshal([]byte(cmd.Body), &result))
+		return result
+	}
+
+	t.Run("finds the active incident by the correlation ID of the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"GET /api/now/table/incident":  {status: http.StatusOK, body: `{"result": []}`},
+			"POST /api/now/table/incident": {status: http.StatusCreated, body: `{"result": {"sys_id": "1", "number": "INC1"}}`},
+		})
+		_, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+
+		search := sender.requests[0]
+		require.Equal(t, "grafana", search.User)
+		require.Equal(t, "secret", search.Password)
+		u, err := url.Parse(search.URL)
+		require.NoError(t, err)
+		require.Equal(t, "correlation_id="+CorrelationID(key)+"^active=true", u.Query().Get("sysparm_query"))
+	})
+
+	t.Run("creates an incident if there is none for the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"GET /api/now/table/incident":  {status: http.StatusOK, body: `{"result": []}`},
+			"POST /api/now/table/incident": {status: http.StatusCreated, body: `{"result": {"sys_id": "1", "number": "INC1"}}`},
+		})
+		retry, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.True(t, retry)
+		require.Equal(t, []string{"GET /api/now/table/incident", "POST /api/now/table/incident"}, sender.calls())
+		require.Equal(t, map[string]any{
+			"short_description":   "test is firing",
+			"description":         "description",
+			"correlation_id":      CorrelationID(key),
+			"correlation_display": "Grafana",
+			"urgency":             "1",
+		}, body(t, sender.requests[1]))
+	})
+
+	t.Run("updates the incident of the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"GET /api/now/table/incident":     {status: http.StatusOK, body: `{"result": [{"sys_id": "abc", "number": "INC1"}]}`},
+			"PUT /api/now/table/incident/abc": {status: http.StatusOK, body: `{"result": {"sys_id": "abc", "number": "INC1"}}`},
+		})
+		_, err := n.Notify(ctx, firing)
+		require.NoError(t, err)
+		require.Equal(t, []string{"GET /api/now/table/incident", "PUT /api/now/table/incident/abc"}, sender.calls())
+		require.Equal(t, map[string]any{"short_description": "test is firing", "description": "description"}, body(t, sender.requests[1]))
+	})
+
+	t.Run("resolves the incident of the group", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"GET /api/now/table/incident":     {status: http.StatusOK, body: `{"result": [{"sys_id": "abc", "number": "INC1"}]}`},
+			"PUT /api/now/table/incident/abc": {status: http.StatusOK, body: `{"result": {"sys_id": "abc", "number": "INC1"}}`},
+		})
+		_, err := n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		require.Equal(t, []string{"GET /api/now/table/incident", "PUT /api/now/table/incident/abc"}, sender.calls())
+		require.Equal(t, map[string]any{
+			"state":       DefaultResolveState,
+			"close_code":  DefaultCloseCode,
+			"close_notes": DefaultCloseNotes,
+		}, body(t, sender.requests[1]))
+	})
+
+	t.Run("does nothing for resolved alerts without an incident", func(t *testing.T) {
+		n, sender := newNotifier(map[string]response{
+			"GET /api/now/table/incident": {status: http.StatusOK, body: `{"result": []}`},
+		})
+		_, err := n.Notify(ctx, resolved)
+		require.NoError(t, err)
+		require.Equal(t, []string{"GET /api/now/table/incident"}, sender.calls())
+	})
+
+	t.Run("retries only errors that are not caused by the request", func(t *testing.T) {
+		n, _ := newNotifier(map[string]response{
+			"GET /api/now/table/incident": {status: http.StatusTooManyRequests},
+		})
+		retry, err := n.Notify(ctx, firing)
+		require.ErrorContains(t, err, "unexpected status code 429 from ServiceNow")
+		require.True(t, retry)
+
+		n, _ = newNotifier(map[string]response{
+			"GET /api/now/table/incident": {status: http.StatusForbidden},
+		})
+		retry, err = n.Notify(ctx, firing)
+		require.ErrorContains(t, err, "unexpected status code 403 from ServiceNow")
+		require.False(t, retry)
+	})
+}
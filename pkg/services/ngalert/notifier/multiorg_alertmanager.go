@@ -574,6 +574,72 @@ func (moa *MultiOrgAlertmanager) updateSilenceState(ctx context.Context, orgAM A
 	return err
 }
 
+// GetAlertmanagerStates returns the state of the Alertmanagers of all organizations. The silences of running
+// Alertmanagers are read from memory, while the notification log is read from its last snapshot in the kvstore.
+func (moa *MultiOrgAlertmanager) GetAlertmanagerStates(ctx context.Context) (map[int64]AlertmanagerState, error) {
+	result, err := NewAlertmanagerStateStore(moa.kvStore).GetAlertmanagerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	moa.alertmanagersMtx.RLock()
+	defer moa.alertmanagersMtx.RUnlock()
+	for orgID, orgAM := range moa.alertmanagers {
+		if !orgAM.Ready() {
+			continue
+		}
+		silences, err := orgAM.SilenceState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get silences of organization %d: %w", orgID, err)
+		}
+		b, err := silences.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal silences of organization %d: %w", orgID, err)
+		}
+		st := result[orgID]
+		st.Silences = b
+		result[orgID] = st
+	}
+	return result, nil
+}
+
+// RestoreAlertmanagerState replaces the state of the Alertmanager of the organization. The running Alertmanager
+// of the organization is stopped before the state is written and started again afterwards, so that it loads it.
+func (moa *MultiOrgAlertmanager) RestoreAlertmanagerState(ctx context.Context, orgID int64, st AlertmanagerState) error {
+	moa.alertmanagersMtx.Lock()
+	if orgAM, ok := moa.alertmanagers[orgID]; ok {
+		moa.logger.Info("Stopping Alertmanager to restore its state", "org", orgID)
+		// Stopping the Alertmanager persists its state, so it must be done before the new state is written.
+		orgAM.StopAndWait()
+		delete(moa.alertmanagers, orgID)
+		moa.metrics.RemoveOrgRegistry(orgID)
+	}
+	err := NewFileStore(orgID, moa.kvStore).SaveState(ctx, st)
+	moa.alertmanagersMtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return moa.LoadAndSyncAlertmanagersForOrgs(ctx)
+}
+
+// ClusterMembers returns the name of this instance in the HA cluster and the names of the live members of the
+// cluster. Without clustering, the name is empty and there are no members.
+func (moa *MultiOrgAlertmanager) ClusterMembers() (string, []string) {
+	switch p := moa.peer.(type) {
+	case *alertingCluster.Peer:
+		peers := p.Peers()
+		members := make([]string, 0, len(peers))
+		for _, m := range peers {
+			members = append(members, m.Name())
+		}
+		return p.Name(), members
+	case *redisPeer:
+		return p.withPrefix(p.name), p.Members()
+	default:
+		return "", nil
+	}
+}
+
 // NilPeer and NilChannel implements the Alertmanager clustering interface.
 type NilPeer struct{}
 
@@ -364,6 +364,51 @@ func TestMultiOrgAlertmanager_Silences(t *testing.T) {
 	require.True(t, time.Now().After(state[sid].Silence.EndsAt)) // Expired.
 }
 
+func TestMultiOrgAlertmanager_AlertmanagerState(t *testing.T) {
+	ctx := context.Background()
+	source := setupMam(t, nil)
+	require.NoError(t, source.LoadAndSyncAlertmanagersForOrgs(ctx))
+
+	gen := models.SilenceGen(models.SilenceMuts.WithEmptyId())
+	sid, err := source.CreateSilence(ctx, 1, gen())
+	require.NoError(t, err)
+
+	// The notification log is only read from the kvstore.
+	now := time.Now()
+	k, v := createNotificationLog("group", "receiver", now, now.Add(time.Hour))
+	nflog, err := nflogState{k: v}.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, source.kvStore.Set(ctx, 1, KVNamespace, NotificationLogFilename, encode(nflog)))
+
+	states, err := source.GetAlertmanagerStates(ctx)
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+	require.Equal(t, nflog, states[1].NotificationLog)
+	require.Empty(t, states[2].NotificationLog)
+
+	// The Alertmanagers are started with the restored state.
+	target := setupMam(t, nil)
+	require.NoError(t, target.RestoreAlertmanagerState(ctx, 1, states[1]))
+	require.Len(t, target.alertmanagers, 3)
+	silence, err := target.GetSilence(ctx, 1, sid)
+	require.NoError(t, err)
+	require.Equal(t, sid, *silence.ID)
+	restored, err := NewFileStore(1, target.kvStore).GetNotificationLog(ctx)
+	require.NoError(t, err)
+	require.Equal(t, string(nflog), restored)
+
+	// Running Alertmanagers are restarted with the restored state.
+	// Give the inhibitors of the Alertmanagers time to start, they can't be stopped before.
+	time.Sleep(100 * time.Millisecond)
+	_, err = source.GetSilence(ctx, 2, sid)
+	require.Error(t, err)
+	require.NoError(t, source.RestoreAlertmanagerState(ctx, 2, states[1]))
+	require.Len(t, source.alertmanagers, 3)
+	silence, err = source.GetSilence(ctx, 2, sid)
+	require.NoError(t, err)
+	require.Equal(t, sid, *silence.ID)
+}
+
 func setupMam(t *testing.T, cfg *setting.Cfg) *MultiOrgAlertmanager {
 	if cfg == nil {
 		tmpDir := t.TempDir()
@@ -0,0 +1,431 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// notificationQueueBatchSize is the maximum number of notifications retried in one poll.
+	notificationQueueBatchSize = 100
+	// notificationQueueSendTimeout limits how long a retry of a notification can take.
+	notificationQueueSendTimeout = 30 * time.Second
+	// notificationQueueWriteTimeout limits how long it can take to save a failed notification.
+	notificationQueueWriteTimeout = 10 * time.Second
+	// notificationQueueCleanupInterval is how often dead notifications older than the retention are deleted.
+	notificationQueueCleanupInterval = time.Hour
+)
+
+var errIntegrationNotFound = errors.New("the integration does not exist anymore")
+
+// queuedNotificationPayload is what is needed to send a queued notification again. It is saved as JSON in the payload
+// of the queued notification.
+type queuedNotificationPayload struct {
+	GroupLabels    model.LabelSet `json:"groupLabels"`
+	Now            time.Time      `json:"now"`
+	RepeatInterval time.Duration  `json:"repeatInterval"`
+	Alerts         []*types.Alert `json:"alerts"`
+}
+
+// DecodeQueuedNotificationAlerts returns the alerts of the queued notification.
+func DecodeQueuedNotificationAlerts(n *models.QueuedNotification) ([]*types.Alert, error) {
+	var payload queuedNotificationPayload
+	if err := json.Unmarshal(n.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode queued notification: %w", err)
+	}
+	return payload.Alerts, nil
+}
+
+type queuedIntegrationKey struct {
+	receiver        string
+	integrationType string
+	index           int
+}
+
+// queuedIntegrations are the integrations of a configuration of the Alertmanager, whose failed notifications are queued.
+// Integrations are added while the configuration is applied. After that it is sealed, so that integrations built
+// later, for example to test receivers, are not used to retry queued notifications.
+type queuedIntegrations struct {
+	mtx          sync.RWMutex
+	sealed       bool
+	integrations map[queuedIntegrationKey]*alertingNotify.Integration
+	// byReceiver contains the integrations of each receiver in the order they were built. It is nil for integrations
+	// whose notifications are not queued.
+	byReceiver map[string][]*alertingNotify.Integration
+}
+
+func newQueuedIntegrations() *queuedIntegrations {
+	return &queuedIntegrations{
+		integrations: make(map[queuedIntegrationKey]*alertingNotify.Integration),
+		byReceiver:   make(map[string][]*alertingNotify.Integration),
+	}
+}
+
+func (qi *queuedIntegrations) add(receiver string, queued []*alertingNotify.Integration) {
+	qi.mtx.Lock()
+	defer qi.mtx.Unlock()
+	if qi.sealed {
+		return
+	}
+	for _, i := range queued {
+		if i != nil {
+			qi.integrations[queuedIntegrationKey{receiver: receiver, integrationType: i.Name(), index: i.Index()}] = i
+		}
+	}
+	qi.byReceiver[receiver] = queued
+}
+
+func (qi *queuedIntegrations) seal() {
+	qi.mtx.Lock()
+	defer qi.mtx.Unlock()
+	qi.sealed = true
+}
+
+func (qi *queuedIntegrations) get(key queuedIntegrationKey) (*alertingNotify.Integration, bool) {
+	qi.mtx.RLock()
+	defer qi.mtx.RUnlock()
+	i, ok := qi.integrations[key]
+	return i, ok
+}
+
+func (qi *queuedIntegrations) receiver(name string) []*alertingNotify.Integration {
+	qi.mtx.RLock()
+	defer qi.mtx.RUnlock()
+	return qi.byReceiver[name]
+}
+
+// notificationQueue persists notifications that integrations of the Alertmanager failed to deliver and retries them
+// in the background with an exponential backoff. Notifications that fail with an error that is not retryable, or that
+// still fail after the maximum number of attempts, are moved to the dead-letter list, from where they can be replayed.
+//
+// Because the notifications are saved in the database, they survive restarts of Grafana, and instances in a
+// high-availability setup can retry notifications queued by other instances.
+type notificationQueue struct {
+	orgID    int64
+	store    store.NotificationQueueStore
+	settings setting.UnifiedAlertingNotificationQueueSettings
+	clock    clock.Clock
+	logger   log.Logger
+
+	mtx          sync.RWMutex
+	integrations *queuedIntegrations
+	// backoff is when the integration can be retried next. While an integration backs off, none of its notifications
+	// are retried, so a failing endpoint does not get a retry for each of its queued notifications.
+	backoff     map[queuedIntegrationKey]time.Time
+	lastCleanup time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newNotificationQueue(orgID int64, store store.NotificationQueueStore, settings setting.UnifiedAlertingNotificationQueueSettings, clock clock.Clock, logger log.Logger) *notificationQueue {
+	return &notificationQueue{
+		orgID:        orgID,
+		store:        store,
+		settings:     settings,
+		clock:        clock,
+		logger:       logger,
+		integrations: newQueuedIntegrations(),
+		backoff:      make(map[queuedIntegrationKey]time.Time),
+		stop:         make(chan struct{}),
+	}
+}
+
+// wrapIntegrations returns the integrations of the receiver with the integrations whose notifications are queued
+// wrapped, so that their failed notifications are saved in the queue. The original integrations are added to
+// registry, which is used to retry the notifications once the configuration is applied.
+func (q *notificationQueue) wrapIntegrations(registry *queuedIntegrations, receiver string, integrations []*alertingNotify.Integration) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	queued := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, i := range integrations {
+		if !q.settings.IsIntegrationQueued(i.Name()) {
+			result = append(result, i)
+			queued = append(queued, nil)
+			continue
+		}
+		n := &queuedNotifier{
+			queue:       q,
+			integration: i,
+			key:         queuedIntegrationKey{receiver: receiver, integrationType: i.Name(), index: i.Index()},
+		}
+		result = append(result, alertingNotify.NewIntegration(n, i, i.Name(), i.Index(), receiver))
+		queued = append(queued, i)
+	}
+	registry.add(receiver, queued)
+	return result
+}
+
+// setIntegrations makes the queue retry notifications with the integrations of the applied configuration.
+func (q *notificationQueue) setIntegrations(registry *queuedIntegrations) {
+	registry.seal()
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.integrations = registry
+}
+
+func (q *notificationQueue) getIntegrations() *queuedIntegrations {
+	q.mtx.RLock()
+	defer q.mtx.RUnlock()
+	return q.integrations
+}
+
+// enqueue saves a notification that the integration failed to deliver. Notifications that failed with a retryable error are
+// retried after the initial backoff, others are moved to the dead-letter list right away.
+func (q *notificationQueue) enqueue(ctx context.Context, key queuedIntegrationKey, retry bool, sendErr error, alerts []*types.Alert) error {
+	groupKey, _ := notify.GroupKey(ctx)
+	groupLabels, _ := notify.GroupLabels(ctx)
+	now, ok := notify.Now(ctx)
+	if !ok {
+		now = q.clock.Now()
+	}
+	repeatInterval, _ := notify.RepeatInterval(ctx)
+	payload, err := json.Marshal(queuedNotificationPayload{
+		GroupLabels:    groupLabels,
+		Now:            now,
+		RepeatInterval: repeatInterval,
+		Alerts:         alerts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ts := q.clock.Now()
+	n := &models.QueuedNotification{
+		OrgID:            q.orgID,
+		Receiver:         key.receiver,
+		IntegrationType:  key.integrationType,
+		IntegrationIndex: key.index,
+		GroupKey:         groupKey,
+		Payload:          payload,
+		Status:           models.QueuedNotificationPending,
+		Attempts:         1,
+		LastError:        sendErr.Error(),
+		NextAttemptAt:    ts.Add(q.backoffAfter(1)),
+		CreatedAt:        ts,
+		UpdatedAt:        ts,
+	}
+	if !retry {
+		n.Status = models.QueuedNotificationDead
+	}
+
+	// The notification pipeline cancels the context when the notification times out, which is one of the reasons the
+	// notification is queued. Therefore, the notification is saved with a context that is not canceled with it.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationQueueWriteTimeout)
+	defer cancel()
+	if err := q.store.EnqueueNotification(ctx, n); err != nil {
+		return err
+	}
+	q.logger.Warn("Failed to send notification, queued it for retry", "receiver", key.receiver, "integration", key.integrationType, "index", key.index, "status", n.Status, "error", sendErr)
+	return nil
+}
+
+// backoffAfter returns how long to wait before the next attempt after the given number of failed attempts.
+func (q *notificationQueue) backoffAfter(attempts int) time.Duration {
+	backoff := q.settings.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= q.settings.MaxBackoff {
+			return q.settings.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// run retries due notifications until the queue is stopped.
+func (q *notificationQueue) run() {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		ticker := q.clock.Ticker(q.settings.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-q.stop:
+				return
+			case <-ticker.C:
+				q.processDue(context.Background())
+			}
+		}
+	}()
+}
+
+func (q *notificationQueue) stopAndWait() {
+	close(q.stop)
+	q.wg.Wait()
+}
+
+// processDue retries the notifications whose next attempt is due. Successfully delivered notifications are deleted
+// from the queue.
+func (q *notificationQueue) processDue(ctx context.Context) {
+	now := q.clock.Now()
+	q.cleanup(ctx, now)
+
+	due, err := q.store.GetDueQueuedNotifications(ctx, q.orgID, now, notificationQueueBatchSize)
+	if err != nil {
+		q.logger.Error("Failed to get queued notifications", "error", err)
+		return
+	}
+	integrations := q.getIntegrations()
+	for _, n := range due {
+		key := queuedIntegrationKey{receiver: n.Receiver, integrationType: n.IntegrationType, index: n.IntegrationIndex}
+		// Replayed notifications have no attempts. They are retried right away, even if the integration backs off,
+		// because the backoff is only known to this instance while notifications can be replayed with any of them.
+		if n.Attempts == 0 {
+			delete(q.backoff, key)
+		}
+		if until, ok := q.backoff[key]; ok && now.Before(until) {
+			continue
+		}
+		// Another instance may be retrying the notification at the same time. The lease makes sure that only one of
+		// them sends it.
+		claimed, err := q.store.ClaimQueuedNotification(ctx, n, now.Add(2*notificationQueueSendTimeout))
+		if err != nil {
+			q.logger.Error("Failed to claim queued notification", "id", n.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		retry, err := q.send(ctx, integrations, key, n)
+		if err == nil {
+			delete(q.backoff, key)
+			if err := q.store.DeleteQueuedNotification(ctx, q.orgID, n.ID); err != nil {
+				q.logger.Error("Failed to delete delivered notification from the queue", "id", n.ID, "error", err)
+			}
+			q.deleteSuperseded(ctx, n)
+			q.logger.Info("Delivered queued notification", "receiver", n.Receiver, "integration", n.IntegrationType, "index", n.IntegrationIndex, "attempts", n.Attempts+1)
+			continue
+		}
+
+		n.Attempts++
+		n.LastError = err.Error()
+		n.UpdatedAt = q.clock.Now()
+		if !retry || n.Attempts >= q.settings.MaxAttempts {
+			n.Status = models.QueuedNotificationDead
+			q.logger.Warn("Moved notification to the dead-letter list", "receiver", n.Receiver, "integration", n.IntegrationType, "index", n.IntegrationIndex, "attempts", n.Attempts, "error", err)
+		} else {
+			n.NextAttemptAt = n.UpdatedAt.Add(q.backoffAfter(n.Attempts))
+			q.backoff[key] = n.NextAttemptAt
+			q.logger.Debug("Failed to retry queued notification", "receiver", n.Receiver, "integration", n.IntegrationType, "index", n.IntegrationIndex, "attempts", n.Attempts, "nextAttempt", n.NextAttemptAt, "error", err)
+		}
+		if err := q.store.UpdateQueuedNotification(ctx, n); err != nil {
+			// The notification is deleted if a newer notification of the same group was queued or delivered meanwhile.
+			if errors.Is(err, models.ErrQueuedNotificationNotFound) {
+				q.logger.Debug("Queued notification was superseded while it was retried", "id", n.ID)
+				continue
+			}
+			q.logger.Error("Failed to update queued notification", "id", n.ID, "error", err)
+		}
+	}
+}
+
+// deleteSuperseded deletes the pending notifications of the same integration and group that were queued before n. They
+// are superseded by n, which contains the latest state of the alerts of the group.
+func (q *notificationQueue) deleteSuperseded(ctx context.Context, n *models.QueuedNotification) {
+	deleted, err := q.store.DeleteSupersededQueuedNotifications(ctx, n)
+	if err != nil {
+		q.logger.Error("Failed to delete superseded notifications from the queue", "receiver", n.Receiver, "integration", n.IntegrationType, "index", n.IntegrationIndex, "error", err)
+		return
+	}
+	if deleted > 0 {
+		q.logger.Debug("Deleted superseded notifications from the queue", "receiver", n.Receiver, "integration", n.IntegrationType, "index", n.IntegrationIndex, "count", deleted)
+	}
+}
+
+// delivered deletes the pending notifications of the group that were queued for the integration, after the
+// notification pipeline delivered a newer notification of the group.
+func (q *notificationQueue) delivered(ctx context.Context, key queuedIntegrationKey) {
+	groupKey, ok := notify.GroupKey(ctx)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notificationQueueWriteTimeout)
+	defer cancel()
+	q.deleteSuperseded(ctx, &models.QueuedNotification{
+		OrgID:            q.orgID,
+		Receiver:         key.receiver,
+		IntegrationType:  key.integrationType,
+		IntegrationIndex: key.index,
+		GroupKey:         groupKey,
+	})
+}
+
+// send sends the queued notification with the integration of the current configuration.
+func (q *notificationQueue) send(ctx context.Context, integrations *queuedIntegrations, key queuedIntegrationKey, n *models.QueuedNotification) (bool, error) {
+	integration, ok := integrations.get(key)
+	if !ok {
+		return false, errIntegrationNotFound
+	}
+	var payload queuedNotificationPayload
+	if err := json.Unmarshal(n.Payload, &payload); err != nil {
+		return false, fmt.Errorf("failed to decode queued notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationQueueSendTimeout)
+	defer cancel()
+	ctx = notify.WithGroupKey(ctx, n.GroupKey)
+	ctx = notify.WithGroupLabels(ctx, payload.GroupLabels)
+	ctx = notify.WithReceiverName(ctx, n.Receiver)
+	ctx = notify.WithNow(ctx, payload.Now)
+	ctx = notify.WithRepeatInterval(ctx, payload.RepeatInterval)
+	return integration.Notify(ctx, payload.Alerts...)
+}
+
+func (q *notificationQueue) cleanup(ctx context.Context, now time.Time) {
+	if q.settings.DeadLetterRetention <= 0 || now.Sub(q.lastCleanup) < notificationQueueCleanupInterval {
+		return
+	}
+	q.lastCleanup = now
+	deleted, err := q.store.DeleteDeadQueuedNotifications(ctx, q.orgID, now.Add(-q.settings.DeadLetterRetention))
+	if err != nil {
+		q.logger.Error("Failed to delete expired dead notifications", "error", err)
+		return
+	}
+	if deleted > 0 {
+		q.logger.Debug("Deleted expired dead notifications", "count", deleted)
+	}
+}
+
+// queuedNotifier sends notifications with an integration and saves the notifications that it fails to deliver in
+// the notification queue.
+type queuedNotifier struct {
+	queue       *notificationQueue
+	integration *alertingNotify.Integration
+	key         queuedIntegrationKey
+}
+
+// Notify implements notify.Notifier. If the notification is queued, it reports success, so that the notification
+// pipeline neither retries it nor sends it again at the next flush of the aggregation group.
+func (n *queuedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	retry, err := n.integration.Notify(ctx, alerts...)
+	// Only the notification pipeline sets the repeat interval. Other notifications, such as test notifications,
+	// are not queued so that their errors are reported to the caller.
+	if _, ok := notify.RepeatInterval(ctx); !ok {
+		return retry, err
+	}
+	if err == nil {
+		n.queue.delivered(ctx, n.key)
+		return retry, nil
+	}
+	if qerr := n.queue.enqueue(ctx, n.key, retry, err, alerts); qerr != nil {
+		n.queue.logger.Error("Failed to queue notification", "receiver", n.key.receiver, "integration", n.key.integrationType, "index", n.key.index, "error", qerr)
+		return retry, err
+	}
+	return false, nil
+}
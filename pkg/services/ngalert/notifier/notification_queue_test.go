@@ -0,0 +1,347 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeQueueNotifier struct {
+	mtx   sync.Mutex
+	err   error
+	retry bool
+	calls []context.Context
+}
+
+func (f *fakeQueueNotifier) Notify(ctx context.Context, _ ...*types.Alert) (bool, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.calls = append(f.calls, ctx)
+	return f.retry, f.err
+}
+
+func (f *fakeQueueNotifier) SendResolved() bool {
+	return true
+}
+
+func (f *fakeQueueNotifier) setError(retry bool, err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.retry = retry
+	f.err = err
+}
+
+func (f *fakeQueueNotifier) callCount() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return len(f.calls)
+}
+
+func TestNotificationQueue(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	settings := setting.UnifiedAlertingNotificationQueueSettings{
+		Enabled:             true,
+		Integrations:        map[string]struct{}{"webhook": {}},
+		MaxAttempts:         3,
+		InitialBackoff:      time.Minute,
+		MaxBackoff:          3 * time.Minute,
+		PollInterval:        10 * time.Second,
+		DeadLetterRetention: time.Hour,
+	}
+
+	type testEnv struct {
+		store    *fakeConfigStore
+		clock    *clock.Mock
+		queue    *notificationQueue
+		webhook  *fakeQueueNotifier
+		email    *fakeQueueNotifier
+		wrapped  []*alertingNotify.Integration
+		original []*alertingNotify.Integration
+	}
+	setup := func(t *testing.T) testEnv {
+		env := testEnv{
+			store:   NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{}),
+			clock:   clock.NewMock(),
+			webhook: &fakeQueueNotifier{},
+			email:   &fakeQueueNotifier{},
+		}
+		env.clock.Set(start)
+		env.queue = newNotificationQueue(1, env.store, settings, env.clock, log.NewNopLogger())
+		env.original = []*alertingNotify.Integration{
+			alertingNotify.NewIntegration(env.webhook, env.webhook, "webhook", 0, "webhook receiver"),
+			alertingNotify.NewIntegration(env.email, env.email, "email", 0, "email receiver"),
+		}
+		registry := newQueuedIntegrations()
+		env.wrapped = env.queue.wrapIntegrations(registry, "team-a", env.original)
+		env.queue.setIntegrations(registry)
+		return env
+	}
+
+	pipelineCtx := func() context.Context {
+		ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+		ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+		ctx = notify.WithReceiverName(ctx, "team-a")
+		ctx = notify.WithNow(ctx, start)
+		return notify.WithRepeatInterval(ctx, 4*time.Hour)
+	}
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test", "team": "a"},
+		StartsAt: start.Add(-time.Minute),
+	}}
+
+	queued := func(t *testing.T, env testEnv, status models.QueuedNotificationStatus) []*models.QueuedNotification {
+		t.Helper()
+		result, err := env.store.ListQueuedNotifications(context.Background(), models.ListQueuedNotificationsQuery{OrgID: 1, Receiver: "team-a", Status: status})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("wraps only integrations whose notifications are queued", func(t *testing.T) {
+		env := setup(t)
+		require.Len(t, env.wrapped, 2)
+		require.NotSame(t, env.original[0], env.wrapped[0])
+		require.Same(t, env.original[1], env.wrapped[1])
+		require.Equal(t, "webhook", env.wrapped[0].Name())
+		require.Equal(t, 0, env.wrapped[0].Index())
+	})
+
+	t.Run("queues failed notifications and reports success to the pipeline", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+
+		retry, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+
+		result := queued(t, env, models.QueuedNotificationPending)
+		require.Len(t, result, 1)
+		n := result[0]
+		require.Equal(t, "team-a", n.Receiver)
+		require.Equal(t, "webhook", n.IntegrationType)
+		require.Equal(t, "{}:{alertname=\"test\"}", n.GroupKey)
+		require.Equal(t, 1, n.Attempts)
+		require.Equal(t, "connection refused", n.LastError)
+		require.Equal(t, start.Add(time.Minute), n.NextAttemptAt)
+
+		alerts, err := DecodeQueuedNotificationAlerts(n)
+		require.NoError(t, err)
+		require.Len(t, alerts, 1)
+		require.Equal(t, alert.Labels, alerts[0].Labels)
+	})
+
+	t.Run("moves notifications that failed with errors that are not retryable to the dead-letter list", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(false, errors.New("bad request"))
+
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		require.Len(t, queued(t, env, models.QueuedNotificationDead), 1)
+	})
+
+	t.Run("does not queue notifications that are not sent by the notification pipeline", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+
+		_, err := env.wrapped[0].Notify(notify.WithGroupKey(context.Background(), "test"), alert)
+		require.ErrorContains(t, err, "connection refused")
+		require.Empty(t, queued(t, env, ""))
+	})
+
+	t.Run("retries queued notifications with exponential backoff", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		require.Equal(t, 1, env.webhook.callCount())
+
+		env.queue.processDue(context.Background())
+		require.Equal(t, 1, env.webhook.callCount(), "the notification should not be retried before the backoff")
+
+		env.clock.Add(time.Minute)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 2, env.webhook.callCount())
+		n := queued(t, env, models.QueuedNotificationPending)[0]
+		require.Equal(t, 2, n.Attempts)
+		require.Equal(t, env.clock.Now().Add(2*time.Minute), n.NextAttemptAt)
+
+		// The retry gets the context of the original notification.
+		ctx := env.webhook.calls[1]
+		groupKey, _ := notify.GroupKey(ctx)
+		require.Equal(t, "{}:{alertname=\"test\"}", groupKey)
+		receiver, _ := notify.ReceiverName(ctx)
+		require.Equal(t, "team-a", receiver)
+		groupLabels, _ := notify.GroupLabels(ctx)
+		require.Equal(t, model.LabelSet{"alertname": "test"}, groupLabels)
+
+		env.clock.Add(2 * time.Minute)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 3, env.webhook.callCount())
+		require.Empty(t, queued(t, env, models.QueuedNotificationPending))
+		dead := queued(t, env, models.QueuedNotificationDead)
+		require.Len(t, dead, 1)
+		require.Equal(t, 3, dead[0].Attempts)
+	})
+
+	t.Run("deletes delivered notifications", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+
+		env.webhook.setError(false, nil)
+		env.clock.Add(time.Minute)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 2, env.webhook.callCount())
+		require.Empty(t, queued(t, env, ""))
+	})
+
+	t.Run("backs off the whole integration after a failed retry", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		env.clock.Add(30 * time.Second)
+		_, err = env.wrapped[0].Notify(notify.WithGroupKey(pipelineCtx(), "{}:{alertname=\"other\"}"), alert)
+		require.NoError(t, err)
+
+		env.clock.Add(30 * time.Second)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 3, env.webhook.callCount(), "only the first notification should be retried")
+
+		env.clock.Add(30 * time.Second)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 3, env.webhook.callCount(), "the second notification should wait for the backoff of the integration")
+	})
+
+	t.Run("supersedes pending notifications of the same group when a newer one is queued", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		first := queued(t, env, models.QueuedNotificationPending)[0]
+
+		env.clock.Add(30 * time.Second)
+		_, err = env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		_, err = env.wrapped[0].Notify(notify.WithGroupKey(pipelineCtx(), "{}:{alertname=\"other\"}"), alert)
+		require.NoError(t, err)
+
+		pending := queued(t, env, models.QueuedNotificationPending)
+		require.Len(t, pending, 2)
+		for _, n := range pending {
+			require.NotEqual(t, first.ID, n.ID)
+		}
+	})
+
+	t.Run("deletes pending notifications of the group when a newer one is delivered", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		_, err = env.wrapped[0].Notify(notify.WithGroupKey(pipelineCtx(), "{}:{alertname=\"other\"}"), alert)
+		require.NoError(t, err)
+
+		env.webhook.setError(false, nil)
+		_, err = env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+
+		pending := queued(t, env, models.QueuedNotificationPending)
+		require.Len(t, pending, 1)
+		require.Equal(t, "{}:{alertname=\"other\"}", pending[0].GroupKey)
+	})
+
+	t.Run("retries replayed notifications even if the integration backs off", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+		env.clock.Add(time.Minute)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 2, env.webhook.callCount())
+
+		_, err = env.store.ReplayQueuedNotifications(context.Background(), models.ReplayQueuedNotificationsCmd{
+			OrgID:    1,
+			Receiver: "team-a",
+			IDs:      []int64{queued(t, env, models.QueuedNotificationPending)[0].ID},
+			Now:      env.clock.Now(),
+		})
+		require.NoError(t, err)
+		env.webhook.setError(false, nil)
+		env.queue.processDue(context.Background())
+		require.Equal(t, 3, env.webhook.callCount())
+		require.Empty(t, queued(t, env, ""))
+		require.Empty(t, env.queue.backoff)
+	})
+
+	t.Run("moves notifications of integrations that do not exist anymore to the dead-letter list", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+
+		env.queue.setIntegrations(newQueuedIntegrations())
+		env.clock.Add(time.Minute)
+		env.queue.processDue(context.Background())
+		dead := queued(t, env, models.QueuedNotificationDead)
+		require.Len(t, dead, 1)
+		require.Equal(t, errIntegrationNotFound.Error(), dead[0].LastError)
+	})
+
+	t.Run("deletes dead notifications after the retention", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(false, errors.New("bad request"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+
+		env.clock.Add(2 * time.Hour)
+		env.queue.processDue(context.Background())
+		require.Empty(t, queued(t, env, ""))
+	})
+
+	t.Run("sealed registries do not accept integrations", func(t *testing.T) {
+		env := setup(t)
+		registry := env.queue.getIntegrations()
+		env.queue.wrapIntegrations(registry, "test-receiver", env.original)
+		require.Nil(t, registry.receiver("test-receiver"))
+	})
+
+	t.Run("reports the delivery status of the queued integrations", func(t *testing.T) {
+		env := setup(t)
+		env.webhook.setError(true, errors.New("connection refused"))
+		_, err := env.wrapped[0].Notify(pipelineCtx(), alert)
+		require.NoError(t, err)
+
+		am := &alertmanager{queue: env.queue}
+		receivers := am.withQueuedDeliveryStatus([]apimodels.Receiver{
+			{Name: "team-a", Integrations: []apimodels.Integration{{Name: "webhook"}, {Name: "email"}}},
+		})
+		require.Equal(t, "connection refused", receivers[0].Integrations[0].LastNotifyAttemptError)
+		require.Empty(t, receivers[0].Integrations[1].LastNotifyAttemptError)
+	})
+}
+
+func TestNotificationQueueBackoff(t *testing.T) {
+	q := &notificationQueue{settings: setting.UnifiedAlertingNotificationQueueSettings{
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+	}}
+	expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, e := range expected {
+		require.Equal(t, e, q.backoffAfter(i+1), "attempt %d", i+1)
+	}
+	require.Equal(t, 5*time.Minute, q.backoffAfter(100))
+}
@@ -45,6 +45,7 @@ type ReceiverService struct {
 	provisioningStore      provisoningStore
 	cfgStore               alertmanagerConfigStore
 	ruleNotificationsStore alertRuleNotificationSettingsStore
+	queueStore             notificationQueueStore
 	encryptionService      secretService
 	xact                   transactionManager
 	log                    log.Logger
@@ -58,6 +59,12 @@ type alertRuleNotificationSettingsStore interface {
 	ListNotificationSettings(ctx context.Context, q models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error)
 }
 
+type notificationQueueStore interface {
+	ListQueuedNotifications(ctx context.Context, q models.ListQueuedNotificationsQuery) ([]*models.QueuedNotification, error)
+	ReplayQueuedNotifications(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd) (int64, error)
+	GetNotificationQueueHealth(ctx context.Context, orgID int64) (map[string]models.NotificationQueueHealth, error)
+}
+
 type secretService interface {
 	Encrypt(ctx context.Context, payload []byte, opt secrets.EncryptionOptions) ([]byte, error)
 	Decrypt(ctx context.Context, payload []byte) ([]byte, error)
@@ -99,6 +106,7 @@ func NewReceiverService(
 	cfgStore alertmanagerConfigStore,
 	provisioningStore provisoningStore,
 	ruleNotificationsStore alertRuleNotificationSettingsStore,
+	queueStore notificationQueueStore,
 	encryptionService secretService,
 	xact transactionManager,
 	log log.Logger,
@@ -110,6 +118,7 @@ func NewReceiverService(
 		provisioningStore:      provisioningStore,
 		cfgStore:               cfgStore,
 		ruleNotificationsStore: ruleNotificationsStore,
+		queueStore:             queueStore,
 		encryptionService:      encryptionService,
 		xact:                   xact,
 		log:                    log,
@@ -618,6 +627,79 @@ func (rs *ReceiverService) InUseMetadata(ctx context.Context, orgID int64, recei
 	return results, nil
 }
 
+// NotificationQueueHealth returns the health of the notification queue for the given Receivers.
+// Receivers without failed notifications are healthy.
+func (rs *ReceiverService) NotificationQueueHealth(ctx context.Context, orgID int64, receivers ...*models.Receiver) (map[string]models.NotificationQueueHealth, error) {
+	health, err := rs.queueStore.GetNotificationQueueHealth(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[string]models.NotificationQueueHealth, len(receivers))
+	for _, rcv := range receivers {
+		results[rcv.GetUID()] = health[rcv.Name]
+	}
+	return results, nil
+}
+
+// ListFailedNotifications returns the notifications that the receiver failed to deliver, along with the health of its
+// notification queue. The user must be able to read the receiver.
+func (rs *ReceiverService) ListFailedNotifications(ctx context.Context, q models.ListQueuedNotificationsQuery, user identity.Requester) ([]*models.QueuedNotification, models.NotificationQueueHealth, error) {
+	ctx, span := rs.tracer.Start(ctx, "alerting.receivers.listFailedNotifications", trace.WithAttributes(
+		attribute.Int64("query_org_id", q.OrgID),
+		attribute.String("query_receiver", q.Receiver),
+		attribute.String("query_status", string(q.Status)),
+	))
+	defer span.End()
+
+	rcv, err := rs.GetReceiver(ctx, models.GetReceiverQuery{OrgID: q.OrgID, Name: q.Receiver}, user)
+	if err != nil {
+		return nil, models.NotificationQueueHealth{}, err
+	}
+	notifications, err := rs.queueStore.ListQueuedNotifications(ctx, q)
+	if err != nil {
+		return nil, models.NotificationQueueHealth{}, err
+	}
+	health, err := rs.NotificationQueueHealth(ctx, q.OrgID, rcv)
+	if err != nil {
+		return nil, models.NotificationQueueHealth{}, err
+	}
+	return notifications, health[rcv.GetUID()], nil
+}
+
+// ReplayFailedNotifications schedules failed notifications of the receiver to be retried immediately, even if their
+// integration backs off. It returns the number of replayed notifications. The user must be able to update the receiver.
+func (rs *ReceiverService) ReplayFailedNotifications(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd, user identity.Requester) (int64, error) {
+	ctx, span := rs.tracer.Start(ctx, "alerting.receivers.replayFailedNotifications", trace.WithAttributes(
+		attribute.Int64("org_id", cmd.OrgID),
+		attribute.String("receiver", cmd.Receiver),
+		attribute.Int("ids", len(cmd.IDs)),
+	))
+	defer span.End()
+
+	revision, err := rs.cfgStore.Get(ctx, cmd.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	postable, err := revision.GetReceiver(legacy_storage.NameToUid(cmd.Receiver))
+	if err != nil {
+		return 0, err
+	}
+	rcv, err := PostableApiReceiverToReceiver(postable, models.ProvenanceNone)
+	if err != nil {
+		return 0, err
+	}
+	if err := rs.authz.AuthorizeUpdate(ctx, user, rcv); err != nil {
+		return 0, err
+	}
+
+	replayed, err := rs.queueStore.ReplayQueuedNotifications(ctx, cmd)
+	if err != nil {
+		return 0, err
+	}
+	rs.log.FromContext(ctx).Info("Replayed failed notifications", "receiver", cmd.Receiver, "count", replayed)
+	return replayed, nil
+}
+
 func removedIntegrations(old, new *models.Receiver) []*models.Integration {
 	updatedUIDs := make(map[string]struct{}, len(new.Integrations))
 	for _, integration := range new.Integrations {
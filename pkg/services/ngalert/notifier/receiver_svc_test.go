@@ -1481,6 +1481,7 @@ func createReceiverServiceSut(t *testing.T, encryptSvc secretService) *ReceiverS
 		legacy_storage.NewAlertmanagerConfigStore(store),
 		provisioningStore,
 		&fakeAlertRuleNotificationStore{},
+		NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{}),
 		encryptSvc,
 		xact,
 		log.NewNopLogger(),
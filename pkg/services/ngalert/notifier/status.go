@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/go-openapi/strfmt"
+
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
@@ -22,3 +24,32 @@ func (am *alertmanager) GetStatus(_ context.Context) (apimodels.GettableStatus,
 
 	return *apimodels.NewGettableStatus(&config.AlertmanagerConfig), nil
 }
+
+// withQueuedDeliveryStatus replaces the last notify attempt of the integrations whose failed notifications are queued.
+// The notification pipeline considers queued notifications delivered, so the status it reports would hide the failures.
+// Instead, the status of the underlying integration is used, which includes the retries of queued notifications.
+func (am *alertmanager) withQueuedDeliveryStatus(receivers []apimodels.Receiver) []apimodels.Receiver {
+	if am.queue == nil {
+		return receivers
+	}
+	queued := am.queue.getIntegrations()
+	for _, rcv := range receivers {
+		integrations := queued.receiver(rcv.Name)
+		if len(integrations) != len(rcv.Integrations) {
+			continue
+		}
+		for idx, integration := range integrations {
+			if integration == nil || integration.Name() != rcv.Integrations[idx].Name {
+				continue
+			}
+			ts, d, err := integration.GetReport()
+			rcv.Integrations[idx].LastNotifyAttempt = strfmt.DateTime(ts)
+			rcv.Integrations[idx].LastNotifyAttemptDuration = d.String()
+			rcv.Integrations[idx].LastNotifyAttemptError = ""
+			if err != nil {
+				rcv.Integrations[idx].LastNotifyAttemptError = err.Error()
+			}
+		}
+	}
+	return receivers
+}
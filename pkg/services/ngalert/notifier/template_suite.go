@@ -0,0 +1,323 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+var ErrTemplateSuiteInvalid = errutil.BadRequest("alerting.notifications.templates.invalidSuite")
+
+// templateSuiteName is the name under which the templates of a suite are rendered. The templated fields of the
+// integrations are defined as templates prefixed with it so that they can be told apart from the definitions of the suite.
+const templateSuiteName = "__template_suite__"
+
+// DefaultIntegrationFields are the templated fields rendered for each integration type of a template suite, with the
+// templates used when the suite does not override them.
+var DefaultIntegrationFields = map[string]map[string]string{
+	"slack": {
+		"title": alertingTemplates.DefaultMessageTitleEmbed,
+		"text":  alertingTemplates.DefaultMessageEmbed,
+	},
+	"email": {
+		"subject": alertingTemplates.DefaultMessageTitleEmbed,
+		"message": alertingTemplates.DefaultMessageEmbed,
+	},
+	"webhook": {
+		"title":   alertingTemplates.DefaultMessageTitleEmbed,
+		"message": alertingTemplates.DefaultMessageEmbed,
+	},
+}
+
+// TemplateTester renders templates against alerts. It is implemented by every Alertmanager.
+type TemplateTester interface {
+	TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*TestTemplatesResults, error)
+}
+
+// NewTemplateTester returns an Alertmanager that is only used to render templates outside of a running Grafana, such as
+// by grafana-cli. It has no configuration and keeps its silences and notification log in memory.
+func NewTemplateTester(orgID int64, externalURL string) (*alertmanager, error) {
+	l := log.New("ngalert.notifier.template-tester", "org", orgID)
+	discard := func(alertingNotify.State) (int64, error) { return 0, nil }
+	amcfg := &alertingNotify.GrafanaAlertmanagerConfig{
+		ExternalURL: externalURL,
+		Silences: maintenanceOptions{
+			retention:            silenceRetention,
+			maintenanceFrequency: maintenanceInterval,
+			maintenanceFunc:      discard,
+		},
+		Nflog: maintenanceOptions{
+			retention:            silenceRetention,
+			maintenanceFrequency: maintenanceInterval,
+			maintenanceFunc:      discard,
+		},
+	}
+
+	gam, err := alertingNotify.NewGrafanaAlertmanager("orgID", orgID, amcfg, &NilPeer{}, l, alertingNotify.NewGrafanaAlertmanagerMetrics(prometheus.NewRegistry(), l))
+	if err != nil {
+		return nil, err
+	}
+	return &alertmanager{
+		Base:   gam,
+		orgID:  orgID,
+		logger: l,
+	}, nil
+}
+
+type suiteField struct {
+	integration string
+	field       string
+	template    string
+}
+
+func (f suiteField) definition() string {
+	return fmt.Sprintf("%s.%s.%s", templateSuiteName, f.integration, f.field)
+}
+
+func (f suiteField) content() string {
+	return fmt.Sprintf(`{{ define "%s" }}%s{{ end }}`, f.definition(), f.template)
+}
+
+// RunTemplateSuite renders the templates of the suite and the templated fields of its integrations against every fixture,
+// and compares the output with the one expected by the fixture. Templates that cannot be parsed are reported without
+// rendering the suite because they would fail for every fixture.
+func RunTemplateSuite(ctx context.Context, tester TemplateTester, suite apimodels.TemplateSuite) (*apimodels.TemplateSuiteResults, error) {
+	fields, err := templateSuiteFields(suite.Integrations)
+	if err != nil {
+		return nil, err
+	}
+	if len(suite.Fixtures) == 0 {
+		return nil, WithPublicError(ErrTemplateSuiteInvalid.Errorf("at least one fixture is required"))
+	}
+	for i, fixture := range suite.Fixtures {
+		if fixture.Name == "" {
+			return nil, WithPublicError(ErrTemplateSuiteInvalid.Errorf("fixture %d has no name", i))
+		}
+	}
+
+	results, err := invalidSuiteTemplates(ctx, tester, suite.Templates, fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		contents := make([]string, 0, len(suite.Templates)+len(fields))
+		for _, t := range suite.Templates {
+			contents = append(contents, t.Template)
+		}
+		for _, f := range fields {
+			contents = append(contents, f.content())
+		}
+		content := strings.Join(contents, "\n")
+
+		for _, fixture := range suite.Fixtures {
+			res, err := tester.TestTemplate(ctx, apimodels.TestTemplatesConfigBodyParams{
+				// The tester adds default labels and annotations to the alerts, they are copied to keep the fixture intact.
+				Alerts:   copyPostableAlerts(fixture.Alerts),
+				Template: content,
+				Name:     templateSuiteName,
+			})
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, fixtureResults(fixture, res)...)
+		}
+	}
+
+	out := &apimodels.TemplateSuiteResults{Results: results}
+	for _, r := range results {
+		if r.Status != apimodels.TemplateSuiteOK {
+			out.Failed++
+		}
+	}
+	return out, nil
+}
+
+// templateSuiteFields returns the templated fields of the integrations, sorted by integration and field.
+func templateSuiteFields(integrations []apimodels.TemplateSuiteIntegration) ([]suiteField, error) {
+	if len(integrations) == 0 {
+		for typ := range DefaultIntegrationFields {
+			integrations = append(integrations, apimodels.TemplateSuiteIntegration{Type: typ})
+		}
+	}
+
+	var fields []suiteField
+	seen := make(map[string]struct{}, len(integrations))
+	for _, integration := range integrations {
+		defaults, ok := DefaultIntegrationFields[integration.Type]
+		if !ok {
+			return nil, WithPublicError(ErrTemplateSuiteInvalid.Errorf("unsupported integration type %q", integration.Type))
+		}
+		if _, ok := seen[integration.Type]; ok {
+			return nil, WithPublicError(ErrTemplateSuiteInvalid.Errorf("integration type %q is defined more than once", integration.Type))
+		}
+		seen[integration.Type] = struct{}{}
+
+		for field := range integration.Fields {
+			if _, ok := defaults[field]; !ok {
+				return nil, WithPublicError(ErrTemplateSuiteInvalid.Errorf("integration type %q has no templated field %q", integration.Type, field))
+			}
+		}
+		for field, tmpl := range defaults {
+			if override, ok := integration.Fields[field]; ok {
+				tmpl = override
+			}
+			fields = append(fields, suiteField{integration: integration.Type, field: field, template: tmpl})
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].integration != fields[j].integration {
+			return fields[i].integration < fields[j].integration
+		}
+		return fields[i].field < fields[j].field
+	})
+	return fields, nil
+}
+
+// invalidSuiteTemplates parses every template and integration field on its own so that syntax errors are attributed
+// to the template they come from.
+func invalidSuiteTemplates(ctx context.Context, tester TemplateTester, templates []apimodels.TemplateSuiteTemplate, fields []suiteField) ([]apimodels.TemplateSuiteResult, error) {
+	var results []apimodels.TemplateSuiteResult
+	check := func(name, content string, result apimodels.TemplateSuiteResult) error {
+		res, err := tester.TestTemplate(ctx, apimodels.TestTemplatesConfigBodyParams{Template: content, Name: name})
+		if err != nil {
+			return err
+		}
+		for _, e := range res.Errors {
+			// Execution errors are expected as there are no alerts to render the template with.
+			if apimodels.TemplateErrorKind(e.Kind) != apimodels.InvalidTemplate {
+				continue
+			}
+			result.Status = apimodels.TemplateSuiteError
+			result.Error = e.Error
+			results = append(results, result)
+		}
+		return nil
+	}
+
+	for _, t := range templates {
+		if err := check(t.Name, t.Template, apimodels.TemplateSuiteResult{Name: t.Name}); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range fields {
+		if err := check(f.definition(), f.content(), apimodels.TemplateSuiteResult{Integration: f.integration, Name: f.field}); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func fixtureResults(fixture apimodels.TemplateSuiteFixture, res *TestTemplatesResults) []apimodels.TemplateSuiteResult {
+	var results []apimodels.TemplateSuiteResult
+	rendered := make(map[string]struct{}, len(res.Results)+len(res.Errors))
+
+	newResult := func(name string) (apimodels.TemplateSuiteResult, string, bool) {
+		rendered[name] = struct{}{}
+		result := apimodels.TemplateSuiteResult{Fixture: fixture.Name, Name: name}
+		if integration, field, ok := parseSuiteFieldDefinition(name); ok {
+			result.Integration = integration
+			result.Name = field
+			expected, ok := fixture.ExpectedFields[integration][field]
+			return result, expected, ok
+		}
+		expected, ok := fixture.Expected[name]
+		return result, expected, ok
+	}
+
+	for _, r := range res.Results {
+		// Text outside any definition is rendered under the name of the suite and is not a template of its own.
+		if r.Name == templateSuiteName {
+			continue
+		}
+		result, expected, ok := newResult(r.Name)
+		result.Status = apimodels.TemplateSuiteOK
+		result.Text = r.Text
+		if ok && strings.TrimSpace(expected) != strings.TrimSpace(r.Text) {
+			result.Status = apimodels.TemplateSuiteMismatch
+			result.Expected = expected
+		}
+		results = append(results, result)
+	}
+	for _, e := range res.Errors {
+		result, _, _ := newResult(e.Name)
+		result.Status = apimodels.TemplateSuiteError
+		result.Error = e.Error
+		results = append(results, result)
+	}
+
+	// Expected output of templates that were not rendered is reported as a mismatch so that typos in names do not go unnoticed.
+	for _, name := range sortedKeys(fixture.Expected) {
+		if _, ok := rendered[name]; !ok {
+			results = append(results, apimodels.TemplateSuiteResult{
+				Fixture:  fixture.Name,
+				Name:     name,
+				Status:   apimodels.TemplateSuiteMismatch,
+				Expected: fixture.Expected[name],
+				Error:    "template was not rendered",
+			})
+		}
+	}
+	for _, integration := range sortedKeys(fixture.ExpectedFields) {
+		for _, field := range sortedKeys(fixture.ExpectedFields[integration]) {
+			if _, ok := rendered[suiteField{integration: integration, field: field}.definition()]; !ok {
+				results = append(results, apimodels.TemplateSuiteResult{
+					Fixture:     fixture.Name,
+					Integration: integration,
+					Name:        field,
+					Status:      apimodels.TemplateSuiteMismatch,
+					Expected:    fixture.ExpectedFields[integration][field],
+					Error:       "integration field was not rendered",
+				})
+			}
+		}
+	}
+	return results
+}
+
+func parseSuiteFieldDefinition(name string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(name, templateSuiteName+".")
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ".")
+}
+
+func copyPostableAlerts(alerts []*amv2.PostableAlert) []*amv2.PostableAlert {
+	result := make([]*amv2.PostableAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert == nil {
+			continue
+		}
+		c := *alert
+		c.Labels = make(amv2.LabelSet, len(alert.Labels))
+		for k, v := range alert.Labels {
+			c.Labels[k] = v
+		}
+		c.Annotations = make(amv2.LabelSet, len(alert.Annotations))
+		for k, v := range alert.Annotations {
+			c.Annotations[k] = v
+		}
+		result = append(result, &c)
+	}
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRunTemplateSuite(t *testing.T) {
+	am := setupAMTest(t)
+
+	templates := []apimodels.TemplateSuiteTemplate{{
+		Name:     "slack",
+		Template: `{{ define "slack.title" }}{{ len .Alerts.Firing }} firing{{ end }}{{ define "slack.summary" }}{{ range .Alerts }}{{ .Labels.alertname }} {{ end }}{{ end }}`,
+	}}
+	fixture := apimodels.TemplateSuiteFixture{
+		Name: "one firing alert",
+		Alerts: []*amv2.PostableAlert{{
+			Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "alert1"}},
+		}},
+		Expected: map[string]string{
+			"slack.summary": "alert2",
+			"slack.missing": "",
+		},
+		ExpectedFields: map[string]map[string]string{
+			"slack": {"title": "1 firing"},
+		},
+	}
+
+	t.Run("should render templates and integration fields and compare them with the expected output", func(t *testing.T) {
+		res, err := RunTemplateSuite(context.Background(), am, apimodels.TemplateSuite{
+			Templates: templates,
+			Integrations: []apimodels.TemplateSuiteIntegration{{
+				Type:   "slack",
+				Fields: map[string]string{"title": `{{ template "slack.title" . }}`},
+			}},
+			Fixtures: []apimodels.TemplateSuiteFixture{fixture},
+		})
+		require.NoError(t, err)
+
+		byName := make(map[string]apimodels.TemplateSuiteResult, len(res.Results))
+		for _, r := range res.Results {
+			assert.Equal(t, fixture.Name, r.Fixture)
+			byName[r.Integration+"/"+r.Name] = r
+		}
+
+		assert.Equal(t, apimodels.TemplateSuiteMismatch, byName["/slack.summary"].Status)
+		assert.Equal(t, "alert1 ", byName["/slack.summary"].Text)
+		assert.Equal(t, apimodels.TemplateSuiteMismatch, byName["/slack.missing"].Status)
+		assert.Equal(t, apimodels.TemplateSuiteOK, byName["slack/title"].Status)
+		assert.Equal(t, "1 firing", byName["slack/title"].Text)
+		assert.Equal(t, apimodels.TemplateSuiteOK, byName["slack/text"].Status)
+		assert.NotEmpty(t, byName["slack/text"].Text)
+		assert.Equal(t, 2, res.Failed)
+
+		// The fixture must not be modified by rendering.
+		assert.Equal(t, amv2.LabelSet{"alertname": "alert1"}, fixture.Alerts[0].Labels)
+		assert.Nil(t, fixture.Alerts[0].Annotations)
+	})
+
+	t.Run("should render the default fields of all integrations", func(t *testing.T) {
+		res, err := RunTemplateSuite(context.Background(), am, apimodels.TemplateSuite{
+			Fixtures: []apimodels.TemplateSuiteFixture{{Name: "no alerts"}},
+		})
+		require.NoError(t, err)
+
+		var fields []string
+		for _, r := range res.Results {
+			assert.Equal(t, apimodels.TemplateSuiteOK, r.Status)
+			fields = append(fields, r.Integration+"/"+r.Name)
+		}
+		assert.ElementsMatch(t, []string{"email/message", "email/subject", "slack/text", "slack/title", "webhook/message", "webhook/title"}, fields)
+		assert.Zero(t, res.Failed)
+	})
+
+	t.Run("should report templates that cannot be parsed without rendering the suite", func(t *testing.T) {
+		res, err := RunTemplateSuite(context.Background(), am, apimodels.TemplateSuite{
+			Templates: append([]apimodels.TemplateSuiteTemplate{{
+				Name:     "broken",
+				Template: `{{ define "broken" }}{{ .Status }{{ end }}`,
+			}}, templates...),
+			Integrations: []apimodels.TemplateSuiteIntegration{{
+				Type:   "webhook",
+				Fields: map[string]string{"message": `{{ template "broken" . `},
+			}},
+			Fixtures: []apimodels.TemplateSuiteFixture{fixture},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 2)
+		assert.Equal(t, 2, res.Failed)
+
+		assert.Equal(t, "broken", res.Results[0].Name)
+		assert.Empty(t, res.Results[0].Fixture)
+		assert.Equal(t, apimodels.TemplateSuiteError, res.Results[0].Status)
+		assert.NotEmpty(t, res.Results[0].Error)
+
+		assert.Equal(t, "webhook", res.Results[1].Integration)
+		assert.Equal(t, "message", res.Results[1].Name)
+		assert.Equal(t, apimodels.TemplateSuiteError, res.Results[1].Status)
+	})
+
+	t.Run("should fail for invalid suites", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			suite apimodels.TemplateSuite
+		}{{
+			name:  "no fixtures",
+			suite: apimodels.TemplateSuite{Templates: templates},
+		}, {
+			name: "fixture without name",
+			suite: apimodels.TemplateSuite{
+				Fixtures: []apimodels.TemplateSuiteFixture{{}},
+			},
+		}, {
+			name: "unsupported integration",
+			suite: apimodels.TemplateSuite{
+				Integrations: []apimodels.TemplateSuiteIntegration{{Type: "pagerduty"}},
+				Fixtures:     []apimodels.TemplateSuiteFixture{fixture},
+			},
+		}, {
+			name: "duplicate integration",
+			suite: apimodels.TemplateSuite{
+				Integrations: []apimodels.TemplateSuiteIntegration{{Type: "email"}, {Type: "email"}},
+				Fixtures:     []apimodels.TemplateSuiteFixture{fixture},
+			},
+		}, {
+			name: "unknown field",
+			suite: apimodels.TemplateSuite{
+				Integrations: []apimodels.TemplateSuiteIntegration{{Type: "email", Fields: map[string]string{"title": ""}}},
+				Fixtures:     []apimodels.TemplateSuiteFixture{fixture},
+			},
+		}}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := RunTemplateSuite(context.Background(), am, tc.suite)
+				require.ErrorIs(t, err, ErrTemplateSuiteInvalid)
+			})
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +31,11 @@ type fakeConfigStore struct {
 
 	// notificationSettings stores notification settings by orgID.
 	notificationSettings map[int64]map[models.AlertRuleKey][]models.NotificationSettings
+
+	queueMtx sync.Mutex
+	// queue stores queued notifications by ID.
+	queue  map[int64]models.QueuedNotification
+	lastID int64
 }
 
 func (f *fakeConfigStore) ListNotificationSettings(ctx context.Context, q models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error) {
@@ -399,3 +406,186 @@ func (f *fakeAlertRuleNotificationStore) ListNotificationSettings(ctx context.Co
 	// Default values when no function hook is provided
 	return nil, nil
 }
+
+func (f *fakeConfigStore) EnqueueNotification(_ context.Context, n *models.QueuedNotification) error {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	if f.queue == nil {
+		f.queue = make(map[int64]models.QueuedNotification)
+	}
+	f.lastID++
+	n.ID = f.lastID
+	f.queue[n.ID] = *n
+	f.deleteSuperseded(n)
+	return nil
+}
+
+func (f *fakeConfigStore) GetDueQueuedNotifications(_ context.Context, orgID int64, now time.Time, limit int) ([]*models.QueuedNotification, error) {
+	f.queueMtx.Lock()
+	superseded := make(map[int64]struct{})
+	for id, n := range f.queue {
+		for _, newer := range f.queue {
+			if newer.ID > id && newer.Status == models.QueuedNotificationPending && sameQueuedGroup(n, newer) {
+				superseded[id] = struct{}{}
+				break
+			}
+		}
+	}
+	f.queueMtx.Unlock()
+	return f.findQueued(func(n models.QueuedNotification) bool {
+		_, ok := superseded[n.ID]
+		return !ok && n.OrgID == orgID && n.Status == models.QueuedNotificationPending && !n.NextAttemptAt.After(now)
+	}, limit, false), nil
+}
+
+func (f *fakeConfigStore) DeleteSupersededQueuedNotifications(_ context.Context, n *models.QueuedNotification) (int64, error) {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	return f.deleteSuperseded(n), nil
+}
+
+func (f *fakeConfigStore) deleteSuperseded(n *models.QueuedNotification) int64 {
+	var deleted int64
+	for id, queued := range f.queue {
+		if queued.Status == models.QueuedNotificationPending && sameQueuedGroup(queued, *n) && (n.ID == 0 || id < n.ID) {
+			delete(f.queue, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+func sameQueuedGroup(a, b models.QueuedNotification) bool {
+	return a.OrgID == b.OrgID && a.Receiver == b.Receiver && a.IntegrationType == b.IntegrationType &&
+		a.IntegrationIndex == b.IntegrationIndex && a.GroupKey == b.GroupKey
+}
+
+func (f *fakeConfigStore) ClaimQueuedNotification(_ context.Context, n *models.QueuedNotification, leaseUntil time.Time) (bool, error) {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	stored, ok := f.queue[n.ID]
+	if !ok || stored.Status != models.QueuedNotificationPending || !stored.NextAttemptAt.Equal(n.NextAttemptAt) {
+		return false, nil
+	}
+	stored.NextAttemptAt = leaseUntil
+	f.queue[n.ID] = stored
+	n.NextAttemptAt = leaseUntil
+	return true, nil
+}
+
+func (f *fakeConfigStore) UpdateQueuedNotification(_ context.Context, n *models.QueuedNotification) error {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	stored, ok := f.queue[n.ID]
+	if !ok || stored.OrgID != n.OrgID {
+		return models.ErrQueuedNotificationNotFound
+	}
+	stored.Status = n.Status
+	stored.Attempts = n.Attempts
+	stored.LastError = n.LastError
+	stored.NextAttemptAt = n.NextAttemptAt
+	stored.UpdatedAt = n.UpdatedAt
+	f.queue[n.ID] = stored
+	return nil
+}
+
+func (f *fakeConfigStore) DeleteQueuedNotification(_ context.Context, orgID, id int64) error {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	if n, ok := f.queue[id]; ok && n.OrgID == orgID {
+		delete(f.queue, id)
+	}
+	return nil
+}
+
+func (f *fakeConfigStore) ListQueuedNotifications(_ context.Context, q models.ListQueuedNotificationsQuery) ([]*models.QueuedNotification, error) {
+	return f.findQueued(func(n models.QueuedNotification) bool {
+		return n.OrgID == q.OrgID && n.Receiver == q.Receiver && (q.Status == "" || n.Status == q.Status)
+	}, q.Limit, true), nil
+}
+
+func (f *fakeConfigStore) ReplayQueuedNotifications(_ context.Context, cmd models.ReplayQueuedNotificationsCmd) (int64, error) {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	ids := make(map[int64]struct{}, len(cmd.IDs))
+	for _, id := range cmd.IDs {
+		ids[id] = struct{}{}
+	}
+	var replayed int64
+	for id, n := range f.queue {
+		if n.OrgID != cmd.OrgID || n.Receiver != cmd.Receiver {
+			continue
+		}
+		if _, ok := ids[id]; len(ids) > 0 && !ok || len(ids) == 0 && n.Status != models.QueuedNotificationDead {
+			continue
+		}
+		n.Status = models.QueuedNotificationPending
+		n.Attempts = 0
+		n.NextAttemptAt = cmd.Now
+		n.UpdatedAt = cmd.Now
+		f.queue[id] = n
+		replayed++
+	}
+	return replayed, nil
+}
+
+func (f *fakeConfigStore) DeleteDeadQueuedNotifications(_ context.Context, orgID int64, before time.Time) (int64, error) {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	var deleted int64
+	for id, n := range f.queue {
+		if n.OrgID == orgID && n.Status == models.QueuedNotificationDead && n.UpdatedAt.Before(before) {
+			delete(f.queue, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (f *fakeConfigStore) GetNotificationQueueHealth(_ context.Context, orgID int64) (map[string]models.NotificationQueueHealth, error) {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	result := make(map[string]models.NotificationQueueHealth)
+	for _, n := range f.queue {
+		if n.OrgID != orgID {
+			continue
+		}
+		h := result[n.Receiver]
+		switch n.Status {
+		case models.QueuedNotificationPending:
+			h.Pending++
+			if h.OldestPending.IsZero() || n.CreatedAt.Before(h.OldestPending) {
+				h.OldestPending = n.CreatedAt
+			}
+		case models.QueuedNotificationDead:
+			h.Dead++
+		}
+		if n.UpdatedAt.After(h.LastAttempt) {
+			h.LastAttempt = n.UpdatedAt
+		}
+		result[n.Receiver] = h
+	}
+	return result, nil
+}
+
+func (f *fakeConfigStore) findQueued(match func(models.QueuedNotification) bool, limit int, newestFirst bool) []*models.QueuedNotification {
+	f.queueMtx.Lock()
+	defer f.queueMtx.Unlock()
+	result := make([]*models.QueuedNotification, 0)
+	for _, n := range f.queue {
+		if match(n) {
+			n := n
+			result = append(result, &n)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if newestFirst {
+			return result[i].ID > result[j].ID
+		}
+		return result[i].ID < result[j].ID
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
@@ -42,5 +42,5 @@ func (am *alertmanager) TestReceivers(ctx context.Context, c apimodels.TestRecei
 }
 
 func (am *alertmanager) GetReceivers(_ context.Context) ([]apimodels.Receiver, error) {
-	return am.Base.GetReceivers(), nil
+	return am.withQueuedDeliveryStatus(am.Base.GetReceivers()), nil
 }
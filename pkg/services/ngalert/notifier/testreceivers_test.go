@@ -2,12 +2,23 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestInvalidReceiverError_Error(t *testing.T) {
@@ -80,3 +91,83 @@ func TestProcessNotifierError(t *testing.T) {
 		require.Equal(t, err, alertingNotify.ProcessIntegrationError(r, err))
 	})
 }
+
+func TestTestReceivers_GrafanaIntegrations(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mtx.Unlock()
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			_, _ = w.Write([]byte(`{"issues": []}`))
+		case "/rest/api/2/issue":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"key": "OPS-1"}`))
+		case "/api/now/table/incident":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	am := setupAMTest(t)
+	nsCfg := setting.NewCfg()
+	nsCfg.Smtp.FromAddress = "from@address.com"
+	ns, err := notifications.ProvideService(bus.ProvideBus(tracing.InitializeTracerForTest()), nsCfg, notifications.NewFakeMailer(), nil)
+	require.NoError(t, err)
+	am.NotificationService = ns
+
+	cfg, err := Load([]byte(fmt.Sprintf(`{
+		"alertmanager_config": {
+			"route": {"receiver": "matrix"},
+			"receivers": [{
+				"name": "matrix",
+				"grafana_managed_receiver_configs": [{
+					"uid": "matrix-uid",
+					"name": "matrix",
+					"type": "matrix",
+					"settings": {"homeserver_url": %[1]q, "room_id": "!room:example.com", "access_token": "token"}
+				}]
+			}]
+		}
+	}`, srv.URL)))
+	require.NoError(t, err)
+	_, err = am.applyConfig(cfg)
+	require.NoError(t, err)
+
+	var body apimodels.TestReceiversConfigBodyParams
+	require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{
+		"receivers": [{
+			"name": "tickets",
+			"grafana_managed_receiver_configs": [
+				{"uid": "jira-uid", "name": "jira", "type": "jira", "settings": {"api_url": %[1]q, "project": "OPS", "api_token": "token"}},
+				{"uid": "servicenow-uid", "name": "servicenow", "type": "servicenow", "settings": {"url": %[1]q, "user": "grafana", "password": "secret"}},
+				{"uid": "matrix-uid", "name": "matrix", "type": "matrix", "settings": {"homeserver_url": %[1]q, "room_id": "!room:example.com", "access_token": "token"}},
+				{"uid": "invalid-uid", "name": "invalid", "type": "matrix", "settings": {"homeserver_url": %[1]q}}
+			]
+		}]
+	}`, srv.URL)), &body))
+
+	result, _, err := am.TestReceivers(context.Background(), body)
+	require.NoError(t, err)
+	statuses := make(map[string]alertingNotify.TestIntegrationConfigResult)
+	for _, r := range result.Receivers {
+		for _, c := range r.Configs {
+			statuses[c.UID] = c
+		}
+	}
+	require.Equal(t, "ok", statuses["jira-uid"].Status)
+	require.Equal(t, "ok", statuses["matrix-uid"].Status)
+	require.Equal(t, "failed", statuses["servicenow-uid"].Status)
+	require.Contains(t, statuses["servicenow-uid"].Error, "unexpected status code 403 from ServiceNow")
+	require.Equal(t, "failed", statuses["invalid-uid"].Status)
+	require.Contains(t, statuses["invalid-uid"].Error, "could not find room_id property in settings")
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Contains(t, requests, "POST /rest/api/2/issue")
+}
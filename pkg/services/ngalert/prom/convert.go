@@ -0,0 +1,263 @@
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	queryRefID     = "query"
+	reduceRefID    = "reduce"
+	mathRefID      = "prometheus_math"
+	thresholdRefID = "threshold"
+
+	// defaultQueryTimeRange is the relative time range of the query. Queries are instant, so it only limits how far
+	// back the data source looks for the latest sample.
+	defaultQueryTimeRange = 10 * time.Minute
+)
+
+var ErrInvalidPrometheusRule = errors.New("invalid Prometheus rule")
+
+// Config is the configuration of the Converter.
+type Config struct {
+	// DatasourceUID is the UID of the data source that the converted rules query.
+	DatasourceUID string
+	// DatasourceType is the type of the data source. Prometheus and Loki data sources are supported.
+	DatasourceType string
+	// DefaultInterval is the evaluation interval of groups that do not specify one.
+	DefaultInterval time.Duration
+}
+
+// Converter translates Prometheus alerting and recording rules to Grafana alert rules.
+//
+// An alerting rule fires for every series its PromQL expression returns, whatever their values are. To have the same
+// behavior, the converted rule reduces the result of the instant query to the last value of each series,
+// turns every value into 1 with a math expression, and uses a threshold expression that checks that the result is
+// greater than 0 as the condition. A recording rule only has the query node and records its result.
+type Converter struct {
+	cfg Config
+}
+
+func NewConverter(cfg Config) (*Converter, error) {
+	if cfg.DatasourceUID == "" {
+		return nil, errors.New("data source UID must not be empty")
+	}
+	switch cfg.DatasourceType {
+	case datasources.DS_PROMETHEUS, datasources.DS_LOKI:
+	default:
+		return nil, fmt.Errorf("data source type %q is not supported, must be %s or %s", cfg.DatasourceType, datasources.DS_PROMETHEUS, datasources.DS_LOKI)
+	}
+	if cfg.DefaultInterval <= 0 {
+		return nil, errors.New("default interval must be positive")
+	}
+	return &Converter{cfg: cfg}, nil
+}
+
+// PrometheusRulesToGrafana converts Prometheus rule groups to Grafana rule groups in the given folder.
+// The UIDs of the converted rules are derived from the organization, folder, group and title of the rule, so
+// converting the same groups again produces the same rules. Titles of rules must be unique in a folder, therefore
+// duplicated names of Prometheus rules get a numeric suffix.
+func (c *Converter) PrometheusRulesToGrafana(orgID int64, namespaceUID string, groups []PrometheusRuleGroup) ([]models.AlertRuleGroup, error) {
+	result := make([]models.AlertRuleGroup, 0, len(groups))
+	groupNames := make(map[string]struct{}, len(groups))
+	titles := make(map[string]int)
+	for _, group := range groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("%w: group name must not be empty", ErrInvalidPrometheusRule)
+		}
+		if _, ok := groupNames[group.Name]; ok {
+			return nil, fmt.Errorf("%w: group %s is defined more than once", ErrInvalidPrometheusRule, group.Name)
+		}
+		groupNames[group.Name] = struct{}{}
+		if group.Limit > 0 {
+			return nil, fmt.Errorf("%w: limit of group %s is not supported", ErrInvalidPrometheusRule, group.Name)
+		}
+
+		interval := time.Duration(group.Interval)
+		if interval == 0 {
+			interval = c.cfg.DefaultInterval
+		}
+		var offset time.Duration
+		if group.QueryOffset != nil {
+			offset = time.Duration(*group.QueryOffset)
+		}
+
+		grafanaGroup := models.AlertRuleGroup{
+			Title:     group.Name,
+			FolderUID: namespaceUID,
+			Interval:  int64(interval.Seconds()),
+			Rules:     make([]models.AlertRule, 0, len(group.Rules)),
+		}
+		for idx, rule := range group.Rules {
+			r, err := c.convertRule(rule, offset)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d of group %s: %w", idx, group.Name, err)
+			}
+			titles[r.Title]++
+			if n := titles[r.Title]; n > 1 {
+				r.Title = fmt.Sprintf("%s (%d)", r.Title, n)
+			}
+			r.OrgID = orgID
+			r.NamespaceUID = namespaceUID
+			r.RuleGroup = group.Name
+			r.RuleGroupIndex = idx + 1
+			r.IntervalSeconds = grafanaGroup.Interval
+			r.UID = ruleUID(orgID, namespaceUID, group.Name, r.Title)
+			grafanaGroup.Rules = append(grafanaGroup.Rules, r)
+		}
+		result = append(result, grafanaGroup)
+	}
+	return result, nil
+}
+
+func (c *Converter) convertRule(rule PrometheusRule, offset time.Duration) (models.AlertRule, error) {
+	if (rule.Alert == "") == (rule.Record == "") {
+		return models.AlertRule{}, fmt.Errorf("%w: exactly one of alert and record must be set", ErrInvalidPrometheusRule)
+	}
+	if rule.Expr == "" {
+		return models.AlertRule{}, fmt.Errorf("%w: expr must not be empty", ErrInvalidPrometheusRule)
+	}
+	if c.cfg.DatasourceType == datasources.DS_PROMETHEUS {
+		if _, err := parser.ParseExpr(rule.Expr); err != nil {
+			return models.AlertRule{}, fmt.Errorf("%w: invalid PromQL expression: %s", ErrInvalidPrometheusRule, err)
+		}
+	}
+	if rule.KeepFiringFor != nil && *rule.KeepFiringFor != 0 {
+		return models.AlertRule{}, fmt.Errorf("%w: keep_firing_for is not supported", ErrInvalidPrometheusRule)
+	}
+
+	query, err := c.createQuery(rule.Expr, offset)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	if rule.Record != "" {
+		if rule.For != nil || len(rule.Annotations) > 0 {
+			return models.AlertRule{}, fmt.Errorf("%w: recording rule %s cannot have for and annotations", ErrInvalidPrometheusRule, rule.Record)
+		}
+		return models.AlertRule{
+			Title:  rule.Record,
+			Data:   []models.AlertQuery{query},
+			Labels: rule.Labels,
+			Record: &models.Record{
+				Metric: rule.Record,
+				From:   queryRefID,
+			},
+		}, nil
+	}
+
+	expressions, err := createExpressions()
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	var forDuration time.Duration
+	if rule.For != nil {
+		forDuration = time.Duration(*rule.For)
+	}
+	return models.AlertRule{
+		Title:     rule.Alert,
+		Condition: thresholdRefID,
+		Data:      append([]models.AlertQuery{query}, expressions...),
+		For:       forDuration,
+		// Prometheus does not fire if the expression returns nothing, and keeps the state of rules if a query fails.
+		NoDataState:  models.OK,
+		ExecErrState: models.KeepLastErrState,
+		Labels:       rule.Labels,
+		Annotations:  rule.Annotations,
+	}, nil
+}
+
+func (c *Converter) createQuery(expression string, offset time.Duration) (models.AlertQuery, error) {
+	queryModel := map[string]any{
+		"refId": queryRefID,
+		"datasource": map[string]any{
+			"type": c.cfg.DatasourceType,
+			"uid":  c.cfg.DatasourceUID,
+		},
+		"expr": expression,
+	}
+	if c.cfg.DatasourceType == datasources.DS_LOKI {
+		queryModel["queryType"] = "instant"
+	} else {
+		queryModel["instant"] = true
+		queryModel["range"] = false
+	}
+	model, err := json.Marshal(queryModel)
+	if err != nil {
+		return models.AlertQuery{}, err
+	}
+	return models.AlertQuery{
+		RefID:         queryRefID,
+		DatasourceUID: c.cfg.DatasourceUID,
+		RelativeTimeRange: models.RelativeTimeRange{
+			From: models.Duration(defaultQueryTimeRange),
+		},
+		TimeShift: models.Duration(offset),
+		Model:     model,
+	}, nil
+}
+
+func createExpressions() ([]models.AlertQuery, error) {
+	expressions := []map[string]any{
+		{
+			"refId":      reduceRefID,
+			"type":       "reduce",
+			"expression": queryRefID,
+			"reducer":    "last",
+		},
+		{
+			"refId":      mathRefID,
+			"type":       "math",
+			"expression": fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", reduceRefID),
+		},
+		{
+			"refId":      thresholdRefID,
+			"type":       "threshold",
+			"expression": mathRefID,
+			"conditions": []any{
+				map[string]any{
+					"evaluator": map[string]any{
+						"type":   "gt",
+						"params": []float64{0},
+					},
+				},
+			},
+		},
+	}
+	result := make([]models.AlertQuery, 0, len(expressions))
+	for _, e := range expressions {
+		e["datasource"] = map[string]any{
+			"type": expr.DatasourceType,
+			"uid":  expr.DatasourceUID,
+		}
+		model, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, models.AlertQuery{
+			RefID:         e["refId"].(string),
+			DatasourceUID: expr.DatasourceUID,
+			Model:         model,
+		})
+	}
+	return result, nil
+}
+
+func ruleUID(orgID int64, namespaceUID, group, title string) string {
+	h := fnv.New64a()
+	for _, s := range []string{strconv.FormatInt(orgID, 10), namespaceUID, group, title} {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{255})
+	}
+	return fmt.Sprintf("prom-%x", h.Sum64())
+}
@@ -0,0 +1,213 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testRules = `
+groups:
+  - name: node
+    interval: 30s
+    query_offset: 1m
+    rules:
+      - alert: HighLoad
+        expr: node_load1 > 10
+        for: 5m
+        labels:
+          severity: warning
+        annotations:
+          summary: "Load is {{ $value }}"
+      - record: instance:node_cpu:rate5m
+        expr: rate(node_cpu_seconds_total[5m])
+        labels:
+          source: import
+  - name: other
+    rules:
+      - alert: HighLoad
+        expr: node_load5 > 10
+`
+
+func newTestConverter(t *testing.T, dsType string) *Converter {
+	t.Helper()
+	c, err := NewConverter(Config{
+		DatasourceUID:   "prom-uid",
+		DatasourceType:  dsType,
+		DefaultInterval: time.Minute,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewConverter(t *testing.T) {
+	_, err := NewConverter(Config{DatasourceType: datasources.DS_PROMETHEUS, DefaultInterval: time.Minute})
+	require.ErrorContains(t, err, "data source UID")
+
+	_, err = NewConverter(Config{DatasourceUID: "uid", DatasourceType: "graphite", DefaultInterval: time.Minute})
+	require.ErrorContains(t, err, "not supported")
+
+	_, err = NewConverter(Config{DatasourceUID: "uid", DatasourceType: datasources.DS_PROMETHEUS})
+	require.ErrorContains(t, err, "default interval")
+}
+
+func TestPrometheusRulesToGrafana(t *testing.T) {
+	var file PrometheusRulesFile
+	require.NoError(t, yaml.Unmarshal([]byte(testRules), &file))
+
+	c := newTestConverter(t, datasources.DS_PROMETHEUS)
+	groups, err := c.PrometheusRulesToGrafana(1, "folder-uid", file.Groups)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	node := groups[0]
+	require.Equal(t, "node", node.Title)
+	require.Equal(t, "folder-uid", node.FolderUID)
+	require.EqualValues(t, 30, node.Interval)
+	require.Len(t, node.Rules, 2)
+
+	t.Run("alerting rule", func(t *testing.T) {
+		rule := node.Rules[0]
+		require.Equal(t, "HighLoad", rule.Title)
+		require.Equal(t, int64(1), rule.OrgID)
+		require.Equal(t, "folder-uid", rule.NamespaceUID)
+		require.Equal(t, "node", rule.RuleGroup)
+		require.Equal(t, 1, rule.RuleGroupIndex)
+		require.EqualValues(t, 30, rule.IntervalSeconds)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, map[string]string{"severity": "warning"}, rule.Labels)
+		require.Equal(t, map[string]string{"summary": "Load is {{ $value }}"}, rule.Annotations)
+		require.Equal(t, models.OK, rule.NoDataState)
+		require.Equal(t, models.KeepLastErrState, rule.ExecErrState)
+		require.Equal(t, thresholdRefID, rule.Condition)
+		require.Nil(t, rule.Record)
+
+		require.Len(t, rule.Data, 4)
+		query := rule.Data[0]
+		require.Equal(t, "prom-uid", query.DatasourceUID)
+		require.Equal(t, models.Duration(time.Minute), query.TimeShift)
+		require.Equal(t, models.Duration(10*time.Minute), query.RelativeTimeRange.From)
+		var queryModel map[string]any
+		require.NoError(t, json.Unmarshal(query.Model, &queryModel))
+		require.Equal(t, "node_load1 > 10", queryModel["expr"])
+		require.Equal(t, true, queryModel["instant"])
+
+		refIDs := make([]string, 0, len(rule.Data))
+		for _, q := range rule.Data {
+			refIDs = append(refIDs, q.RefID)
+		}
+		require.Equal(t, []string{queryRefID, reduceRefID, mathRefID, thresholdRefID}, refIDs)
+
+		require.NoError(t, rule.ValidateAlertRule(setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}))
+	})
+
+	t.Run("recording rule", func(t *testing.T) {
+		rule := node.Rules[1]
+		require.Equal(t, "instance:node_cpu:rate5m", rule.Title)
+		require.Equal(t, &models.Record{Metric: "instance:node_cpu:rate5m", From: queryRefID}, rule.Record)
+		require.Len(t, rule.Data, 1)
+		require.Equal(t, map[string]string{"source": "import"}, rule.Labels)
+	})
+
+	t.Run("group without interval uses the default one", func(t *testing.T) {
+		require.EqualValues(t, 60, groups[1].Interval)
+		require.Equal(t, models.Duration(0), groups[1].Rules[0].Data[0].TimeShift)
+	})
+
+	t.Run("duplicated titles get a suffix", func(t *testing.T) {
+		require.Equal(t, "HighLoad (2)", groups[1].Rules[0].Title)
+		require.NotEqual(t, node.Rules[0].UID, groups[1].Rules[0].UID)
+	})
+
+	t.Run("UIDs are stable", func(t *testing.T) {
+		again, err := c.PrometheusRulesToGrafana(1, "folder-uid", file.Groups)
+		require.NoError(t, err)
+		require.Equal(t, groups, again)
+
+		otherOrg, err := c.PrometheusRulesToGrafana(2, "folder-uid", file.Groups)
+		require.NoError(t, err)
+		require.NotEqual(t, node.Rules[0].UID, otherOrg[0].Rules[0].UID)
+	})
+}
+
+func TestPrometheusRulesToGrafana_Loki(t *testing.T) {
+	c := newTestConverter(t, datasources.DS_LOKI)
+	groups, err := c.PrometheusRulesToGrafana(1, "folder-uid", []PrometheusRuleGroup{
+		{
+			Name:  "logs",
+			Rules: []PrometheusRule{{Alert: "Errors", Expr: `sum(rate({app="a"} |= "error" [5m])) > 0`}},
+		},
+	})
+	require.NoError(t, err)
+
+	var queryModel map[string]any
+	require.NoError(t, json.Unmarshal(groups[0].Rules[0].Data[0].Model, &queryModel))
+	require.Equal(t, "instant", queryModel["queryType"])
+	require.NotContains(t, queryModel, "instant")
+}
+
+func TestPrometheusRulesToGrafana_Invalid(t *testing.T) {
+	d := prommodel.Duration(time.Minute)
+	testCases := []struct {
+		name   string
+		groups []PrometheusRuleGroup
+		err    string
+	}{
+		{
+			name:   "empty group name",
+			groups: []PrometheusRuleGroup{{Rules: []PrometheusRule{{Alert: "a", Expr: "up"}}}},
+			err:    "group name must not be empty",
+		},
+		{
+			name:   "duplicated group",
+			groups: []PrometheusRuleGroup{{Name: "g"}, {Name: "g"}},
+			err:    "group g is defined more than once",
+		},
+		{
+			name:   "limit",
+			groups: []PrometheusRuleGroup{{Name: "g", Limit: 10}},
+			err:    "limit of group g is not supported",
+		},
+		{
+			name:   "alert and record",
+			groups: []PrometheusRuleGroup{{Name: "g", Rules: []PrometheusRule{{Alert: "a", Record: "b", Expr: "up"}}}},
+			err:    "exactly one of alert and record must be set",
+		},
+		{
+			name:   "empty expression",
+			groups: []PrometheusRuleGroup{{Name: "g", Rules: []PrometheusRule{{Alert: "a"}}}},
+			err:    "expr must not be empty",
+		},
+		{
+			name:   "invalid expression",
+			groups: []PrometheusRuleGroup{{Name: "g", Rules: []PrometheusRule{{Alert: "a", Expr: "sum(up"}}}},
+			err:    "invalid PromQL expression",
+		},
+		{
+			name:   "keep_firing_for",
+			groups: []PrometheusRuleGroup{{Name: "g", Rules: []PrometheusRule{{Alert: "a", Expr: "up", KeepFiringFor: &d}}}},
+			err:    "keep_firing_for is not supported",
+		},
+		{
+			name:   "recording rule with for",
+			groups: []PrometheusRuleGroup{{Name: "g", Rules: []PrometheusRule{{Record: "a", Expr: "up", For: &d}}}},
+			err:    "recording rule a cannot have for and annotations",
+		},
+	}
+	c := newTestConverter(t, datasources.DS_PROMETHEUS)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := c.PrometheusRulesToGrafana(1, "folder-uid", tc.groups)
+			require.ErrorIs(t, err, ErrInvalidPrometheusRule)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package prom
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// PrometheusRulesFile is a rule file in the format used by Prometheus and Mimir.
+type PrometheusRulesFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// PrometheusRuleGroup is a group of Prometheus alerting and recording rules.
+type PrometheusRuleGroup struct {
+	Name        string           `yaml:"name" json:"name"`
+	Interval    model.Duration   `yaml:"interval,omitempty" json:"interval,omitempty"`
+	QueryOffset *model.Duration  `yaml:"query_offset,omitempty" json:"query_offset,omitempty"`
+	Limit       int              `yaml:"limit,omitempty" json:"limit,omitempty"`
+	Rules       []PrometheusRule `yaml:"rules" json:"rules"`
+}
+
+// PrometheusRule is either a Prometheus alerting rule or a recording rule.
+type PrometheusRule struct {
+	Alert         string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Record        string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Expr          string            `yaml:"expr" json:"expr"`
+	For           *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
+	KeepFiringFor *model.Duration   `yaml:"keep_firing_for,omitempty" json:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
@@ -490,7 +490,11 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, user identity
 					return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
 				}
 			}
-			if err := service.deleteRules(ctx, user.GetOrgID(), delta.Delete...); err != nil {
+			updated := make([]*models.AlertRule, 0, len(delta.Update))
+			for _, update := range delta.Update {
+				updated = append(updated, update.New)
+			}
+			if err := service.deleteRules(ctx, user.GetOrgID(), updated, delta.Delete...); err != nil {
 				return err
 			}
 		}
@@ -663,7 +667,7 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user ident
 	// This is different from deleting groups. We delete the rules directly rather than persisting a delta here to keep the semantics the same.
 	// TODO: Either persist a delta here as a breaking change, or deprecate this endpoint in favor of the group endpoint.
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
-		return service.deleteRules(ctx, user.GetOrgID(), rule)
+		return service.deleteRules(ctx, user.GetOrgID(), nil, rule)
 	})
 }
 
@@ -689,13 +693,18 @@ func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context,
 }
 
 // deleteRules deletes a set of target rules and associated data, while checking for database consistency.
-func (service *AlertRuleService) deleteRules(ctx context.Context, orgID int64, targets ...*models.AlertRule) error {
+// It fails if a rule that is not deleted depends on one of the target rules, taking into account the dependencies
+// of the rules that are updated along with the deletion.
+func (service *AlertRuleService) deleteRules(ctx context.Context, orgID int64, updated []*models.AlertRule, targets ...*models.AlertRule) error {
 	uids := make([]string, 0, len(targets))
 	for _, tgt := range targets {
 		if tgt != nil {
 			uids = append(uids, tgt.UID)
 		}
 	}
+	if err := store.VerifyNoDependentRules(ctx, service.ruleStore, orgID, uids, updated); err != nil {
+		return err
+	}
 	if err := service.ruleStore.DeleteAlertRulesByUID(ctx, orgID, uids...); err != nil {
 		return err
 	}
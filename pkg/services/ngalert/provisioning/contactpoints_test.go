@@ -491,6 +491,7 @@ func createContactPointServiceSutWithConfigStore(t *testing.T, secretService sec
 		legacy_storage.NewAlertmanagerConfigStore(configStore),
 		provisioningStore,
 		&fakeAlertRuleNotificationStore{},
+		nil,
 		secretService,
 		xact,
 		log.NewNopLogger(),
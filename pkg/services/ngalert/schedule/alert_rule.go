@@ -12,7 +12,9 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -129,6 +131,14 @@ type alertRule struct {
 	evalFactory  eval.EvaluatorFactory
 	ruleProvider ruleProvider
 
+	// cost is the cost of the last evaluation of the rule.
+	cost *atomic.Pointer[ngmodels.EvaluationCost]
+	// seriesLimitExceeded is the fingerprint of the rule version that exceeded its series limit. The rule is not
+	// evaluated again until its definition changes. It is only accessed by the evaluation routine.
+	seriesLimitExceeded fingerprint
+	// seriesLimitError is the error of the evaluation that exceeded the series limit while the rule is paused.
+	seriesLimitError *atomic.Error
+
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
 	stopAppliedHook stopAppliedFunc
@@ -170,6 +180,8 @@ func newAlertRule(
 		stateManager:         stateManager,
 		evalFactory:          evalFactory,
 		ruleProvider:         ruleProvider,
+		cost:                 atomic.NewPointer[ngmodels.EvaluationCost](nil),
+		seriesLimitError:     atomic.NewError(nil),
 		evalAppliedHook:      evalAppliedHook,
 		stopAppliedHook:      stopAppliedHook,
 		metrics:              met,
@@ -183,7 +195,18 @@ func (a *alertRule) Type() ngmodels.RuleType {
 }
 
 func (a *alertRule) Status() ngmodels.RuleStatus {
-	return a.stateManager.GetStatusForRuleUID(a.key.OrgID, a.key.UID)
+	status := a.stateManager.GetStatusForRuleUID(a.key.OrgID, a.key.UID)
+	if cost := a.cost.Load(); cost != nil {
+		status.Cost = *cost
+	}
+	// the error of a rule that exceeded its series limit does not result in an error state if the rule executes
+	// errors as another state.
+	if err := a.seriesLimitError.Load(); err != nil {
+		status.Health = "error"
+		status.LastError = err
+		status.SeriesLimitExceeded = true
+	}
+	return status
 }
 
 // eval signals the rule evaluation routine to perform the evaluation of the rule. Does nothing if the loop is stopped.
@@ -300,6 +323,18 @@ func (a *alertRule) Run() error {
 						logger.Debug("Skip rule evaluation because it is paused")
 						return
 					}
+					// A rule that depends on a rule that does not exist is evaluated to an error state instead of being skipped.
+					dependencies := state.CheckRuleDependencies(a.stateManager, ctx.rule, a.ruleExists)
+					if unmet, ok := state.FirstUnmetRuleDependency(dependencies); ok && state.MissingRuleDependencyParentError(dependencies) == nil {
+						logger.Debug("Skip rule evaluation because a dependency is not met", "parentRuleUID", unmet.RuleUID, "requiredState", unmet.State, "parentState", unmet.ParentState)
+						a.resetSkippedState(grafanaCtx, ctx.rule)
+						return
+					}
+					if a.seriesLimitExceeded == f {
+						logger.Debug("Skip rule evaluation because it exceeded its series limit")
+						return
+					}
+					a.seriesLimitError.Store(nil)
 
 					// Only increment evaluation counter once, not per-retry.
 					if attempt == 1 {
@@ -378,11 +413,22 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	if err != nil {
 		dur = a.clock.Now().Sub(start)
 		logger.Error("Failed to build rule evaluator", "error", err)
+	} else if parentErr := state.MissingRuleDependencyParentError(state.CheckRuleDependencies(a.stateManager, e.rule, a.ruleExists)); parentErr != nil {
+		dur = a.clock.Now().Sub(start)
+		logger.Warn("Rule depends on a rule that does not exist", "error", parentErr)
+		results = eval.Results{eval.NewResultFromError(parentErr, e.scheduledAt, dur)}
 	} else {
-		results, err = ruleEval.Evaluate(ctx, e.scheduledAt)
+		stats := &expr.ExecutionStats{}
+		results, err = ruleEval.Evaluate(expr.WithExecutionStats(ctx, stats), e.scheduledAt)
 		dur = a.clock.Now().Sub(start)
+		a.cost.Store(evaluationCost(stats, len(results)))
 		if err != nil {
 			logger.Error("Failed to evaluate rule", "error", err, "duration", dur)
+		} else if limitErr := checkSeriesLimit(e.rule, len(results)); limitErr != nil {
+			logger.Warn("Rule exceeded its series limit and is paused until it is updated", "series", len(results), "limit", e.rule.MaxSeries)
+			a.seriesLimitExceeded = e.Fingerprint()
+			a.seriesLimitError.Store(limitErr)
+			results = eval.Results{eval.NewResultFromError(limitErr, e.scheduledAt, dur)}
 		}
 	}
 
@@ -490,6 +536,21 @@ func (a *alertRule) resetState(ctx context.Context, isPaused bool) {
 	a.expireAndSend(ctx, states)
 }
 
+// resetSkippedState resolves the alerts of a rule that is skipped because a dependency is not met, so that they do not
+// keep firing, or stay pending, while the rule is not evaluated.
+func (a *alertRule) resetSkippedState(ctx context.Context, rule *ngmodels.AlertRule) {
+	if len(a.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)) == 0 {
+		return
+	}
+	states := a.stateManager.ResetStateByRuleUID(ctx, rule, ngmodels.StateReasonDependencyNotMet)
+	a.expireAndSend(ctx, states)
+}
+
+// ruleExists returns true if the rule is known to the scheduler.
+func (a *alertRule) ruleExists(key ngmodels.AlertRuleKey) bool {
+	return a.ruleProvider.get(key) != nil
+}
+
 // evalApplied is only used on tests.
 func (a *alertRule) evalApplied(now time.Time) {
 	if a.evalAppliedHook == nil {
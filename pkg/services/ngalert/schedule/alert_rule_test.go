@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/util"
@@ -465,6 +466,121 @@ func TestRuleRoutine(t *testing.T) {
 		})
 	}
 
+	t.Run("when a dependency of the rule is not met", func(t *testing.T) {
+		evalAppliedChan := make(chan time.Time)
+		sch, ruleStore, _, _ := createSchedule(evalAppliedChan, nil)
+
+		parent := gen.GenerateRef()
+		rule := gen.With(withQueryForState(t, eval.Alerting), gen.WithOrgID(parent.OrgID), gen.WithErrorExecAs(models.ErrorErrState), gen.WithDependencies(models.RuleDependency{
+			RuleUID: parent.UID,
+			State:   models.RuleDependencyStateNormal,
+		})).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+		sch.schedulableAlertRules.update(parent)
+		factory := ruleFactoryFromScheduler(sch)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		ruleInfo := factory.new(ctx, rule)
+		go func() {
+			_ = ruleInfo.Run()
+		}()
+
+		evaluate := func() {
+			ruleInfo.Eval(&Evaluation{
+				scheduledAt: time.Now(),
+				rule:        rule,
+				folderTitle: ruleStore.getNamespaceTitle(rule.NamespaceUID),
+			})
+			_ = waitForTimeChannel(t, evalAppliedChan)
+		}
+
+		t.Run("it should not evaluate the rule if the parent rule has no state", func(t *testing.T) {
+			evaluate()
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it should not evaluate the rule if the parent rule is in another state", func(t *testing.T) {
+			sch.stateManager.Put([]*state.State{{OrgID: parent.OrgID, AlertRuleUID: parent.UID, CacheID: 1, State: eval.Alerting}})
+			evaluate()
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it should evaluate the rule when the parent rule is in the required state", func(t *testing.T) {
+			sch.stateManager.Put([]*state.State{{OrgID: parent.OrgID, AlertRuleUID: parent.UID, CacheID: 1, State: eval.Normal}})
+			evaluate()
+			require.Len(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+		})
+
+		t.Run("it should resolve the alerts of the rule when the dependency is not met anymore", func(t *testing.T) {
+			sch.stateManager.Put([]*state.State{{OrgID: parent.OrgID, AlertRuleUID: parent.UID, CacheID: 1, State: eval.Alerting}})
+			evaluate()
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it should evaluate the rule to an error when the parent rule does not exist", func(t *testing.T) {
+			sch.schedulableAlertRules.del(parent.GetKey())
+			sch.stateManager.DeleteStateByRuleUID(context.Background(), parent.GetKeyWithGroup(), models.StateReasonRuleDeleted)
+			evaluate()
+			states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			require.Equal(t, eval.Error, states[0].State)
+			require.ErrorIs(t, states[0].Error, eval.ErrRuleDependencyParentMissing)
+		})
+	})
+
+	t.Run("when the rule exceeds its series limit", func(t *testing.T) {
+		evalAppliedChan := make(chan time.Time)
+		sch, ruleStore, _, _ := createSchedule(evalAppliedChan, nil)
+
+		evaluator := eval_mocks.NewConditionEvaluatorMock(t)
+		// The rule must be evaluated only once as it is paused after it exceeded its series limit.
+		evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(eval.Results{
+			{Instance: data.Labels{"series": "1"}, State: eval.Alerting},
+			{Instance: data.Labels{"series": "2"}, State: eval.Alerting},
+		}, nil).Once()
+		sch.evaluatorFactory = eval_mocks.NewEvaluatorFactory(evaluator)
+
+		rule := gen.With(withQueryForState(t, eval.Alerting), gen.WithErrorExecAs(models.ErrorErrState), gen.WithMaxSeries(1)).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+		factory := ruleFactoryFromScheduler(sch)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		ruleInfo := factory.new(ctx, rule)
+		go func() {
+			_ = ruleInfo.Run()
+		}()
+
+		evaluate := func() {
+			ruleInfo.Eval(&Evaluation{
+				scheduledAt: time.Now(),
+				rule:        rule,
+				folderTitle: ruleStore.getNamespaceTitle(rule.NamespaceUID),
+			})
+			_ = waitForTimeChannel(t, evalAppliedChan)
+		}
+
+		t.Run("it should replace the results with an error and record the cost of the evaluation", func(t *testing.T) {
+			evaluate()
+			states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			require.Equal(t, eval.Error, states[0].State)
+			require.ErrorIs(t, states[0].Error, eval.ErrSeriesLimitExceeded)
+			require.Equal(t, 2, ruleInfo.Status().Cost.SeriesCount)
+		})
+
+		t.Run("it should not evaluate the rule again until it is updated", func(t *testing.T) {
+			evaluate()
+			require.Len(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+		})
+
+		t.Run("it should report the pause in the status of the rule", func(t *testing.T) {
+			status := ruleInfo.Status()
+			require.True(t, status.SeriesLimitExceeded)
+			require.Equal(t, "error", status.Health)
+			require.ErrorIs(t, status.LastError, eval.ErrSeriesLimitExceeded)
+		})
+	})
+
 	t.Run("should exit", func(t *testing.T) {
 		t.Run("and not clear the state if parent context is cancelled", func(t *testing.T) {
 			stoppedChan := make(chan error)
@@ -0,0 +1,26 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// evaluationCost returns the cost of an evaluation that produced the given number of series.
+func evaluationCost(stats *expr.ExecutionStats, series int) *ngmodels.EvaluationCost {
+	return &ngmodels.EvaluationCost{
+		QueryDuration:      stats.DatasourceDuration,
+		ExpressionDuration: stats.ExpressionDuration,
+		SeriesCount:        series,
+	}
+}
+
+// checkSeriesLimit returns an error if an evaluation of the rule produced more series than the rule allows.
+func checkSeriesLimit(rule *ngmodels.AlertRule, series int) error {
+	if rule.MaxSeries <= 0 || int64(series) <= rule.MaxSeries {
+		return nil
+	}
+	return fmt.Errorf("%w: the rule produced %d series (limit: %d) and is paused until it is updated", eval.ErrSeriesLimitExceeded, series, rule.MaxSeries)
+}
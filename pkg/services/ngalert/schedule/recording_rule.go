@@ -2,6 +2,7 @@ package schedule
 
 import (
 	context "context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -39,6 +41,13 @@ type recordingRule struct {
 	lastError           *atomic.Error
 	evaluationTimestamp *atomic.Time
 	evaluationDuration  *atomic.Duration
+	cost                *atomic.Pointer[ngmodels.EvaluationCost]
+
+	// seriesLimitExceeded is the fingerprint of the rule version that exceeded its series limit. The rule is not
+	// evaluated again until its definition changes. It is only accessed by the evaluation routine.
+	seriesLimitExceeded fingerprint
+	// seriesLimitError is the error of the evaluation that exceeded the series limit while the rule is paused.
+	seriesLimitError *atomic.Error
 
 	maxAttempts int64
 
@@ -67,6 +76,8 @@ func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKey, maxAtte
 		lastError:           atomic.NewError(nil),
 		evaluationTimestamp: atomic.NewTime(time.Time{}),
 		evaluationDuration:  atomic.NewDuration(0),
+		cost:                atomic.NewPointer[ngmodels.EvaluationCost](nil),
+		seriesLimitError:    atomic.NewError(nil),
 		clock:               clock,
 		evalFactory:         evalFactory,
 		cfg:                 cfg,
@@ -85,12 +96,17 @@ func (r *recordingRule) Type() ngmodels.RuleType {
 }
 
 func (r *recordingRule) Status() ngmodels.RuleStatus {
-	return ngmodels.RuleStatus{
+	status := ngmodels.RuleStatus{
 		Health:              r.health.Load(),
 		LastError:           r.lastError.Load(),
 		EvaluationTimestamp: r.evaluationTimestamp.Load(),
 		EvaluationDuration:  r.evaluationDuration.Load(),
 	}
+	if cost := r.cost.Load(); cost != nil {
+		status.Cost = *cost
+	}
+	status.SeriesLimitExceeded = r.seriesLimitError.Load() != nil
+	return status
 }
 
 func (r *recordingRule) Eval(eval *Evaluation) (bool, *Evaluation) {
@@ -172,6 +188,11 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 		logger.Debug("Skip recording rule evaluation because it is paused")
 		return
 	}
+	if r.seriesLimitExceeded == ev.Fingerprint() {
+		logger.Debug("Skip recording rule evaluation because it exceeded its series limit")
+		return
+	}
+	r.seriesLimitError.Store(nil)
 
 	ctx, span := r.tracer.Start(ctx, "recording rule execution", trace.WithAttributes(
 		attribute.String("rule_uid", ev.rule.UID),
@@ -217,6 +238,10 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 	}
 
 	if latestError != nil {
+		if errors.Is(latestError, eval.ErrSeriesLimitExceeded) {
+			r.seriesLimitExceeded = ev.Fingerprint()
+			r.seriesLimitError.Store(latestError)
+		}
 		evalTotalFailures.Inc()
 		span.SetStatus(codes.Error, "rule evaluation failed")
 		span.RecordError(latestError)
@@ -236,8 +261,10 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logger log.Logger) error {
 	evalStart := r.clock.Now()
 	evalCtx := eval.NewContext(ctx, SchedulerUserFor(ev.rule.OrgID))
-	result, err := r.buildAndExecutePipeline(ctx, evalCtx, ev, logger)
+	stats := &expr.ExecutionStats{}
+	result, err := r.buildAndExecutePipeline(expr.WithExecutionStats(ctx, stats), evalCtx, ev, logger)
 	evalDur := r.clock.Now().Sub(evalStart)
+	r.cost.Store(evaluationCost(stats, 0))
 	if err != nil {
 		return fmt.Errorf("server side expressions pipeline returned an error: %w", err)
 	}
@@ -264,6 +291,12 @@ func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logge
 		return nil
 	}
 
+	r.cost.Store(evaluationCost(stats, len(frames)))
+	if err := checkSeriesLimit(ev.rule, len(frames)); err != nil {
+		logger.Warn("Recording rule exceeded its series limit and is paused until it is updated", "series", len(frames), "limit", ev.rule.MaxSeries)
+		return err
+	}
+
 	writeStart := r.clock.Now()
 	err = r.writer.Write(ctx, ev.rule.Record.Metric, ev.scheduledAt, frames, ev.rule.OrgID, ev.rule.Labels)
 	writeDur := r.clock.Now().Sub(writeStart)
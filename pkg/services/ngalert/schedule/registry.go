@@ -18,6 +18,7 @@ import (
 var (
 	errRuleDeleted   = errors.New("rule deleted")
 	errRuleRestarted = errors.New("rule restarted")
+	errRuleReleased  = errors.New("rule evaluated by another instance")
 )
 
 type ruleFactory interface {
@@ -285,6 +286,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 					writeString(q.QueryType)
 					writeInt(int64(q.RelativeTimeRange.From))
 					writeInt(int64(q.RelativeTimeRange.To))
+					writeInt(int64(q.TimeShift))
 					writeBytes(q.Model)
 					break
 				}
@@ -331,6 +333,11 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(rule.Record.Fingerprint()))
 		writeBytes(tmp)
 	}
+	for _, dependency := range rule.Dependencies {
+		binary.LittleEndian.PutUint64(tmp, uint64(dependency.Fingerprint()))
+		writeBytes(tmp)
+	}
+	writeInt(rule.MaxSeries)
 
 	return fingerprint(sum.Sum64())
 }
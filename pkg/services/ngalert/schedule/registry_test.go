@@ -147,6 +147,12 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 		f2 := ruleWithFolder{rule: cp, folderTitle: title}.Fingerprint()
 		require.Equal(t, f, f2)
 	})
+	t.Run("time shift of a query should be used in fingerprint", func(t *testing.T) {
+		cp := models.CopyRule(rule)
+		cp.Data[0].TimeShift += models.Duration(time.Hour)
+		f2 := ruleWithFolder{rule: cp, folderTitle: title}.Fingerprint()
+		require.NotEqual(t, f, f2)
+	})
 	t.Run("folder name should be used in fingerprint", func(t *testing.T) {
 		f2 := ruleWithFolder{rule: rule, folderTitle: uuid.NewString()}.Fingerprint()
 		require.NotEqual(t, f, f2)
@@ -204,6 +210,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NotificationSettings: []models.NotificationSettings{
 				models.NotificationSettingsGen()(),
 			},
+			Dependencies: []models.RuleDependency{
+				{RuleUID: "parent-uid", State: models.RuleDependencyStateNormal},
+			},
+			MaxSeries: 100,
 			Metadata: models.AlertRuleMetadata{
 				EditorSettings: models.EditorSettings{
 					SimplifiedQueryAndExpressionsSection: false,
@@ -249,6 +259,10 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NotificationSettings: []models.NotificationSettings{
 				models.NotificationSettingsGen()(),
 			},
+			Dependencies: []models.RuleDependency{
+				{RuleUID: "parent-uid2", State: models.RuleDependencyStateAlerting},
+			},
+			MaxSeries: 200,
 			Metadata: models.AlertRuleMetadata{
 				EditorSettings: models.EditorSettings{
 					SimplifiedQueryAndExpressionsSection: true,
@@ -100,6 +100,12 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	// clusterMembers is used to shard the evaluation of alert rules across the instances of the HA cluster.
+	// The evaluation is not sharded when it is nil.
+	clusterMembers ClusterMembers
+	// ring is the shard ring of the previous tick.
+	ring *shardRing
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -119,6 +125,8 @@ type SchedulerCfg struct {
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 	RecordingWriter      RecordingWriter
+	// ClusterMembers enables sharding of the evaluation of alert rules across the instances of the HA cluster.
+	ClusterMembers ClusterMembers
 }
 
 // NewScheduler returns a new scheduler.
@@ -148,6 +156,7 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
 		recordingWriter:       cfg.RecordingWriter,
+		clusterMembers:        cfg.ClusterMembers,
 	}
 
 	return &sch
@@ -225,6 +234,32 @@ func (sch *schedule) schedulePeriodic(ctx context.Context, t *ticker.T) error {
 	}
 }
 
+// shardAlertRules returns the alert rules that are evaluated by this instance and the alert rules that are evaluated
+// by other instances of the HA cluster. All rules are returned when the evaluation is not sharded.
+func (sch *schedule) shardAlertRules(alertRules []*ngmodels.AlertRule) ([]*ngmodels.AlertRule, []*ngmodels.AlertRule) {
+	if sch.clusterMembers == nil {
+		return alertRules, nil
+	}
+	ring := newShardRing(sch.clusterMembers.ClusterMembers())
+	if sch.ring == nil || !sch.ring.equal(ring) {
+		sch.log.Info("Cluster members changed, sharding alert rules", "members", len(ring.members), "self", ring.self)
+	}
+	sch.ring = &ring
+
+	keys := shardKeys(alertRules)
+	owned := make([]*ngmodels.AlertRule, 0, len(alertRules)/len(ring.members)+1)
+	var released []*ngmodels.AlertRule
+	for _, rule := range alertRules {
+		if ring.owns(keys[rule.GetKey()]) {
+			owned = append(owned, rule)
+		} else {
+			released = append(released, rule)
+		}
+	}
+	sch.metrics.OwnedAlertRules.Set(float64(len(owned)))
+	return owned, released
+}
+
 type readyToRunItem struct {
 	ruleRoutine Rule
 	Evaluation
@@ -256,6 +291,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	// rules that are taken over from another instance of the HA cluster continue from the state that instance
+	// saved. On the first tick, the state was loaded when the state cache was warmed up.
+	loadState := sch.ring != nil
+	alertRules, released := sch.shardAlertRules(alertRules)
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
@@ -299,7 +339,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		if newRoutine && !invalidInterval {
+			_, registered := registeredDefinitions[key]
+			takeOver := loadState && !registered
 			dispatcherGroup.Go(func() error {
+				if takeOver {
+					sch.stateManager.LoadStateByRuleUID(ctx, item)
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -387,6 +432,20 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
+	// stop routines of the alert rules that are evaluated by another instance of the HA cluster now
+	for _, rule := range released {
+		key := rule.GetKey()
+		if _, ok := registeredDefinitions[key]; !ok {
+			continue
+		}
+		delete(registeredDefinitions, key)
+		if ruleRoutine, ok := sch.registry.del(key); ok {
+			ruleRoutine.Stop(errRuleReleased)
+		}
+	}
+	// the state of the rules evaluated by other instances is read from the instance store when it is read from the API.
+	sch.stateManager.SetRemoteRules(released)
+
 	// unregister and stop routines of the deleted alert rules
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"unsafe"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ClusterMembers provides the members of the HA cluster that share the evaluation of alert rules.
+type ClusterMembers interface {
+	// ClusterMembers returns the name of this instance and the names of the live members of the cluster.
+	ClusterMembers() (string, []string)
+}
+
+// shardRing assigns shard keys to the members of the HA cluster using rendezvous hashing: every key is owned by the
+// member with the highest hash of the member name and the key. When a member joins or leaves the cluster, only the
+// keys owned by that member move to other members.
+type shardRing struct {
+	self    string
+	members []string
+}
+
+// newShardRing creates a ring of the given members. This instance is always a member of the ring, so that the rules
+// are still evaluated when the member list is not known yet.
+func newShardRing(self string, members []string) shardRing {
+	result := make([]string, 0, len(members)+1)
+	result = append(result, self)
+	for _, m := range members {
+		if m != "" {
+			result = append(result, m)
+		}
+	}
+	slices.Sort(result)
+	return shardRing{self: self, members: slices.Compact(result)}
+}
+
+func (r shardRing) equal(other shardRing) bool {
+	return r.self == other.self && slices.Equal(r.members, other.members)
+}
+
+func (r shardRing) owner(key string) string {
+	var owner string
+	var maxScore uint64
+	for _, m := range r.members {
+		if score := shardScore(m, key); owner == "" || score > maxScore {
+			owner, maxScore = m, score
+		}
+	}
+	return owner
+}
+
+func (r shardRing) owns(key string) bool {
+	return len(r.members) <= 1 || r.owner(key) == r.self
+}
+
+func shardScore(member, key string) uint64 {
+	h := fnv.New64a()
+	// save on extra slice allocation when string is converted to bytes.
+	_, _ = h.Write(unsafe.Slice(unsafe.StringData(member), len(member))) //nolint:gosec
+	_, _ = h.Write([]byte{255})                                          // use an invalid utf-8 sequence as separator
+	_, _ = h.Write(unsafe.Slice(unsafe.StringData(key), len(key)))       //nolint:gosec
+	// FNV does not mix the last bytes well enough to compare hashes of similar strings, so the hash is finalized
+	// with the mixing function of SplitMix64.
+	z := h.Sum64()
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// shardKeys returns the keys by which the rules are assigned to the members of the HA cluster. Rules are assigned by
+// rule group. Groups that contain rules that depend on each other, directly or through other rules, get the same
+// key, because dependencies are checked against the state cache of the instance that evaluates the rule.
+func shardKeys(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleKey]string {
+	groupKey := func(k ngmodels.AlertRuleGroupKey) string {
+		return fmt.Sprintf("%d/%s/%s", k.OrgID, k.NamespaceUID, k.RuleGroup)
+	}
+
+	// union-find over the rule groups, where the root of every set is the smallest key of the set.
+	parents := make(map[string]string)
+	var find func(string) string
+	find = func(k string) string {
+		p, ok := parents[k]
+		if !ok || p == k {
+			return k
+		}
+		root := find(p)
+		parents[k] = root
+		return root
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		parents[rb] = ra
+	}
+
+	byUID := make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule, len(rules))
+	for _, r := range rules {
+		byUID[r.GetKey()] = r
+	}
+	for _, r := range rules {
+		for _, d := range r.Dependencies {
+			parent, ok := byUID[ngmodels.AlertRuleKey{OrgID: r.OrgID, UID: d.RuleUID}]
+			if !ok {
+				continue
+			}
+			union(groupKey(r.GetGroupKey()), groupKey(parent.GetGroupKey()))
+		}
+	}
+
+	result := make(map[ngmodels.AlertRuleKey]string, len(rules))
+	for _, r := range rules {
+		result[r.GetKey()] = find(groupKey(r.GetGroupKey()))
+	}
+	return result
+}
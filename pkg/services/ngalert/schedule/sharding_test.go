@@ -0,0 +1,281 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type fakeClusterMembers struct {
+	mtx     sync.Mutex
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembers) ClusterMembers() (string, []string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.self, f.members
+}
+
+func (f *fakeClusterMembers) setMembers(members ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.members = members
+}
+
+// instanceStoreWithInstances returns the alert instances of every rule from a fixed list.
+type instanceStoreWithInstances struct {
+	*state.FakeInstanceStore
+	instances []*models.AlertInstance
+	// orgReads is the number of times the alert instances of a whole organization were listed.
+	orgReads atomic.Int64
+}
+
+func (f *instanceStoreWithInstances) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	if q.RuleUID == "" {
+		f.orgReads.Add(1)
+	}
+	var result []*models.AlertInstance
+	for _, i := range f.instances {
+		if i.RuleOrgID == q.RuleOrgID && (q.RuleUID == "" || i.RuleUID == q.RuleUID) {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+func TestShardRing(t *testing.T) {
+	keys := make([]string, 0, 1000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, fmt.Sprintf("1/folder/group-%d", i))
+	}
+
+	t.Run("owns all keys when it is the only member", func(t *testing.T) {
+		for _, ring := range []shardRing{newShardRing("a", nil), newShardRing("a", []string{"a"}), newShardRing("", nil)} {
+			for _, k := range keys {
+				require.True(t, ring.owns(k))
+			}
+		}
+	})
+
+	t.Run("every key is owned by exactly one member", func(t *testing.T) {
+		members := []string{"a", "b", "c"}
+		owned := make(map[string]int, len(members))
+		for _, k := range keys {
+			owners := 0
+			for _, m := range members {
+				if newShardRing(m, members).owns(k) {
+					owners++
+					owned[m]++
+				}
+			}
+			require.Equal(t, 1, owners, "key %s", k)
+		}
+		for _, m := range members {
+			require.InDelta(t, len(keys)/len(members), owned[m], float64(len(keys))/10, "member %s owns an unbalanced number of keys", m)
+		}
+	})
+
+	t.Run("only keys of the member that joins or leaves move", func(t *testing.T) {
+		before := newShardRing("a", []string{"a", "b", "c"})
+		after := newShardRing("a", []string{"a", "b", "c", "d"})
+		for _, k := range keys {
+			if owner := after.owner(k); owner != "d" {
+				require.Equal(t, before.owner(k), owner, "key %s", k)
+			}
+		}
+	})
+
+	t.Run("includes itself and ignores duplicates and empty names", func(t *testing.T) {
+		ring := newShardRing("b", []string{"c", "", "a", "c"})
+		require.Equal(t, []string{"a", "b", "c"}, ring.members)
+		require.True(t, ring.equal(newShardRing("b", []string{"a", "c"})))
+		require.False(t, ring.equal(newShardRing("a", []string{"b", "c"})))
+	})
+}
+
+func TestShardKeys(t *testing.T) {
+	gen := models.RuleGen.With(models.RuleMuts.WithOrgID(1))
+	inGroup := func(group string) models.AlertRuleMutator {
+		return func(r *models.AlertRule) {
+			r.NamespaceUID = "folder"
+			r.RuleGroup = group
+		}
+	}
+	dependsOn := func(parent *models.AlertRule) models.AlertRuleMutator {
+		return func(r *models.AlertRule) {
+			r.Dependencies = append(r.Dependencies, models.RuleDependency{RuleUID: parent.UID, State: models.RuleDependencyStateNormal})
+		}
+	}
+
+	root := gen.With(inGroup("c")).GenerateRef()
+	sibling := gen.With(inGroup("c")).GenerateRef()
+	child := gen.With(inGroup("d"), dependsOn(root)).GenerateRef()
+	grandchild := gen.With(inGroup("b"), dependsOn(child)).GenerateRef()
+	independent := gen.With(inGroup("a")).GenerateRef()
+	missingParent := gen.With(inGroup("e"), dependsOn(gen.GenerateRef())).GenerateRef()
+
+	keys := shardKeys([]*models.AlertRule{grandchild, child, root, sibling, independent, missingParent})
+	require.Equal(t, map[models.AlertRuleKey]string{
+		root.GetKey():          "1/folder/b",
+		sibling.GetKey():       "1/folder/b",
+		child.GetKey():         "1/folder/b",
+		grandchild.GetKey():    "1/folder/b",
+		independent.GetKey():   "1/folder/a",
+		missingParent.GetKey(): "1/folder/e",
+	}, keys)
+}
+
+func TestProcessTicksWithSharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	cluster := &fakeClusterMembers{self: "self", members: []string{"self", "other"}}
+	sched.clusterMembers = cluster
+	ring := newShardRing("self", []string{"other"})
+
+	// rules are evaluated every 10 ticks, so that they are not evaluated on the ticks of this test.
+	gen := models.RuleGen.With(models.RuleMuts.WithOrgID(1), models.RuleMuts.WithIntervalSeconds(10))
+	var owned, notOwned *models.AlertRule
+	for owned == nil || notOwned == nil {
+		rule := gen.GenerateRef()
+		if ring.owns(shardKeys([]*models.AlertRule{rule})[rule.GetKey()]) {
+			owned = rule
+		} else {
+			notOwned = rule
+		}
+	}
+	ruleStore.PutRule(ctx, owned, notOwned)
+
+	instance := func(rule *models.AlertRule, s models.InstanceStateType) *models.AlertInstance {
+		labels := models.InstanceLabels{"rule": rule.UID}
+		_, hash, err := labels.StringAndHash()
+		require.NoError(t, err)
+		return &models.AlertInstance{
+			AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash},
+			Labels:            labels,
+			CurrentState:      s,
+			CurrentStateSince: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	instances := &instanceStoreWithInstances{
+		FakeInstanceStore: &state.FakeInstanceStore{},
+		instances:         []*models.AlertInstance{instance(owned, models.InstanceStateFiring), instance(notOwned, models.InstanceStatePending)},
+	}
+	clk := clock.NewMock()
+	st := state.NewManager(state.ManagerCfg{
+		Metrics:        metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:  instances,
+		Images:         &state.NoopImageService{},
+		Clock:          clk,
+		Historian:      &state.FakeHistorian{},
+		Tracer:         tracing.InitializeTracerForTest(),
+		Log:            log.New("ngalert.state.manager"),
+		RemoteStateTTL: 10 * time.Second,
+	}, state.NewNoopPersister())
+	// the state cache was warmed up with the state of all rules.
+	for _, i := range instances.instances {
+		st.Put([]*state.State{{
+			OrgID:        i.RuleOrgID,
+			AlertRuleUID: i.RuleUID,
+			CacheID:      i.Labels.Fingerprint(),
+			Labels:       map[string]string(i.Labels),
+			StartsAt:     i.CurrentStateSince,
+		}})
+	}
+	sched.stateManager = st
+
+	stopAppliedCh := make(chan models.AlertRuleKey, 1)
+	sched.stopAppliedFunc = func(key models.AlertRuleKey) {
+		stopAppliedCh <- key
+	}
+
+	statesOf := func(rule *models.AlertRule) []*state.State {
+		return st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	}
+
+	tick := time.Unix(1, 0)
+	t.Run("on 1st tick only the owned rule should be scheduled", func(t *testing.T) {
+		sched.processTick(ctx, dispatcherGroup, tick)
+
+		_, ok := sched.registry.get(owned.GetKey())
+		require.True(t, ok)
+		_, ok = sched.registry.get(notOwned.GetKey())
+		require.False(t, ok)
+		require.Len(t, statesOf(owned), 1)
+		require.Zero(t, instances.orgReads.Load(), "the instance store should not be read on ticks")
+
+		st.LoadRemoteStates(ctx, 1)
+		require.Len(t, statesOf(notOwned), 1, "the state of rules that are not owned should be read from the instance store")
+		require.Equal(t, eval.Pending, statesOf(notOwned)[0].State)
+	})
+
+	t.Run("when the other member leaves the rule is taken over with its state", func(t *testing.T) {
+		cluster.setMembers("self")
+		tick = tick.Add(time.Second)
+		sched.processTick(ctx, dispatcherGroup, tick)
+
+		_, ok := sched.registry.get(notOwned.GetKey())
+		require.True(t, ok)
+		require.Eventually(t, func() bool {
+			return len(statesOf(notOwned)) == 1
+		}, time.Second, 10*time.Millisecond)
+		s := statesOf(notOwned)[0]
+		require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), s.StartsAt, "the pending duration should be preserved")
+	})
+
+	t.Run("when the other member joins the rule is released without deleting its state", func(t *testing.T) {
+		cluster.setMembers("self", "other")
+		tick = tick.Add(time.Second)
+		_, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+
+		require.Empty(t, stopped, "released rules should not be deleted")
+		assertStopRun(t, stopAppliedCh, notOwned.GetKey())
+		_, ok := sched.registry.get(notOwned.GetKey())
+		require.False(t, ok)
+		require.Len(t, statesOf(notOwned), 1)
+		require.Len(t, statesOf(owned), 1)
+		require.Empty(t, instances.RecordedOps(), "the instance store should not be changed")
+	})
+
+	t.Run("the state of rules evaluated by the other member is read at most once per TTL", func(t *testing.T) {
+		reads := instances.orgReads.Load()
+		st.LoadRemoteStates(ctx, 1)
+		require.Equal(t, reads+1, instances.orgReads.Load(), "the states should be read again when the rules of the other member changed")
+		reads++
+		require.Equal(t, eval.Pending, statesOf(notOwned)[0].State)
+
+		instances.instances[1].CurrentState = models.InstanceStateFiring
+		tick = tick.Add(time.Second)
+		sched.processTick(ctx, dispatcherGroup, tick)
+		st.LoadRemoteStates(ctx, 1)
+		require.Equal(t, reads, instances.orgReads.Load(), "the instance store should not be read again before the TTL")
+		require.Equal(t, eval.Pending, statesOf(notOwned)[0].State)
+
+		clk.Add(10 * time.Second)
+		st.LoadRemoteStates(ctx, 1)
+		require.Equal(t, reads+1, instances.orgReads.Load())
+		require.Len(t, statesOf(notOwned), 1)
+		require.Equal(t, eval.Alerting, statesOf(notOwned)[0].State)
+		require.Len(t, statesOf(owned), 1)
+		require.Equal(t, eval.Normal, statesOf(owned)[0].State, "the state of owned rules should not be read from the instance store")
+	})
+}
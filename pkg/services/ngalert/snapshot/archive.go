@@ -0,0 +1,160 @@
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ArchiveVersion is the version of the archive format written by Write.
+const ArchiveVersion = 1
+
+// Archive is a portable snapshot of the alerting state of a Grafana instance. It does not depend on the database
+// backend of the instance it was exported from.
+type Archive struct {
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Orgs      []OrgState `json:"orgs"`
+}
+
+// OrgState is the alerting state of an organization.
+type OrgState struct {
+	OrgID          int64           `json:"orgId"`
+	AlertInstances []AlertInstance `json:"alertInstances,omitempty"`
+	// Silences is the binary snapshot of the silences of the Alertmanager of the organization.
+	Silences []byte `json:"silences,omitempty"`
+	// NotificationLog is the binary snapshot of the notification log of the Alertmanager of the organization.
+	NotificationLog []byte `json:"notificationLog,omitempty"`
+}
+
+// AlertInstance is the state of a single alert instance.
+type AlertInstance struct {
+	RuleUID           string            `json:"ruleUid"`
+	Labels            map[string]string `json:"labels"`
+	State             string            `json:"state"`
+	Reason            string            `json:"reason,omitempty"`
+	StateSince        time.Time         `json:"stateSince"`
+	StateEnd          time.Time         `json:"stateEnd"`
+	LastEvalTime      time.Time         `json:"lastEvalTime"`
+	LastSentAt        *time.Time        `json:"lastSentAt,omitempty"`
+	ResolvedAt        *time.Time        `json:"resolvedAt,omitempty"`
+	ResultFingerprint string            `json:"resultFingerprint,omitempty"`
+}
+
+func fromAlertInstance(i models.AlertInstance) AlertInstance {
+	return AlertInstance{
+		RuleUID:           i.RuleUID,
+		Labels:            i.Labels,
+		State:             string(i.CurrentState),
+		Reason:            i.CurrentReason,
+		StateSince:        i.CurrentStateSince,
+		StateEnd:          i.CurrentStateEnd,
+		LastEvalTime:      i.LastEvalTime,
+		LastSentAt:        i.LastSentAt,
+		ResolvedAt:        i.ResolvedAt,
+		ResultFingerprint: i.ResultFingerprint,
+	}
+}
+
+func (i AlertInstance) toAlertInstance(orgID int64) (models.AlertInstance, error) {
+	labels := models.InstanceLabels(i.Labels)
+	_, hash, err := labels.StringAndHash()
+	if err != nil {
+		return models.AlertInstance{}, err
+	}
+	result := models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  orgID,
+			RuleUID:    i.RuleUID,
+			LabelsHash: hash,
+		},
+		Labels:            labels,
+		CurrentState:      models.InstanceStateType(i.State),
+		CurrentReason:     i.Reason,
+		CurrentStateSince: i.StateSince,
+		CurrentStateEnd:   i.StateEnd,
+		LastEvalTime:      i.LastEvalTime,
+		LastSentAt:        i.LastSentAt,
+		ResolvedAt:        i.ResolvedAt,
+		ResultFingerprint: i.ResultFingerprint,
+	}
+	if err := models.ValidateAlertInstance(result); err != nil {
+		return models.AlertInstance{}, err
+	}
+	return result, nil
+}
+
+// sortInstances sorts the instances by rule and labels so that archives of the same state are identical.
+func sortInstances(instances []AlertInstance) {
+	labelsKey := func(i AlertInstance) string {
+		labels := models.InstanceLabels(i.Labels)
+		key, _ := labels.StringKey()
+		return key
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].RuleUID != instances[j].RuleUID {
+			return instances[i].RuleUID < instances[j].RuleUID
+		}
+		return labelsKey(instances[i]) < labelsKey(instances[j])
+	})
+}
+
+// Write writes the archive to w as gzip compressed JSON.
+func Write(w io.Writer, archive *Archive) error {
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(archive); err != nil {
+		return fmt.Errorf("failed to encode archive: %w", err)
+	}
+	return gz.Close()
+}
+
+// ErrArchiveTooLarge is returned by Read when the decompressed archive is larger than the limit.
+var ErrArchiveTooLarge = errors.New("the decompressed archive is too large")
+
+// Read reads an archive written by Write. If maxSize is greater than 0, it fails with ErrArchiveTooLarge when the
+// decompressed archive is larger than maxSize bytes, so that a small compressed archive cannot exhaust the memory.
+func Read(r io.Reader, maxSize int64) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var decompressed io.Reader = gz
+	if maxSize > 0 {
+		decompressed = &limitedReader{r: gz, remaining: maxSize}
+	}
+	var archive Archive
+	if err := json.NewDecoder(decompressed).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+	if archive.Version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d, expected %d", archive.Version, ArchiveVersion)
+	}
+	return &archive, nil
+}
+
+// limitedReader is like io.LimitedReader, but it fails with ErrArchiveTooLarge instead of returning io.EOF when the
+// limit is reached.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, ErrArchiveTooLarge
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,240 @@
+// Package snapshot exports the alerting state of a Grafana instance to a portable archive and imports it into
+// another instance. The state consists of the alert instances of all organizations, including the timestamps that
+// are needed to not re-fire them, and the silences and notification log of the Alertmanagers.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// InstanceStore is the store the alert instances are exported from and imported into.
+type InstanceStore interface {
+	state.InstanceReader
+	SaveAlertInstance(ctx context.Context, instance models.AlertInstance) error
+	DeleteAlertInstances(ctx context.Context, keys ...models.AlertInstanceKey) error
+}
+
+// AlertmanagerStateStore reads and restores the state of the Alertmanagers.
+type AlertmanagerStateStore interface {
+	GetAlertmanagerStates(ctx context.Context) (map[int64]notifier.AlertmanagerState, error)
+	RestoreAlertmanagerState(ctx context.Context, orgID int64, st notifier.AlertmanagerState) error
+}
+
+// TransactionManager runs the changes of the alert instances of an organization in a single transaction.
+type TransactionManager interface {
+	InTransaction(ctx context.Context, work func(ctx context.Context) error) error
+}
+
+// StateCache is the in-memory state of a running instance. WarmOrg reloads the state of an organization from the
+// instance store into the cache of this instance only, and keeps the cached state if it cannot be read.
+type StateCache interface {
+	GetAlertInstances() []models.AlertInstance
+	WarmOrg(ctx context.Context, orgID int64, rulesReader state.RuleReader, instanceReader state.InstanceReader) error
+}
+
+// ErrImportWithHA is returned by Import when high availability is configured. Only the state cache of the instance
+// that imports the state is refreshed, while the other instances keep evaluating rules with their previous state and
+// overwrite the imported alert instances.
+var ErrImportWithHA = errors.New("the alerting state cannot be imported while high availability is configured, stop the other instances and import the state with the Grafana CLI instead")
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	Orgs                  int
+	AlertInstances        int
+	SkippedAlertInstances int
+}
+
+type Service struct {
+	instances     InstanceStore
+	rules         state.RuleReader
+	alertmanagers AlertmanagerStateStore
+	xact          TransactionManager
+	cache         StateCache
+	haEnabled     bool
+	clock         clock.Clock
+	logger        log.Logger
+}
+
+// NewService creates a snapshot service. The cache is optional: without it, alert instances are exported from the
+// instance store only, which can miss the latest changes of a running instance, and imported instances are loaded
+// when the instance starts. The cache is the state of the instance that runs the service only, therefore state
+// cannot be imported if haEnabled is set.
+func NewService(instances InstanceStore, rules state.RuleReader, alertmanagers AlertmanagerStateStore, xact TransactionManager, cache StateCache, haEnabled bool, logger log.Logger) *Service {
+	return &Service{
+		instances:     instances,
+		rules:         rules,
+		alertmanagers: alertmanagers,
+		xact:          xact,
+		cache:         cache,
+		haEnabled:     haEnabled,
+		clock:         clock.New(),
+		logger:        logger,
+	}
+}
+
+// Export returns the alerting state of all organizations.
+func (s *Service) Export(ctx context.Context) (*Archive, error) {
+	instances, err := s.listAlertInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amStates, err := s.alertmanagers.GetAlertmanagerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make(map[int64]*OrgState)
+	getOrg := func(orgID int64) *OrgState {
+		if org, ok := orgs[orgID]; ok {
+			return org
+		}
+		org := &OrgState{OrgID: orgID}
+		orgs[orgID] = org
+		return org
+	}
+	for _, i := range instances {
+		org := getOrg(i.RuleOrgID)
+		org.AlertInstances = append(org.AlertInstances, fromAlertInstance(i))
+	}
+	for orgID, st := range amStates {
+		org := getOrg(orgID)
+		org.Silences = st.Silences
+		org.NotificationLog = st.NotificationLog
+	}
+
+	archive := &Archive{
+		Version:   ArchiveVersion,
+		CreatedAt: s.clock.Now().UTC(),
+		Orgs:      make([]OrgState, 0, len(orgs)),
+	}
+	for _, org := range orgs {
+		sortInstances(org.AlertInstances)
+		archive.Orgs = append(archive.Orgs, *org)
+	}
+	sort.Slice(archive.Orgs, func(i, j int) bool {
+		return archive.Orgs[i].OrgID < archive.Orgs[j].OrgID
+	})
+	return archive, nil
+}
+
+func (s *Service) listAlertInstances(ctx context.Context) ([]models.AlertInstance, error) {
+	if s.cache != nil {
+		return s.cache.GetAlertInstances(), nil
+	}
+	orgIDs, err := s.instances.FetchOrgIds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organizations: %w", err)
+	}
+	var result []models.AlertInstance
+	for _, orgID := range orgIDs {
+		instances, err := s.instances.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: orgID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list alert instances of organization %d: %w", orgID, err)
+		}
+		for _, i := range instances {
+			result = append(result, *i)
+		}
+	}
+	return result, nil
+}
+
+// Import replaces the alerting state of the organizations in the archive. Alert instances of rules that do not
+// exist in the organization are skipped. The alert instances of each organization are replaced in a single
+// transaction.
+func (s *Service) Import(ctx context.Context, archive *Archive) (ImportResult, error) {
+	var result ImportResult
+	if s.haEnabled {
+		return result, ErrImportWithHA
+	}
+	for _, org := range archive.Orgs {
+		imported, skipped, err := s.importOrg(ctx, org)
+		if err != nil {
+			return result, fmt.Errorf("failed to import the state of organization %d: %w", org.OrgID, err)
+		}
+		result.Orgs++
+		result.AlertInstances += imported
+		result.SkippedAlertInstances += skipped
+	}
+	return result, nil
+}
+
+func (s *Service) importOrg(ctx context.Context, org OrgState) (int, int, error) {
+	logger := s.logger.FromContext(ctx).New("org", org.OrgID)
+
+	rules, err := s.rules.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: org.OrgID})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	ruleUIDs := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		ruleUIDs[r.UID] = struct{}{}
+	}
+
+	instances := make([]models.AlertInstance, 0, len(org.AlertInstances))
+	skipped := 0
+	for _, i := range org.AlertInstances {
+		if _, ok := ruleUIDs[i.RuleUID]; !ok {
+			skipped++
+			continue
+		}
+		instance, err := i.toAlertInstance(org.OrgID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid alert instance of rule %s: %w", i.RuleUID, err)
+		}
+		instances = append(instances, instance)
+	}
+	if skipped > 0 {
+		logger.Warn("Skipping alert instances of rules that do not exist", "count", skipped)
+	}
+
+	err = s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.instances.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: org.OrgID})
+		if err != nil {
+			return fmt.Errorf("failed to list alert instances: %w", err)
+		}
+		keys := make([]models.AlertInstanceKey, 0, len(existing))
+		for _, i := range existing {
+			keys = append(keys, i.AlertInstanceKey)
+		}
+		if err := s.instances.DeleteAlertInstances(ctx, keys...); err != nil {
+			return fmt.Errorf("failed to delete alert instances: %w", err)
+		}
+		for _, i := range instances {
+			if err := s.instances.SaveAlertInstance(ctx, i); err != nil {
+				return fmt.Errorf("failed to save alert instance: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(org.Silences) > 0 || len(org.NotificationLog) > 0 {
+		err := s.alertmanagers.RestoreAlertmanagerState(ctx, org.OrgID, notifier.AlertmanagerState{
+			Silences:        org.Silences,
+			NotificationLog: org.NotificationLog,
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to restore Alertmanager state: %w", err)
+		}
+	}
+
+	if s.cache != nil {
+		if err := s.cache.WarmOrg(ctx, org.OrgID, s.rules, s.instances); err != nil {
+			return 0, 0, fmt.Errorf("failed to reload the imported state: %w", err)
+		}
+	}
+	logger.Info("Imported alerting state", "instances", len(instances))
+	return len(instances), skipped, nil
+}
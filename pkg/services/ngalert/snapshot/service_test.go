@@ -0,0 +1,267 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+type fakeInstanceStore struct {
+	instances map[models.AlertInstanceKey]models.AlertInstance
+}
+
+func newFakeInstanceStore(instances ...models.AlertInstance) *fakeInstanceStore {
+	s := &fakeInstanceStore{instances: map[models.AlertInstanceKey]models.AlertInstance{}}
+	for _, i := range instances {
+		s.instances[i.AlertInstanceKey] = i
+	}
+	return s
+}
+
+func (f *fakeInstanceStore) FetchOrgIds(_ context.Context) ([]int64, error) {
+	orgs := map[int64]struct{}{}
+	var result []int64
+	for k := range f.instances {
+		if _, ok := orgs[k.RuleOrgID]; !ok {
+			orgs[k.RuleOrgID] = struct{}{}
+			result = append(result, k.RuleOrgID)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeInstanceStore) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var result []*models.AlertInstance
+	for _, i := range f.instances {
+		if i.RuleOrgID == q.RuleOrgID {
+			result = append(result, &i)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeInstanceStore) SaveAlertInstance(_ context.Context, instance models.AlertInstance) error {
+	f.instances[instance.AlertInstanceKey] = instance
+	return nil
+}
+
+func (f *fakeInstanceStore) DeleteAlertInstances(_ context.Context, keys ...models.AlertInstanceKey) error {
+	for _, k := range keys {
+		delete(f.instances, k)
+	}
+	return nil
+}
+
+type fakeAlertmanagerStateStore struct {
+	states map[int64]notifier.AlertmanagerState
+}
+
+func (f *fakeAlertmanagerStateStore) GetAlertmanagerStates(_ context.Context) (map[int64]notifier.AlertmanagerState, error) {
+	return f.states, nil
+}
+
+func (f *fakeAlertmanagerStateStore) RestoreAlertmanagerState(_ context.Context, orgID int64, st notifier.AlertmanagerState) error {
+	f.states[orgID] = st
+	return nil
+}
+
+type fakeTransactionManager struct {
+	transactions int
+}
+
+func (f *fakeTransactionManager) InTransaction(ctx context.Context, work func(ctx context.Context) error) error {
+	f.transactions++
+	return work(ctx)
+}
+
+type fakeStateCache struct {
+	instances []models.AlertInstance
+	warmed    []int64
+	warmErr   error
+}
+
+func (f *fakeStateCache) GetAlertInstances() []models.AlertInstance {
+	return f.instances
+}
+
+func (f *fakeStateCache) WarmOrg(_ context.Context, orgID int64, _ state.RuleReader, _ state.InstanceReader) error {
+	if f.warmErr != nil {
+		return f.warmErr
+	}
+	f.warmed = append(f.warmed, orgID)
+	return nil
+}
+
+func newInstance(t *testing.T, orgID int64, ruleUID string, labels models.InstanceLabels, st models.InstanceStateType) models.AlertInstance {
+	t.Helper()
+	_, hash, err := labels.StringAndHash()
+	require.NoError(t, err)
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sentAt := since.Add(time.Minute)
+	return models.AlertInstance{
+		AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: orgID, RuleUID: ruleUID, LabelsHash: hash},
+		Labels:            labels,
+		CurrentState:      st,
+		CurrentStateSince: since,
+		CurrentStateEnd:   since.Add(4 * time.Minute),
+		LastEvalTime:      since.Add(time.Minute),
+		LastSentAt:        &sentAt,
+		ResultFingerprint: "a1b2c3",
+	}
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	firing := newInstance(t, 1, "rule-1", models.InstanceLabels{"team": "b"}, models.InstanceStateFiring)
+	pending := newInstance(t, 1, "rule-1", models.InstanceLabels{"team": "a"}, models.InstanceStatePending)
+	orphan := newInstance(t, 1, "deleted-rule", models.InstanceLabels{"team": "a"}, models.InstanceStateFiring)
+	otherOrg := newInstance(t, 2, "rule-2", models.InstanceLabels{"team": "c"}, models.InstanceStateNoData)
+	amStates := map[int64]notifier.AlertmanagerState{
+		1: {Silences: []byte("silences"), NotificationLog: []byte("nflog")},
+		3: {Silences: []byte("silences of org 3")},
+	}
+
+	export := func(t *testing.T, cache StateCache) *Archive {
+		t.Helper()
+		s := NewService(newFakeInstanceStore(firing, pending, orphan, otherOrg), fakes.NewRuleStore(t), &fakeAlertmanagerStateStore{states: amStates}, &fakeTransactionManager{}, cache, false, log.NewNopLogger())
+		mock := clock.NewMock()
+		mock.Set(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+		s.clock = mock
+		archive, err := s.Export(ctx)
+		require.NoError(t, err)
+		return archive
+	}
+
+	t.Run("exports the state of all organizations", func(t *testing.T) {
+		archive := export(t, nil)
+		require.Equal(t, ArchiveVersion, archive.Version)
+		require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), archive.CreatedAt)
+		require.Len(t, archive.Orgs, 3)
+
+		org1 := archive.Orgs[0]
+		require.EqualValues(t, 1, org1.OrgID)
+		require.Equal(t, []AlertInstance{fromAlertInstance(orphan), fromAlertInstance(pending), fromAlertInstance(firing)}, org1.AlertInstances)
+		require.Equal(t, []byte("silences"), org1.Silences)
+		require.Equal(t, []byte("nflog"), org1.NotificationLog)
+
+		require.EqualValues(t, 2, archive.Orgs[1].OrgID)
+		require.Len(t, archive.Orgs[1].AlertInstances, 1)
+		require.Nil(t, archive.Orgs[1].Silences)
+
+		require.EqualValues(t, 3, archive.Orgs[2].OrgID)
+		require.Empty(t, archive.Orgs[2].AlertInstances)
+	})
+
+	t.Run("exports the alert instances of the cache when there is one", func(t *testing.T) {
+		archive := export(t, &fakeStateCache{instances: []models.AlertInstance{otherOrg}})
+		require.Len(t, archive.Orgs, 3)
+		require.Empty(t, archive.Orgs[0].AlertInstances)
+		require.Equal(t, []AlertInstance{fromAlertInstance(otherOrg)}, archive.Orgs[1].AlertInstances)
+	})
+
+	t.Run("archives can be written and read", func(t *testing.T) {
+		archive := export(t, nil)
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, archive))
+		read, err := Read(&buf, 0)
+		require.NoError(t, err)
+		require.Equal(t, archive, read)
+	})
+
+	t.Run("rejects archives that are larger than the limit when decompressed", func(t *testing.T) {
+		archive := export(t, nil)
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, archive))
+		size := buf.Len()
+
+		_, err := Read(bytes.NewReader(buf.Bytes()), int64(size))
+		require.ErrorIs(t, err, ErrArchiveTooLarge)
+
+		var decompressed bytes.Buffer
+		require.NoError(t, json.NewEncoder(&decompressed).Encode(archive))
+		read, err := Read(bytes.NewReader(buf.Bytes()), int64(decompressed.Len()))
+		require.NoError(t, err)
+		require.Equal(t, archive, read)
+	})
+
+	t.Run("rejects archives of unsupported versions", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, &Archive{Version: 2}))
+		_, err := Read(&buf, 0)
+		require.ErrorContains(t, err, "unsupported archive version 2")
+	})
+
+	t.Run("imports the state and replaces the existing one", func(t *testing.T) {
+		archive := export(t, nil)
+
+		rules := fakes.NewRuleStore(t)
+		rules.PutRule(ctx,
+			models.RuleGen.With(models.RuleMuts.WithOrgID(1), func(r *models.AlertRule) { r.UID = "rule-1" }).GenerateRef(),
+			models.RuleGen.With(models.RuleMuts.WithOrgID(2), func(r *models.AlertRule) { r.UID = "rule-2" }).GenerateRef(),
+		)
+		stale := newInstance(t, 1, "rule-1", models.InstanceLabels{"team": "z"}, models.InstanceStateFiring)
+		untouched := newInstance(t, 4, "rule-4", models.InstanceLabels{"team": "z"}, models.InstanceStateFiring)
+		instances := newFakeInstanceStore(stale, untouched)
+		ams := &fakeAlertmanagerStateStore{states: map[int64]notifier.AlertmanagerState{}}
+		cache := &fakeStateCache{}
+		xact := &fakeTransactionManager{}
+		s := NewService(instances, rules, ams, xact, cache, false, log.NewNopLogger())
+
+		result, err := s.Import(ctx, archive)
+		require.NoError(t, err)
+		require.Equal(t, ImportResult{Orgs: 3, AlertInstances: 3, SkippedAlertInstances: 1}, result)
+
+		assert.Equal(t, map[models.AlertInstanceKey]models.AlertInstance{
+			firing.AlertInstanceKey:    firing,
+			pending.AlertInstanceKey:   pending,
+			otherOrg.AlertInstanceKey:  otherOrg,
+			untouched.AlertInstanceKey: untouched,
+		}, instances.instances)
+		require.Equal(t, amStates, ams.states)
+		require.Equal(t, []int64{1, 2, 3}, cache.warmed)
+		require.Equal(t, 3, xact.transactions, "the alert instances of each organization should be replaced in a transaction")
+	})
+
+	t.Run("refuses to import when high availability is configured", func(t *testing.T) {
+		instances := newFakeInstanceStore(firing)
+		s := NewService(instances, fakes.NewRuleStore(t), &fakeAlertmanagerStateStore{}, &fakeTransactionManager{}, &fakeStateCache{}, true, log.NewNopLogger())
+		_, err := s.Import(ctx, &Archive{Version: ArchiveVersion, Orgs: []OrgState{{OrgID: 1}}})
+		require.ErrorIs(t, err, ErrImportWithHA)
+		require.Len(t, instances.instances, 1)
+	})
+
+	t.Run("fails when the state cache cannot be reloaded", func(t *testing.T) {
+		rules := fakes.NewRuleStore(t)
+		rules.PutRule(ctx, models.RuleGen.With(models.RuleMuts.WithOrgID(1), func(r *models.AlertRule) { r.UID = "rule-1" }).GenerateRef())
+		cache := &fakeStateCache{warmErr: errors.New("database is locked")}
+		s := NewService(newFakeInstanceStore(), rules, &fakeAlertmanagerStateStore{}, &fakeTransactionManager{}, cache, false, log.NewNopLogger())
+
+		_, err := s.Import(ctx, &Archive{Version: ArchiveVersion, Orgs: []OrgState{{OrgID: 1}}})
+		require.ErrorContains(t, err, "failed to reload the imported state: database is locked")
+	})
+
+	t.Run("fails on invalid alert instances", func(t *testing.T) {
+		rules := fakes.NewRuleStore(t)
+		rules.PutRule(ctx, models.RuleGen.With(models.RuleMuts.WithOrgID(1), func(r *models.AlertRule) { r.UID = "rule-1" }).GenerateRef())
+		s := NewService(newFakeInstanceStore(), rules, &fakeAlertmanagerStateStore{}, &fakeTransactionManager{}, nil, false, log.NewNopLogger())
+
+		_, err := s.Import(ctx, &Archive{Version: ArchiveVersion, Orgs: []OrgState{{
+			OrgID:          1,
+			AlertInstances: []AlertInstance{{RuleUID: "rule-1", State: "Unknown"}},
+		}}})
+		require.ErrorContains(t, err, "invalid alert instance of rule rule-1")
+	})
+}
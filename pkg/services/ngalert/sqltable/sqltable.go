@@ -0,0 +1,87 @@
+// Package sqltable has helpers for the append-only tables of the Grafana database that alerting writes timestamped
+// rows to, such as the samples of recording rules and the state history.
+package sqltable
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	// InsertBatchSize limits the number of rows inserted by a single statement.
+	InsertBatchSize = 100
+	// CleanupInterval is how often a Cleaner deletes the rows that are older than the retention.
+	CleanupInterval = 10 * time.Minute
+)
+
+// Insert inserts the rows in a single transaction, with at most InsertBatchSize rows per statement.
+func Insert[T any](ctx context.Context, store db.DB, rows []T) error {
+	return store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for start := 0; start < len(rows); start += InsertBatchSize {
+			batch := rows[start:min(start+InsertBatchSize, len(rows))]
+			if _, err := sess.Insert(&batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Cleaner deletes the rows of a table that are older than a retention. The time of a row is a column that holds
+// milliseconds since epoch.
+type Cleaner struct {
+	db         db.DB
+	table      string
+	timeColumn string
+	retention  time.Duration
+	log        log.Logger
+
+	mtx         sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewCleaner creates a new Cleaner. It does not delete anything if retention is 0.
+func NewCleaner(store db.DB, table, timeColumn string, retention time.Duration, logger log.Logger) *Cleaner {
+	return &Cleaner{
+		db:         store,
+		table:      table,
+		timeColumn: timeColumn,
+		retention:  retention,
+		log:        logger,
+	}
+}
+
+// Cleanup deletes the rows that are older than the retention at now, if the last cleanup was longer than
+// CleanupInterval ago. Errors are logged, as the rows are deleted by the next cleanup.
+func (c *Cleaner) Cleanup(ctx context.Context, now time.Time) {
+	if c.retention <= 0 {
+		return
+	}
+	c.mtx.Lock()
+	if now.Sub(c.lastCleanup) < CleanupInterval {
+		c.mtx.Unlock()
+		return
+	}
+	c.lastCleanup = now
+	c.mtx.Unlock()
+
+	var deleted int64
+	err := c.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s < ?", c.table, c.timeColumn), now.Add(-c.retention).UnixMilli())
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		c.log.FromContext(ctx).Error("Failed to delete expired rows", "table", c.table, "error", err)
+		return
+	}
+	c.log.FromContext(ctx).Debug("Deleted expired rows", "table", c.table, "count", deleted)
+}
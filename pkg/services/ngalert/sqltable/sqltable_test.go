@@ -0,0 +1,75 @@
+package sqltable
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+type testRow struct {
+	ID         int64   `xorm:"pk autoincr 'id'"`
+	OrgID      int64   `xorm:"org_id"`
+	Metric     string  `xorm:"metric"`
+	Labels     string  `xorm:"labels"`
+	RecordedAt int64   `xorm:"recorded_at"`
+	Value      float64 `xorm:"value"`
+}
+
+func (testRow) TableName() string {
+	return "alert_recorded_sample"
+}
+
+func TestIntegrationInsertAndCleanup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := db.InitTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	count := func() int64 {
+		t.Helper()
+		var n int64
+		require.NoError(t, store.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			n, err = sess.Count(&testRow{})
+			return err
+		}))
+		return n
+	}
+
+	rows := make([]testRow, 0, 2*InsertBatchSize+1)
+	for i := 0; i < cap(rows); i++ {
+		rows = append(rows, testRow{OrgID: 1, Metric: "metric", Labels: "{}", RecordedAt: now.Add(-time.Duration(i) * time.Minute).UnixMilli()})
+	}
+	require.NoError(t, Insert(ctx, store, rows))
+	require.Equal(t, int64(len(rows)), count())
+
+	t.Run("does not delete anything without retention", func(t *testing.T) {
+		NewCleaner(store, "alert_recorded_sample", "recorded_at", 0, log.NewNopLogger()).Cleanup(ctx, now)
+		require.Equal(t, int64(len(rows)), count())
+	})
+
+	t.Run("deletes rows older than the retention once per interval", func(t *testing.T) {
+		cleaner := NewCleaner(store, "alert_recorded_sample", "recorded_at", time.Hour, log.NewNopLogger())
+		cleaner.Cleanup(ctx, now)
+		// The rows of the last hour are kept, including the one exactly at the retention.
+		require.Equal(t, int64(61), count())
+
+		cleaner.Cleanup(ctx, now.Add(CleanupInterval-time.Second))
+		require.Equal(t, int64(61), count())
+
+		cleaner.Cleanup(ctx, now.Add(CleanupInterval))
+		require.Equal(t, int64(51), count())
+	})
+}
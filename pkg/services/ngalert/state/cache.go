@@ -271,6 +271,20 @@ func (c *cache) setRuleStates(ruleKey ngModels.AlertRuleKey, s ruleStates) {
 	c.states[ruleKey.OrgID][ruleKey.UID] = &s
 }
 
+// setOrgStates replaces all states of the organization with the given ones.
+func (c *cache) setOrgStates(orgID int64, states []*State) {
+	orgStates := make(map[string]*ruleStates)
+	for _, s := range states {
+		if _, ok := orgStates[s.AlertRuleUID]; !ok {
+			orgStates[s.AlertRuleUID] = &ruleStates{states: make(map[data.Fingerprint]*State)}
+		}
+		orgStates[s.AlertRuleUID].states[s.CacheID] = s
+	}
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	c.states[orgID] = orgStates
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -425,6 +425,22 @@ func Test_create(t *testing.T) {
 	})
 }
 
+func Test_setOrgStates(t *testing.T) {
+	c := newCache()
+	kept := randomSate(models.AlertRuleKey{OrgID: 2, UID: "rule"})
+	c.set(&kept)
+	replaced := randomSate(models.AlertRuleKey{OrgID: 1, UID: "rule-1"})
+	c.set(&replaced)
+
+	s1 := randomSate(models.AlertRuleKey{OrgID: 1, UID: "rule-1"})
+	s2 := randomSate(models.AlertRuleKey{OrgID: 1, UID: "rule-2"})
+	c.setOrgStates(1, []*State{&s1, &s2})
+
+	assert.ElementsMatch(t, []*State{&s1, &s2}, c.getAll(1, false))
+	assert.Nil(t, c.get(1, "rule-1", replaced.CacheID))
+	assert.Equal(t, []*State{&kept}, c.getAll(2, false))
+}
+
 func Test_mergeLabels(t *testing.T) {
 	t.Run("merges two maps", func(t *testing.T) {
 		a := models.GenerateAlertLabels(5, "set1-")
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// RuleDependencyStatus is the result of checking a dependency of a rule against the current state of its parent rule.
+type RuleDependencyStatus struct {
+	ngModels.RuleDependency
+	// ParentState is the state of the parent rule as a whole, or nil if the parent rule has no state,
+	// for example because it has not been evaluated yet or does not exist anymore.
+	ParentState *eval.State
+	// Met is true if the parent rule is in the state required by the dependency.
+	Met bool
+	// ParentMissing is true if the parent rule does not exist, for example because it was deleted.
+	ParentMissing bool
+}
+
+// RuleState returns the state of a rule as a whole from the states of its alert instances. It is the state
+// of the instance with the highest precedence of Alerting, Pending, Error, NoData and Normal, so a rule is
+// Normal only if all of its instances are. It returns false if the rule has no alert instances.
+func RuleState(states []*State) (eval.State, bool) {
+	if len(states) == 0 {
+		return eval.Normal, false
+	}
+	precedence := map[eval.State]int{
+		eval.Normal:   0,
+		eval.NoData:   1,
+		eval.Error:    2,
+		eval.Pending:  3,
+		eval.Alerting: 4,
+	}
+	result := eval.Normal
+	for _, s := range states {
+		if precedence[s.State] > precedence[result] {
+			result = s.State
+		}
+	}
+	return result, true
+}
+
+// CheckRuleDependencies checks the dependencies of the rule against the current state of their parent rules.
+// If ruleExists is not nil, it tells the parent rules that do not exist from the ones that have no state yet.
+func CheckRuleDependencies(manager AlertInstanceManager, rule *ngModels.AlertRule, ruleExists func(ngModels.AlertRuleKey) bool) []RuleDependencyStatus {
+	if len(rule.Dependencies) == 0 {
+		return nil
+	}
+	result := make([]RuleDependencyStatus, 0, len(rule.Dependencies))
+	for _, dependency := range rule.Dependencies {
+		status := RuleDependencyStatus{RuleDependency: dependency}
+		if parentState, ok := RuleState(manager.GetStatesForRuleUID(rule.OrgID, dependency.RuleUID)); ok {
+			status.ParentState = &parentState
+			status.Met = parentState.String() == string(dependency.State)
+		} else if ruleExists != nil && !ruleExists(ngModels.AlertRuleKey{OrgID: rule.OrgID, UID: dependency.RuleUID}) {
+			status.ParentMissing = true
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// FirstUnmetRuleDependency returns the first dependency in the list that is not met, or false if all of them are met.
+func FirstUnmetRuleDependency(statuses []RuleDependencyStatus) (RuleDependencyStatus, bool) {
+	for _, status := range statuses {
+		if !status.Met {
+			return status, true
+		}
+	}
+	return RuleDependencyStatus{}, false
+}
+
+// MissingRuleDependencyParentError returns an error if the parent rule of one of the dependencies does not exist.
+func MissingRuleDependencyParentError(statuses []RuleDependencyStatus) error {
+	for _, status := range statuses {
+		if status.ParentMissing {
+			return fmt.Errorf("%w: parent rule %s does not exist", eval.ErrRuleDependencyParentMissing, status.RuleUID)
+		}
+	}
+	return nil
+}
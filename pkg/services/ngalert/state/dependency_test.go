@@ -0,0 +1,104 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeAlertInstanceManager map[string][]*State
+
+func (f fakeAlertInstanceManager) GetAll(_ int64) []*State {
+	return nil
+}
+
+func (f fakeAlertInstanceManager) GetStatesForRuleUID(_ int64, alertRuleUID string) []*State {
+	return f[alertRuleUID]
+}
+
+func TestRuleState(t *testing.T) {
+	statesOf := func(states ...eval.State) []*State {
+		result := make([]*State, 0, len(states))
+		for _, s := range states {
+			result = append(result, &State{State: s})
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name     string
+		states   []*State
+		expected eval.State
+		ok       bool
+	}{
+		{name: "no states", states: nil, ok: false},
+		{name: "all normal", states: statesOf(eval.Normal, eval.Normal), expected: eval.Normal, ok: true},
+		{name: "one alerting", states: statesOf(eval.Normal, eval.Alerting, eval.Pending), expected: eval.Alerting, ok: true},
+		{name: "one pending", states: statesOf(eval.Normal, eval.Pending, eval.Error), expected: eval.Pending, ok: true},
+		{name: "one error", states: statesOf(eval.NoData, eval.Error), expected: eval.Error, ok: true},
+		{name: "one no data", states: statesOf(eval.Normal, eval.NoData), expected: eval.NoData, ok: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, ok := RuleState(tc.states)
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				require.Equal(t, tc.expected, s)
+			}
+		})
+	}
+}
+
+func TestCheckRuleDependencies(t *testing.T) {
+	manager := fakeAlertInstanceManager{
+		"normal":   {{State: eval.Normal}},
+		"alerting": {{State: eval.Normal}, {State: eval.Alerting}},
+	}
+	rule := &ngmodels.AlertRule{
+		OrgID: 1,
+		UID:   "child",
+		Dependencies: []ngmodels.RuleDependency{
+			{RuleUID: "normal", State: ngmodels.RuleDependencyStateNormal},
+			{RuleUID: "alerting", State: ngmodels.RuleDependencyStateNormal},
+			{RuleUID: "missing", State: ngmodels.RuleDependencyStateAlerting},
+			{RuleUID: "not-evaluated", State: ngmodels.RuleDependencyStateNormal},
+		},
+	}
+	ruleExists := func(key ngmodels.AlertRuleKey) bool {
+		return key.OrgID == 1 && key.UID != "missing"
+	}
+
+	statuses := CheckRuleDependencies(manager, rule, ruleExists)
+	require.Len(t, statuses, 4)
+
+	require.True(t, statuses[0].Met)
+	require.Equal(t, eval.Normal, *statuses[0].ParentState)
+
+	require.False(t, statuses[1].Met)
+	require.Equal(t, eval.Alerting, *statuses[1].ParentState)
+
+	require.False(t, statuses[2].Met)
+	require.Nil(t, statuses[2].ParentState)
+	require.True(t, statuses[2].ParentMissing)
+
+	require.False(t, statuses[3].Met)
+	require.Nil(t, statuses[3].ParentState)
+	require.False(t, statuses[3].ParentMissing)
+
+	require.ErrorIs(t, MissingRuleDependencyParentError(statuses), eval.ErrRuleDependencyParentMissing)
+	require.NoError(t, MissingRuleDependencyParentError(statuses[:2]))
+	require.False(t, CheckRuleDependencies(manager, rule, nil)[2].ParentMissing)
+
+	unmet, ok := FirstUnmetRuleDependency(statuses)
+	require.True(t, ok)
+	require.Equal(t, "alerting", unmet.RuleUID)
+
+	_, ok = FirstUnmetRuleDependency(statuses[:1])
+	require.False(t, ok)
+
+	require.Nil(t, CheckRuleDependencies(manager, &ngmodels.AlertRule{OrgID: 1, UID: "no-dependencies"}, ruleExists))
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -271,6 +272,17 @@ func TestBuildLogQuery(t *testing.T) {
 			},
 			exp: []string{`{orgID="123",from="state-history"} | json | ruleUID="rule-uid" | labels_customlabel="customvalue"`},
 		},
+		{
+			name: "filters instance labels by matchers in log line",
+			query: models.HistoryQuery{
+				OrgID: 123,
+				Matchers: labels.Matchers{
+					mustMatcher(t, labels.MatchRegexp, "severity", "crit.*"),
+					mustMatcher(t, labels.MatchNotEqual, "team", "a"),
+				},
+			},
+			exp: []string{`{orgID="123",from="state-history"} | json | labels_severity=~"crit.*" | labels_team!="a"`},
+		},
 		{
 			name: "should return if query does not exceed max limit",
 			query: models.HistoryQuery{
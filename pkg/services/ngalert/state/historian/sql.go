@@ -0,0 +1,332 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/sqltable"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+)
+
+// sqlQueryBatchSize is the number of rows read at once when the history is filtered by labels, which is done after reading.
+const sqlQueryBatchSize = 1000
+
+// stateHistoryEntry is a row of the alert_state_history table.
+type stateHistoryEntry struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"org_id"`
+	RuleUID      string `xorm:"rule_uid"`
+	RuleID       int64  `xorm:"rule_id"`
+	RuleTitle    string `xorm:"rule_title"`
+	RuleGroup    string `xorm:"rule_group"`
+	FolderUID    string `xorm:"folder_uid"`
+	Condition    string `xorm:"rule_condition"`
+	DashboardUID string `xorm:"dashboard_uid"`
+	PanelID      int64  `xorm:"panel_id"`
+	Fingerprint  string `xorm:"fingerprint"`
+	Labels       string `xorm:"labels"`
+	Previous     string `xorm:"previous_state"`
+	Current      string `xorm:"current_state"`
+	Values       string `xorm:"state_values"`
+	Error        string `xorm:"state_error"`
+	// Created is the time of the evaluation that caused the transition, in milliseconds since epoch.
+	Created int64 `xorm:"'created'"`
+}
+
+func (stateHistoryEntry) TableName() string {
+	return "alert_state_history"
+}
+
+// SQLBackend is a state.Historian that records state history to the alert_state_history table of the Grafana database.
+// Unlike annotations, it keeps labels, values and errors of every transition, and it can be queried by labels.
+// Query returns the history in the same format as the Loki backend.
+type SQLBackend struct {
+	db        db.DB
+	retention time.Duration
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	ac        AccessControl
+	ruleStore RuleStore
+	cleaner   *sqltable.Cleaner
+	// queryBatchSize is the number of rows read at once when the history is filtered by labels.
+	queryBatchSize int
+}
+
+// NewSQLBackend creates a new SQLBackend. Entries older than retention are deleted periodically, unless retention is 0.
+func NewSQLBackend(logger log.Logger, db db.DB, retention time.Duration, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *SQLBackend {
+	return &SQLBackend{
+		db:        db,
+		retention: retention,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+		ac:        ac,
+		ruleStore: ruleStore,
+		cleaner:   sqltable.NewCleaner(db, stateHistoryEntry{}.TableName(), "created", retention, logger),
+
+		queryBatchSize: sqlQueryBatchSize,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	// Build entries before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	entries := statesToEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	// This also prevents timeouts or other lingering objects (like transactions) from being
+	// incorrectly propagated here from other areas.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(entries))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, BackendTypeSQL.String()).Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		if err := sqltable.Insert(ctx, h.db, entries); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, BackendTypeSQL.String()).Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(entries))
+		h.cleaner.Cleanup(ctx, h.clock.Now())
+	}(writeCtx)
+	return errCh
+}
+
+// Query retrieves state history entries from the database and formats the results into a dataframe.
+// If the query has more entries than the limit, the latest ones are returned.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := getFolderUIDsForFilter(ctx, query, h.ac, h.ruleStore)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maximumPageSize {
+		limit = maximumPageSize
+	}
+	filterByLabels := len(query.Labels) > 0 || len(query.Matchers) > 0
+
+	entries := make([]stateHistoryEntry, 0)
+	err = h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		// When filtering by labels, the entries are read in batches from the latest. Each batch starts after the
+		// last entry of the previous one, so that reading a batch does not get slower with the number of skipped entries.
+		var last *stateHistoryEntry
+		for {
+			q := sess.Where("org_id = ? AND created >= ? AND created <= ?", query.OrgID, query.From.UnixMilli(), query.To.UnixMilli())
+			if query.RuleUID != "" {
+				q = q.And("rule_uid = ?", query.RuleUID)
+			}
+			if query.DashboardUID != "" {
+				q = q.And("dashboard_uid = ?", query.DashboardUID)
+			}
+			if query.PanelID != 0 {
+				q = q.And("panel_id = ?", query.PanelID)
+			}
+			if len(uids) > 0 {
+				q = q.In("folder_uid", uids)
+			}
+			if last != nil {
+				q = q.And("(created < ? OR (created = ? AND id < ?))", last.Created, last.Created, last.ID)
+			}
+			q = q.Desc("created", "id")
+			if !filterByLabels {
+				return q.Limit(limit).Find(&entries)
+			}
+
+			var batch []stateHistoryEntry
+			if err := q.Limit(h.queryBatchSize).Find(&batch); err != nil {
+				return err
+			}
+			for _, e := range batch {
+				var lbls map[string]string
+				if err := json.Unmarshal([]byte(e.Labels), &lbls); err != nil {
+					return fmt.Errorf("failed to parse labels of state history entry %d: %w", e.ID, err)
+				}
+				if !matchesLabels(lbls, query) {
+					continue
+				}
+				entries = append(entries, e)
+				if len(entries) == limit {
+					return nil
+				}
+			}
+			if len(batch) < h.queryBatchSize {
+				return nil
+			}
+			last = &batch[len(batch)-1]
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries are read from the latest, but the history is returned in chronological order.
+	slices.Reverse(entries)
+	return entriesToFrame(entries)
+}
+
+func matchesLabels(lbls map[string]string, query models.HistoryQuery) bool {
+	for k, v := range query.Labels {
+		if lbls[k] != v {
+			return false
+		}
+	}
+	for _, m := range query.Matchers {
+		if !m.Matches(lbls[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// entriesToFrame converts entries to the same frame as the Loki backend returns. The line of each entry is a LokiEntry,
+// and the labels are the labels of the stream that the Loki backend would write the entry to.
+func entriesToFrame(entries []stateHistoryEntry) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		var instanceLabels map[string]string
+		if err := json.Unmarshal([]byte(e.Labels), &instanceLabels); err != nil {
+			return nil, fmt.Errorf("failed to parse labels of state history entry %d: %w", e.ID, err)
+		}
+		values := simplejson.New()
+		if e.Values != "" {
+			v, err := simplejson.NewJson([]byte(e.Values))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse values of state history entry %d: %w", e.ID, err)
+			}
+			values = v
+		}
+		line, err := json.Marshal(LokiEntry{
+			SchemaVersion:  1,
+			Previous:       e.Previous,
+			Current:        e.Current,
+			Error:          e.Error,
+			Values:         values,
+			Condition:      e.Condition,
+			DashboardUID:   e.DashboardUID,
+			PanelID:        e.PanelID,
+			Fingerprint:    e.Fingerprint,
+			RuleTitle:      e.RuleTitle,
+			RuleID:         e.RuleID,
+			RuleUID:        e.RuleUID,
+			InstanceLabels: instanceLabels,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize state history entry %d: %w", e.ID, err)
+		}
+		streamLabels, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.FolderUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize labels of state history entry %d: %w", e.ID, err)
+		}
+
+		times = append(times, time.UnixMilli(e.Created))
+		lines = append(lines, line)
+		labels = append(labels, streamLabels)
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame, nil
+}
+
+func statesToEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []stateHistoryEntry {
+	entries := make([]stateHistoryEntry, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		sanitizedLabels := removePrivateLabels(state.Labels)
+		lbls, err := json.Marshal(sanitizedLabels)
+		if err != nil {
+			logger.Error("Failed to serialize labels of state, skipping", "error", err)
+			continue
+		}
+		var values []byte
+		if v := valuesAsDataBlob(state.State); v != nil {
+			values, err = v.MarshalJSON()
+			if err != nil {
+				logger.Error("Failed to serialize values of state, skipping", "error", err)
+				continue
+			}
+		}
+		entry := stateHistoryEntry{
+			OrgID:        rule.OrgID,
+			RuleUID:      rule.UID,
+			RuleID:       rule.ID,
+			RuleTitle:    rule.Title,
+			RuleGroup:    rule.Group,
+			FolderUID:    rule.NamespaceUID,
+			Condition:    rule.Condition,
+			DashboardUID: rule.DashboardUID,
+			PanelID:      rule.PanelID,
+			Fingerprint:  labelFingerprint(sanitizedLabels),
+			Labels:       string(lbls),
+			Previous:     state.PreviousFormatted(),
+			Current:      state.Formatted(),
+			Values:       string(values),
+			Created:      state.State.LastEvaluationTime.UnixMilli(),
+		}
+		if state.State.State == eval.Error && state.Error != nil {
+			entry.Error = state.Error.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,227 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	createBackend := func(ac AccessControl, rules RuleStore) *SQLBackend {
+		b := NewSQLBackend(log.NewNopLogger(), sqlStore, time.Hour, metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem), rules, ac)
+		clk := clock.NewMock()
+		clk.Set(start.Add(10 * time.Minute))
+		b.clock = clk
+		return b
+	}
+	readAll := &acfakes.FakeRuleService{
+		CanReadAllRulesFunc: func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		},
+	}
+	backend := createBackend(readAll, fakes.NewRuleStore(t))
+
+	rule := createTestRule()
+	otherRule := createTestRule()
+	otherRule.UID = "other-rule-uid"
+	otherRule.NamespaceUID = "other-folder"
+	otherRule.DashboardUID = ""
+	otherRule.PanelID = 0
+
+	transition := func(at time.Time, current eval.State, lbls data.Labels) state.StateTransition {
+		st := &state.State{
+			State:              current,
+			Labels:             lbls,
+			LastEvaluationTime: at,
+			Values:             map[string]float64{"A": 1},
+		}
+		if current == eval.Error {
+			st.Error = errors.New("query failed")
+		}
+		return state.StateTransition{State: st, PreviousState: eval.Normal}
+	}
+
+	require.NoError(t, <-backend.Record(context.Background(), rule, []state.StateTransition{
+		transition(start, eval.Alerting, data.Labels{"severity": "critical", "team": "a", "__private__": "x"}),
+		transition(start.Add(time.Minute), eval.Error, data.Labels{"severity": "warning", "team": "b"}),
+		// This transition does not change the state, so it is not recorded.
+		{State: &state.State{State: eval.Normal, LastEvaluationTime: start}, PreviousState: eval.Normal},
+	}))
+	require.NoError(t, <-backend.Record(context.Background(), otherRule, []state.StateTransition{
+		transition(start.Add(2*time.Minute), eval.Alerting, data.Labels{"severity": "critical", "team": "c"}),
+	}))
+
+	query := func(b *SQLBackend, q models.HistoryQuery) []LokiEntry {
+		t.Helper()
+		q.OrgID = rule.OrgID
+		q.From = start
+		q.To = start.Add(time.Hour)
+		frame, err := b.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+		result := make([]LokiEntry, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			result = append(result, entry)
+		}
+		return result
+	}
+	titles := func(entries []LokiEntry) []string {
+		result := make([]string, 0, len(entries))
+		for _, e := range entries {
+			result = append(result, e.RuleUID+"/"+e.InstanceLabels["team"])
+		}
+		return result
+	}
+
+	t.Run("returns all entries in chronological order", func(t *testing.T) {
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: start, To: start.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, start, frame.Fields[0].At(0).(time.Time).UTC())
+
+		var streamLabels map[string]string
+		require.NoError(t, json.Unmarshal(frame.Fields[2].At(0).(json.RawMessage), &streamLabels))
+		require.Equal(t, map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           "1",
+			GroupLabel:           rule.Group,
+			FolderUIDLabel:       rule.NamespaceUID,
+		}, streamLabels)
+
+		entries := query(backend, models.HistoryQuery{})
+		require.Equal(t, []string{"rule-uid/a", "rule-uid/b", "other-rule-uid/c"}, titles(entries))
+
+		first := entries[0]
+		require.Equal(t, "Normal", first.Previous)
+		require.Equal(t, "Alerting", first.Current)
+		require.Equal(t, rule.Title, first.RuleTitle)
+		require.Equal(t, rule.DashboardUID, first.DashboardUID)
+		require.Equal(t, rule.PanelID, first.PanelID)
+		require.Equal(t, map[string]string{"severity": "critical", "team": "a"}, first.InstanceLabels)
+		require.Equal(t, labelFingerprint(data.Labels{"severity": "critical", "team": "a"}), first.Fingerprint)
+		require.Equal(t, 1.0, first.Values.Get("A").MustFloat64())
+
+		require.Equal(t, "query failed", entries[1].Error)
+	})
+
+	t.Run("filters by rule, dashboard and panel", func(t *testing.T) {
+		require.Equal(t, []string{"other-rule-uid/c"}, titles(query(backend, models.HistoryQuery{RuleUID: otherRule.UID})))
+		require.Equal(t, []string{"rule-uid/a", "rule-uid/b"}, titles(query(backend, models.HistoryQuery{DashboardUID: rule.DashboardUID, PanelID: rule.PanelID})))
+	})
+
+	t.Run("filters by labels and label matchers", func(t *testing.T) {
+		require.Equal(t, []string{"rule-uid/a", "other-rule-uid/c"}, titles(query(backend, models.HistoryQuery{Labels: map[string]string{"severity": "critical"}})))
+		require.Equal(t, []string{"rule-uid/b", "other-rule-uid/c"}, titles(query(backend, models.HistoryQuery{
+			Matchers: labels.Matchers{mustMatcher(t, labels.MatchRegexp, "team", "b|c")},
+		})))
+		require.Equal(t, []string{"other-rule-uid/c"}, titles(query(backend, models.HistoryQuery{
+			Labels:   map[string]string{"severity": "critical"},
+			Matchers: labels.Matchers{mustMatcher(t, labels.MatchNotEqual, "team", "a")},
+		})))
+
+		batched := createBackend(readAll, fakes.NewRuleStore(t))
+		batched.queryBatchSize = 1
+		require.Equal(t, []string{"rule-uid/a", "other-rule-uid/c"}, titles(query(batched, models.HistoryQuery{Labels: map[string]string{"severity": "critical"}})))
+	})
+
+	t.Run("returns latest entries when limited", func(t *testing.T) {
+		require.Equal(t, []string{"rule-uid/b", "other-rule-uid/c"}, titles(query(backend, models.HistoryQuery{Limit: 2})))
+		require.Equal(t, []string{"other-rule-uid/c"}, titles(query(backend, models.HistoryQuery{
+			Limit:    1,
+			Matchers: labels.Matchers{mustMatcher(t, labels.MatchEqual, "severity", "critical")},
+		})))
+	})
+
+	t.Run("returns only entries of folders the user can read", func(t *testing.T) {
+		rules := fakes.NewRuleStore(t)
+		rules.Folders = map[int64][]*folder.Folder{
+			rule.OrgID: {{UID: rule.NamespaceUID, OrgID: rule.OrgID}, {UID: otherRule.NamespaceUID, OrgID: rule.OrgID}},
+		}
+		rules.Rules = map[int64][]*models.AlertRule{
+			rule.OrgID: {},
+		}
+		ac := &acfakes.FakeRuleService{
+			HasAccessInFolderFunc: func(ctx context.Context, user identity.Requester, namespaced models.Namespaced) (bool, error) {
+				return namespaced.GetNamespaceUID() == otherRule.NamespaceUID, nil
+			},
+		}
+		require.Equal(t, []string{"other-rule-uid/c"}, titles(query(createBackend(ac, rules), models.HistoryQuery{})))
+	})
+
+	t.Run("deletes entries older than retention", func(t *testing.T) {
+		clk := clock.NewMock()
+		clk.Set(start.Add(time.Hour + 90*time.Second))
+		backend.clock = clk
+		require.NoError(t, <-backend.Record(context.Background(), rule, []state.StateTransition{
+			transition(clk.Now(), eval.Alerting, data.Labels{"team": "d"}),
+		}))
+
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: start, To: clk.Now()})
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+	})
+
+	t.Run("reads entries with the same time in batches", func(t *testing.T) {
+		at := start.Add(2 * time.Hour)
+		clk := clock.NewMock()
+		clk.Set(at)
+		backend.clock = clk
+		backend.queryBatchSize = 2
+		require.NoError(t, <-backend.Record(context.Background(), rule, []state.StateTransition{
+			transition(at, eval.Alerting, data.Labels{"team": "e", "batch": "true"}),
+			transition(at, eval.Alerting, data.Labels{"team": "f", "batch": "true"}),
+			transition(at, eval.Alerting, data.Labels{"team": "g"}),
+			transition(at, eval.Alerting, data.Labels{"team": "h", "batch": "true"}),
+			transition(at, eval.Alerting, data.Labels{"team": "i"}),
+		}))
+
+		frame, err := backend.Query(context.Background(), models.HistoryQuery{OrgID: rule.OrgID, From: at, To: at, Labels: map[string]string{"batch": "true"}})
+		require.NoError(t, err)
+		teams := make([]string, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			teams = append(teams, entry.InstanceLabels["team"])
+		}
+		require.Equal(t, []string{"e", "f", "h"}, teams)
+	})
+}
+
+func mustMatcher(t *testing.T, mt labels.MatchType, name, value string) *labels.Matcher {
+	t.Helper()
+	m, err := labels.NewMatcher(mt, name, value)
+	require.NoError(t, err)
+	return m
+}
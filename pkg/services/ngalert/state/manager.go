@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -58,6 +59,9 @@ type Manager struct {
 	rulesPerRuleGroupLimit         int64
 
 	persister StatePersister
+
+	// remote are the alert rules evaluated by other instances of the HA cluster.
+	remote *remoteRules
 }
 
 type ManagerCfg struct {
@@ -81,6 +85,10 @@ type ManagerCfg struct {
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedRetention time.Duration
 
+	// RemoteStateTTL is how long the states of the alert rules evaluated by other instances of the HA cluster are
+	// read from the cache before they are read from the instance store again, see LoadRemoteStates.
+	RemoteStateTTL time.Duration
+
 	Tracer tracing.Tracer
 	Log    log.Logger
 }
@@ -109,6 +117,7 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		rulesPerRuleGroupLimit:         cfg.RulesPerRuleGroupLimit,
 		persister:                      statePersister,
 		tracer:                         cfg.Tracer,
+		remote:                         newRemoteRules(cfg.RemoteStateTTL),
 	}
 
 	if m.applyNoDataAndErrorToAllStates {
@@ -141,89 +150,145 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader, instanceRea
 
 	statesCount := 0
 	for _, orgId := range orgIds {
-		// Get Rules
-		ruleCmd := ngModels.ListAlertRulesQuery{
-			OrgID: orgId,
-		}
-		alertRules, err := rulesReader.ListAlertRules(ctx, &ruleCmd)
+		states, err := st.loadOrgStates(ctx, logger, orgId, rulesReader, instanceReader)
 		if err != nil {
-			logger.Error("Unable to fetch previous state", "error", err)
+			logger.Error("Unable to fetch previous state", "org", orgId, "error", err)
+			continue
 		}
-
-		ruleByUID := make(map[string]*ngModels.AlertRule, len(alertRules))
-		groupSizes := make(map[string]int64)
-		for _, rule := range alertRules {
-			ruleByUID[rule.UID] = rule
-			groupSizes[rule.RuleGroup] += 1
+		for _, state := range states {
+			st.cache.set(state)
+			statesCount++
 		}
+	}
 
-		// Emit a warning if we detect a large group.
-		// We will not enforce this here, but it's convenient to emit the warning here as we load up all the rules.
-		for name, size := range groupSizes {
-			if st.rulesPerRuleGroupLimit > 0 && size > st.rulesPerRuleGroupLimit {
-				logger.Warn(
-					"Large rule group was loaded. Large groups are discouraged and changes to them may be disallowed in the future.",
-					"limit", st.rulesPerRuleGroupLimit,
-					"actual", size,
-					"group", name,
-				)
-			}
-		}
+	logger.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
+}
+
+// WarmOrg replaces the cached states of the organization with the alert instances read from the instance reader.
+// It is used to load alert instances that were written to the instance store while the server is running. The cached
+// states are kept if the alert instances cannot be read.
+func (st *Manager) WarmOrg(ctx context.Context, orgID int64, rulesReader RuleReader, instanceReader InstanceReader) error {
+	logger := st.log.FromContext(ctx).New("org", orgID)
+	states, err := st.loadOrgStates(ctx, logger, orgID, rulesReader, instanceReader)
+	if err != nil {
+		return err
+	}
+	st.cache.setOrgStates(orgID, states)
+	logger.Info("State cache of organization has been reloaded", "states", len(states))
+	return nil
+}
 
-		// Get Instances
-		cmd := ngModels.ListAlertInstancesQuery{
-			RuleOrgID: orgId,
+// loadOrgStates reads the alert instances of the organization and converts them to states.
+// Instances of rules that do not exist are skipped.
+func (st *Manager) loadOrgStates(ctx context.Context, logger log.Logger, orgID int64, rulesReader RuleReader, instanceReader InstanceReader) ([]*State, error) {
+	// Get Rules
+	ruleCmd := ngModels.ListAlertRulesQuery{
+		OrgID: orgID,
+	}
+	alertRules, err := rulesReader.ListAlertRules(ctx, &ruleCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	ruleByUID := make(map[string]*ngModels.AlertRule, len(alertRules))
+	groupSizes := make(map[string]int64)
+	for _, rule := range alertRules {
+		ruleByUID[rule.UID] = rule
+		groupSizes[rule.RuleGroup] += 1
+	}
+
+	// Emit a warning if we detect a large group.
+	// We will not enforce this here, but it's convenient to emit the warning here as we load up all the rules.
+	for name, size := range groupSizes {
+		if st.rulesPerRuleGroupLimit > 0 && size > st.rulesPerRuleGroupLimit {
+			logger.Warn(
+				"Large rule group was loaded. Large groups are discouraged and changes to them may be disallowed in the future.",
+				"limit", st.rulesPerRuleGroupLimit,
+				"actual", size,
+				"group", name,
+			)
 		}
-		alertInstances, err := instanceReader.ListAlertInstances(ctx, &cmd)
-		if err != nil {
-			logger.Error("Unable to fetch previous state", "error", err)
+	}
+
+	// Get Instances
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: orgID,
+	}
+	alertInstances, err := instanceReader.ListAlertInstances(ctx, &cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert instances: %w", err)
+	}
+
+	states := make([]*State, 0, len(alertInstances))
+	for _, entry := range alertInstances {
+		ruleForEntry, ok := ruleByUID[entry.RuleUID]
+		if !ok {
+			// TODO Should we delete the orphaned state from the db?
+			continue
 		}
+		states = append(states, stateFromAlertInstance(logger, ruleForEntry, entry))
+	}
+	return states, nil
+}
 
-		for _, entry := range alertInstances {
-			ruleForEntry, ok := ruleByUID[entry.RuleUID]
-			if !ok {
-				// TODO Should we delete the orphaned state from the db?
-				continue
-			}
+// LoadStateByRuleUID replaces the cached states of the rule with the alert instances read from the instance store.
+// It is used when the evaluation of the rule is taken over from another instance of the HA cluster.
+func (st *Manager) LoadStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) int {
+	if st.instanceStore == nil {
+		return 0
+	}
+	logger := st.log.FromContext(ctx).New(rule.GetKey().LogContext()...)
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch previous state", "error", err)
+		return 0
+	}
+	rs := ruleStates{states: make(map[data.Fingerprint]*State, len(alertInstances))}
+	for _, entry := range alertInstances {
+		s := stateFromAlertInstance(logger, rule, entry)
+		rs.states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(rule.GetKey(), rs)
+	logger.Debug("State of the rule has been loaded", "states", len(rs.states))
+	return len(rs.states)
+}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
+func stateFromAlertInstance(logger log.Logger, ruleForEntry *ngModels.AlertRule, entry *ngModels.AlertInstance) *State {
+	// nil safety.
+	annotations := ruleForEntry.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			state := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
-			st.cache.set(state)
-			statesCount++
+	lbs := map[string]string(entry.Labels)
+	cacheID := entry.Labels.Fingerprint()
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
 		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
 	}
-
-	logger.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
@@ -560,6 +625,12 @@ func (st *Manager) GetStatusForRuleUID(orgID int64, alertRuleUID string) ngModel
 	return StatesToRuleStatus(states)
 }
 
+// GetAlertInstances returns the states of all organizations in the cache as alert instances, including the ones
+// in Normal state.
+func (st *Manager) GetAlertInstances() []ngModels.AlertInstance {
+	return st.cache.GetAlertInstances(false)
+}
+
 func (st *Manager) Put(states []*State) {
 	for _, s := range states {
 		st.cache.set(s)
@@ -243,6 +243,24 @@ func TestWarmStateCache(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("warming an organization keeps the cached states when the alert instances cannot be read", func(t *testing.T) {
+		err := st.WarmOrg(ctx, mainOrgID, dbstore, &failingInstanceReader{err: errors.New("database is locked")})
+		require.ErrorContains(t, err, "database is locked")
+		require.Len(t, st.GetStatesForRuleUID(mainOrgID, rule.UID), len(expectedEntries))
+	})
+}
+
+type failingInstanceReader struct {
+	err error
+}
+
+func (r *failingInstanceReader) FetchOrgIds(context.Context) ([]int64, error) {
+	return nil, r.err
+}
+
+func (r *failingInstanceReader) ListAlertInstances(context.Context, *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	return nil, r.err
 }
 
 func TestDashboardAnnotations(t *testing.T) {
@@ -0,0 +1,136 @@
+package state
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// remoteRules are the alert rules evaluated by other instances of the HA cluster, by organization and rule UID. The
+// instances that evaluate them save their state after every evaluation, and it is read from the instance store when
+// the states of their organization are read, at most once per TTL.
+type remoteRules struct {
+	mtx   sync.Mutex
+	ttl   time.Duration
+	rules map[int64]map[string]*ngModels.AlertRule
+	// loadedAt is the time the states of the remote rules of each organization were last read.
+	loadedAt map[int64]time.Time
+	// version is incremented every time the remote rules change, so that states read before the change are not cached.
+	version int64
+
+	// loadMtx makes concurrent reads of the states of remote rules wait for the read in progress.
+	loadMtx sync.Mutex
+}
+
+func newRemoteRules(ttl time.Duration) *remoteRules {
+	return &remoteRules{
+		ttl:      ttl,
+		rules:    make(map[int64]map[string]*ngModels.AlertRule),
+		loadedAt: make(map[int64]time.Time),
+	}
+}
+
+// SetRemoteRules sets the alert rules that are evaluated by other instances of the HA cluster. The states of the
+// organizations whose remote rules changed, because the ownership of rules changed or because rules were updated,
+// are read from the instance store the next time they are read, see LoadRemoteStates.
+func (st *Manager) SetRemoteRules(rules []*ngModels.AlertRule) {
+	byOrg := make(map[int64]map[string]*ngModels.AlertRule)
+	for _, rule := range rules {
+		if _, ok := byOrg[rule.OrgID]; !ok {
+			byOrg[rule.OrgID] = make(map[string]*ngModels.AlertRule)
+		}
+		byOrg[rule.OrgID][rule.UID] = rule
+	}
+
+	st.remote.mtx.Lock()
+	defer st.remote.mtx.Unlock()
+	changed := false
+	for orgID, previous := range st.remote.rules {
+		if !sameRules(previous, byOrg[orgID]) {
+			delete(st.remote.loadedAt, orgID)
+			changed = true
+		}
+	}
+	for orgID, current := range byOrg {
+		if _, ok := st.remote.rules[orgID]; !ok && len(current) > 0 {
+			delete(st.remote.loadedAt, orgID)
+			changed = true
+		}
+	}
+	if changed {
+		st.remote.version++
+	}
+	st.remote.rules = byOrg
+}
+
+func sameRules(a, b map[string]*ngModels.AlertRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for uid, rule := range a {
+		other, ok := b[uid]
+		if !ok || other.Version != rule.Version {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadRemoteStates replaces the cached states of the alert rules of the organization that are evaluated by other
+// instances of the HA cluster with their alert instances in the instance store, unless they were read less than the
+// TTL ago. It is called before the states of an organization are read, so that the states of all rules can be read
+// from any instance of the HA cluster.
+func (st *Manager) LoadRemoteStates(ctx context.Context, orgID int64) {
+	if st.instanceStore == nil {
+		return
+	}
+	st.remote.loadMtx.Lock()
+	defer st.remote.loadMtx.Unlock()
+
+	st.remote.mtx.Lock()
+	rules := st.remote.rules[orgID]
+	loadedAt, loaded := st.remote.loadedAt[orgID]
+	version := st.remote.version
+	st.remote.mtx.Unlock()
+	now := st.clock.Now()
+	if len(rules) == 0 || loaded && now.Sub(loadedAt) < st.remote.ttl {
+		return
+	}
+
+	logger := st.log.FromContext(ctx).New("org", orgID)
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{RuleOrgID: orgID})
+	if err != nil {
+		logger.Error("Unable to fetch the state of rules evaluated by other instances", "error", err)
+		return
+	}
+	byRule := make(map[string]ruleStates, len(rules))
+	for uid := range rules {
+		byRule[uid] = ruleStates{states: make(map[data.Fingerprint]*State)}
+	}
+	states := 0
+	for _, entry := range alertInstances {
+		rs, ok := byRule[entry.RuleUID]
+		if !ok {
+			continue
+		}
+		s := stateFromAlertInstance(logger, rules[entry.RuleUID], entry)
+		rs.states[s.CacheID] = s
+		states++
+	}
+
+	st.remote.mtx.Lock()
+	defer st.remote.mtx.Unlock()
+	if st.remote.version != version {
+		// the ownership of rules changed while the states were read, they are read again the next time.
+		return
+	}
+	for uid, rs := range byRule {
+		st.cache.setRuleStates(rules[uid].GetKey(), rs)
+	}
+	st.remote.loadedAt[orgID] = now
+	logger.Debug("State of rules evaluated by other instances has been loaded", "rules", len(rules), "states", states)
+}
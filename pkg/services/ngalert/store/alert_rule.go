@@ -495,6 +495,13 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 			}
 		}
 
+		if len(query.DependsOnRuleUIDs) > 0 {
+			q, err = st.filterByDependencyParents(query.DependsOnRuleUIDs, q)
+			if err != nil {
+				return err
+			}
+		}
+
 		q = q.Asc("namespace_uid", "rule_group", "rule_group_idx", "id")
 
 		alertRules := make([]*ngmodels.AlertRule, 0)
@@ -534,6 +541,13 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 					continue
 				}
 			}
+			if len(query.DependsOnRuleUIDs) > 0 {
+				if !slices.ContainsFunc(converted.Dependencies, func(d ngmodels.RuleDependency) bool {
+					return slices.Contains(query.DependsOnRuleUIDs, d.RuleUID)
+				}) {
+					continue
+				}
+			}
 			// MySQL (and potentially other databases) can use case-insensitive comparison.
 			// This code makes sure we return groups that only exactly match the filter.
 			if groupsMap != nil {
@@ -745,7 +759,11 @@ func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodel
 }
 
 // DeleteInFolder deletes the rules contained in a given folder along with their associated data.
+// It fails if a rule outside the folders depends on one of the rules.
 func (st DBstore) DeleteInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) error {
+	if len(folderUIDs) == 0 {
+		return nil
+	}
 	for _, folderUID := range folderUIDs {
 		evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleDelete, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID))
 		canSave, err := st.AccessControl.Evaluate(ctx, user, evaluator)
@@ -757,27 +775,27 @@ func (st DBstore) DeleteInFolders(ctx context.Context, orgID int64, folderUIDs [
 			st.Logger.Error("user is not allowed to delete alert rules in folder", "folder", folderUID, "user")
 			return dashboards.ErrFolderAccessDenied
 		}
+	}
 
-		rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
-			OrgID:         orgID,
-			NamespaceUIDs: []string{folderUID},
-		})
-		if err != nil {
-			return err
-		}
+	rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: folderUIDs,
+	})
+	if err != nil {
+		return err
+	}
 
-		uids := make([]string, 0, len(rules))
-		for _, tgt := range rules {
-			if tgt != nil {
-				uids = append(uids, tgt.UID)
-			}
+	uids := make([]string, 0, len(rules))
+	for _, tgt := range rules {
+		if tgt != nil {
+			uids = append(uids, tgt.UID)
 		}
+	}
 
-		if err := st.DeleteAlertRulesByUID(ctx, orgID, uids...); err != nil {
-			return err
-		}
+	if err := VerifyNoDependentRules(ctx, st, orgID, uids, nil); err != nil {
+		return err
 	}
-	return nil
+	return st.DeleteAlertRulesByUID(ctx, orgID, uids...)
 }
 
 // Kind returns the name of the alert rule type of entity.
@@ -899,6 +917,28 @@ func (st DBstore) filterByContentInNotificationSettings(value string, sess *xorm
 	return sess.And(fmt.Sprintf("notification_settings %s ?", st.SQLStore.GetDialect().LikeStr()), "%"+search+"%"), nil
 }
 
+// filterByDependencyParents keeps the rules whose dependencies mention one of the parent rules. It can return false
+// positives, which the caller must remove after the dependencies are parsed.
+func (st DBstore) filterByDependencyParents(parentUIDs []string, sess *xorm.Session) (*xorm.Session, error) {
+	conditions := make([]string, 0, len(parentUIDs))
+	args := make([]any, 0, len(parentUIDs))
+	for _, uid := range parentUIDs {
+		// marshall string according to JSON rules so we follow escaping rules.
+		b, err := json.Marshal(uid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshall string for dependencies content filter: %w", err)
+		}
+		var search = string(b)
+		if st.SQLStore.GetDialect().DriverName() != migrator.SQLite {
+			// this escapes escaped double quote (\") to \\\"
+			search = strings.ReplaceAll(strings.ReplaceAll(search, `\`, `\\`), `"`, `\"`)
+		}
+		conditions = append(conditions, fmt.Sprintf("dependencies %s ?", st.SQLStore.GetDialect().LikeStr()))
+		args = append(args, "%"+search+"%")
+	}
+	return sess.And("("+strings.Join(conditions, " OR ")+")", args...), nil
+}
+
 func (st DBstore) RenameReceiverInNotificationSettings(ctx context.Context, orgID int64, oldReceiver, newReceiver string, validateProvenance func(ngmodels.Provenance) bool, dryRun bool) ([]ngmodels.AlertRuleKey, []ngmodels.AlertRuleKey, error) {
 	// fetch entire rules because Update method requires it because it copies rules to version table
 	rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
@@ -612,6 +612,25 @@ func TestIntegration_DeleteInFolder(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, int64(0), c)
 	})
+
+	t.Run("should not be able to delete folder when a rule in another folder depends on its rules", func(t *testing.T) {
+		store.AccessControl = acmock.New().WithPermissions([]accesscontrol.Permission{
+			{Action: accesscontrol.ActionAlertingRuleDelete, Scope: dashboards.ScopeFoldersAll},
+		})
+		parent := createRule(t, store, nil)
+		gen := models.RuleGen
+		child := createRule(t, store, gen.With(gen.WithOrgID(parent.OrgID), gen.WithDependencies(models.RuleDependency{
+			RuleUID: parent.UID,
+			State:   models.RuleDependencyStateNormal,
+		})))
+		require.NotEqual(t, parent.NamespaceUID, child.NamespaceUID)
+
+		err := store.DeleteInFolders(context.Background(), parent.OrgID, []string{parent.NamespaceUID}, &user.SignedInUser{})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		err = store.DeleteInFolders(context.Background(), parent.OrgID, []string{parent.NamespaceUID, child.NamespaceUID}, &user.SignedInUser{})
+		require.NoError(t, err)
+	})
 }
 
 func TestIntegration_DeleteAlertRulesByUID(t *testing.T) {
@@ -33,6 +33,7 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		RuleGroupIndex:  ar.RuleGroupIndex,
 		For:             ar.For,
 		IsPaused:        ar.IsPaused,
+		MaxSeries:       ar.MaxSeries,
 	}
 
 	if ar.NoDataState != "" {
@@ -81,6 +82,13 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		result.NotificationSettings = ns
 	}
 
+	if ar.Dependencies != "" {
+		err = json.Unmarshal([]byte(ar.Dependencies), &result.Dependencies)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse dependencies: %w", err)
+		}
+	}
+
 	if ar.Metadata != "" {
 		err = json.Unmarshal([]byte(ar.Metadata), &result.Metadata)
 		if err != nil {
@@ -118,6 +126,7 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 		ExecErrState:    ar.ExecErrState.String(),
 		For:             ar.For,
 		IsPaused:        ar.IsPaused,
+		MaxSeries:       ar.MaxSeries,
 	}
 
 	// Serialize complex types to JSON strings
@@ -159,6 +168,14 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 		result.NotificationSettings = string(notificationSettingsData)
 	}
 
+	if len(ar.Dependencies) > 0 {
+		dependenciesData, err := json.Marshal(ar.Dependencies)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal dependencies: %w", err)
+		}
+		result.Dependencies = string(dependenciesData)
+	}
+
 	metadata, err := json.Marshal(ar.Metadata)
 	if err != nil {
 		return alertRule{}, fmt.Errorf("failed to metadata: %w", err)
@@ -191,6 +208,8 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		Labels:               rule.Labels,
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: rule.NotificationSettings,
+		Dependencies:         rule.Dependencies,
+		MaxSeries:            rule.MaxSeries,
 		Metadata:             rule.Metadata,
 	}
 }
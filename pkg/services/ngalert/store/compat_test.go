@@ -23,6 +23,15 @@ func TestAlertRuleToModelsAlertRule(t *testing.T) {
 		}
 	})
 
+	t.Run("make sure dependencies are kept between conversions", func(t *testing.T) {
+		rule := g.With(g.WithDependencies(ngmodels.RuleDependency{RuleUID: util.GenerateShortUID(), State: ngmodels.RuleDependencyStateNormal})).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.Dependencies, clone.Dependencies)
+	})
+
 	t.Run("should use NoData if NoDataState is not known", func(t *testing.T) {
 		rule, err := alertRuleFromModelsAlertRule(g.Generate())
 		require.NoError(t, err)
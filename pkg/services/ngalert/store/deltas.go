@@ -52,6 +52,60 @@ func (c *GroupDelta) NewOrUpdatedNotificationSettings() []models.NotificationSet
 	return settings
 }
 
+// NewOrUpdatedDependencies returns a list of rule dependencies that are either new or updated in the group.
+func (c *GroupDelta) NewOrUpdatedDependencies() []models.RuleDependency {
+	var dependencies []models.RuleDependency
+	for _, rule := range c.New {
+		dependencies = append(dependencies, rule.Dependencies...)
+	}
+	for _, delta := range c.Update {
+		if len(delta.New.Dependencies) == 0 {
+			continue
+		}
+		d := delta.Diff.GetDiffsForField("Dependencies")
+		if len(d) == 0 {
+			continue
+		}
+		dependencies = append(dependencies, delta.New.Dependencies...)
+	}
+	return dependencies
+}
+
+// VerifyNoDependentRules returns an error if a rule that is not deleted depends on one of the deleted rules. The
+// dependencies of the updated rules replace the ones in the database, as they are saved along with the deletion.
+func VerifyNoDependentRules(ctx context.Context, ruleReader RuleReader, orgID int64, deletedUIDs []string, updated []*models.AlertRule) error {
+	if len(deletedUIDs) == 0 {
+		return nil
+	}
+	dependents, err := ruleReader.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, DependsOnRuleUIDs: deletedUIDs})
+	if err != nil {
+		return fmt.Errorf("failed to fetch rules that depend on the deleted rules: %w", err)
+	}
+	deleted := make(map[string]struct{}, len(deletedUIDs))
+	for _, uid := range deletedUIDs {
+		deleted[uid] = struct{}{}
+	}
+	updatedDependencies := make(map[string][]models.RuleDependency, len(updated))
+	for _, rule := range updated {
+		updatedDependencies[rule.UID] = rule.Dependencies
+	}
+	for _, rule := range dependents {
+		if _, ok := deleted[rule.UID]; ok {
+			continue
+		}
+		dependencies := rule.Dependencies
+		if d, ok := updatedDependencies[rule.UID]; ok {
+			dependencies = d
+		}
+		for _, d := range dependencies {
+			if _, ok := deleted[d.RuleUID]; ok {
+				return fmt.Errorf("%w: rule %s cannot be deleted because rule %s depends on it", models.ErrAlertRuleFailedValidation, d.RuleUID, rule.UID)
+			}
+		}
+	}
+	return nil
+}
+
 type RuleReader interface {
 	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
@@ -684,3 +684,36 @@ func groupByUID(t *testing.T, list []*models.AlertRule) map[string]*models.Alert
 	}
 	return result
 }
+
+func TestVerifyNoDependentRules(t *testing.T) {
+	orgID := int64(rand.Int31())
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID))
+
+	parent := gen.GenerateRef()
+	otherParent := gen.GenerateRef()
+	child := gen.With(gen.WithDependencies(
+		models.RuleDependency{RuleUID: parent.UID, State: models.RuleDependencyStateNormal},
+	)).GenerateRef()
+	fakeStore := fakes.NewRuleStore(t)
+	fakeStore.PutRule(context.Background(), parent, otherParent, child)
+
+	t.Run("fails when a rule that is not deleted depends on a deleted rule", func(t *testing.T) {
+		err := VerifyNoDependentRules(context.Background(), fakeStore, orgID, []string{parent.UID}, nil)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, fmt.Sprintf("rule %s cannot be deleted because rule %s depends on it", parent.UID, child.UID))
+	})
+
+	t.Run("succeeds when the dependent rule is deleted as well", func(t *testing.T) {
+		require.NoError(t, VerifyNoDependentRules(context.Background(), fakeStore, orgID, []string{parent.UID, child.UID}, nil))
+	})
+
+	t.Run("succeeds when the dependency is removed by the same change", func(t *testing.T) {
+		updated := models.CopyRule(child)
+		updated.Dependencies = []models.RuleDependency{{RuleUID: otherParent.UID, State: models.RuleDependencyStateAlerting}}
+		require.NoError(t, VerifyNoDependentRules(context.Background(), fakeStore, orgID, []string{parent.UID}, []*models.AlertRule{updated}))
+	})
+
+	t.Run("succeeds when no rule depends on the deleted rules", func(t *testing.T) {
+		require.NoError(t, VerifyNoDependentRules(context.Background(), fakeStore, orgID, []string{otherParent.UID}, nil))
+	})
+}
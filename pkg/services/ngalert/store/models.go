@@ -26,6 +26,8 @@ type alertRule struct {
 	Labels               string
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
+	Dependencies         string `xorm:"dependencies"`
+	MaxSeries            int64  `xorm:"max_series"`
 	Metadata             string `xorm:"metadata"`
 }
 
@@ -60,6 +62,8 @@ type alertRuleVersion struct {
 	Labels               string
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
+	Dependencies         string `xorm:"dependencies"`
+	MaxSeries            int64  `xorm:"max_series"`
 	Metadata             string `xorm:"metadata"`
 }
 
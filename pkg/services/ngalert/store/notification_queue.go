@@ -0,0 +1,320 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationQueueStore persists notifications that contact points failed to deliver, so they can be retried.
+type NotificationQueueStore interface {
+	// EnqueueNotification saves a new notification in the queue and sets its ID. The pending notifications of the same
+	// integration and group that were queued before it are deleted, as they are superseded by the new notification.
+	EnqueueNotification(ctx context.Context, n *models.QueuedNotification) error
+
+	// GetDueQueuedNotifications returns pending notifications of the organization that should be retried at or before now,
+	// oldest first. Notifications are not returned if a newer notification of the same integration and group is pending.
+	GetDueQueuedNotifications(ctx context.Context, orgID int64, now time.Time, limit int) ([]*models.QueuedNotification, error)
+
+	// ClaimQueuedNotification moves the next attempt of a due notification to leaseUntil, so that no other instance retries
+	// it at the same time. It returns false if the notification was changed since it was read.
+	ClaimQueuedNotification(ctx context.Context, n *models.QueuedNotification, leaseUntil time.Time) (bool, error)
+
+	// UpdateQueuedNotification saves the status, attempts, last error and next attempt of the notification.
+	UpdateQueuedNotification(ctx context.Context, n *models.QueuedNotification) error
+
+	// DeleteQueuedNotification deletes the notification. It does not return an error if the notification does not exist.
+	DeleteQueuedNotification(ctx context.Context, orgID, id int64) error
+
+	// DeleteSupersededQueuedNotifications deletes the pending notifications of the same integration and group as n that
+	// were queued before it. If n has no ID, because it was delivered without being queued, all of them are deleted.
+	// It returns the number of deleted notifications.
+	DeleteSupersededQueuedNotifications(ctx context.Context, n *models.QueuedNotification) (int64, error)
+
+	// ListQueuedNotifications returns the queued notifications of a receiver, newest first.
+	ListQueuedNotifications(ctx context.Context, q models.ListQueuedNotificationsQuery) ([]*models.QueuedNotification, error)
+
+	// ReplayQueuedNotifications resets the attempts of the dead notifications and makes them pending, so that they are
+	// retried immediately. It returns the number of replayed notifications.
+	ReplayQueuedNotifications(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd) (int64, error)
+
+	// DeleteDeadQueuedNotifications deletes dead notifications of the organization that were last updated before the given time.
+	DeleteDeadQueuedNotifications(ctx context.Context, orgID int64, before time.Time) (int64, error)
+
+	// GetNotificationQueueHealth returns the health of the queue for each receiver of the organization that has queued notifications.
+	GetNotificationQueueHealth(ctx context.Context, orgID int64) (map[string]models.NotificationQueueHealth, error)
+}
+
+// queuedNotification is a row of the alert_notification_queue table.
+type queuedNotification struct {
+	ID               int64  `xorm:"pk autoincr 'id'"`
+	OrgID            int64  `xorm:"org_id"`
+	Receiver         string `xorm:"receiver"`
+	IntegrationType  string `xorm:"integration_type"`
+	IntegrationIndex int    `xorm:"integration_index"`
+	GroupKey         string `xorm:"group_key"`
+	Payload          string `xorm:"payload"`
+	Status           string `xorm:"status"`
+	Attempts         int    `xorm:"attempts"`
+	LastError        string `xorm:"last_error"`
+	NextAttemptAt    int64  `xorm:"next_attempt_at"`
+	CreatedAt        int64  `xorm:"created_at"`
+	UpdatedAt        int64  `xorm:"updated_at"`
+}
+
+func (queuedNotification) TableName() string {
+	return "alert_notification_queue"
+}
+
+func queuedNotificationToRow(n *models.QueuedNotification) queuedNotification {
+	return queuedNotification{
+		ID:               n.ID,
+		OrgID:            n.OrgID,
+		Receiver:         n.Receiver,
+		IntegrationType:  n.IntegrationType,
+		IntegrationIndex: n.IntegrationIndex,
+		GroupKey:         n.GroupKey,
+		Payload:          string(n.Payload),
+		Status:           string(n.Status),
+		Attempts:         n.Attempts,
+		LastError:        n.LastError,
+		NextAttemptAt:    n.NextAttemptAt.UnixMilli(),
+		CreatedAt:        n.CreatedAt.UnixMilli(),
+		UpdatedAt:        n.UpdatedAt.UnixMilli(),
+	}
+}
+
+func (r queuedNotification) toModel() *models.QueuedNotification {
+	return &models.QueuedNotification{
+		ID:               r.ID,
+		OrgID:            r.OrgID,
+		Receiver:         r.Receiver,
+		IntegrationType:  r.IntegrationType,
+		IntegrationIndex: r.IntegrationIndex,
+		GroupKey:         r.GroupKey,
+		Payload:          []byte(r.Payload),
+		Status:           models.QueuedNotificationStatus(r.Status),
+		Attempts:         r.Attempts,
+		LastError:        r.LastError,
+		NextAttemptAt:    time.UnixMilli(r.NextAttemptAt).UTC(),
+		CreatedAt:        time.UnixMilli(r.CreatedAt).UTC(),
+		UpdatedAt:        time.UnixMilli(r.UpdatedAt).UTC(),
+	}
+}
+
+func (st DBstore) EnqueueNotification(ctx context.Context, n *models.QueuedNotification) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		row := queuedNotificationToRow(n)
+		row.ID = 0
+		if _, err := sess.Insert(&row); err != nil {
+			return fmt.Errorf("failed to insert queued notification: %w", err)
+		}
+		n.ID = row.ID
+		if _, err := deleteSupersededQueuedNotifications(sess, n); err != nil {
+			return fmt.Errorf("failed to delete superseded queued notifications: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetDueQueuedNotifications(ctx context.Context, orgID int64, now time.Time, limit int) ([]*models.QueuedNotification, error) {
+	var rows []queuedNotification
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND status = ? AND next_attempt_at <= ?", orgID, models.QueuedNotificationPending, now.UnixMilli()).
+			And("NOT EXISTS (SELECT 1 FROM alert_notification_queue AS newer WHERE newer.org_id = alert_notification_queue.org_id "+
+				"AND newer.receiver = alert_notification_queue.receiver AND newer.integration_type = alert_notification_queue.integration_type "+
+				"AND newer.integration_index = alert_notification_queue.integration_index AND newer.group_key = alert_notification_queue.group_key "+
+				"AND newer.status = ? AND newer.id > alert_notification_queue.id)", models.QueuedNotificationPending).
+			Asc("id").
+			Limit(limit).
+			Find(&rows)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get due queued notifications: %w", err)
+	}
+	return queuedNotificationsToModels(rows), nil
+}
+
+func (st DBstore) ClaimQueuedNotification(ctx context.Context, n *models.QueuedNotification, leaseUntil time.Time) (bool, error) {
+	var claimed bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec(
+			"UPDATE alert_notification_queue SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at = ?",
+			leaseUntil.UnixMilli(), n.ID, models.QueuedNotificationPending, n.NextAttemptAt.UnixMilli(),
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = affected == 1
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim queued notification: %w", err)
+	}
+	if claimed {
+		n.NextAttemptAt = time.UnixMilli(leaseUntil.UnixMilli()).UTC()
+	}
+	return claimed, nil
+}
+
+func (st DBstore) UpdateQueuedNotification(ctx context.Context, n *models.QueuedNotification) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := queuedNotificationToRow(n)
+		affected, err := sess.ID(n.ID).Where("org_id = ?", n.OrgID).
+			Cols("status", "attempts", "last_error", "next_attempt_at", "updated_at").
+			Update(&row)
+		if err != nil {
+			return fmt.Errorf("failed to update queued notification: %w", err)
+		}
+		if affected == 0 {
+			return models.ErrQueuedNotificationNotFound
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteQueuedNotification(ctx context.Context, orgID, id int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM alert_notification_queue WHERE org_id = ? AND id = ?", orgID, id); err != nil {
+			return fmt.Errorf("failed to delete queued notification: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteSupersededQueuedNotifications(ctx context.Context, n *models.QueuedNotification) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = deleteSupersededQueuedNotifications(sess, n)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete superseded queued notifications: %w", err)
+	}
+	return deleted, nil
+}
+
+func deleteSupersededQueuedNotifications(sess *db.Session, n *models.QueuedNotification) (int64, error) {
+	query := "DELETE FROM alert_notification_queue WHERE org_id = ? AND receiver = ? AND integration_type = ? AND integration_index = ? AND group_key = ? AND status = ?"
+	args := []any{n.OrgID, n.Receiver, n.IntegrationType, n.IntegrationIndex, n.GroupKey, models.QueuedNotificationPending}
+	if n.ID != 0 {
+		query += " AND id < ?"
+		args = append(args, n.ID)
+	}
+	res, err := sess.Exec(append([]any{query}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (st DBstore) ListQueuedNotifications(ctx context.Context, q models.ListQueuedNotificationsQuery) ([]*models.QueuedNotification, error) {
+	var rows []queuedNotification
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		s := sess.Where("org_id = ? AND receiver = ?", q.OrgID, q.Receiver)
+		if q.Status != "" {
+			s = s.And("status = ?", q.Status)
+		}
+		if q.Limit > 0 {
+			s = s.Limit(q.Limit)
+		}
+		return s.Desc("id").Find(&rows)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list queued notifications: %w", err)
+	}
+	return queuedNotificationsToModels(rows), nil
+}
+
+func (st DBstore) ReplayQueuedNotifications(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd) (int64, error) {
+	var replayed int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		// Only dead notifications are replayed, pending ones may be being delivered by another instance.
+		s := sess.Table(queuedNotification{}).Where("org_id = ? AND receiver = ? AND status = ?", cmd.OrgID, cmd.Receiver, models.QueuedNotificationDead)
+		if len(cmd.IDs) > 0 {
+			s = s.In("id", cmd.IDs)
+		}
+		affected, err := s.Update(map[string]any{
+			"status":          models.QueuedNotificationPending,
+			"attempts":        0,
+			"next_attempt_at": cmd.Now.UnixMilli(),
+			"updated_at":      cmd.Now.UnixMilli(),
+		})
+		if err != nil {
+			return err
+		}
+		replayed = affected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to replay queued notifications: %w", err)
+	}
+	return replayed, nil
+}
+
+func (st DBstore) DeleteDeadQueuedNotifications(ctx context.Context, orgID int64, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_notification_queue WHERE org_id = ? AND status = ? AND updated_at < ?",
+			orgID, models.QueuedNotificationDead, before.UnixMilli())
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete dead queued notifications: %w", err)
+	}
+	return deleted, nil
+}
+
+func (st DBstore) GetNotificationQueueHealth(ctx context.Context, orgID int64) (map[string]models.NotificationQueueHealth, error) {
+	var rows []struct {
+		Receiver    string `xorm:"receiver"`
+		Status      string `xorm:"status"`
+		Count       int64  `xorm:"cnt"`
+		OldestAt    int64  `xorm:"oldest_at"`
+		LastUpdated int64  `xorm:"last_updated"`
+	}
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(
+			"SELECT receiver, status, COUNT(*) AS cnt, MIN(created_at) AS oldest_at, MAX(updated_at) AS last_updated "+
+				"FROM alert_notification_queue WHERE org_id = ? GROUP BY receiver, status",
+			orgID,
+		).Find(&rows)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get notification queue health: %w", err)
+	}
+
+	result := make(map[string]models.NotificationQueueHealth)
+	for _, row := range rows {
+		h := result[row.Receiver]
+		switch models.QueuedNotificationStatus(row.Status) {
+		case models.QueuedNotificationPending:
+			h.Pending = row.Count
+			h.OldestPending = time.UnixMilli(row.OldestAt).UTC()
+		case models.QueuedNotificationDead:
+			h.Dead = row.Count
+		}
+		if lastUpdated := time.UnixMilli(row.LastUpdated).UTC(); lastUpdated.After(h.LastAttempt) {
+			h.LastAttempt = lastUpdated
+		}
+		result[row.Receiver] = h
+	}
+	return result, nil
+}
+
+func queuedNotificationsToModels(rows []queuedNotification) []*models.QueuedNotification {
+	result := make([]*models.QueuedNotification, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, r.toModel())
+	}
+	return result
+}
@@ -0,0 +1,201 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationQueue(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	enqueueGroup := func(orgID int64, receiver, groupKey string, status models.QueuedNotificationStatus, nextAttempt time.Time) *models.QueuedNotification {
+		n := &models.QueuedNotification{
+			OrgID:            orgID,
+			Receiver:         receiver,
+			IntegrationType:  "webhook",
+			IntegrationIndex: 0,
+			GroupKey:         groupKey,
+			Payload:          []byte(`{"alerts":[]}`),
+			Status:           status,
+			Attempts:         1,
+			LastError:        "connection refused",
+			NextAttemptAt:    nextAttempt,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+		require.NoError(t, dbstore.EnqueueNotification(ctx, n))
+		require.NotZero(t, n.ID)
+		return n
+	}
+	groups := 0
+	// enqueue queues a notification of a new group, so that it does not supersede other notifications.
+	enqueue := func(orgID int64, receiver string, status models.QueuedNotificationStatus, nextAttempt time.Time) *models.QueuedNotification {
+		groups++
+		return enqueueGroup(orgID, receiver, fmt.Sprintf("{}:{alertname=\"test-%d\"}", groups), status, nextAttempt)
+	}
+
+	due := enqueue(1, "receiver-a", models.QueuedNotificationPending, now)
+	later := enqueue(1, "receiver-a", models.QueuedNotificationPending, now.Add(time.Minute))
+	dead := enqueue(1, "receiver-a", models.QueuedNotificationDead, now)
+	otherReceiver := enqueue(1, "receiver-b", models.QueuedNotificationPending, now)
+	otherOrg := enqueue(2, "receiver-a", models.QueuedNotificationPending, now)
+
+	ids := func(notifications []*models.QueuedNotification) []int64 {
+		result := make([]int64, 0, len(notifications))
+		for _, n := range notifications {
+			result = append(result, n.ID)
+		}
+		return result
+	}
+
+	t.Run("returns due pending notifications of the organization", func(t *testing.T) {
+		result, err := dbstore.GetDueQueuedNotifications(ctx, 1, now, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{due.ID, otherReceiver.ID}, ids(result))
+		require.Equal(t, *due, *result[0])
+
+		result, err = dbstore.GetDueQueuedNotifications(ctx, 1, now.Add(time.Minute), 1)
+		require.NoError(t, err)
+		require.Equal(t, []int64{due.ID}, ids(result))
+	})
+
+	t.Run("lists notifications of a receiver", func(t *testing.T) {
+		result, err := dbstore.ListQueuedNotifications(ctx, models.ListQueuedNotificationsQuery{OrgID: 1, Receiver: "receiver-a"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{dead.ID, later.ID, due.ID}, ids(result))
+
+		result, err = dbstore.ListQueuedNotifications(ctx, models.ListQueuedNotificationsQuery{OrgID: 1, Receiver: "receiver-a", Status: models.QueuedNotificationDead})
+		require.NoError(t, err)
+		require.Equal(t, []int64{dead.ID}, ids(result))
+	})
+
+	t.Run("returns health per receiver", func(t *testing.T) {
+		health, err := dbstore.GetNotificationQueueHealth(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]models.NotificationQueueHealth{
+			"receiver-a": {Pending: 2, Dead: 1, OldestPending: now, LastAttempt: now},
+			"receiver-b": {Pending: 1, OldestPending: now, LastAttempt: now},
+		}, health)
+	})
+
+	t.Run("only one claim of a notification succeeds", func(t *testing.T) {
+		first := *otherReceiver
+		second := *otherReceiver
+		claimed, err := dbstore.ClaimQueuedNotification(ctx, &first, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, claimed)
+		require.Equal(t, now.Add(time.Minute), first.NextAttemptAt)
+
+		claimed, err = dbstore.ClaimQueuedNotification(ctx, &second, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.False(t, claimed)
+	})
+
+	t.Run("updates and deletes notifications", func(t *testing.T) {
+		due.Attempts = 2
+		due.Status = models.QueuedNotificationDead
+		due.LastError = "timeout"
+		due.UpdatedAt = now.Add(time.Second)
+		require.NoError(t, dbstore.UpdateQueuedNotification(ctx, due))
+
+		result, err := dbstore.ListQueuedNotifications(ctx, models.ListQueuedNotificationsQuery{OrgID: 1, Receiver: "receiver-a", Status: models.QueuedNotificationDead})
+		require.NoError(t, err)
+		require.Equal(t, []int64{dead.ID, due.ID}, ids(result))
+		require.Equal(t, *due, *result[1])
+
+		require.NoError(t, dbstore.DeleteQueuedNotification(ctx, 1, otherReceiver.ID))
+		require.NoError(t, dbstore.DeleteQueuedNotification(ctx, 1, otherOrg.ID), "notifications of other organizations are not deleted")
+		result, err = dbstore.GetDueQueuedNotifications(ctx, 2, now, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{otherOrg.ID}, ids(result))
+
+		require.ErrorIs(t, dbstore.UpdateQueuedNotification(ctx, otherReceiver), models.ErrQueuedNotificationNotFound)
+	})
+
+	t.Run("replays dead notifications", func(t *testing.T) {
+		replayAt := now.Add(time.Hour)
+		replayed, err := dbstore.ReplayQueuedNotifications(ctx, models.ReplayQueuedNotificationsCmd{OrgID: 1, Receiver: "receiver-a", IDs: []int64{later.ID}, Now: replayAt})
+		require.NoError(t, err)
+		require.Zero(t, replayed, "pending notifications should not be replayed")
+
+		replayed, err = dbstore.ReplayQueuedNotifications(ctx, models.ReplayQueuedNotificationsCmd{OrgID: 1, Receiver: "receiver-a", IDs: []int64{dead.ID}, Now: replayAt})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, replayed)
+
+		replayed, err = dbstore.ReplayQueuedNotifications(ctx, models.ReplayQueuedNotificationsCmd{OrgID: 1, Receiver: "receiver-a", Now: replayAt})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, replayed, "only the remaining dead notification should be replayed")
+
+		result, err := dbstore.GetDueQueuedNotifications(ctx, 1, replayAt, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{due.ID, later.ID, dead.ID}, ids(result))
+		for _, n := range []*models.QueuedNotification{result[0], result[2]} {
+			require.Equal(t, models.QueuedNotificationPending, n.Status)
+			require.Zero(t, n.Attempts)
+			require.Equal(t, replayAt, n.NextAttemptAt)
+		}
+	})
+
+	t.Run("supersedes pending notifications of the same group", func(t *testing.T) {
+		const groupKey = "{}:{alertname=\"superseded\"}"
+		replayed := enqueueGroup(3, "receiver-a", groupKey, models.QueuedNotificationDead, now)
+		first := enqueueGroup(3, "receiver-a", groupKey, models.QueuedNotificationPending, now)
+		second := enqueueGroup(3, "receiver-a", groupKey, models.QueuedNotificationPending, now)
+		otherGroup := enqueue(3, "receiver-a", models.QueuedNotificationPending, now)
+
+		result, err := dbstore.ListQueuedNotifications(ctx, models.ListQueuedNotificationsQuery{OrgID: 3, Receiver: "receiver-a"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{otherGroup.ID, second.ID, replayed.ID}, ids(result), "the first notification should be deleted when the second one is queued")
+		require.NotContains(t, ids(result), first.ID)
+
+		_, err = dbstore.ReplayQueuedNotifications(ctx, models.ReplayQueuedNotificationsCmd{OrgID: 3, Receiver: "receiver-a", Now: now})
+		require.NoError(t, err)
+		result, err = dbstore.GetDueQueuedNotifications(ctx, 3, now, 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{second.ID, otherGroup.ID}, ids(result), "the replayed notification is superseded by the pending one")
+
+		deleted, err := dbstore.DeleteSupersededQueuedNotifications(ctx, second)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+		deleted, err = dbstore.DeleteSupersededQueuedNotifications(ctx, &models.QueuedNotification{
+			OrgID:           3,
+			Receiver:        "receiver-a",
+			IntegrationType: "webhook",
+			GroupKey:        groupKey,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		result, err = dbstore.ListQueuedNotifications(ctx, models.ListQueuedNotificationsQuery{OrgID: 3, Receiver: "receiver-a"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{otherGroup.ID}, ids(result))
+	})
+
+	t.Run("deletes expired dead notifications", func(t *testing.T) {
+		expired := enqueue(1, "receiver-a", models.QueuedNotificationDead, now)
+		recent := enqueue(1, "receiver-a", models.QueuedNotificationDead, now)
+		recent.UpdatedAt = now.Add(2 * time.Hour)
+		require.NoError(t, dbstore.UpdateQueuedNotification(ctx, recent))
+
+		deleted, err := dbstore.DeleteDeadQueuedNotifications(ctx, 1, now.Add(time.Hour))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		result, err := dbstore.ListQueuedNotifications(ctx, models.ListQueuedNotificationsQuery{OrgID: 1, Receiver: "receiver-a", Status: models.QueuedNotificationDead})
+		require.NoError(t, err)
+		require.Equal(t, []int64{recent.ID}, ids(result))
+		require.NotContains(t, ids(result), expired.ID)
+	})
+}
@@ -101,6 +101,19 @@ func (fkv *FakeKVStore) GetAll(ctx context.Context, orgId int64, namespace strin
 	fkv.Mtx.Lock()
 	defer fkv.Mtx.Unlock()
 
+	if orgId == kvstore.AllOrganizations {
+		all := map[int64]map[string]string{}
+		for id, org := range fkv.Store {
+			for k, v := range org[namespace] {
+				if _, ok := all[id]; !ok {
+					all[id] = make(map[string]string)
+				}
+				all[id][k] = v
+			}
+		}
+		return all, nil
+	}
+
 	all := map[int64]map[string]string{
 		orgId: make(map[string]string),
 	}
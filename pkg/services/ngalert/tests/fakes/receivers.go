@@ -16,6 +16,9 @@ type FakeReceiverService struct {
 	MethodCalls     []ReceiverServiceMethodCall
 	GetReceiverFn   func(ctx context.Context, q models.GetReceiverQuery, u identity.Requester) (*models.Receiver, error)
 	ListReceiversFn func(ctx context.Context, q models.ListReceiversQuery, u identity.Requester) ([]*models.Receiver, error)
+
+	ListFailedNotificationsFn   func(ctx context.Context, q models.ListQueuedNotificationsQuery, u identity.Requester) ([]*models.QueuedNotification, models.NotificationQueueHealth, error)
+	ReplayFailedNotificationsFn func(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd, u identity.Requester) (int64, error)
 }
 
 func NewFakeReceiverService() *FakeReceiverService {
@@ -35,6 +38,22 @@ func (f *FakeReceiverService) ListReceivers(ctx context.Context, q models.ListRe
 	return f.ListReceiversFn(ctx, q, u)
 }
 
+func (f *FakeReceiverService) ListFailedNotifications(ctx context.Context, q models.ListQueuedNotificationsQuery, u identity.Requester) ([]*models.QueuedNotification, models.NotificationQueueHealth, error) {
+	f.MethodCalls = append(f.MethodCalls, ReceiverServiceMethodCall{Method: "ListFailedNotifications", Args: []interface{}{ctx, q}})
+	if f.ListFailedNotificationsFn == nil {
+		return nil, models.NotificationQueueHealth{}, nil
+	}
+	return f.ListFailedNotificationsFn(ctx, q, u)
+}
+
+func (f *FakeReceiverService) ReplayFailedNotifications(ctx context.Context, cmd models.ReplayQueuedNotificationsCmd, u identity.Requester) (int64, error) {
+	f.MethodCalls = append(f.MethodCalls, ReceiverServiceMethodCall{Method: "ReplayFailedNotifications", Args: []interface{}{ctx, cmd}})
+	if f.ReplayFailedNotificationsFn == nil {
+		return 0, nil
+	}
+	return f.ReplayFailedNotificationsFn(ctx, cmd, u)
+}
+
 func (f *FakeReceiverService) PopMethodCall() ReceiverServiceMethodCall {
 	if len(f.MethodCalls) == 0 {
 		return ReceiverServiceMethodCall{}
@@ -48,6 +67,8 @@ func (f *FakeReceiverService) Reset() {
 	f.MethodCalls = nil
 	f.GetReceiverFn = defaultReceiverFn
 	f.ListReceiversFn = defaultReceiversFn
+	f.ListFailedNotificationsFn = nil
+	f.ReplayFailedNotificationsFn = nil
 }
 
 func defaultReceiverFn(ctx context.Context, q models.GetReceiverQuery, u identity.Requester) (*models.Receiver, error) {
@@ -213,6 +213,11 @@ func (f *RuleStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQu
 		if len(q.RuleUIDs) > 0 && !slices.Contains(q.RuleUIDs, r.UID) {
 			continue
 		}
+		if len(q.DependsOnRuleUIDs) > 0 && !slices.ContainsFunc(r.Dependencies, func(d models.RuleDependency) bool {
+			return slices.Contains(q.DependsOnRuleUIDs, d.RuleUID)
+		}) {
+			continue
+		}
 
 		ruleList = append(ruleList, r)
 	}
@@ -344,6 +349,30 @@ func (f *RuleStore) IncreaseVersionForAllRulesInNamespaces(_ context.Context, or
 	return result, nil
 }
 
+func (f *RuleStore) ListNotificationSettings(_ context.Context, q models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, q)
+
+	result := make(map[models.AlertRuleKey][]models.NotificationSettings)
+	for _, rule := range f.Rules[q.OrgID] {
+		ns := make([]models.NotificationSettings, 0, len(rule.NotificationSettings))
+		for _, setting := range rule.NotificationSettings {
+			if q.ReceiverName != "" && q.ReceiverName != setting.Receiver {
+				continue
+			}
+			if q.TimeIntervalName != "" && !slices.Contains(setting.MuteTimeIntervals, q.TimeIntervalName) {
+				continue
+			}
+			ns = append(ns, setting)
+		}
+		if len(ns) > 0 {
+			result[rule.GetKey()] = ns
+		}
+	}
+	return result, nil
+}
+
 func (f *RuleStore) CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, u identity.Requester) (int64, error) {
 	return 0, nil
 }
@@ -0,0 +1,49 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxErrorBodySize limits how much of the response body is included in write errors.
+const maxErrorBodySize = 1024
+
+// sendWriteRequest posts the body to the URL and converts unsuccessful responses to write errors.
+// It returns the status code of the response, or 0 if the request failed.
+func sendWriteRequest(ctx context.Context, client *http.Client, url string, timeout time.Duration, contentType string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "grafana-recording-rule")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return res.StatusCode, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	writeErr := fmt.Errorf("write request failed with status %d: %s", res.StatusCode, bytes.TrimSpace(msg))
+	// Only 4xx responses other than rate limiting are caused by the written data.
+	if res.StatusCode/100 == 4 && res.StatusCode != http.StatusTooManyRequests {
+		return res.StatusCode, errors.Join(ErrRejectedWrite, writeErr)
+	}
+	return res.StatusCode, errors.Join(ErrUnexpectedWriteFailure, writeErr)
+}
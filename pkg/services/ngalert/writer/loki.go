@@ -0,0 +1,126 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	lokiBackendType = "loki"
+	lokiPushPath    = "/loki/api/v1/push"
+
+	// LokiRecordingRuleLabel is the value of the "from" label of the streams that recorded metrics are written to.
+	LokiRecordingRuleLabel = "recording-rule"
+)
+
+// LokiWriter writes recorded metrics to Loki as log lines. All samples of a metric are written to a single stream
+// with the labels from, orgID and metric, so writing does not create streams for every series. The line of each sample
+// is a JSON object with the labels and the value of the series, which can be queried back with
+// `{from="recording-rule", metric="<name>"} | json | unwrap value`.
+type LokiWriter struct {
+	client  *http.Client
+	url     string
+	timeout time.Duration
+	clock   clock.Clock
+	logger  log.Logger
+	metrics *metrics.RemoteWriter
+}
+
+func NewLokiWriter(
+	settings setting.RecordingRuleSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*LokiWriter, error) {
+	if settings.URL == "" {
+		return nil, errors.New("URL is required")
+	}
+	cl, err := newHTTPClient(settings, httpClientProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	url := settings.URL
+	if !strings.HasSuffix(url, lokiPushPath) {
+		url = strings.TrimSuffix(url, "/") + lokiPushPath
+	}
+
+	return &LokiWriter{
+		client:  cl,
+		url:     url,
+		timeout: settings.Timeout,
+		clock:   clock,
+		logger:  l,
+		metrics: metrics,
+	}, nil
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiLine struct {
+	Labels map[string]string `json:"labels"`
+	Value  string            `json:"value"`
+}
+
+// Write writes the given frames to the Loki push API.
+func (w LokiWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), lokiBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	stream := lokiStream{
+		Stream: map[string]string{
+			"from":   LokiRecordingRuleLabel,
+			"orgID":  fmt.Sprint(orgID),
+			"metric": name,
+		},
+		Values: make([][2]string, 0, len(points)),
+	}
+	for _, p := range points {
+		line, err := json.Marshal(lokiLine{
+			Labels: p.Labels,
+			Value:  strconv.FormatFloat(p.Metric.V, 'g', -1, 64),
+		})
+		if err != nil {
+			return errors.Join(ErrBadFrame, err)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(p.Metric.T.UnixNano(), 10), string(line)})
+	}
+	body, err := json.Marshal(lokiPushRequest{Streams: []lokiStream{stream}})
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	status, err := sendWriteRequest(ctx, w.client, w.url, w.timeout, "application/json", body)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+	w.metrics.WritesTotal.WithLabelValues(append(lvs, fmt.Sprint(status))...).Inc()
+
+	return err
+}
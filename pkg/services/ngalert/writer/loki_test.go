@@ -0,0 +1,103 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLokiWriter_Write(t *testing.T) {
+	status := http.StatusNoContent
+	var lastPath string
+	var lastBody lokiPushRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastPath = r.URL.Path
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &lastBody))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	writer, err := NewLokiWriter(setting.RecordingRuleSettings{
+		URL:     srv.URL,
+		Timeout: time.Second,
+	}, httpclient.NewProvider(), clock.New(), log.NewNopLogger(), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.Now()
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+
+	t.Run("writes all series to a single stream", func(t *testing.T) {
+		err := writer.Write(context.Background(), "test", now, frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+
+		require.Equal(t, lokiPushPath, lastPath)
+		require.Len(t, lastBody.Streams, 1)
+		stream := lastBody.Streams[0]
+		require.Equal(t, map[string]string{"from": LokiRecordingRuleLabel, "orgID": "1", "metric": "test"}, stream.Stream)
+		require.Len(t, stream.Values, len(series))
+		for _, v := range stream.Values {
+			require.Equal(t, now.Format(time.RFC3339), time.Unix(0, mustParseInt(t, v[0])).Format(time.RFC3339))
+			var line lokiLine
+			require.NoError(t, json.Unmarshal([]byte(v[1]), &line))
+			require.Equal(t, "label", line.Labels["extra"])
+			require.Contains(t, []string{"1", "2"}, line.Labels["foo"])
+			require.NotEmpty(t, line.Value)
+		}
+	})
+
+	t.Run("rejected write when Loki returns 400", func(t *testing.T) {
+		status = http.StatusBadRequest
+		t.Cleanup(func() { status = http.StatusNoContent })
+
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrRejectedWrite)
+	})
+
+	t.Run("unexpected failure when Loki returns 500", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		t.Cleanup(func() { status = http.StatusNoContent })
+
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrUnexpectedWriteFailure)
+	})
+
+	t.Run("error when frames are empty", func(t *testing.T) {
+		err := writer.Write(context.Background(), "test", now, data.Frames{data.NewFrame("test")}, 1, nil)
+		require.ErrorIs(t, err, ErrBadFrame)
+	})
+}
+
+func TestNewLokiWriter(t *testing.T) {
+	m := metrics.NewRemoteWriterMetrics(prometheus.NewRegistry())
+
+	_, err := NewLokiWriter(setting.RecordingRuleSettings{Timeout: time.Second}, httpclient.NewProvider(), clock.New(), log.NewNopLogger(), m)
+	require.Error(t, err)
+
+	w, err := NewLokiWriter(setting.RecordingRuleSettings{URL: "http://localhost:3100/loki/api/v1/push", Timeout: time.Second}, httpclient.NewProvider(), clock.New(), log.NewNopLogger(), m)
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:3100/loki/api/v1/push", w.url)
+}
+
+func mustParseInt(t *testing.T, s string) int64 {
+	t.Helper()
+	var v int64
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
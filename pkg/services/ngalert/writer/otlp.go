@@ -0,0 +1,102 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const otlpBackendType = "otlp"
+
+// OTLPWriter writes recorded metrics as OTLP gauges to an OTLP/HTTP metrics endpoint, such as the one of
+// an OpenTelemetry Collector or the OTLP endpoint of Mimir and Loki.
+type OTLPWriter struct {
+	client  *http.Client
+	url     string
+	timeout time.Duration
+	clock   clock.Clock
+	logger  log.Logger
+	metrics *metrics.RemoteWriter
+}
+
+func NewOTLPWriter(
+	settings setting.RecordingRuleSettings,
+	httpClientProvider HttpClientProvider,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*OTLPWriter, error) {
+	if settings.URL == "" {
+		return nil, errors.New("URL is required")
+	}
+	cl, err := newHTTPClient(settings, httpClientProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPWriter{
+		client:  cl,
+		url:     settings.URL,
+		timeout: settings.Timeout,
+		clock:   clock,
+		logger:  l,
+		metrics: metrics,
+	}, nil
+}
+
+// Write writes the given frames to the OTLP endpoint.
+func (w OTLPWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), otlpBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	body, err := otlpRequestFromPoints(name, points).MarshalProto()
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	status, err := sendWriteRequest(ctx, w.client, w.url, w.timeout, "application/x-protobuf", body)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+	w.metrics.WritesTotal.WithLabelValues(append(lvs, fmt.Sprint(status))...).Inc()
+
+	return err
+}
+
+func otlpRequestFromPoints(name string, points []Point) pmetricotlp.ExportRequest {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "grafana")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("grafana-recording-rule")
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	gauge := m.SetEmptyGauge()
+	for _, p := range points {
+		dp := gauge.DataPoints().AppendEmpty()
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(p.Metric.T))
+		dp.SetDoubleValue(p.Metric.V)
+		for k, v := range p.Labels {
+			dp.Attributes().PutStr(k, v)
+		}
+	}
+	return pmetricotlp.NewExportRequestFromMetrics(md)
+}
@@ -0,0 +1,83 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestOTLPWriter_Write(t *testing.T) {
+	status := http.StatusOK
+	var contentType string
+	lastRequest := pmetricotlp.NewExportRequest()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, lastRequest.UnmarshalProto(b))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	writer, err := NewOTLPWriter(setting.RecordingRuleSettings{
+		URL:     srv.URL + "/v1/metrics",
+		Timeout: time.Second,
+	}, httpclient.NewProvider(), clock.New(), log.NewNopLogger(), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.Now()
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericWide, series)
+
+	t.Run("writes a gauge data point for every series", func(t *testing.T) {
+		err := writer.Write(context.Background(), "test", now, frames, 1, map[string]string{"extra": "label"})
+		require.NoError(t, err)
+		require.Equal(t, "application/x-protobuf", contentType)
+
+		md := lastRequest.Metrics()
+		require.Equal(t, 1, md.MetricCount())
+		m := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, "test", m.Name())
+		dps := m.Gauge().DataPoints()
+		require.Equal(t, len(series), dps.Len())
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			require.Equal(t, now.UnixNano(), dp.Timestamp().AsTime().UnixNano())
+			attrs := dp.Attributes().AsRaw()
+			require.Equal(t, "label", attrs["extra"])
+			foo, ok := attrs["foo"].(string)
+			require.True(t, ok)
+			require.Equal(t, extractValue(t, frames, map[string]string{"foo": foo}, data.FrameTypeNumericWide), dp.DoubleValue())
+		}
+	})
+
+	t.Run("rejected write when endpoint returns 400", func(t *testing.T) {
+		status = http.StatusBadRequest
+		t.Cleanup(func() { status = http.StatusOK })
+
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrRejectedWrite)
+	})
+
+	t.Run("unexpected failure when endpoint is rate limiting", func(t *testing.T) {
+		status = http.StatusTooManyRequests
+		t.Cleanup(func() { status = http.StatusOK })
+
+		err := writer.Write(context.Background(), "test", now, frames, 1, nil)
+		require.ErrorIs(t, err, ErrUnexpectedWriteFailure)
+	})
+}
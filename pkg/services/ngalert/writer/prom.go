@@ -123,19 +123,7 @@ func NewPrometheusWriter(
 	l log.Logger,
 	metrics *metrics.RemoteWriter,
 ) (*PrometheusWriter, error) {
-	if err := validateSettings(settings); err != nil {
-		return nil, err
-	}
-
-	headers := make(http.Header)
-	for k, v := range settings.CustomHeaders {
-		headers.Add(k, v)
-	}
-
-	cl, err := httpClientProvider.New(httpclient.Options{
-		BasicAuth: createAuthOpts(settings.BasicAuthUsername, settings.BasicAuthPassword),
-		Header:    headers,
-	})
+	cl, err := newHTTPClient(settings, httpClientProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +148,23 @@ func NewPrometheusWriter(
 	}, nil
 }
 
+// newHTTPClient validates the settings and creates a client for write requests.
+func newHTTPClient(settings setting.RecordingRuleSettings, httpClientProvider HttpClientProvider) (*http.Client, error) {
+	if err := validateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	headers := make(http.Header)
+	for k, v := range settings.CustomHeaders {
+		headers.Add(k, v)
+	}
+
+	return httpClientProvider.New(httpclient.Options{
+		BasicAuth: createAuthOpts(settings.BasicAuthUsername, settings.BasicAuthPassword),
+		Header:    headers,
+	})
+}
+
 func validateSettings(settings setting.RecordingRuleSettings) error {
 	if settings.BasicAuthUsername != "" && settings.BasicAuthPassword == "" {
 		return fmt.Errorf("basic auth password is required if username is set")
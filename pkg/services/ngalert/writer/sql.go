@@ -0,0 +1,201 @@
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/sqltable"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	sqlBackendType = "sql"
+	// sqlMaxReadSamples limits the number of samples read by a query, so that a query of a long time range does not
+	// load the whole table into memory.
+	sqlMaxReadSamples = 100000
+)
+
+// recordedSample is a row of the alert_recorded_sample table.
+type recordedSample struct {
+	ID         int64   `xorm:"pk autoincr 'id'"`
+	OrgID      int64   `xorm:"org_id"`
+	Metric     string  `xorm:"metric"`
+	Labels     string  `xorm:"labels"`
+	RecordedAt int64   `xorm:"recorded_at"`
+	Value      float64 `xorm:"value"`
+}
+
+func (recordedSample) TableName() string {
+	return "alert_recorded_sample"
+}
+
+// SQLWriter stores recorded metrics in the Grafana database. The samples can be queried back with SQLReader, which is what
+// the "-- Grafana --" data source does. Samples older than the retention are deleted when metrics are written.
+// Samples with NaN or infinite values are skipped, because not all databases can store them.
+type SQLWriter struct {
+	db        db.DB
+	retention time.Duration
+	clock     clock.Clock
+	logger    log.Logger
+	metrics   *metrics.RemoteWriter
+	cleaner   *sqltable.Cleaner
+}
+
+func NewSQLWriter(
+	settings setting.RecordingRuleSettings,
+	db db.DB,
+	clock clock.Clock,
+	l log.Logger,
+	metrics *metrics.RemoteWriter,
+) (*SQLWriter, error) {
+	if settings.SQLRetention <= 0 {
+		return nil, errors.New("retention must be greater than 0")
+	}
+	return &SQLWriter{
+		db:        db,
+		retention: settings.SQLRetention,
+		clock:     clock,
+		logger:    l,
+		metrics:   metrics,
+		cleaner:   sqltable.NewCleaner(db, recordedSample{}.TableName(), "recorded_at", settings.SQLRetention, l),
+	}, nil
+}
+
+// Write stores the given frames in the database.
+func (w *SQLWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), sqlBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	rows := make([]recordedSample, 0, len(points))
+	for _, p := range points {
+		if math.IsNaN(p.Metric.V) || math.IsInf(p.Metric.V, 0) {
+			l.Debug("Skipping sample with a value that cannot be stored", "name", name, "value", p.Metric.V)
+			continue
+		}
+		// Keys of maps are sorted when they are marshalled, so the same labels are always stored as the same string.
+		labels, err := json.Marshal(p.Labels)
+		if err != nil {
+			return errors.Join(ErrBadFrame, err)
+		}
+		rows = append(rows, recordedSample{
+			OrgID:      orgID,
+			Metric:     name,
+			Labels:     string(labels),
+			RecordedAt: p.Metric.T.UnixMilli(),
+			Value:      p.Metric.V,
+		})
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	err = sqltable.Insert(ctx, w.db, rows)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+	status := "200"
+	if err != nil {
+		status = "500"
+	}
+	w.metrics.WritesTotal.WithLabelValues(append(lvs, status)...).Inc()
+	if err != nil {
+		return errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+
+	w.cleaner.Cleanup(ctx, w.clock.Now())
+	return nil
+}
+
+// SQLReader reads metrics stored by SQLWriter. It implements the reader of recorded metrics of the "-- Grafana --" data source.
+type SQLReader struct {
+	db db.DB
+	// maxSamples is the maximum number of samples a query can read.
+	maxSamples int
+}
+
+func NewSQLReader(db db.DB) *SQLReader {
+	return &SQLReader{db: db, maxSamples: sqlMaxReadSamples}
+}
+
+// Query returns a frame for every series of the metric whose labels have the given values, with the samples recorded
+// in the time range, ordered by labels. It fails if the metric has more than maxSamples samples in the time range.
+func (r *SQLReader) Query(ctx context.Context, orgID int64, metric string, labels map[string]string, timeRange backend.TimeRange) (data.Frames, error) {
+	if metric == "" {
+		return nil, errors.New("metric is required")
+	}
+	var rows []recordedSample
+	err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND metric = ? AND recorded_at >= ? AND recorded_at <= ?",
+			orgID, metric, timeRange.From.UnixMilli(), timeRange.To.UnixMilli()).
+			Asc("recorded_at").
+			Limit(r.maxSamples + 1).
+			Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > r.maxSamples {
+		return nil, fmt.Errorf("metric %s has more than %d samples in the time range, query a shorter time range", metric, r.maxSamples)
+	}
+
+	seriesByLabels := make(map[string]*data.Frame)
+	for _, row := range rows {
+		frame, ok := seriesByLabels[row.Labels]
+		if !ok {
+			var seriesLabels data.Labels
+			if err := json.Unmarshal([]byte(row.Labels), &seriesLabels); err != nil {
+				return nil, fmt.Errorf("failed to parse labels of sample %d: %w", row.ID, err)
+			}
+			if !matchesLabels(seriesLabels, labels) {
+				seriesByLabels[row.Labels] = nil
+				continue
+			}
+			frame = data.NewFrame(metric,
+				data.NewField(data.TimeSeriesTimeFieldName, nil, []time.Time{}),
+				data.NewField(data.TimeSeriesValueFieldName, seriesLabels, []float64{}),
+			)
+			frame.SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti, TypeVersion: data.FrameTypeVersion{0, 1}})
+			seriesByLabels[row.Labels] = frame
+		}
+		if frame == nil {
+			continue
+		}
+		frame.AppendRow(time.UnixMilli(row.RecordedAt).UTC(), row.Value)
+	}
+
+	keys := make([]string, 0, len(seriesByLabels))
+	for k, frame := range seriesByLabels {
+		if frame != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	frames := make(data.Frames, 0, len(keys))
+	for _, k := range keys {
+		frames = append(frames, seriesByLabels[k])
+	}
+	return frames, nil
+}
+
+func matchesLabels(labels data.Labels, matchers map[string]string) bool {
+	for k, v := range matchers {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
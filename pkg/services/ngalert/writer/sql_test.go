@@ -0,0 +1,100 @@
+package writer
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLWriter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	clk := clock.NewMock()
+	clk.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	writer, err := NewSQLWriter(setting.RecordingRuleSettings{SQLRetention: time.Hour}, sqlStore, clk, log.NewNopLogger(),
+		metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+	reader := NewSQLReader(sqlStore)
+	ctx := context.Background()
+
+	frame := func(values ...float64) data.Frames {
+		f := data.NewFrame("").SetMeta(&data.FrameMeta{Type: data.FrameTypeNumericWide})
+		for i, v := range values {
+			f.Fields = append(f.Fields, data.NewField("", data.Labels{"series": string(rune('a' + i))}, []float64{v}))
+		}
+		return data.Frames{f}
+	}
+
+	start := clk.Now()
+	require.NoError(t, writer.Write(ctx, "test_metric", start, frame(1, 10), 1, map[string]string{"extra": "label"}))
+	require.NoError(t, writer.Write(ctx, "test_metric", start.Add(time.Minute), frame(2, math.NaN()), 1, map[string]string{"extra": "label"}))
+	require.NoError(t, writer.Write(ctx, "test_metric", start, frame(100), 2, nil))
+	require.NoError(t, writer.Write(ctx, "other_metric", start, frame(100), 1, nil))
+
+	t.Run("returns a frame for every series", func(t *testing.T) {
+		frames, err := reader.Query(ctx, 1, "test_metric", nil, backend.TimeRange{From: start, To: start.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, frames, 2)
+
+		require.Equal(t, data.Labels{"extra": "label", "series": "a"}, frames[0].Fields[1].Labels)
+		require.Equal(t, 2, frames[0].Rows())
+		require.Equal(t, []any{start, 1.0}, frames[0].RowCopy(0))
+		require.Equal(t, []any{start.Add(time.Minute), 2.0}, frames[0].RowCopy(1))
+
+		// The NaN sample of the second series is skipped.
+		require.Equal(t, "b", frames[1].Fields[1].Labels["series"])
+		require.Equal(t, 1, frames[1].Rows())
+		require.Equal(t, []any{start, 10.0}, frames[1].RowCopy(0))
+	})
+
+	t.Run("filters by labels and time range", func(t *testing.T) {
+		frames, err := reader.Query(ctx, 1, "test_metric", map[string]string{"series": "a"}, backend.TimeRange{From: start.Add(time.Second), To: start.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		require.Equal(t, []any{start.Add(time.Minute), 2.0}, frames[0].RowCopy(0))
+	})
+
+	t.Run("does not return samples of other organizations", func(t *testing.T) {
+		frames, err := reader.Query(ctx, 3, "test_metric", nil, backend.TimeRange{From: start, To: start.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Empty(t, frames)
+	})
+
+	t.Run("fails when the query reads too many samples", func(t *testing.T) {
+		limited := NewSQLReader(sqlStore)
+		limited.maxSamples = 2
+		_, err := limited.Query(ctx, 1, "test_metric", nil, backend.TimeRange{From: start, To: start.Add(time.Hour)})
+		require.ErrorContains(t, err, "more than 2 samples")
+	})
+
+	t.Run("deletes samples older than retention", func(t *testing.T) {
+		clk.Add(2 * time.Hour)
+		require.NoError(t, writer.Write(ctx, "test_metric", clk.Now(), frame(3), 1, nil))
+
+		frames, err := reader.Query(ctx, 1, "test_metric", nil, backend.TimeRange{From: start, To: clk.Now()})
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, []any{clk.Now(), 3.0}, frames[0].RowCopy(0))
+	})
+}
@@ -42,8 +42,15 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 
 	ns.log.Debug("Sending webhook", "url", webhook.Url, "http method", webhook.HttpMethod)
 
-	if webhook.HttpMethod != http.MethodPost && webhook.HttpMethod != http.MethodPut {
-		return fmt.Errorf("webhook only supports HTTP methods PUT or POST")
+	switch webhook.HttpMethod {
+	case http.MethodPost, http.MethodPut:
+	case http.MethodGet:
+		// GET is used by integrations that look up existing tickets before they create or update one.
+		if webhook.Body != "" {
+			return fmt.Errorf("webhook does not support a body with HTTP method GET")
+		}
+	default:
+		return fmt.Errorf("webhook only supports HTTP methods PUT, POST or GET")
 	}
 
 	request, err := http.NewRequestWithContext(ctx, webhook.HttpMethod, webhook.Url, bytes.NewReader([]byte(webhook.Body)))
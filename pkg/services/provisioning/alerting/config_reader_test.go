@@ -34,6 +34,7 @@ const (
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
+	testFileCorrectProperties_pr        = "./testdata/prometheus_rules/correct-properties"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -160,6 +161,22 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("a file with Prometheus rules should convert them to rule groups", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_pr)
+		require.NoError(t, err)
+		require.Len(t, file[0].Groups, 1)
+		group := file[0].Groups[0]
+		require.Equal(t, "node", group.Title)
+		require.Equal(t, "Prometheus/node", group.FolderFullpath)
+		require.Equal(t, int64(1), group.OrgID)
+		require.Equal(t, int64(30), group.Interval)
+		require.Len(t, group.Rules, 2)
+		require.Equal(t, "HighLoad", group.Rules[0].Title)
+		require.Equal(t, "Load is {{ $value }}", group.Rules[0].Annotations["summary"])
+		require.NotEmpty(t, group.Rules[0].UID)
+		require.Equal(t, "prometheus-uid", group.Rules[0].Data[0].DatasourceUID)
+		require.NotNil(t, group.Rules[1].Record)
+	})
 	t.Run("a rule file with dasboard typo", func(t *testing.T) {
 		ruleFiles, err := configReader.readConfig(ctx, testFileDasboardTypoSupport)
 		require.NoError(t, err)
@@ -189,3 +206,15 @@ func TestConfigReader(t *testing.T) {
 		})
 	})
 }
+
+func TestReadTemplates(t *testing.T) {
+	templates, err := ReadTemplates(context.Background(), testFileMultipleTs, 1)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, "my_second_template", templates[0].Name)
+
+	templates, err = ReadTemplates(context.Background(), testFileMultipleTs, 1337)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, "my_first_template", templates[0].Name)
+}
@@ -0,0 +1,82 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const defaultPrometheusRulesInterval = time.Minute
+
+// PrometheusRulesV1 is a set of Prometheus rule groups that are provisioned as Grafana-managed rules.
+// The groups use the Prometheus rule file format and are not interpolated, because Prometheus templates use the same syntax
+// as environment variables.
+type PrometheusRulesV1 struct {
+	OrgID          values.Int64Value          `json:"orgId" yaml:"orgId"`
+	Folder         values.StringValue         `json:"folder" yaml:"folder"`
+	DatasourceUID  values.StringValue         `json:"datasourceUid" yaml:"datasourceUid"`
+	DatasourceType values.StringValue         `json:"datasourceType" yaml:"datasourceType"`
+	Interval       values.StringValue         `json:"interval" yaml:"interval"`
+	Groups         []prom.PrometheusRuleGroup `json:"groups" yaml:"groups"`
+}
+
+// MapToModel converts the Prometheus rule groups to Grafana rule groups. The folder path is used instead of the folder UID to derive
+// UIDs of the rules, because the folder might not exist yet.
+func (rulesV1 *PrometheusRulesV1) MapToModel() ([]models.AlertRuleGroupWithFolderFullpath, error) {
+	orgID := rulesV1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	folder := rulesV1.Folder.Value()
+	if strings.TrimSpace(folder) == "" {
+		return nil, errors.New("Prometheus rules have no folder set")
+	}
+	dsType := rulesV1.DatasourceType.Value()
+	if dsType == "" {
+		dsType = datasources.DS_PROMETHEUS
+	}
+	interval := defaultPrometheusRulesInterval
+	if rulesV1.Interval.Value() != "" {
+		d, err := model.ParseDuration(rulesV1.Interval.Value())
+		if err != nil {
+			return nil, err
+		}
+		interval = time.Duration(d)
+	}
+
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:   rulesV1.DatasourceUID.Value(),
+		DatasourceType:  dsType,
+		DefaultInterval: interval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	groups, err := converter.PrometheusRulesToGrafana(orgID, folder, rulesV1.Groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Prometheus rules: %w", err)
+	}
+
+	result := make([]models.AlertRuleGroupWithFolderFullpath, 0, len(groups))
+	for i := range groups {
+		g := &groups[i]
+		g.FolderUID = ""
+		for j := range g.Rules {
+			g.Rules[j].NamespaceUID = ""
+		}
+		result = append(result, models.AlertRuleGroupWithFolderFullpath{
+			AlertRuleGroup: g,
+			OrgID:          orgID,
+			FolderFullpath: folder,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+)
+
+func TestPrometheusRulesV1(t *testing.T) {
+	validRules := func() PrometheusRulesV1 {
+		return PrometheusRulesV1{
+			Folder:        stringToStringValue("my_folder"),
+			DatasourceUID: stringToStringValue("prometheus-uid"),
+			Groups: []prom.PrometheusRuleGroup{
+				{
+					Name:  "my_group",
+					Rules: []prom.PrometheusRule{{Alert: "my_alert", Expr: "up == 0"}},
+				},
+			},
+		}
+	}
+
+	t.Run("valid Prometheus rules should be converted with defaults", func(t *testing.T) {
+		rules := validRules()
+		groups, err := rules.MapToModel()
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, int64(1), groups[0].OrgID)
+		require.Equal(t, "my_folder", groups[0].FolderFullpath)
+		require.Equal(t, int64(60), groups[0].Interval)
+		require.Empty(t, groups[0].FolderUID)
+		require.Empty(t, groups[0].Rules[0].NamespaceUID)
+	})
+	t.Run("rule UIDs should not change between runs", func(t *testing.T) {
+		rules := validRules()
+		first, err := rules.MapToModel()
+		require.NoError(t, err)
+		second, err := rules.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, first[0].Rules[0].UID, second[0].Rules[0].UID)
+	})
+	t.Run("the interval should be used for groups without one", func(t *testing.T) {
+		rules := validRules()
+		rules.Interval = stringToStringValue("2m")
+		groups, err := rules.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, int64(120), groups[0].Interval)
+	})
+	t.Run("Prometheus rules without a folder should error", func(t *testing.T) {
+		rules := validRules()
+		rules.Folder = stringToStringValue("")
+		_, err := rules.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("Prometheus rules without a data source should error", func(t *testing.T) {
+		rules := validRules()
+		rules.DatasourceUID = stringToStringValue("")
+		_, err := rules.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("Prometheus rules with an unsupported data source type should error", func(t *testing.T) {
+		rules := validRules()
+		rules.DatasourceType = stringToStringValue("graphite")
+		_, err := rules.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("invalid Prometheus rules should error", func(t *testing.T) {
+		rules := validRules()
+		rules.Groups[0].Rules[0].Expr = "sum(up"
+		_, err := rules.MapToModel()
+		require.ErrorIs(t, err, prom.ErrInvalidPrometheusRule)
+	})
+}
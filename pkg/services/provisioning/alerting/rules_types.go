@@ -77,6 +77,8 @@ type AlertRuleV1 struct {
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record               *RecordV1               `json:"record" yaml:"record"`
+	Dependencies         []RuleDependencyV1      `json:"dependencies" yaml:"dependencies"`
+	MaxSeries            values.Int64Value       `json:"maxSeries" yaml:"maxSeries"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -162,6 +164,17 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.Record = &record
 	}
+	for _, dependencyV1 := range rule.Dependencies {
+		dependency, err := dependencyV1.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.Dependencies = append(alertRule.Dependencies, dependency)
+	}
+	alertRule.MaxSeries = rule.MaxSeries.Value()
+	if alertRule.MaxSeries < 0 {
+		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: maxSeries must be greater than or equal to 0", alertRule.Title)
+	}
 	return alertRule, nil
 }
 
@@ -169,6 +182,7 @@ type QueryV1 struct {
 	RefID             values.StringValue       `json:"refId" yaml:"refId"`
 	QueryType         values.StringValue       `json:"queryType" yaml:"queryType"`
 	RelativeTimeRange models.RelativeTimeRange `json:"relativeTimeRange" yaml:"relativeTimeRange"`
+	TimeShift         models.Duration          `json:"timeShift,omitempty" yaml:"timeShift,omitempty"`
 	DatasourceUID     values.StringValue       `json:"datasourceUid" yaml:"datasourceUid"`
 	Model             values.JSONValue         `json:"model" yaml:"model"`
 }
@@ -198,6 +212,7 @@ func (queryV1 *QueryV1) mapToModel() (models.AlertQuery, error) {
 		QueryType:         queryV1.QueryType.Value(),
 		DatasourceUID:     queryV1.DatasourceUID.Value(),
 		RelativeTimeRange: queryV1.RelativeTimeRange,
+		TimeShift:         queryV1.TimeShift,
 		Model:             rawMessage,
 	}, nil
 }
@@ -281,3 +296,19 @@ func (record *RecordV1) mapToModel() (models.Record, error) {
 		From:   record.From.Value(),
 	}, nil
 }
+
+type RuleDependencyV1 struct {
+	RuleUID values.StringValue `json:"rule_uid" yaml:"rule_uid"`
+	State   values.StringValue `json:"state" yaml:"state"`
+}
+
+func (dependency *RuleDependencyV1) mapToModel() (models.RuleDependency, error) {
+	state, err := models.RuleDependencyStateFromString(strings.TrimSpace(dependency.State.Value()))
+	if err != nil {
+		return models.RuleDependency{}, err
+	}
+	return models.RuleDependency{
+		RuleUID: dependency.RuleUID.Value(),
+		State:   state,
+	}, nil
+}
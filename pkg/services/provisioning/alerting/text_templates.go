@@ -53,3 +53,22 @@ func (c *defaultTextTemplateProvisioner) Unprovision(ctx context.Context,
 	}
 	return nil
 }
+
+// ReadTemplates returns the templates of the organization that are defined in the alerting provisioning files of the
+// directory, in the order of the files.
+func ReadTemplates(ctx context.Context, path string, orgID int64) ([]definitions.NotificationTemplate, error) {
+	cfgReader := newRulesConfigReader(log.New("provisioning.alerting"))
+	files, err := cfgReader.readConfig(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var templates []definitions.NotificationTemplate
+	for _, file := range files {
+		for _, template := range file.Templates {
+			if template.OrgID == orgID {
+				templates = append(templates, template.Data)
+			}
+		}
+	}
+	return templates, nil
+}
@@ -287,6 +287,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		configStore,
 		ps.alertingStore,
 		ps.alertingStore,
+		ps.alertingStore,
 		ps.secretService,
 		ps.SQLStore,
 		ps.log,
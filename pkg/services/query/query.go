@@ -200,6 +200,7 @@ func buildErrorResponses(err error, queries []*simplejson.Json) splitResponse {
 }
 
 // handleExpressions handles POST /api/ds/query when there is an expression.
+// The timeShift of the queries is only applied here, requests without expressions ignore it.
 func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Requester, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	exprReq := expr.Request{
 		Queries: []expr.Query{},
@@ -219,6 +220,20 @@ func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Reque
 			})
 		}
 
+		var timeShift time.Duration
+		if shift := pq.rawQuery.Get("timeShift").MustString(""); shift != "" {
+			var err error
+			timeShift, err = gtime.ParseDuration(shift)
+			if err != nil || timeShift < 0 {
+				return nil, ErrInvalidTimeShift.Build(errutil.TemplateData{
+					Public: map[string]any{
+						"RefId": pq.query.RefID,
+					},
+					Error: fmt.Errorf("%q is not a positive duration", shift),
+				})
+			}
+		}
+
 		exprReq.Queries = append(exprReq.Queries, expr.Query{
 			JSON:          pq.query.JSON,
 			Interval:      pq.query.Interval,
@@ -230,6 +245,7 @@ func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Reque
 				From: pq.query.TimeRange.From,
 				To:   pq.query.TimeRange.To,
 			},
+			TimeShift: timeShift,
 		})
 	}
 
@@ -141,4 +141,14 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	externalsession.AddMigration(mg)
 
 	accesscontrol.AddReceiverCreateScopeMigration(mg)
+
+	ualert.AddRuleDependenciesColumns(mg)
+
+	ualert.AddRecordedSampleTable(mg)
+
+	ualert.AddStateHistoryTable(mg)
+
+	ualert.AddNotificationQueueTable(mg)
+
+	ualert.AddRuleMaxSeriesColumns(mg)
 }
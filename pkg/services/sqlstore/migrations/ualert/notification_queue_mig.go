@@ -0,0 +1,33 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddNotificationQueueTable adds the table that stores notifications that contact points failed to deliver.
+func AddNotificationQueueTable(mg *migrator.Migrator) {
+	queueTable := migrator.Table{
+		Name: "alert_notification_queue",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "payload", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "attempts", Type: migrator.DB_Int, Nullable: false},
+			{Name: "last_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "next_attempt_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "status", "next_attempt_at"}},
+			{Cols: []string{"org_id", "receiver", "status"}},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_queue table", migrator.NewAddTableMigration(queueTable))
+	mg.AddMigration("add index on org_id, status and next_attempt_at to alert_notification_queue table", migrator.NewAddIndexMigration(queueTable, queueTable.Indices[0]))
+	mg.AddMigration("add index on org_id, receiver and status to alert_notification_queue table", migrator.NewAddIndexMigration(queueTable, queueTable.Indices[1]))
+}
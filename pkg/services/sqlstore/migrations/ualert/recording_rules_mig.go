@@ -16,3 +16,26 @@ func AddRecordingRuleColumns(mg *migrator.Migrator) {
 		Nullable: true,
 	}))
 }
+
+// AddRecordedSampleTable adds the table that stores metrics of recording rules when they are written to the Grafana database.
+func AddRecordedSampleTable(mg *migrator.Migrator) {
+	sampleTable := migrator.Table{
+		Name: "alert_recorded_sample",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "metric", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "recorded_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "value", Type: migrator.DB_Double, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "metric", "recorded_at"}},
+			{Cols: []string{"recorded_at"}},
+		},
+	}
+
+	mg.AddMigration("create alert_recorded_sample table", migrator.NewAddTableMigration(sampleTable))
+	mg.AddMigration("add index on org_id, metric and recorded_at to alert_recorded_sample table", migrator.NewAddIndexMigration(sampleTable, sampleTable.Indices[0]))
+	mg.AddMigration("add index on recorded_at to alert_recorded_sample table", migrator.NewAddIndexMigration(sampleTable, sampleTable.Indices[1]))
+}
@@ -0,0 +1,41 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddStateHistoryTable adds the table that stores alert state transitions when state history uses the sql backend.
+func AddStateHistoryTable(mg *migrator.Migrator) {
+	historyTable := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_condition", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: true},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: true},
+			{Name: "fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: false},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "state_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "state_error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created"}},
+			{Cols: []string{"org_id", "rule_uid", "created"}},
+			{Cols: []string{"org_id", "dashboard_uid", "panel_id", "created"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(historyTable))
+	mg.AddMigration("add index on org_id and created to alert_state_history table", migrator.NewAddIndexMigration(historyTable, historyTable.Indices[0]))
+	mg.AddMigration("add index on org_id, rule_uid and created to alert_state_history table", migrator.NewAddIndexMigration(historyTable, historyTable.Indices[1]))
+	mg.AddMigration("add index on org_id, dashboard_uid, panel_id and created to alert_state_history table", migrator.NewAddIndexMigration(historyTable, historyTable.Indices[2]))
+	mg.AddMigration("add index on created to alert_state_history table", migrator.NewAddIndexMigration(historyTable, historyTable.Indices[3]))
+}
@@ -61,11 +61,19 @@ const (
 	// with intervals that are not exactly divided by this number not to be evaluated
 	SchedulerBaseInterval = 10 * time.Second
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
-	DefaultRuleEvaluationInterval  = SchedulerBaseInterval * 6 // == 60 seconds
-	stateHistoryDefaultEnabled     = true
-	lokiDefaultMaxQueryLength      = 721 * time.Hour // 30d1h, matches the default value in Loki
-	defaultRecordingRequestTimeout = 10 * time.Second
-	lokiDefaultMaxQuerySize        = 65536 // 64kb
+	DefaultRuleEvaluationInterval   = SchedulerBaseInterval * 6 // == 60 seconds
+	stateHistoryDefaultEnabled      = true
+	lokiDefaultMaxQueryLength       = 721 * time.Hour // 30d1h, matches the default value in Loki
+	defaultRecordingRequestTimeout  = 10 * time.Second
+	defaultRecordingSQLRetention    = 7 * 24 * time.Hour
+	stateHistoryDefaultSQLRetention = 30 * 24 * time.Hour
+	lokiDefaultMaxQuerySize         = 65536 // 64kb
+
+	notificationQueueDefaultMaxAttempts         = 10
+	notificationQueueDefaultInitialBackoff      = 30 * time.Second
+	notificationQueueDefaultMaxBackoff          = 30 * time.Minute
+	notificationQueueDefaultPollInterval        = 10 * time.Second
+	notificationQueueDefaultDeadLetterRetention = 7 * 24 * time.Hour
 )
 
 type UnifiedAlertingSettings struct {
@@ -80,6 +88,7 @@ type UnifiedAlertingSettings struct {
 	HAGossipInterval                time.Duration
 	HAReconnectTimeout              time.Duration
 	HAPushPullInterval              time.Duration
+	HARuleShardingEnabled           bool
 	HALabel                         string
 	HARedisClusterModeEnabled       bool
 	HARedisAddr                     string
@@ -112,6 +121,7 @@ type UnifiedAlertingSettings struct {
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	RecordingRules                RecordingRuleSettings
+	NotificationQueue             UnifiedAlertingNotificationQueueSettings
 
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency   int
@@ -132,11 +142,13 @@ type UnifiedAlertingSettings struct {
 
 type RecordingRuleSettings struct {
 	Enabled           bool
+	Backend           string
 	URL               string
 	BasicAuthUsername string
 	BasicAuthPassword string
 	CustomHeaders     map[string]string
 	Timeout           time.Duration
+	SQLRetention      time.Duration
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -176,6 +188,32 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLRetention is how long the sql backend keeps state history. 0 keeps it forever.
+	SQLRetention time.Duration
+}
+
+// UnifiedAlertingNotificationQueueSettings configures the queue that persists notifications that contact points failed
+// to deliver and retries them.
+type UnifiedAlertingNotificationQueueSettings struct {
+	Enabled bool
+	// Integrations are the types of integrations whose failed notifications are queued, for example webhook or slack.
+	Integrations map[string]struct{}
+	// MaxAttempts is the number of retries after which a notification is moved to the dead-letter list.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	PollInterval   time.Duration
+	// DeadLetterRetention is how long notifications are kept in the dead-letter list. 0 keeps them forever.
+	DeadLetterRetention time.Duration
+}
+
+// IsIntegrationQueued returns true if failed notifications of the integration type are queued.
+func (s UnifiedAlertingNotificationQueueSettings) IsIntegrationQueued(integrationType string) bool {
+	if !s.Enabled {
+		return false
+	}
+	_, ok := s.Integrations[integrationType]
+	return ok
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -184,6 +222,17 @@ func (u *UnifiedAlertingSettings) IsEnabled() bool {
 	return u.Enabled == nil || *u.Enabled
 }
 
+// IsHAEnabled returns true if high availability is configured, either with a gossip cluster or with Redis.
+func (u *UnifiedAlertingSettings) IsHAEnabled() bool {
+	return len(u.HAPeers) > 0 || u.HARedisAddr != ""
+}
+
+// IsRuleShardingEnabled returns true if the evaluation of alert rules is sharded across the instances of the HA
+// cluster. Sharding requires high availability to be configured.
+func (u *UnifiedAlertingSettings) IsRuleShardingEnabled() bool {
+	return u.HARuleShardingEnabled && u.IsHAEnabled()
+}
+
 // IsReservedLabelDisabled returns true if UnifiedAlertingReservedLabelSettings.DisabledLabels contains the given reserved label.
 func (u *UnifiedAlertingReservedLabelSettings) IsReservedLabelDisabled(label string) bool {
 	_, ok := u.DisabledLabels[label]
@@ -265,6 +314,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HAListenAddr = ua.Key("ha_listen_address").MustString(alertmanagerDefaultClusterAddr)
 	uaCfg.HAAdvertiseAddr = ua.Key("ha_advertise_address").MustString("")
 	uaCfg.HALabel = ua.Key("ha_label").MustString("")
+	uaCfg.HARuleShardingEnabled = ua.Key("ha_rule_sharding_enabled").MustBool(false)
 	uaCfg.HARedisClusterModeEnabled = ua.Key("ha_redis_cluster_mode_enabled").MustBool(false)
 	uaCfg.HARedisAddr = ua.Key("ha_redis_address").MustString("")
 	uaCfg.HARedisPeerName = ua.Key("ha_redis_peer_name").MustString("")
@@ -429,16 +479,19 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		SQLRetention:          stateHistory.Key("sql_retention").MustDuration(stateHistoryDefaultSQLRetention),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
 	rr := iniFile.Section("recording_rules")
 	uaCfgRecordingRules := RecordingRuleSettings{
 		Enabled:           rr.Key("enabled").MustBool(false),
+		Backend:           rr.Key("backend").MustString("prometheus"),
 		URL:               rr.Key("url").MustString(""),
 		BasicAuthUsername: rr.Key("basic_auth_username").MustString(""),
 		BasicAuthPassword: rr.Key("basic_auth_password").MustString(""),
 		Timeout:           rr.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+		SQLRetention:      rr.Key("sql_retention").MustDuration(defaultRecordingSQLRetention),
 	}
 
 	rrHeaders := iniFile.Section("recording_rules.custom_headers")
@@ -450,6 +503,30 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfg.RecordingRules = uaCfgRecordingRules
 
+	nq := iniFile.Section("unified_alerting.notification_queue")
+	uaCfgNotificationQueue := UnifiedAlertingNotificationQueueSettings{
+		Enabled:             nq.Key("enabled").MustBool(false),
+		Integrations:        make(map[string]struct{}),
+		MaxAttempts:         nq.Key("max_attempts").MustInt(notificationQueueDefaultMaxAttempts),
+		InitialBackoff:      nq.Key("initial_backoff").MustDuration(notificationQueueDefaultInitialBackoff),
+		MaxBackoff:          nq.Key("max_backoff").MustDuration(notificationQueueDefaultMaxBackoff),
+		PollInterval:        nq.Key("poll_interval").MustDuration(notificationQueueDefaultPollInterval),
+		DeadLetterRetention: nq.Key("dead_letter_retention").MustDuration(notificationQueueDefaultDeadLetterRetention),
+	}
+	for _, integration := range util.SplitString(nq.Key("integrations").MustString("webhook,slack,pagerduty")) {
+		uaCfgNotificationQueue.Integrations[integration] = struct{}{}
+	}
+	if uaCfgNotificationQueue.MaxAttempts < 1 {
+		return fmt.Errorf("value of setting 'max_attempts' in section 'unified_alerting.notification_queue' must be at least 1")
+	}
+	if uaCfgNotificationQueue.InitialBackoff <= 0 || uaCfgNotificationQueue.MaxBackoff < uaCfgNotificationQueue.InitialBackoff {
+		return fmt.Errorf("value of setting 'initial_backoff' in section 'unified_alerting.notification_queue' must be positive and not greater than 'max_backoff'")
+	}
+	if uaCfgNotificationQueue.PollInterval <= 0 {
+		return fmt.Errorf("value of setting 'poll_interval' in section 'unified_alerting.notification_queue' must be positive")
+	}
+	uaCfg.NotificationQueue = uaCfgNotificationQueue
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	uaCfg.StatePeriodicSaveInterval, err = gtime.ParseDuration(valueAsString(ua, "state_periodic_save_interval", (time.Minute * 5).String()))
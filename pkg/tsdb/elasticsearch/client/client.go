@@ -51,6 +51,7 @@ type Client interface {
 	GetConfiguredFields() ConfiguredFields
 	ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error)
 	MultiSearch() *MultiSearchRequestBuilder
+	ExecuteColumnarQuery(r *ColumnarQueryRequest) (*ColumnarQueryResponse, error)
 }
 
 // NewClient creates a new elasticsearch client
@@ -97,7 +98,7 @@ func (c *baseClientImpl) executeBatchRequest(uriPath, uriQuery string, requests
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(http.MethodPost, uriPath, uriQuery, bytes)
+	return c.executeRequest(http.MethodPost, uriPath, uriQuery, "application/x-ndjson", bytes)
 }
 
 func (c *baseClientImpl) encodeBatchRequests(requests []*multiRequest) ([]byte, error) {
@@ -129,7 +130,7 @@ func (c *baseClientImpl) encodeBatchRequests(requests []*multiRequest) ([]byte,
 	return payload.Bytes(), nil
 }
 
-func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body []byte) (*http.Response, error) {
+func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery, contentType string, body []byte) (*http.Response, error) {
 	c.logger.Debug("Sending request to Elasticsearch", "url", c.ds.URL)
 	u, err := url.Parse(c.ds.URL)
 	if err != nil {
@@ -148,7 +149,7 @@ func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body [
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Content-Type", contentType)
 
 	//nolint:bodyclose
 	resp, err := c.ds.HTTPClient.Do(req)
@@ -264,3 +265,58 @@ func (c *baseClientImpl) getMultiSearchQueryParameters() string {
 func (c *baseClientImpl) MultiSearch() *MultiSearchRequestBuilder {
 	return NewMultiSearchRequestBuilder()
 }
+
+// ExecuteColumnarQuery executes an ES|QL or PPL query. These queries are sent on their own as they cannot be part of a
+// multi search request.
+func (c *baseClientImpl) ExecuteColumnarQuery(r *ColumnarQueryRequest) (*ColumnarQueryResponse, error) {
+	var err error
+	uriPath, uriQuery := "_query", "format=json"
+	if r.Language == QueryLanguagePPL {
+		uriPath, uriQuery = "_plugins/_ppl", ""
+	}
+	_, span := tracing.DefaultTracer().Start(c.ctx, "datasource.elasticsearch.queryData.executeColumnarQuery", trace.WithAttributes(
+		attribute.String("language", string(r.Language)),
+		attribute.String("url", c.ds.URL),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	body, err := json.Marshal(map[string]string{"query": r.Query})
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	clientRes, err := c.executeRequest(http.MethodPost, uriPath, uriQuery, "application/json", body)
+	if err != nil {
+		status := "error"
+		if errors.Is(err, context.Canceled) {
+			status = "cancelled"
+		}
+		c.logger.Error("Error received from Elasticsearch", "error", err, "status", status, "duration", time.Since(start), "stage", StageDatabaseRequest, "language", r.Language)
+		return nil, err
+	}
+	res := clientRes
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	c.logger.Info("Response received from Elasticsearch", "status", "ok", "statusCode", res.StatusCode, "contentLength", res.ContentLength, "duration", time.Since(start), "stage", StageDatabaseRequest, "language", r.Language)
+
+	var cqr ColumnarQueryResponse
+	err = json.NewDecoder(res.Body).Decode(&cqr)
+	if err != nil {
+		c.logger.Error("Failed to decode response from Elasticsearch", "error", err, "language", r.Language)
+		return nil, err
+	}
+	cqr.Status = res.StatusCode
+
+	return &cqr, nil
+}
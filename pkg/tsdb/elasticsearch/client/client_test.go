@@ -3,6 +3,7 @@ package es
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -183,6 +184,58 @@ func TestClient_ExecuteMultisearch(t *testing.T) {
 	})
 }
 
+func TestClient_ExecuteColumnarQuery(t *testing.T) {
+	var request *http.Request
+	var requestBody []byte
+	var responseBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		request = r
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requestBody = buf
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, err = rw.Write([]byte(responseBody))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	ds := DatasourceInfo{
+		URL:        ts.URL,
+		HTTPClient: ts.Client(),
+		Database:   "logs",
+	}
+	c, err := NewClient(context.Background(), &ds, log.New())
+	require.NoError(t, err)
+
+	t.Run("should send ES|QL queries to the query endpoint", func(t *testing.T) {
+		responseBody = `{"columns": [{"name": "count", "type": "long"}], "values": [[9007199254740993]]}`
+		res, err := c.ExecuteColumnarQuery(&ColumnarQueryRequest{Language: QueryLanguageESQL, Query: "FROM logs | STATS count = COUNT(*)"})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.MethodPost, request.Method)
+		assert.Equal(t, "/_query", request.URL.Path)
+		assert.Equal(t, "format=json", request.URL.RawQuery)
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		assert.JSONEq(t, `{"query": "FROM logs | STATS count = COUNT(*)"}`, string(requestBody))
+
+		assert.Equal(t, 200, res.Status)
+		assert.Equal(t, []ColumnarQueryColumn{{Name: "count", Type: "long"}}, res.Columns)
+		assert.Equal(t, [][]any{{json.Number("9007199254740993")}}, res.Values)
+	})
+
+	t.Run("should send PPL queries to the PPL endpoint", func(t *testing.T) {
+		responseBody = `{"schema": [{"name": "host", "type": "string"}], "datarows": [["a"]], "total": 1, "size": 1}`
+		res, err := c.ExecuteColumnarQuery(&ColumnarQueryRequest{Language: QueryLanguagePPL, Query: "source=logs | fields host"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/_plugins/_ppl", request.URL.Path)
+		assert.Empty(t, request.URL.RawQuery)
+		assert.Equal(t, []ColumnarQueryColumn{{Name: "host", Type: "string"}}, res.Columns)
+		assert.Equal(t, [][]any{{"a"}}, res.Values)
+	})
+}
+
 func TestClient_Index(t *testing.T) {
 	tt := []struct {
 		name                string
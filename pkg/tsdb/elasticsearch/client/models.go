@@ -1,6 +1,7 @@
 package es
 
 import (
+	"bytes"
 	"encoding/json"
 	"time"
 
@@ -67,6 +68,59 @@ type MultiSearchResponse struct {
 	Responses []*SearchResponse `json:"responses"`
 }
 
+// QueryLanguage represents a piped query language whose results are returned as columns
+type QueryLanguage string
+
+const (
+	// QueryLanguageESQL represents the Elasticsearch Query Language (ES|QL)
+	QueryLanguageESQL QueryLanguage = "esql"
+	// QueryLanguagePPL represents the OpenSearch Piped Processing Language (PPL)
+	QueryLanguagePPL QueryLanguage = "ppl"
+)
+
+// ColumnarQueryRequest represents an ES|QL or PPL query request
+type ColumnarQueryRequest struct {
+	Language QueryLanguage
+	Query    string
+}
+
+// ColumnarQueryColumn represents a column of an ES|QL or PPL query response
+type ColumnarQueryColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ColumnarQueryResponse represents an ES|QL or PPL query response. ES|QL returns columns and values while PPL returns
+// the same data as schema and datarows.
+type ColumnarQueryResponse struct {
+	Status  int                    `json:"-"`
+	Columns []ColumnarQueryColumn  `json:"columns"`
+	Values  [][]any                `json:"values"`
+	Error   map[string]interface{} `json:"error"`
+}
+
+// UnmarshalJSON decodes both ES|QL and PPL responses.
+func (r *ColumnarQueryResponse) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Columns  []ColumnarQueryColumn  `json:"columns"`
+		Values   [][]any                `json:"values"`
+		Schema   []ColumnarQueryColumn  `json:"schema"`
+		DataRows [][]any                `json:"datarows"`
+		Error    map[string]interface{} `json:"error"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	// Numbers are kept as they are sent so that the precision of long values is not lost.
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	r.Columns, r.Values, r.Error = raw.Columns, raw.Values, raw.Error
+	if raw.Schema != nil {
+		r.Columns, r.Values = raw.Schema, raw.DataRows
+	}
+	return nil
+}
+
 // Query represents a query
 type Query struct {
 	Bool *BoolQuery `json:"bool"`
@@ -245,6 +299,14 @@ type TermsAggregation struct {
 	Missing     *string                `json:"missing,omitempty"`
 }
 
+// CompositeAggregation represents a composite aggregation, whose buckets are paginated with the after key of the
+// previous page
+type CompositeAggregation struct {
+	Size    int                      `json:"size"`
+	Sources []map[string]interface{} `json:"sources"`
+	After   map[string]interface{}   `json:"after,omitempty"`
+}
+
 // NestedAggregation represents a nested aggregation
 type NestedAggregation struct {
 	Path string `json:"path"`
@@ -0,0 +1,325 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental/errorsource"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const (
+	// esqlQueryType is the query type of raw ES|QL queries
+	esqlQueryType = "esql"
+	// pplQueryType is the query type of raw OpenSearch PPL queries
+	pplQueryType = "ppl"
+)
+
+// columnarTimeTypes are the column types of ES|QL and PPL responses whose values are converted to time.
+var columnarTimeTypes = map[string]bool{
+	"date":       true,
+	"date_nanos": true,
+	"timestamp":  true,
+}
+
+// columnarIntTypes are the column types of ES|QL and PPL responses whose values are converted to int64.
+var columnarIntTypes = map[string]bool{
+	"byte":            true,
+	"short":           true,
+	"integer":         true,
+	"long":            true,
+	"counter_integer": true,
+	"counter_long":    true,
+}
+
+// columnarFloatTypes are the column types of ES|QL and PPL responses whose values are converted to float64.
+var columnarFloatTypes = map[string]bool{
+	"double":         true,
+	"float":          true,
+	"half_float":     true,
+	"scaled_float":   true,
+	"unsigned_long":  true,
+	"counter_double": true,
+}
+
+// columnarTimeLayouts are the layouts of the time values of ES|QL (RFC 3339) and PPL responses.
+var columnarTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func splitColumnarQueries(queries []backend.DataQuery) ([]backend.DataQuery, []backend.DataQuery) {
+	var columnarQueries, dataQueries []backend.DataQuery
+	for _, q := range queries {
+		if q.QueryType == esqlQueryType || q.QueryType == pplQueryType {
+			columnarQueries = append(columnarQueries, q)
+			continue
+		}
+		dataQueries = append(dataQueries, q)
+	}
+	return columnarQueries, dataQueries
+}
+
+// executeColumnarQuery executes a raw ES|QL or PPL query and converts its columns to the fields of a single frame.
+func (e *elasticsearchDataQuery) executeColumnarQuery(q backend.DataQuery) backend.DataResponse {
+	model, err := simplejson.NewJson(q.JSON)
+	if err != nil {
+		return errorsource.Response(errorsource.PluginError(err, false))
+	}
+	rawQuery := model.Get("query").MustString()
+	if strings.TrimSpace(rawQuery) == "" {
+		return errorsource.Response(errorsource.DownstreamError(errors.New("received invalid query. query is empty"), false))
+	}
+
+	interval := q.Interval
+	if interval <= 0 {
+		interval = time.Duration(model.Get("intervalMs").MustInt64(0)) * time.Millisecond
+	}
+	timeField := e.client.GetConfiguredFields().TimeField
+	language := es.QueryLanguage(q.QueryType)
+	query := interpolateColumnarQuery(rawQuery, language, timeField, q.TimeRange, interval)
+
+	res, err := e.client.ExecuteColumnarQuery(&es.ColumnarQueryRequest{Language: language, Query: query})
+	if err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			err = errorsource.DownstreamError(err, false)
+		}
+		return errorsource.Response(err)
+	}
+	if res.Status >= 400 || res.Error != nil {
+		errWithSource := errorsource.SourceError(backend.ErrorSourceFromHTTPStatus(res.Status), errors.New(columnarErrorReason(res)), false)
+		return errorsource.Response(errWithSource)
+	}
+
+	frame, err := columnarResponseToFrame(res, timeField)
+	if err != nil {
+		e.logger.Error("Failed to convert columnar response", "error", err, "queryType", q.QueryType, "stage", es.StageParseResponse)
+		return errorsource.Response(errorsource.PluginError(err, false))
+	}
+	frame.RefID = q.RefID
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	frame.Meta.ExecutedQueryString = query
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+// interpolateColumnarQuery replaces the time range and interval macros of a raw ES|QL or PPL query with the literals
+// of the language:
+//   - $__timeFilter is replaced with a condition on the configured time field
+//   - $__timeFrom and $__timeTo are replaced with the start and end of the time range
+//   - $__interval_ms and $__interval are replaced with the interval as milliseconds and as a time span
+func interpolateColumnarQuery(query string, language es.QueryLanguage, timeField string, timeRange backend.TimeRange, interval time.Duration) string {
+	var from, to, span string
+	if language == es.QueryLanguagePPL {
+		from = "'" + timeRange.From.UTC().Format("2006-01-02 15:04:05.000") + "'"
+		to = "'" + timeRange.To.UTC().Format("2006-01-02 15:04:05.000") + "'"
+		span = fmt.Sprintf("%dms", interval.Milliseconds())
+	} else {
+		from = `TO_DATETIME("` + timeRange.From.UTC().Format("2006-01-02T15:04:05.000Z") + `")`
+		to = `TO_DATETIME("` + timeRange.To.UTC().Format("2006-01-02T15:04:05.000Z") + `")`
+		span = fmt.Sprintf("%d milliseconds", interval.Milliseconds())
+	}
+	field := "`" + timeField + "`"
+	timeFilter := fmt.Sprintf("%s >= %s AND %s <= %s", field, from, field, to)
+
+	return strings.NewReplacer(
+		"$__timeFilter", timeFilter,
+		"$__timeFrom", from,
+		"$__timeTo", to,
+		"$__interval_ms", strconv.FormatInt(interval.Milliseconds(), 10),
+		"$__interval", span,
+	).Replace(query)
+}
+
+func columnarErrorReason(res *es.ColumnarQueryResponse) string {
+	if reason, ok := res.Error["reason"].(string); ok && reason != "" {
+		return reason
+	}
+	if res.Error != nil {
+		if b, err := json.Marshal(res.Error); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("unexpected status code: %d", res.Status)
+}
+
+// columnarResponseToFrame converts the columns of an ES|QL or PPL response to the fields of a frame. The time field of
+// the frame is the configured time field if it is returned, otherwise the first time column. Responses with a time
+// field and numeric fields are returned as time series: rows are sorted by time and long frames are converted to wide.
+func columnarResponseToFrame(res *es.ColumnarQueryResponse, configuredTimeField string) (*data.Frame, error) {
+	timeIndex := -1
+	fields := make([]*data.Field, 0, len(res.Columns))
+	hasNumbers := false
+	for i, col := range res.Columns {
+		field, err := columnarField(col, res.Values, i)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+
+		switch {
+		case columnarTimeTypes[col.Type]:
+			if timeIndex == -1 || col.Name == configuredTimeField {
+				timeIndex = i
+			}
+		case columnarIntTypes[col.Type] || columnarFloatTypes[col.Type]:
+			hasNumbers = true
+		}
+	}
+
+	frame := data.NewFrame("", fields...)
+	if timeIndex == -1 || !hasNumbers {
+		return frame, nil
+	}
+
+	// The time field goes first so that the frame is recognized as a time series.
+	if timeIndex > 0 {
+		fields = append([]*data.Field{fields[timeIndex]}, append(fields[:timeIndex:timeIndex], fields[timeIndex+1:]...)...)
+		frame = data.NewFrame("", fields...)
+	}
+	frame = sortFrameByTime(frame)
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeGraph}
+
+	if frame.TimeSeriesSchema().Type == data.TimeSeriesTypeLong {
+		wide, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return nil, err
+		}
+		wide.Meta = frame.Meta
+		return wide, nil
+	}
+	return frame, nil
+}
+
+func columnarField(col es.ColumnarQueryColumn, rows [][]any, index int) (*data.Field, error) {
+	value := func(row []any) any {
+		if index < len(row) {
+			return row[index]
+		}
+		return nil
+	}
+
+	switch {
+	case columnarTimeTypes[col.Type]:
+		values := make([]*time.Time, len(rows))
+		for i, row := range rows {
+			t, err := parseColumnarTime(value(row))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse value of column %s: %w", col.Name, err)
+			}
+			values[i] = t
+		}
+		return data.NewField(col.Name, nil, values), nil
+	case columnarIntTypes[col.Type]:
+		values := make([]*int64, len(rows))
+		for i, row := range rows {
+			if n, ok := value(row).(json.Number); ok {
+				v, err := n.Int64()
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse value of column %s: %w", col.Name, err)
+				}
+				values[i] = &v
+			}
+		}
+		return data.NewField(col.Name, nil, values), nil
+	case columnarFloatTypes[col.Type]:
+		values := make([]*float64, len(rows))
+		for i, row := range rows {
+			if n, ok := value(row).(json.Number); ok {
+				v, err := n.Float64()
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse value of column %s: %w", col.Name, err)
+				}
+				values[i] = &v
+			}
+		}
+		return data.NewField(col.Name, nil, values), nil
+	case col.Type == "boolean":
+		values := make([]*bool, len(rows))
+		for i, row := range rows {
+			if b, ok := value(row).(bool); ok {
+				values[i] = &b
+			}
+		}
+		return data.NewField(col.Name, nil, values), nil
+	default:
+		values := make([]*string, len(rows))
+		for i, row := range rows {
+			switch v := value(row).(type) {
+			case nil:
+			case string:
+				values[i] = &v
+			default:
+				// Multi-valued fields and objects are returned as JSON.
+				b, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				s := string(b)
+				values[i] = &s
+			}
+		}
+		return data.NewField(col.Name, nil, values), nil
+	}
+}
+
+func parseColumnarTime(value any) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		ms, err := v.Int64()
+		if err != nil {
+			return nil, err
+		}
+		t := time.UnixMilli(ms).UTC()
+		return &t, nil
+	case string:
+		for _, layout := range columnarTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				t = t.UTC()
+				return &t, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported time format: %s", v)
+	default:
+		return nil, fmt.Errorf("unsupported time value: %v", v)
+	}
+}
+
+// sortFrameByTime returns a copy of the frame with its rows sorted by the values of its first field, which must be
+// a nullable time field. The time field of the copy is not nullable and rows without time are dropped.
+func sortFrameByTime(frame *data.Frame) *data.Frame {
+	timeField := frame.Fields[0]
+	order := make([]int, 0, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		if _, ok := timeField.ConcreteAt(i); ok {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, _ := timeField.ConcreteAt(order[i])
+		b, _ := timeField.ConcreteAt(order[j])
+		return a.(time.Time).Before(b.(time.Time))
+	})
+
+	sorted := frame.EmptyCopy()
+	sorted.Fields[0] = data.NewField(timeField.Name, timeField.Labels, make([]time.Time, 0, len(order)))
+	for _, i := range order {
+		row := frame.RowCopy(i)
+		row[0] = *row[0].(*time.Time)
+		sorted.AppendRow(row...)
+	}
+	return sorted
+}
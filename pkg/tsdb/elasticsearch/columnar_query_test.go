@@ -0,0 +1,174 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestExecuteColumnarQuery(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+
+	execute := func(c *fakeClient, queryType, body string) *backend.QueryDataResponse {
+		t.Helper()
+		req := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				QueryType: queryType,
+				JSON:      json.RawMessage(body),
+				TimeRange: backend.TimeRange{From: from, To: to},
+				Interval:  time.Minute,
+			}},
+		}
+		res, err := newElasticsearchDataQuery(context.Background(), c, req, log.New()).execute()
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should send ES|QL queries with interpolated macros", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarQueryResponse = &es.ColumnarQueryResponse{Status: 200}
+		res := execute(c, esqlQueryType, `{"query": "FROM logs | WHERE $__timeFilter | STATS count = COUNT(*) BY bucket = BUCKET(@timestamp, $__interval)"}`)
+
+		require.NoError(t, res.Responses["A"].Error)
+		require.Len(t, c.columnarQueryRequests, 1)
+		assert.Empty(t, c.multisearchRequests)
+		assert.Equal(t, es.QueryLanguageESQL, c.columnarQueryRequests[0].Language)
+		assert.Equal(t, "FROM logs | WHERE `@timestamp` >= TO_DATETIME(\"2024-01-01T00:00:00.000Z\") AND `@timestamp` <= TO_DATETIME(\"2024-01-01T01:00:00.000Z\") | STATS count = COUNT(*) BY bucket = BUCKET(@timestamp, 60000 milliseconds)", c.columnarQueryRequests[0].Query)
+	})
+
+	t.Run("should send PPL queries with interpolated macros", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarQueryResponse = &es.ColumnarQueryResponse{Status: 200}
+		execute(c, pplQueryType, `{"query": "source=logs | where $__timeFilter | stats count() by span(@timestamp, $__interval)"}`)
+
+		require.Len(t, c.columnarQueryRequests, 1)
+		assert.Equal(t, es.QueryLanguagePPL, c.columnarQueryRequests[0].Language)
+		assert.Equal(t, "source=logs | where `@timestamp` >= '2024-01-01 00:00:00.000' AND `@timestamp` <= '2024-01-01 01:00:00.000' | stats count() by span(@timestamp, 60000ms)", c.columnarQueryRequests[0].Query)
+	})
+
+	t.Run("should return the error of the response", func(t *testing.T) {
+		c := newFakeClient()
+		c.columnarQueryResponse = &es.ColumnarQueryResponse{
+			Status: 400,
+			Error:  map[string]any{"type": "verification_exception", "reason": "Unknown index [logs]"},
+		}
+		res := execute(c, esqlQueryType, `{"query": "FROM logs"}`)
+
+		require.Error(t, res.Responses["A"].Error)
+		assert.Equal(t, "Unknown index [logs]", res.Responses["A"].Error.Error())
+	})
+
+	t.Run("should fail on empty queries", func(t *testing.T) {
+		c := newFakeClient()
+		res := execute(c, esqlQueryType, `{"query": " "}`)
+
+		require.Error(t, res.Responses["A"].Error)
+		assert.Empty(t, c.columnarQueryRequests)
+	})
+}
+
+func TestColumnarResponseToFrame(t *testing.T) {
+	decode := func(t *testing.T, body string) *es.ColumnarQueryResponse {
+		t.Helper()
+		var res es.ColumnarQueryResponse
+		require.NoError(t, json.Unmarshal([]byte(body), &res))
+		return &res
+	}
+
+	t.Run("should convert ES|QL time series to a wide frame sorted by time", func(t *testing.T) {
+		res := decode(t, `{
+			"columns": [
+				{"name": "count", "type": "long"},
+				{"name": "host", "type": "keyword"},
+				{"name": "bucket", "type": "date"}
+			],
+			"values": [
+				[3, "a", "2024-01-01T00:01:00.000Z"],
+				[1, "a", "2024-01-01T00:00:00.000Z"],
+				[2, "b", "2024-01-01T00:00:00.000Z"],
+				[4, "b", "2024-01-01T00:01:00.000Z"]
+			]
+		}`)
+
+		frame, err := columnarResponseToFrame(res, "@timestamp")
+		require.NoError(t, err)
+
+		assert.Equal(t, data.TimeSeriesTypeWide, frame.TimeSeriesSchema().Type)
+		require.Len(t, frame.Fields, 3)
+		assert.Equal(t, "bucket", frame.Fields[0].Name)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), frame.Fields[0].At(0))
+		assert.Equal(t, data.Labels{"host": "a"}, frame.Fields[1].Labels)
+		assert.Equal(t, int64(1), *frame.Fields[1].At(0).(*int64))
+		assert.Equal(t, int64(3), *frame.Fields[1].At(1).(*int64))
+		assert.Equal(t, data.Labels{"host": "b"}, frame.Fields[2].Labels)
+		assert.Equal(t, data.VisTypeGraph, frame.Meta.PreferredVisualization)
+	})
+
+	t.Run("should convert PPL responses and prefer the configured time field", func(t *testing.T) {
+		res := decode(t, `{
+			"schema": [
+				{"name": "created", "type": "timestamp"},
+				{"name": "@timestamp", "type": "timestamp"},
+				{"name": "avg", "type": "double"}
+			],
+			"datarows": [
+				["2023-12-31 00:00:00", "2024-01-01 00:00:00.5", 1.5],
+				["2023-12-31 00:00:00", "2024-01-01 00:00:01", null]
+			],
+			"total": 2,
+			"size": 2
+		}`)
+
+		frame, err := columnarResponseToFrame(res, "@timestamp")
+		require.NoError(t, err)
+
+		require.Len(t, frame.Fields, 3)
+		assert.Equal(t, "@timestamp", frame.Fields[0].Name)
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 500000000, time.UTC), frame.Fields[0].At(0))
+		assert.Equal(t, 1.5, *frame.Fields[2].At(0).(*float64))
+		assert.Nil(t, frame.Fields[2].At(1))
+	})
+
+	t.Run("should keep logs as a table", func(t *testing.T) {
+		res := decode(t, `{
+			"columns": [
+				{"name": "@timestamp", "type": "date"},
+				{"name": "message", "type": "text"},
+				{"name": "tags", "type": "keyword"},
+				{"name": "error", "type": "boolean"}
+			],
+			"values": [
+				["2024-01-01T00:01:00.000Z", "second", ["a", "b"], true],
+				["2024-01-01T00:00:00.000Z", "first", "a", null]
+			]
+		}`)
+
+		frame, err := columnarResponseToFrame(res, "@timestamp")
+		require.NoError(t, err)
+
+		assert.Nil(t, frame.Meta)
+		require.Equal(t, 2, frame.Rows())
+		assert.Equal(t, "second", *frame.Fields[1].At(0).(*string))
+		assert.Equal(t, `["a","b"]`, *frame.Fields[2].At(0).(*string))
+		assert.True(t, *frame.Fields[3].At(0).(*bool))
+		assert.Nil(t, frame.Fields[3].At(1))
+	})
+
+	t.Run("should fail on unsupported time values", func(t *testing.T) {
+		res := decode(t, `{"columns": [{"name": "@timestamp", "type": "date"}], "values": [["yesterday"]]}`)
+
+		_, err := columnarResponseToFrame(res, "@timestamp")
+		require.Error(t, err)
+	})
+}
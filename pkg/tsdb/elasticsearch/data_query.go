@@ -19,6 +19,8 @@ import (
 
 const (
 	defaultSize = 500
+	// defaultCompositeMaxBuckets is the default maximum number of buckets returned by the pages of a composite aggregation
+	defaultCompositeMaxBuckets = 10000
 )
 
 type elasticsearchDataQuery struct {
@@ -45,14 +47,37 @@ var newElasticsearchDataQuery = func(ctx context.Context, client es.Client, req
 }
 
 func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
+	columnarQueries, dataQueries := splitColumnarQueries(e.dataQueries)
+	if len(columnarQueries) == 0 {
+		return e.executeMultisearch(dataQueries)
+	}
+
+	response := backend.NewQueryDataResponse()
+	if len(dataQueries) > 0 {
+		res, err := e.executeMultisearch(dataQueries)
+		if err != nil {
+			return res, err
+		}
+		for refID, r := range res.Responses {
+			response.Responses[refID] = r
+		}
+	}
+	for _, q := range columnarQueries {
+		response.Responses[q.RefID] = e.executeColumnarQuery(q)
+	}
+	return response, nil
+}
+
+// executeMultisearch executes the queries built from metric and bucket aggregations in a single multi search request.
+func (e *elasticsearchDataQuery) executeMultisearch(dataQueries []backend.DataQuery) (*backend.QueryDataResponse, error) {
 	start := time.Now()
 	response := backend.NewQueryDataResponse()
-	e.logger.Debug("Parsing queries", "queriesLength", len(e.dataQueries))
-	queries, err := parseQuery(e.dataQueries, e.logger)
+	e.logger.Debug("Parsing queries", "queriesLength", len(dataQueries))
+	queries, err := parseQuery(dataQueries, e.logger)
 	if err != nil {
-		mq, _ := json.Marshal(e.dataQueries)
+		mq, _ := json.Marshal(dataQueries)
 		e.logger.Error("Failed to parse queries", "error", err, "queries", string(mq), "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddPluginErrorToResponse(dataQueries[0].RefID, response, err), nil
 	}
 
 	ms := e.client.MultiSearch()
@@ -69,9 +94,9 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 
 	req, err := ms.Build()
 	if err != nil {
-		mqs, _ := json.Marshal(e.dataQueries)
+		mqs, _ := json.Marshal(dataQueries)
 		e.logger.Error("Failed to build multisearch request", "error", err, "queriesLength", len(queries), "queries", string(mqs), "duration", time.Since(start), "stage", es.StagePrepareRequest)
-		return errorsource.AddPluginErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddPluginErrorToResponse(dataQueries[0].RefID, response, err), nil
 	}
 
 	e.logger.Info("Prepared request", "queriesLength", len(queries), "duration", time.Since(start), "stage", es.StagePrepareRequest)
@@ -80,17 +105,107 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		if backend.IsDownstreamHTTPError(err) {
 			err = errorsource.DownstreamError(err, false)
 		}
-		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, err), nil
+		return errorsource.AddErrorToResponse(dataQueries[0].RefID, response, err), nil
 	}
 
 	if res.Status >= 400 {
 		errWithSource := errorsource.SourceError(backend.ErrorSourceFromHTTPStatus(res.Status), fmt.Errorf("unexpected status code: %d", res.Status), false)
-		return errorsource.AddErrorToResponse(e.dataQueries[0].RefID, response, errWithSource), nil
+		return errorsource.AddErrorToResponse(dataQueries[0].RefID, response, errWithSource), nil
+	}
+
+	if err := e.fetchCompositePages(queries, res.Responses); err != nil {
+		if backend.IsDownstreamHTTPError(err) {
+			err = errorsource.DownstreamError(err, false)
+		}
+		return errorsource.AddErrorToResponse(dataQueries[0].RefID, response, err), nil
 	}
 
 	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.keepLabelsInResponse, e.logger)
 }
 
+// fetchCompositePages requests the next pages of the composite aggregations of the responses, as long as their after
+// key is returned with a full page and their buckets do not exceed the cap of the aggregation. The pages of all queries
+// are requested in the same multi search request and are merged into the responses.
+func (e *elasticsearchDataQuery) fetchCompositePages(queries []*Query, responses []*es.SearchResponse) error {
+	lastPageSizes := make([]int, len(responses))
+	for i, res := range responses {
+		if i >= len(queries) {
+			break
+		}
+		if bucketAgg := compositeBucketAgg(queries[i]); bucketAgg != nil {
+			lastPageSizes[i] = len(compositeBuckets(res, bucketAgg.ID))
+		}
+	}
+
+	for {
+		ms := e.client.MultiSearch()
+		var pending []int
+		for i, res := range responses {
+			if i >= len(queries) || res.Error != nil {
+				continue
+			}
+			q := queries[i]
+			bucketAgg := compositeBucketAgg(q)
+			if bucketAgg == nil {
+				continue
+			}
+			buckets, maxBuckets := len(compositeBuckets(res, bucketAgg.ID)), compositeMaxBuckets(bucketAgg)
+			if buckets > maxBuckets {
+				q.compositeTruncated = true
+				trimCompositeBuckets(res, bucketAgg.ID, maxBuckets)
+				continue
+			}
+			after := compositeAfterKey(res, bucketAgg.ID)
+			if after == nil || lastPageSizes[i] < compositePageSize(bucketAgg) {
+				continue
+			}
+			if buckets == maxBuckets {
+				// More buckets may remain but are not requested.
+				q.compositeTruncated = true
+				continue
+			}
+
+			q.compositeAfter = after
+			from := q.TimeRange.From.UnixNano() / int64(time.Millisecond)
+			to := q.TimeRange.To.UnixNano() / int64(time.Millisecond)
+			if err := e.processQuery(q, ms, from, to); err != nil {
+				return err
+			}
+			pending = append(pending, i)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		req, err := ms.Build()
+		if err != nil {
+			return err
+		}
+		e.logger.Debug("Requesting next pages of composite aggregations", "queriesLength", len(pending))
+		res, err := e.client.ExecuteMultisearch(req)
+		if err != nil {
+			return err
+		}
+		if res.Status >= 400 {
+			return errorsource.SourceError(backend.ErrorSourceFromHTTPStatus(res.Status), fmt.Errorf("unexpected status code: %d", res.Status), false)
+		}
+
+		for j, i := range pending {
+			if j >= len(res.Responses) {
+				break
+			}
+			page := res.Responses[j]
+			if page.Error != nil {
+				responses[i] = page
+				continue
+			}
+			aggID := compositeBucketAgg(queries[i]).ID
+			lastPageSizes[i] = len(compositeBuckets(page, aggID))
+			mergeCompositePage(responses[i], page, aggID)
+		}
+	}
+}
+
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
 	err := isQueryWithError(q)
 	if err != nil {
@@ -277,6 +392,66 @@ func addTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, metrics []*Metr
 	return aggBuilder
 }
 
+// addCompositeAgg adds a terms aggregation as a composite aggregation, whose buckets are paginated with the after key
+// of the previous page instead of being limited by the size of the aggregation. Buckets are ordered by term.
+func addCompositeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, after map[string]any) es.AggBuilder {
+	aggBuilder.Composite(bucketAgg.ID, func(a *es.CompositeAggregation, b es.AggBuilder) {
+		a.Size = compositePageSize(bucketAgg)
+		a.After = after
+
+		order := "asc"
+		orderBy := bucketAgg.Settings.Get("orderBy").MustString("_term")
+		if orderBy == "_term" || orderBy == "_key" {
+			order = bucketAgg.Settings.Get("order").MustString(order)
+		}
+		a.Sources = []map[string]any{
+			{bucketAgg.ID: map[string]any{"terms": map[string]any{"field": bucketAgg.Field, "order": order}}},
+		}
+
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+// isCompositeAgg reports whether a terms aggregation is executed as a composite aggregation.
+func isCompositeAgg(bucketAgg *BucketAgg) bool {
+	if bucketAgg.Type != termsType || bucketAgg.Settings == nil {
+		return false
+	}
+	if composite, err := bucketAgg.Settings.Get("composite").Bool(); err == nil {
+		return composite
+	}
+	return bucketAgg.Settings.Get("composite").MustString() == "true"
+}
+
+// compositeBucketAgg returns the bucket aggregation of a time series query that is executed as a composite
+// aggregation. Only the first bucket aggregation of a query can be paginated.
+func compositeBucketAgg(q *Query) *BucketAgg {
+	if len(q.BucketAggs) == 0 || len(q.Metrics) == 0 || isLogsQuery(q) || isDocumentQuery(q) {
+		return nil
+	}
+	if !isCompositeAgg(q.BucketAggs[0]) {
+		return nil
+	}
+	return q.BucketAggs[0]
+}
+
+func compositePageSize(bucketAgg *BucketAgg) int {
+	size, err := bucketAgg.Settings.Get("size").Int()
+	if err != nil || size <= 0 {
+		size = stringToIntWithDefaultValue(bucketAgg.Settings.Get("size").MustString(), defaultSize)
+	}
+	return min(size, compositeMaxBuckets(bucketAgg))
+}
+
+func compositeMaxBuckets(bucketAgg *BucketAgg) int {
+	if maxBuckets, err := bucketAgg.Settings.Get("maxBuckets").Int(); err == nil && maxBuckets > 0 {
+		return maxBuckets
+	}
+	return stringToIntWithDefaultValue(bucketAgg.Settings.Get("maxBuckets").MustString(), defaultCompositeMaxBuckets)
+}
+
 func addNestedAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	aggBuilder.Nested(bucketAgg.ID, bucketAgg.Field, func(a *es.NestedAggregation, b es.AggBuilder) {
 		aggBuilder = b
@@ -412,10 +587,14 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 	aggBuilder := b.Agg()
 	// Process buckets
 	// iterate backwards to create aggregations bottom-down
-	for _, bucketAgg := range q.BucketAggs {
+	for i, bucketAgg := range q.BucketAggs {
 		bucketAgg.Settings = simplejson.NewFromAny(
 			bucketAgg.generateSettingsForDSL(),
 		)
+		if i == 0 && isCompositeAgg(bucketAgg) {
+			aggBuilder = addCompositeAgg(aggBuilder, bucketAgg, q.compositeAfter)
+			continue
+		}
 		switch bucketAgg.Type {
 		case dateHistType:
 			aggBuilder = addDateHistogramAgg(aggBuilder, bucketAgg, from, to, defaultTimeField)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -117,6 +118,56 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, firstLevel.Aggregation.Aggregation.(*es.TermsAggregation).Order["_key"], "asc")
 		})
 
+		t.Run("With composite term agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"type": "terms",
+						"field": "@host",
+						"id": "2",
+						"settings": { "size": "5", "order": "desc", "orderBy": "_term", "composite": true }
+					},
+					{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+			firstLevel := sr.Aggs[0]
+			require.Equal(t, "2", firstLevel.Key)
+			require.Equal(t, "composite", firstLevel.Aggregation.Type)
+			compositeAgg := firstLevel.Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, 5, compositeAgg.Size)
+			require.Nil(t, compositeAgg.After)
+			require.Equal(t, []map[string]any{
+				{"2": map[string]any{"terms": map[string]any{"field": "@host", "order": "desc"}}},
+			}, compositeAgg.Sources)
+			secondLevel := firstLevel.Aggregation.Aggs[0]
+			require.Equal(t, "3", secondLevel.Key)
+			require.Equal(t, "@timestamp", secondLevel.Aggregation.Aggregation.(*es.DateHistogramAgg).Field)
+		})
+
+		t.Run("With composite term agg and size larger than max buckets", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{
+						"type": "terms",
+						"field": "@host",
+						"id": "2",
+						"settings": { "size": "500", "orderBy": "_count", "composite": "true", "maxBuckets": "100" }
+					},
+					{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			compositeAgg := c.multisearchRequests[0].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, 100, compositeAgg.Size)
+			require.Equal(t, "asc", compositeAgg.Sources[0]["2"].(map[string]any)["terms"].(map[string]any)["order"])
+		})
+
 		t.Run("With term agg and order by metric agg", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -1799,12 +1850,96 @@ func TestSettingsCasting(t *testing.T) {
 	})
 }
 
+func TestCompositeAggregationPaging(t *testing.T) {
+	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
+	to := time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC)
+	query := func(settings string) string {
+		return `{
+			"bucketAggs": [
+				{ "type": "terms", "field": "host", "id": "2", "settings": ` + settings + ` },
+				{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+			],
+			"metrics": [{"type": "count", "id": "1" }]
+		}`
+	}
+	page := func(t *testing.T, hosts []string, afterKey string) *es.MultiSearchResponse {
+		t.Helper()
+		buckets := make([]map[string]any, 0, len(hosts))
+		for _, host := range hosts {
+			buckets = append(buckets, map[string]any{
+				"key":       map[string]any{"2": host},
+				"doc_count": 1,
+				"3": map[string]any{
+					"buckets": []map[string]any{{"key": 1000, "doc_count": 1}},
+				},
+			})
+		}
+		agg := map[string]any{"buckets": buckets}
+		if afterKey != "" {
+			agg["after_key"] = map[string]any{"2": afterKey}
+		}
+		b, err := json.Marshal(map[string]any{
+			"responses": []map[string]any{{"aggregations": map[string]any{"2": agg}}},
+		})
+		require.NoError(t, err)
+		var res es.MultiSearchResponse
+		require.NoError(t, json.Unmarshal(b, &res))
+		return &res
+	}
+
+	t.Run("Requests pages until the after key is exhausted", func(t *testing.T) {
+		c := newFakeClient()
+		c.multiSearchResponses = []*es.MultiSearchResponse{
+			page(t, []string{"host-a", "host-b"}, "host-b"),
+			page(t, []string{"host-c", "host-d"}, "host-d"),
+			page(t, []string{"host-e"}, "host-e"),
+		}
+		res, err := executeElasticsearchDataQuery(c, query(`{ "size": "2", "composite": true }`), from, to)
+		require.NoError(t, err)
+
+		require.Len(t, c.multisearchRequests, 3)
+		require.Nil(t, c.multisearchRequests[0].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation).After)
+		require.Equal(t, map[string]any{"2": "host-b"}, c.multisearchRequests[1].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation).After)
+		require.Equal(t, map[string]any{"2": "host-d"}, c.multisearchRequests[2].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation).After)
+
+		frames := res.Responses["A"].Frames
+		require.Len(t, frames, 5)
+		for i, host := range []string{"host-a", "host-b", "host-c", "host-d", "host-e"} {
+			require.Equal(t, host, frames[i].Name)
+			require.Empty(t, frames[i].Meta.Notices)
+		}
+	})
+
+	t.Run("Stops at max buckets with a warning notice", func(t *testing.T) {
+		c := newFakeClient()
+		c.multiSearchResponses = []*es.MultiSearchResponse{
+			page(t, []string{"host-a", "host-b"}, "host-b"),
+			page(t, []string{"host-c", "host-d"}, "host-d"),
+		}
+		res, err := executeElasticsearchDataQuery(c, query(`{ "size": "2", "composite": true, "maxBuckets": "3" }`), from, to)
+		require.NoError(t, err)
+
+		require.Len(t, c.multisearchRequests, 2)
+		frames := res.Responses["A"].Frames
+		require.Len(t, frames, 3)
+		for _, frame := range frames {
+			require.NotNil(t, frame.Meta)
+			require.Len(t, frame.Meta.Notices, 1)
+			require.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+			require.Contains(t, frame.Meta.Notices[0].Text, "limited to 3 terms of host")
+		}
+	})
+}
+
 type fakeClient struct {
-	configuredFields    es.ConfiguredFields
-	multiSearchResponse *es.MultiSearchResponse
-	multiSearchError    error
-	builder             *es.MultiSearchRequestBuilder
-	multisearchRequests []*es.MultiSearchRequest
+	configuredFields      es.ConfiguredFields
+	multiSearchResponse   *es.MultiSearchResponse
+	multiSearchResponses  []*es.MultiSearchResponse
+	multiSearchError      error
+	builder               *es.MultiSearchRequestBuilder
+	multisearchRequests   []*es.MultiSearchRequest
+	columnarQueryResponse *es.ColumnarQueryResponse
+	columnarQueryRequests []*es.ColumnarQueryRequest
 }
 
 func newFakeClient() *fakeClient {
@@ -1827,9 +1962,18 @@ func (c *fakeClient) GetConfiguredFields() es.ConfiguredFields {
 
 func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSearchResponse, error) {
 	c.multisearchRequests = append(c.multisearchRequests, r)
+	// Responses of consecutive requests, such as the pages of composite aggregations, are returned in order.
+	if i := len(c.multisearchRequests) - 1; i < len(c.multiSearchResponses) {
+		return c.multiSearchResponses[i], c.multiSearchError
+	}
 	return c.multiSearchResponse, c.multiSearchError
 }
 
+func (c *fakeClient) ExecuteColumnarQuery(r *es.ColumnarQueryRequest) (*es.ColumnarQueryResponse, error) {
+	c.columnarQueryRequests = append(c.columnarQueryRequests, r)
+	return c.columnarQueryResponse, nil
+}
+
 func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	c.builder = es.NewMultiSearchRequestBuilder()
 	return c.builder
@@ -18,6 +18,11 @@ type Query struct {
 	RefID         string
 	MaxDataPoints int64
 	TimeRange     backend.TimeRange
+
+	// compositeAfter is the key of the last bucket of the previous page of the composite aggregation of the query
+	compositeAfter map[string]any
+	// compositeTruncated is set when the composite aggregation of the query has more buckets than its cap
+	compositeTruncated bool
 }
 
 // BucketAgg represents a bucket aggregation of the time series query model of the datasource
@@ -104,6 +104,9 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 			result.Responses[target.RefID] = queryRes
 		} else {
 			// Process as metric query result
+			if bucketAgg := compositeBucketAgg(target); bucketAgg != nil {
+				flattenCompositeBuckets(res, bucketAgg.ID)
+			}
 			props := make(map[string]string)
 			err := processBuckets(res.Aggregations, target, &queryRes, props, 0)
 			logger.Debug("Processed metric query response")
@@ -120,6 +123,9 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 			}
 			nameFields(queryRes, target, keepLabelsInResponse)
 			trimDatapoints(queryRes, target)
+			if target.compositeTruncated {
+				addCompositeTruncatedNotice(queryRes, compositeBucketAgg(target))
+			}
 
 			result.Responses[target.RefID] = queryRes
 		}
@@ -130,6 +136,76 @@ func parseResponse(ctx context.Context, responses []*es.SearchResponse, targets
 	return &result, nil
 }
 
+// compositeBuckets returns the buckets of the composite aggregation of a response.
+func compositeBuckets(res *es.SearchResponse, aggID string) []any {
+	agg, ok := res.Aggregations[aggID].(map[string]any)
+	if !ok {
+		return nil
+	}
+	buckets, _ := agg["buckets"].([]any)
+	return buckets
+}
+
+// compositeAfterKey returns the key of the last bucket of the composite aggregation of a response, which is used to
+// request the next page of buckets. It is nil when all buckets have been returned.
+func compositeAfterKey(res *es.SearchResponse, aggID string) map[string]any {
+	agg, ok := res.Aggregations[aggID].(map[string]any)
+	if !ok {
+		return nil
+	}
+	after, _ := agg["after_key"].(map[string]any)
+	return after
+}
+
+// mergeCompositePage appends the buckets of the next page of a composite aggregation to the buckets of the response
+// and replaces its after key with the one of the page.
+func mergeCompositePage(res, page *es.SearchResponse, aggID string) {
+	agg, ok := res.Aggregations[aggID].(map[string]any)
+	if !ok {
+		return
+	}
+	agg["buckets"] = append(compositeBuckets(res, aggID), compositeBuckets(page, aggID)...)
+	if after := compositeAfterKey(page, aggID); after != nil {
+		agg["after_key"] = after
+	} else {
+		delete(agg, "after_key")
+	}
+}
+
+func trimCompositeBuckets(res *es.SearchResponse, aggID string, maxBuckets int) {
+	if buckets := compositeBuckets(res, aggID); len(buckets) > maxBuckets {
+		res.Aggregations[aggID].(map[string]any)["buckets"] = buckets[:maxBuckets]
+	}
+}
+
+// flattenCompositeBuckets replaces the keys of the buckets of a composite aggregation, which are objects with a value
+// per source, with the value of its single terms source so that the buckets are processed as terms buckets.
+func flattenCompositeBuckets(res *es.SearchResponse, aggID string) {
+	for _, b := range compositeBuckets(res, aggID) {
+		bucket, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		if key, ok := bucket["key"].(map[string]any); ok {
+			bucket["key"] = key[aggID]
+		}
+	}
+}
+
+func addCompositeTruncatedNotice(queryRes backend.DataResponse, bucketAgg *BucketAgg) {
+	notice := data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text: fmt.Sprintf("Results are limited to %d terms of %s. Increase the max buckets of the terms aggregation to return all terms.",
+			compositeMaxBuckets(bucketAgg), bucketAgg.Field),
+	}
+	for _, frame := range queryRes.Frames {
+		if frame.Meta == nil {
+			frame.Meta = &data.FrameMeta{}
+		}
+		frame.Meta.Notices = append(frame.Meta.Notices, notice)
+	}
+}
+
 func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields es.ConfiguredFields, queryRes *backend.DataResponse, logger log.Logger) error {
 	propNames := make(map[string]bool)
 	docs := make([]map[string]interface{}, len(res.Hits.Hits))
@@ -3737,3 +3737,29 @@ func requireFloatAt(t *testing.T, expected float64, field *data.Field, index int
 func requireTimeSeriesName(t *testing.T, expected string, frame *data.Frame) {
 	require.Equal(t, expected, frame.Name)
 }
+
+func TestMergeCompositePage(t *testing.T) {
+	res := &es.SearchResponse{Aggregations: map[string]any{
+		"2": map[string]any{
+			"buckets":   []any{map[string]any{"key": map[string]any{"2": "host-a"}, "doc_count": 1.0}},
+			"after_key": map[string]any{"2": "host-a"},
+		},
+	}}
+	page := &es.SearchResponse{Aggregations: map[string]any{
+		"2": map[string]any{
+			"buckets": []any{map[string]any{"key": map[string]any{"2": "host-b"}, "doc_count": 2.0}},
+		},
+	}}
+
+	mergeCompositePage(res, page, "2")
+	require.Len(t, compositeBuckets(res, "2"), 2)
+	require.Nil(t, compositeAfterKey(res, "2"))
+
+	flattenCompositeBuckets(res, "2")
+	buckets := compositeBuckets(res, "2")
+	require.Equal(t, "host-a", buckets[0].(map[string]any)["key"])
+	require.Equal(t, "host-b", buckets[1].(map[string]any)["key"])
+
+	trimCompositeBuckets(res, "2", 1)
+	require.Len(t, compositeBuckets(res, "2"), 1)
+}
@@ -52,16 +52,24 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
-	return newService(search, store, features)
+// RecordedMetricsReader reads the metrics that recording rules store in the Grafana database.
+type RecordedMetricsReader interface {
+	// Query returns a frame for every series of the metric whose labels have the given values, with the samples
+	// recorded in the time range.
+	Query(ctx context.Context, orgID int64, metric string, labels map[string]string, timeRange backend.TimeRange) (data.Frames, error)
 }
 
-func newService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
+func ProvideService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, recorded RecordedMetricsReader) *Service {
+	return newService(search, store, features, recorded)
+}
+
+func newService(search searchV2.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, recorded RecordedMetricsReader) *Service {
 	s := &Service{
 		search:   search,
 		store:    store,
 		log:      log.New("grafanads"),
 		features: features,
+		recorded: recorded,
 	}
 
 	return s
@@ -73,6 +81,7 @@ type Service struct {
 	store    store.StorageService
 	log      log.Logger
 	features featuremgmt.FeatureToggles
+	recorded RecordedMetricsReader
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -100,6 +109,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch, queryTypeSearchNext:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeRecordedMetric:
+			response.Responses[q.RefID] = s.doRecordedMetricQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -166,6 +177,26 @@ func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
+func (s *Service) doRecordedMetricQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &recordedMetricQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	if s.recorded == nil {
+		response.Error = fmt.Errorf("recorded metrics are not available")
+		return response
+	}
+
+	frames, err := s.recorded.Query(ctx, req.PluginContext.OrgID, q.Metric, q.Labels, query.TimeRange)
+	response.Error = err
+	response.Frames = frames
+	return response
+}
+
 func (s *Service) doRandomWalk(query backend.DataQuery) backend.DataResponse {
 	response := backend.DataResponse{}
 
@@ -51,6 +51,12 @@ type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 	Id         int64
+	// Version is the Graphite version configured in the data source settings, if any.
+	Version string
+}
+
+type jsonData struct {
+	GraphiteVersion string `json:"graphiteVersion"`
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -59,16 +65,25 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 		if err != nil {
 			return nil, err
 		}
+		opts.ForwardHTTPHeaders = true
 
 		client, err := httpClientProvider.New(opts)
 		if err != nil {
 			return nil, err
 		}
 
+		jd := jsonData{}
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jd); err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+
 		model := datasourceInfo{
 			HTTPClient: client,
 			URL:        settings.URL,
 			Id:         settings.ID,
+			Version:    jd.GraphiteVersion,
 		}
 
 		return model, nil
@@ -0,0 +1,168 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// healthCheckTarget is rendered to check the connectivity with Graphite versions that do not expose their version.
+const healthCheckTarget = "constantLine(100)"
+
+// CheckHealth checks that Graphite can be reached and that it is not older than the version configured in the data
+// source settings, as the query editor relies on the features of that version.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusUnknown,
+			Message: "Failed to get data source info",
+		}, err
+	}
+
+	version, status, err := s.getVersion(ctx, dsInfo)
+	if err != nil {
+		logger.Warn("Failed to get Graphite version", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Failed to connect to Graphite: %s", err),
+		}, nil
+	}
+
+	switch {
+	case status == http.StatusNotFound:
+		// Graphite exposes its version since 1.1, connectivity with older versions is checked with a render request.
+		if err := s.checkRender(ctx, dsInfo); err != nil {
+			logger.Warn("Graphite health check render request failed", "error", err)
+			return &backend.CheckHealthResult{
+				Status:  backend.HealthStatusError,
+				Message: fmt.Sprintf("Failed to connect to Graphite: %s", err),
+			}, nil
+		}
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusOk,
+			Message: "Successfully connected to Graphite",
+		}, nil
+	case status/100 != 2:
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Graphite returned an unexpected status: %d %s", status, http.StatusText(status)),
+		}, nil
+	}
+
+	if older, ok := olderVersion(version, dsInfo.Version); ok && older {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Graphite %s is older than the version configured in the data source settings (%s)", version, dsInfo.Version),
+		}, nil
+	}
+
+	message := "Successfully connected to Graphite"
+	if version != "" {
+		message = fmt.Sprintf("%s %s", message, version)
+	}
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: message,
+	}, nil
+}
+
+// getVersion returns the version reported by Graphite along with the status code of the response.
+func (s *Service) getVersion(ctx context.Context, dsInfo *datasourceInfo) (string, int, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return "", 0, err
+	}
+	u.Path = path.Join(u.Path, "version")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", res.StatusCode, err
+	}
+	return strings.Trim(strings.TrimSpace(string(body)), `"`), res.StatusCode, nil
+}
+
+func (s *Service) checkRender(ctx context.Context, dsInfo *datasourceInfo) error {
+	req, err := s.createRequest(ctx, logger, dsInfo, url.Values{
+		"from":   []string{"-5min"},
+		"until":  []string{"now"},
+		"format": []string{"json"},
+		"target": []string{healthCheckTarget},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status: %s", res.Status)
+	}
+	return nil
+}
+
+// olderVersion reports whether the major and minor version of version are lower than the ones of configured. The
+// second value is false if either version cannot be parsed.
+func olderVersion(version, configured string) (bool, bool) {
+	v, ok := parseVersion(version)
+	if !ok {
+		return false, false
+	}
+	c, ok := parseVersion(configured)
+	if !ok {
+		return false, false
+	}
+	if v[0] != c[0] {
+		return v[0] < c[0], true
+	}
+	return v[1] < c[1], true
+}
+
+func parseVersion(version string) ([2]int, bool) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return [2]int{}, false
+	}
+	var v [2]int
+	for i := range v {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return [2]int{}, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
@@ -0,0 +1,142 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	testCases := []struct {
+		name            string
+		configured      string
+		handler         http.HandlerFunc
+		expectedStatus  backend.HealthStatus
+		expectedMessage string
+	}{
+		{
+			name:       "should succeed and report the version of Graphite",
+			configured: "1.1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("1.1.10\n"))
+			},
+			expectedStatus:  backend.HealthStatusOk,
+			expectedMessage: "Successfully connected to Graphite 1.1.10",
+		},
+		{
+			name:       "should fail when Graphite is older than the configured version",
+			configured: "1.1",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("1.0.2"))
+			},
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "Graphite 1.0.2 is older than the version configured in the data source settings (1.1)",
+		},
+		{
+			name:       "should fall back to a render request when Graphite does not expose its version",
+			configured: "0.9",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/render" {
+					_, _ = w.Write([]byte("[]"))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus:  backend.HealthStatusOk,
+			expectedMessage: "Successfully connected to Graphite",
+		},
+		{
+			name: "should fail when the render request fails",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/render" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "Failed to connect to Graphite: unexpected status: 401 Unauthorized",
+		},
+		{
+			name: "should fail on unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "Graphite returned an unexpected status: 502 Bad Gateway",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			t.Cleanup(srv.Close)
+
+			service := &Service{
+				im:     fakeServerInstanceManager{datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL, Version: tc.configured}},
+				tracer: tracing.InitializeTracerForTest(),
+			}
+			res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, res.Status)
+			assert.Equal(t, tc.expectedMessage, res.Message)
+		})
+	}
+
+	t.Run("should fail when Graphite cannot be reached", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		service := &Service{
+			im:     fakeServerInstanceManager{datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}},
+			tracer: tracing.InitializeTracerForTest(),
+		}
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Contains(t, res.Message, "Failed to connect to Graphite")
+	})
+}
+
+func TestOlderVersion(t *testing.T) {
+	testCases := []struct {
+		version    string
+		configured string
+		older      bool
+		ok         bool
+	}{
+		{version: "1.1.10", configured: "1.1", older: false, ok: true},
+		{version: "1.0.2", configured: "1.1", older: true, ok: true},
+		{version: "0.9.16", configured: "1.0", older: true, ok: true},
+		{version: "2.0.0", configured: "1.1", older: false, ok: true},
+		{version: "1.1.10", configured: "", older: false, ok: false},
+		{version: "dev", configured: "1.1", older: false, ok: false},
+	}
+
+	for _, tc := range testCases {
+		older, ok := olderVersion(tc.version, tc.configured)
+		assert.Equal(t, tc.older, older, "version %s, configured %s", tc.version, tc.configured)
+		assert.Equal(t, tc.ok, ok, "version %s, configured %s", tc.version, tc.configured)
+	}
+}
+
+// fakeServerInstanceManager returns the data source info of a test server.
+type fakeServerInstanceManager struct {
+	dsInfo datasourceInfo
+}
+
+func (f fakeServerInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f fakeServerInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
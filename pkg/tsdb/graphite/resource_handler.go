@@ -0,0 +1,124 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// resourcePaths are the Graphite endpoints used by the query editor that can be called as resources, with the methods
+// the query editor calls them with.
+var resourcePaths = map[string][]string{
+	"metrics/find":             {http.MethodGet, http.MethodPost},
+	"tags/autoComplete/tags":   {http.MethodGet},
+	"tags/autoComplete/values": {http.MethodGet},
+	"functions":                {http.MethodGet},
+}
+
+// CallResource proxies the calls of the query editor to Graphite so that they are authenticated the same way as queries.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath := strings.Trim(req.Path, "/")
+	methods, ok := resourcePaths[resourcePath]
+	if !ok {
+		logger.Error("Invalid resource path", "path", req.Path)
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf("invalid resource path: %s", req.Path)),
+		})
+	}
+	if req.Method != "" && !slices.Contains(methods, req.Method) {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusMethodNotAllowed,
+			Body:   []byte(fmt.Sprintf("method not allowed: %s", req.Method)),
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	graphiteReq, err := createResourceRequest(ctx, dsInfo, req, resourcePath)
+	if err != nil {
+		logger.Error("Failed to create request", "error", err, "path", req.Path)
+		return err
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("path", resourcePath),
+		attribute.Int64("datasource_id", dsInfo.Id),
+		attribute.Int64("org_id", req.PluginContext.OrgID),
+	)
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error("Failed to send resource request", "error", err, "path", resourcePath)
+		return err
+	}
+	span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read response body", "error", err)
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = []string{contentType}
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+func createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, req *backend.CallResourceRequest, resourcePath string) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+	u.RawQuery = reqURL.RawQuery
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	graphiteReq, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType := req.GetHTTPHeader("Content-Type"); contentType != "" {
+		graphiteReq.Header.Set("Content-Type", contentType)
+	}
+	return graphiteReq, nil
+}
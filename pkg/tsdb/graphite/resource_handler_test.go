@@ -0,0 +1,146 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCallResource(t *testing.T) {
+	var received *http.Request
+	var receivedBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"text":"app","id":"app","leaf":0}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im:     fakeServerInstanceManager{datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL + "/graphite"}},
+		tracer: tracing.InitializeTracerForTest(),
+	}
+
+	t.Run("should forward allowed paths to Graphite", func(t *testing.T) {
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Path:   "metrics/find",
+			Method: http.MethodPost,
+			URL:    "metrics/find?from=-1h&until=now",
+			Body:   []byte("query=app.*"),
+			Headers: map[string][]string{
+				"Content-Type": {"application/x-www-form-urlencoded"},
+			},
+		}, sender)
+		require.NoError(t, err)
+
+		require.NotNil(t, received)
+		assert.Equal(t, http.MethodPost, received.Method)
+		assert.Equal(t, "/graphite/metrics/find", received.URL.Path)
+		assert.Equal(t, "from=-1h&until=now", received.URL.RawQuery)
+		assert.Equal(t, "application/x-www-form-urlencoded", received.Header.Get("Content-Type"))
+		assert.Equal(t, "query=app.*", receivedBody)
+
+		require.NotNil(t, sender.res)
+		assert.Equal(t, http.StatusOK, sender.res.Status)
+		assert.Equal(t, []string{"application/json"}, sender.res.Headers["content-type"])
+		assert.JSONEq(t, `[{"text":"app","id":"app","leaf":0}]`, string(sender.res.Body))
+	})
+
+	t.Run("should reject other paths and methods", func(t *testing.T) {
+		received = nil
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Path:   "render",
+			Method: http.MethodGet,
+			URL:    "render?target=app.*",
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.res)
+		assert.Equal(t, http.StatusNotFound, sender.res.Status)
+
+		err = service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Path:   "functions",
+			Method: http.MethodPost,
+			URL:    "functions",
+		}, sender)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, sender.res.Status)
+		assert.Nil(t, received)
+	})
+}
+
+func TestForwardHTTPHeaders(t *testing.T) {
+	var received http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte("1.1.10"))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL, JSONData: []byte(`{}`)},
+	}
+
+	t.Run("should forward the identity of the user to Graphite in resource calls", func(t *testing.T) {
+		received = nil
+		req := &backend.CallResourceRequest{PluginContext: pluginCtx, Path: "functions", Method: http.MethodGet, URL: "functions"}
+		req.SetHTTPHeader(backend.OAuthIdentityTokenHeaderName, "Bearer token")
+		req.SetHTTPHeader(backend.OAuthIdentityIDTokenHeaderName, "id-token")
+		err := service.CallResource(forwardHeaders(context.Background(), req), req, &fakeSender{})
+		require.NoError(t, err)
+		require.NotNil(t, received)
+		assert.Equal(t, "Bearer token", received.Get("Authorization"))
+		assert.Equal(t, "id-token", received.Get("X-Id-Token"))
+	})
+
+	t.Run("should forward the identity of the user to Graphite in health checks", func(t *testing.T) {
+		received = nil
+		req := &backend.CheckHealthRequest{PluginContext: pluginCtx}
+		req.SetHTTPHeader(backend.OAuthIdentityTokenHeaderName, "Bearer token")
+		req.SetHTTPHeader(backend.OAuthIdentityIDTokenHeaderName, "id-token")
+		res, err := service.CheckHealth(forwardHeaders(context.Background(), req), req)
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		require.NotNil(t, received)
+		assert.Equal(t, "Bearer token", received.Get("Authorization"))
+		assert.Equal(t, "id-token", received.Get("X-Id-Token"))
+	})
+}
+
+// forwardHeaders forwards the HTTP headers of a request to the data source like the plugin middleware does, which
+// only applies to HTTP clients created with ForwardHTTPHeaders.
+func forwardHeaders(ctx context.Context, req backend.ForwardHTTPHeaders) context.Context {
+	return httpclient.WithContextualMiddleware(ctx, httpclient.MiddlewareFunc(func(opts httpclient.Options, next http.RoundTripper) http.RoundTripper {
+		if !opts.ForwardHTTPHeaders {
+			return next
+		}
+		return httpclient.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			for k, v := range req.GetHTTPHeaders() {
+				r.Header[k] = v
+			}
+			return next.RoundTrip(r)
+		})
+	}))
+}
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
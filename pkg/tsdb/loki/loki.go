@@ -68,6 +68,9 @@ type datasourceInfo struct {
 	// open streams
 	streams   map[string]data.FrameJSONCache
 	streamsMu sync.RWMutex
+
+	// tailDialer connects the tails of live streams to Loki
+	tailDialer *tailDialer
 }
 
 type QueryJSONModel struct {
@@ -94,6 +97,7 @@ func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.Ins
 			return nil, err
 		}
 		opts.ForwardHTTPHeaders = true
+		tailDialer := configureTailDialer(&opts)
 
 		client, err := httpClientProvider.New(opts)
 		if err != nil {
@@ -104,6 +108,7 @@ func newInstanceSettings(httpClientProvider *httpclient.Provider) datasource.Ins
 			HTTPClient: client,
 			URL:        settings.URL,
 			streams:    make(map[string]data.FrameJSONCache),
+			tailDialer: tailDialer,
 		}
 		return model, nil
 	}
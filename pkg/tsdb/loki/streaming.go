@@ -2,15 +2,10 @@ package loki
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/url"
-	"os"
-	"os/signal"
 	"strings"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -58,7 +53,13 @@ func (s *Service) SubscribeStream(ctx context.Context, req *backend.SubscribeStr
 	}, err
 }
 
-// Single instance for each channel (results are shared with all listeners)
+// tailFlushInterval is the maximum delay of tailed lines before they are sent to the channel.
+const tailFlushInterval = 250 * time.Millisecond
+
+// tailBatchSize is the maximum number of tailed lines sent to the channel in a single frame.
+const tailBatchSize = 500
+
+// Single instance for each channel (results are shared with all listeners), each tailing the query of the channel.
 func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
 	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
 	if err != nil {
@@ -70,101 +71,124 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 		return err
 	}
 	if query.Expr == nil || *query.Expr == "" {
-		return fmt.Errorf("missing expr in cuannel")
+		return fmt.Errorf("missing expr in channel")
 	}
 
 	logger := s.logger.FromContext(ctx)
-	count := int64(0)
-
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
-
-	params := url.Values{}
-	params.Add("query", *query.Expr)
-
-	wsurl, _ := url.Parse(dsInfo.URL)
-
-	wsurl.Path = "/loki/api/v2alpha/tail"
-
-	if wsurl.Scheme == "https" {
-		wsurl.Scheme = "wss"
-	} else {
-		wsurl.Scheme = "ws"
+	var limit int64
+	if query.MaxLines != nil {
+		limit = *query.MaxLines
 	}
-	wsurl.RawQuery = params.Encode()
 
-	logger.Info("Connecting to websocket", "url", wsurl)
-	c, r, err := websocket.DefaultDialer.Dial(wsurl.String(), nil)
+	session, err := dsInfo.openTail(ctx, logger, *query.Expr, limit)
 	if err != nil {
-		logger.Error("Error connecting to websocket", "err", err)
-		return fmt.Errorf("error connecting to websocket")
+		logger.Error("Error connecting to Loki tail", "error", err)
+		return err
 	}
-
 	defer func() {
 		dsInfo.streamsMu.Lock()
 		delete(dsInfo.streams, req.Path)
 		dsInfo.streamsMu.Unlock()
-		if r != nil {
-			_ = r.Body.Close()
+		if err := session.close(); err != nil {
+			logger.Warn("Error closing Loki tail", "error", err)
 		}
-		err = c.Close()
-		logger.Error("Closing loki websocket", "err", err)
 	}()
 
 	prev := data.FrameJSONCache{}
-
-	// Read all messages
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		for {
-			_, message, err := c.ReadMessage()
-			if err != nil {
-				logger.Error("Websocket read:", "err", err)
-				return
-			}
-
-			frame := &data.Frame{}
-			err = json.Unmarshal(message, &frame)
-
-			if err == nil && frame != nil {
-				next, _ := data.FrameToJSONCache(frame)
-				if next.SameSchema(&prev) {
-					err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
-				} else {
-					err = sender.SendFrame(frame, data.IncludeAll)
-				}
-				prev = next
-
-				// Cache the initial data
-				dsInfo.streamsMu.Lock()
-				dsInfo.streams[req.Path] = prev
-				dsInfo.streamsMu.Unlock()
-			}
-
-			if err != nil {
-				logger.Error("Websocket write:", "err", err, "raw", message)
-				return
-			}
+	send := func(lines []tailLine) error {
+		frame := tailFrame(lines, session.dropped.Swap(0), session.droppedUpstream.Swap(0))
+		if frame == nil {
+			return nil
 		}
-	}()
+		next, err := data.FrameToJSONCache(frame)
+		if err != nil {
+			return err
+		}
+		if next.SameSchema(&prev) {
+			err = sender.SendBytes(next.Bytes(data.IncludeDataOnly))
+		} else {
+			err = sender.SendFrame(frame, data.IncludeAll)
+		}
+		prev = next
 
-	ticker := time.NewTicker(time.Second * 60) // .Step)
+		// Cache the initial data
+		dsInfo.streamsMu.Lock()
+		dsInfo.streams[req.Path] = prev
+		dsInfo.streamsMu.Unlock()
+		return err
+	}
+
+	ticker := time.NewTicker(tailFlushInterval)
 	defer ticker.Stop()
 
+	lines := make([]tailLine, 0, tailBatchSize)
 	for {
 		select {
-		case <-done:
-			logger.Info("Socket done")
-			return nil
 		case <-ctx.Done():
 			logger.Info("Stop streaming (context canceled)")
 			return nil
-		case t := <-ticker.C:
-			count++
-			logger.Error("Loki websocket ping?", "time", t, "count", count)
+		case <-session.done:
+			return fmt.Errorf("loki tail closed: %w", session.err)
+		case line := <-session.lines:
+			lines = append(lines, line)
+			if len(lines) < tailBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		if err := send(lines); err != nil {
+			logger.Error("Error sending tailed lines", "error", err)
+			return err
 		}
+		lines = lines[:0]
+	}
+}
+
+// tailFrame returns the frame of tailed lines, with a warning notice when lines were dropped. It returns nil when
+// there is nothing to send.
+func tailFrame(lines []tailLine, dropped, droppedUpstream int64) *data.Frame {
+	if len(lines) == 0 && dropped == 0 && droppedUpstream == 0 {
+		return nil
+	}
+
+	labelsField := data.NewFieldFromFieldType(data.FieldTypeJSON, len(lines))
+	timeField := data.NewFieldFromFieldType(data.FieldTypeTime, len(lines))
+	lineField := data.NewFieldFromFieldType(data.FieldTypeString, len(lines))
+	stringTimeField := data.NewFieldFromFieldType(data.FieldTypeString, len(lines))
+	for i, l := range lines {
+		labelsField.Set(i, l.labels)
+		timeField.Set(i, l.time)
+		lineField.Set(i, l.line)
+		stringTimeField.Set(i, l.tsNs)
+	}
+	labelsField.Name = "labels"
+	timeField.Name = "Time"
+	lineField.Name = "Line"
+	stringTimeField.Name = "tsNs"
+
+	frame := data.NewFrame("", labelsField, timeField, lineField, stringTimeField)
+	if idField, err := makeIdField(stringTimeField, lineField, labelsField, ""); err == nil {
+		frame.Fields = append(frame.Fields, idField)
+	}
+	frame.Meta = &data.FrameMeta{
+		Custom: map[string]string{
+			"frameType": "LabeledTimeValues",
+		},
+	}
+	if dropped > 0 {
+		frame.Meta.Notices = append(frame.Meta.Notices, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("%d lines were dropped because the live stream could not keep up with the tailed logs", dropped),
+		})
+	}
+	if droppedUpstream > 0 {
+		frame.Meta.Notices = append(frame.Meta.Notices, data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("%d lines were dropped by Loki because the tail could not keep up with the ingested logs", droppedUpstream),
+		})
 	}
+	return frame
 }
 
 func (s *Service) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
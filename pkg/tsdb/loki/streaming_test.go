@@ -0,0 +1,245 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTailServer struct {
+	mu      sync.Mutex
+	conns   []*websocket.Conn
+	queries []string
+	limits  []string
+	headers []http.Header
+}
+
+func (s *fakeTailServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/loki/api/v1/tail" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	// The lock is held during the upgrade so that the request is recorded once the client is connected.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.conns = append(s.conns, conn)
+	s.queries = append(s.queries, r.URL.Query().Get("query"))
+	s.limits = append(s.limits, r.URL.Query().Get("limit"))
+	s.headers = append(s.headers, r.Header.Clone())
+}
+
+func (s *fakeTailServer) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// sendTo sends a message to the tails of a query.
+func (s *fakeTailServer) sendTo(t *testing.T, query, msg string) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, conn := range s.conns {
+		if s.queries[i] == query {
+			require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+		}
+	}
+}
+
+type fakeStreamPacketSender struct {
+	packets chan *backend.StreamPacket
+}
+
+func (s *fakeStreamPacketSender) Send(packet *backend.StreamPacket) error {
+	s.packets <- packet
+	return nil
+}
+
+// nextLines returns the lines of the next frame sent to the channel.
+func (s *fakeStreamPacketSender) nextLines(t *testing.T) []any {
+	t.Helper()
+	select {
+	case packet := <-s.packets:
+		var frame struct {
+			Data struct {
+				Values [][]any `json:"values"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(packet.Data, &frame))
+		require.GreaterOrEqual(t, len(frame.Data.Values), 3)
+		return frame.Data.Values[2]
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for tailed lines")
+		return nil
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	server := &fakeTailServer{}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im:     datasource.NewInstanceManager(newInstanceSettings(httpclient.NewProvider())),
+		logger: backend.NewLoggerWith("logger", "tsdb.loki test"),
+	}
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:                      1,
+			UID:                     "loki",
+			URL:                     srv.URL,
+			BasicAuthEnabled:        true,
+			BasicAuthUser:           "user",
+			DecryptedSecureJSONData: map[string]string{"basicAuthPassword": "password"},
+		},
+	}
+	dsInfo, err := service.getDSInfo(context.Background(), pluginCtx)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	run := func(path, expr string) *fakeStreamPacketSender {
+		sender := &fakeStreamPacketSender{packets: make(chan *backend.StreamPacket, 10)}
+		body, err := json.Marshal(map[string]string{"expr": expr})
+		require.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := service.RunStream(ctx, &backend.RunStreamRequest{
+				PluginContext: pluginCtx,
+				Path:          path,
+				Data:          body,
+			}, backend.NewStreamSender(sender))
+			assert.NoError(t, err)
+		}()
+		return sender
+	}
+
+	errorLines := run("tail/errors", `{app="api", env="prod"} |= "error"`)
+	otherLines := run("tail/others", `{env="prod", app="api"} != "error"`)
+
+	require.Eventually(t, func() bool {
+		return server.connections() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ElementsMatch(t, []string{`{app="api", env="prod"} |= "error"`, `{env="prod", app="api"} != "error"`}, server.queries, "the queries should be sent to Loki as is")
+	require.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", server.headers[0].Get("Authorization"))
+
+	// Loki filters the lines of each tail.
+	server.sendTo(t, `{app="api", env="prod"} |= "error"`, `{"streams": [{"stream": {"app": "api", "env": "prod"}, "values": [
+		["1700000000000000000", "an error line"]
+	]}]}`)
+	server.sendTo(t, `{env="prod", app="api"} != "error"`, `{"streams": [{"stream": {"app": "api", "env": "prod"}, "values": [
+		["1700000000000000001", "an info line"]
+	]}]}`)
+
+	require.Equal(t, []any{"an error line"}, errorLines.nextLines(t))
+	require.Equal(t, []any{"an info line"}, otherLines.nextLines(t))
+
+	cancel()
+	wg.Wait()
+
+	dsInfo.streamsMu.RLock()
+	defer dsInfo.streamsMu.RUnlock()
+	require.Empty(t, dsInfo.streams)
+}
+
+func TestOpenTail(t *testing.T) {
+	server := &fakeTailServer{}
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	im := datasource.NewInstanceManager(newInstanceSettings(httpclient.NewProvider()))
+	instance, err := im.Get(context.Background(), backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:                      1,
+			UID:                     "loki",
+			URL:                     srv.URL,
+			JSONData:                []byte(`{"httpHeaderName1": "X-Scope-OrgID"}`),
+			DecryptedSecureJSONData: map[string]string{"httpHeaderValue1": "tenant"},
+		},
+	})
+	require.NoError(t, err)
+	dsInfo := instance.(*datasourceInfo)
+	logger := backend.NewLoggerWith("logger", "tsdb.loki test")
+
+	t.Run("should connect with the middlewares of the data source and the headers of the user", func(t *testing.T) {
+		ctx := httpclient.WithContextualMiddleware(context.Background(), httpclient.MiddlewareFunc(func(opts httpclient.Options, next http.RoundTripper) http.RoundTripper {
+			return httpclient.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				r.Header.Set("Authorization", "Bearer token")
+				r.Header.Set("X-Id-Token", "id-token")
+				return next.RoundTrip(r)
+			})
+		}))
+		session, err := dsInfo.openTail(ctx, logger, `{app="api"} | logfmt | level="error"`, 10)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = session.close() })
+
+		require.Equal(t, 1, server.connections())
+		require.Equal(t, []string{`{app="api"} | logfmt | level="error"`}, server.queries)
+		require.Equal(t, []string{"10"}, server.limits)
+		header := server.headers[0]
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+		assert.Equal(t, "id-token", header.Get("X-Id-Token"))
+		assert.Equal(t, "tenant", header.Get("X-Scope-OrgID"))
+	})
+
+	t.Run("should not tail metric queries", func(t *testing.T) {
+		_, err := dsInfo.openTail(context.Background(), logger, `rate({app="api"}[1m])`, 0)
+		require.ErrorContains(t, err, "only log queries can be tailed")
+		require.Equal(t, 1, server.connections())
+	})
+}
+
+func TestTailSessionDispatch(t *testing.T) {
+	session := newTailSession(nil, backend.NewLoggerWith("logger", "tsdb.loki test"), 1)
+
+	session.dispatch(tailResponse{})
+	var res tailResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"streams": [{"stream": {"app": "api"}, "values": [
+			["1700000000000000000", "level=error msg=first"],
+			["1700000000000000001", "level=error msg=second"],
+			["1700000000000000002", "level=error msg=third"]
+		]}],
+		"dropped_entries": [
+			{"labels": {"app": "api"}, "timestamp": "1699999999999999998"},
+			{"labels": {"app": "api"}, "timestamp": "1699999999999999999"}
+		]
+	}`), &res))
+	session.dispatch(res)
+
+	// The buffer of the tail holds a single line, the other lines are dropped.
+	require.Len(t, session.lines, 1)
+	line := <-session.lines
+	require.Equal(t, "level=error msg=first", line.line)
+	require.Equal(t, "1700000000000000000", line.tsNs)
+	require.JSONEq(t, `{"app": "api"}`, string(line.labels))
+	require.Equal(t, int64(2), session.dropped.Load())
+	require.Equal(t, int64(2), session.droppedUpstream.Load())
+
+	frame := tailFrame([]tailLine{line}, session.dropped.Swap(0), session.droppedUpstream.Swap(0))
+	require.Equal(t, 1, frame.Rows())
+	require.Len(t, frame.Meta.Notices, 2)
+	require.Equal(t, data.NoticeSeverityWarning, frame.Meta.Notices[0].Severity)
+	require.Equal(t, "2 lines were dropped because the live stream could not keep up with the tailed logs", frame.Meta.Notices[0].Text)
+	require.Equal(t, "2 lines were dropped by Loki because the tail could not keep up with the ingested logs", frame.Meta.Notices[1].Text)
+
+	require.Nil(t, tailFrame(nil, 0, 0))
+}
@@ -0,0 +1,256 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+const (
+	// tailBufferSize is the number of tailed lines buffered before they are sent to the live channel. Lines are
+	// dropped when the channel does not keep up, so that the tail keeps reading from Loki.
+	tailBufferSize = 1000
+	// tailDialTimeout is the timeout of the websocket handshake with Loki.
+	tailDialTimeout = 30 * time.Second
+	// tailHandshakeMiddlewareName is the name of the middleware capturing the headers of the websocket handshakes.
+	tailHandshakeMiddlewareName = "loki-tail-handshake"
+)
+
+// tailHandshakeKey is the context key of the headers of a websocket handshake, see tailHandshakeMiddleware.
+type tailHandshakeKey struct{}
+
+// tailResponse is a message of the websocket tail API of Loki.
+type tailResponse struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+	DroppedEntries []struct {
+		Labels    map[string]string `json:"labels"`
+		Timestamp string            `json:"timestamp"`
+	} `json:"dropped_entries"`
+}
+
+// tailLine is a line tailed from Loki.
+type tailLine struct {
+	labels json.RawMessage
+	time   time.Time
+	tsNs   string
+	line   string
+}
+
+// tailSession is a websocket tail of the query of a live channel. The live channel is shared by all users watching
+// the same query, so that Loki is tailed once however many users watch the same logs.
+type tailSession struct {
+	conn   *websocket.Conn
+	logger log.Logger
+
+	lines chan tailLine
+	// dropped is the number of lines dropped because the buffer of the tail was full.
+	dropped atomic.Int64
+	// droppedUpstream is the number of lines dropped by Loki because the tail could not keep up.
+	droppedUpstream atomic.Int64
+
+	done chan struct{}
+	// err is the error that ended the tail, it is set before done is closed.
+	err error
+}
+
+func newTailSession(conn *websocket.Conn, logger log.Logger, bufferSize int) *tailSession {
+	return &tailSession{
+		conn:   conn,
+		logger: logger,
+		lines:  make(chan tailLine, bufferSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// run reads the messages of the tail until the connection is closed.
+func (t *tailSession) run() {
+	defer close(t.done)
+	for {
+		var res tailResponse
+		if err := t.conn.ReadJSON(&res); err != nil {
+			t.err = err
+			return
+		}
+		t.dispatch(res)
+	}
+}
+
+// dispatch buffers the lines of a message. It never blocks: lines are dropped and counted when the buffer is full.
+func (t *tailSession) dispatch(res tailResponse) {
+	for _, stream := range res.Streams {
+		lbls := labelsJSON(stream.Stream)
+		for _, value := range stream.Values {
+			ns, err := strconv.ParseInt(value[0], 10, 64)
+			if err != nil {
+				t.logger.Warn("Invalid timestamp in tail response", "timestamp", value[0])
+				continue
+			}
+			select {
+			case t.lines <- tailLine{labels: lbls, time: time.Unix(0, ns).UTC(), tsNs: value[0], line: value[1]}:
+			default:
+				t.dropped.Add(1)
+			}
+		}
+	}
+	t.droppedUpstream.Add(int64(len(res.DroppedEntries)))
+}
+
+// openTail connects to the tail of a log query with its line limit. The query is sent to Loki as is, so that Loki
+// applies its filters before the line limit.
+func (dsInfo *datasourceInfo) openTail(ctx context.Context, logger log.Logger, expr string, limit int64) (*tailSession, error) {
+	if _, err := syntax.ParseLogSelector(expr, true); err != nil {
+		return nil, fmt.Errorf("only log queries can be tailed: %w", err)
+	}
+	conn, err := dsInfo.dialTail(ctx, expr, limit)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("Started tailing Loki", "limit", limit)
+	session := newTailSession(conn, logger, tailBufferSize)
+	go session.run()
+	return session, nil
+}
+
+// close closes the connection of the tail and waits for it to stop reading.
+func (t *tailSession) close() error {
+	err := t.conn.Close()
+	<-t.done
+	return err
+}
+
+// dialTail connects to the tail of Loki. The handshake request first goes through the middlewares of the HTTP client
+// of the data source, for the authentication and the headers of the data source and of the user, and is then sent by
+// the websocket dialer with the headers set by the middlewares.
+func (dsInfo *datasourceInfo) dialTail(ctx context.Context, expr string, limit int64) (*websocket.Conn, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "/loki/api/v1/tail")
+	params := url.Values{}
+	params.Add("query", expr)
+	if limit > 0 {
+		params.Add("limit", strconv.FormatInt(limit, 10))
+	}
+	u.RawQuery = params.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, tailDialTimeout)
+	defer cancel()
+
+	header := http.Header{}
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, tailHandshakeKey{}, header), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to Loki tail: %w", err)
+	}
+	_ = res.Body.Close()
+
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	conn, res, err := dsInfo.tailDialer.dialer().DialContext(ctx, u.String(), header)
+	if res != nil {
+		_ = res.Body.Close()
+	}
+	if err != nil {
+		if res != nil {
+			return nil, fmt.Errorf("error connecting to Loki tail: %w (status %s)", err, res.Status)
+		}
+		return nil, fmt.Errorf("error connecting to Loki tail: %w", err)
+	}
+	return conn, nil
+}
+
+// tailDialer opens the websocket connections of tails with the TLS settings, the proxy and the dialer of the transport
+// of the HTTP client of the data source, including the secure socks proxy.
+type tailDialer struct {
+	transport *http.Transport
+}
+
+// configureTailDialer configures the options of the HTTP client of the data source so that tails connect to Loki the
+// same way as queries. The transport of the client is captured by the returned dialer when the client is created, and
+// the middleware capturing the headers of the handshakes runs last, after the middlewares signing the requests.
+func configureTailDialer(opts *httpclient.Options) *tailDialer {
+	d := &tailDialer{}
+
+	configureTransport := opts.ConfigureTransport
+	opts.ConfigureTransport = func(opts httpclient.Options, transport *http.Transport) {
+		if configureTransport != nil {
+			configureTransport(opts, transport)
+		}
+		d.transport = transport
+	}
+
+	configureMiddleware := opts.ConfigureMiddleware
+	opts.ConfigureMiddleware = func(opts httpclient.Options, existingMiddleware []httpclient.Middleware) []httpclient.Middleware {
+		if configureMiddleware != nil {
+			existingMiddleware = configureMiddleware(opts, existingMiddleware)
+		}
+		return append(existingMiddleware, httpclient.NamedMiddlewareFunc(tailHandshakeMiddlewareName, tailHandshakeMiddleware))
+	}
+
+	return d
+}
+
+func (d *tailDialer) dialer() *websocket.Dialer {
+	if d.transport == nil {
+		return &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: tailDialTimeout,
+		}
+	}
+	return &websocket.Dialer{
+		NetDialContext:   d.transport.DialContext,
+		Proxy:            d.transport.Proxy,
+		TLSClientConfig:  d.transport.TLSClientConfig,
+		HandshakeTimeout: tailDialTimeout,
+	}
+}
+
+// tailHandshakeMiddleware captures the headers of the handshake requests of tails instead of sending them, so that the
+// websocket dialer sends them with the handshake.
+func tailHandshakeMiddleware(_ httpclient.Options, next http.RoundTripper) http.RoundTripper {
+	return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		header, ok := req.Context().Value(tailHandshakeKey{}).(http.Header)
+		if !ok {
+			return next.RoundTrip(req)
+		}
+		for name, values := range req.Header {
+			header[name] = append([]string(nil), values...)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     http.StatusText(http.StatusOK),
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+}
+
+func labelsJSON(lbls map[string]string) json.RawMessage {
+	b, err := json.Marshal(lbls)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	return b
+}
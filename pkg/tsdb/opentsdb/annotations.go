@@ -0,0 +1,89 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// annotationsQueryType is the query type of the queries that return the annotations of a metric instead of its data points.
+const annotationsQueryType = "annotations"
+
+// queryAnnotations returns the annotations of the metric of the query. The metric is given by the target of the query,
+// as in annotation queries of the query editor, or by its metric. Global annotations are returned instead if isGlobal is set.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery) backend.DataResponse {
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("failed to parse query: %s", err))
+	}
+	metric := model.Get("target").MustString()
+	if metric == "" {
+		metric = model.Get("metric").MustString()
+	}
+	if metric == "" {
+		return backend.ErrDataResponse(backend.StatusBadRequest, "annotation query has no metric")
+	}
+	isGlobal := model.Get("isGlobal").MustBool()
+
+	request, err := s.createRequest(ctx, logger, dsInfo, OpenTsdbQuery{
+		Start:             query.TimeRange.From.UnixNano() / int64(time.Millisecond),
+		End:               query.TimeRange.To.UnixNano() / int64(time.Millisecond),
+		Queries:           []map[string]any{{"aggregator": "sum", "metric": metric}},
+		GlobalAnnotations: isGlobal,
+	})
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	responseData, err := s.decodeResponse(logger, res)
+	if err != nil {
+		return backend.DataResponse{Error: err}
+	}
+
+	var annotations []OpenTsdbAnnotation
+	// Annotations are returned for every time series of the metric, only the ones of the first series are used as
+	// the query editor does. Global annotations are the same for all series.
+	if len(responseData) > 0 {
+		annotations = responseData[0].Annotations
+		if isGlobal {
+			annotations = responseData[0].GlobalAnnotations
+		}
+	}
+
+	return backend.DataResponse{Frames: data.Frames{annotationsFrame(query.RefID, annotations)}}
+}
+
+func annotationsFrame(refID string, annotations []OpenTsdbAnnotation) *data.Frame {
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, a := range annotations {
+		times = append(times, time.Unix(a.StartTime, 0).UTC())
+		var timeEnd *time.Time
+		if a.EndTime > 0 {
+			t := time.Unix(a.EndTime, 0).UTC()
+			timeEnd = &t
+		}
+		timeEnds = append(timeEnds, timeEnd)
+		texts = append(texts, a.Description)
+	}
+
+	frame := data.NewFrame(annotationsQueryType,
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts),
+	)
+	frame.RefID = refID
+	return frame
+}
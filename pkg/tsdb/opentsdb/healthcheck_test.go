@@ -0,0 +1,60 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHealth(t *testing.T) {
+	testCases := []struct {
+		name            string
+		handler         http.HandlerFunc
+		expectedStatus  backend.HealthStatus
+		expectedMessage string
+	}{
+		{
+			name: "should succeed and report the version of OpenTSDB",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/version", r.URL.Path)
+				_, _ = w.Write([]byte(`{"version": "2.4.1", "short_revision": "abc"}`))
+			},
+			expectedStatus:  backend.HealthStatusOk,
+			expectedMessage: "Successfully connected to OpenTSDB 2.4.1",
+		},
+		{
+			name: "should succeed when the version cannot be read",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`not json`))
+			},
+			expectedStatus:  backend.HealthStatusOk,
+			expectedMessage: "Successfully connected to OpenTSDB",
+		},
+		{
+			name: "should fail on unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			expectedStatus:  backend.HealthStatusError,
+			expectedMessage: "OpenTSDB returned an unexpected status: 401 Unauthorized",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			t.Cleanup(srv.Close)
+
+			service := &Service{im: fakeInstanceManager{&datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}}}
+			res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, res.Status)
+			assert.Equal(t, tc.expectedMessage, res.Message)
+		})
+	}
+}
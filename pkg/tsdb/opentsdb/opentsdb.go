@@ -47,6 +47,7 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 		if err != nil {
 			return nil, err
 		}
+		opts.ForwardHTTPHeaders = true
 
 		client, err := httpClientProvider.New(opts)
 		if err != nil {
@@ -63,18 +64,40 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 }
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	var tsdbQuery OpenTsdbQuery
+	if len(req.Queries) == 0 {
+		return nil, fmt.Errorf("query contains no queries")
+	}
 
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	result := backend.NewQueryDataResponse()
+	metricQueries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		if query.QueryType == annotationsQueryType {
+			result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query)
+			continue
+		}
+		metricQueries = append(metricQueries, query)
+	}
+	if len(metricQueries) == 0 {
+		return result, nil
+	}
+
+	var tsdbQuery OpenTsdbQuery
+
+	q := metricQueries[0]
 
 	myRefID := q.RefID
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
 
-	for _, query := range req.Queries {
+	for _, query := range metricQueries {
 		metric := s.buildMetric(query)
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
 	}
@@ -84,11 +107,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -106,11 +124,14 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		}
 	}()
 
-	result, err := s.parseResponse(logger, res, myRefID)
+	metricResult, err := s.parseResponse(logger, res, myRefID)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
 
+	for refID, r := range metricResult.Responses {
+		result.Responses[refID] = r
+	}
 	return result, nil
 }
 
@@ -143,27 +164,10 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID string) (*backend.QueryDataResponse, error) {
 	resp := backend.NewQueryDataResponse()
 
-	body, err := io.ReadAll(res.Body)
+	responseData, err := s.decodeResponse(logger, res)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Warn("Failed to close response body", "err", err)
-		}
-	}()
-
-	if res.StatusCode/100 != 2 {
-		logger.Info("Request failed", "status", res.Status, "body", string(body))
-		return nil, fmt.Errorf("request failed, status: %s", res.Status)
-	}
-
-	var responseData []OpenTsdbResponse
-	err = json.Unmarshal(body, &responseData)
-	if err != nil {
-		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
-		return nil, err
-	}
 
 	frames := data.Frames{}
 	for _, val := range responseData {
@@ -193,6 +197,31 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response, myRefID s
 	return resp, nil
 }
 
+func (s *Service) decodeResponse(logger log.Logger, res *http.Response) ([]OpenTsdbResponse, error) {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	var responseData []OpenTsdbResponse
+	err = json.Unmarshal(body, &responseData)
+	if err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+	return responseData, nil
+}
+
 func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 	metric := make(map[string]any)
 
@@ -201,8 +230,13 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		return nil
 	}
 
-	// Setting metric and aggregator
-	metric["metric"] = model.Get("metric").MustString()
+	// Setting the time series, either by metric or by TSUIDs, and the aggregator
+	tsuids := model.Get("tsuids").MustStringArray()
+	if len(tsuids) > 0 {
+		metric["tsuids"] = tsuids
+	} else {
+		metric["metric"] = model.Get("metric").MustString()
+	}
 	metric["aggregator"] = model.Get("aggregator").MustString()
 
 	// Setting downsampling options
@@ -255,6 +289,11 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]any {
 		metric["filters"] = filters.MustArray()
 	}
 
+	// Only return the time series with exactly the tags and filters of the query
+	if model.Get("explicitTags").MustBool() {
+		metric["explicitTags"] = true
+	}
+
 	return metric
 }
 
@@ -2,14 +2,17 @@ package opentsdb
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -286,4 +289,133 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(45), metricRateOptions["counterMax"])
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
+
+	t.Run("Build metric with explicit tags", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "cpu.average.percent",
+						"aggregator": "avg",
+						"disableDownsampling": true,
+						"explicitTags": true,
+						"tags": {
+							"env": "prod"
+						}
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		require.Len(t, metric, 4)
+		require.Equal(t, "cpu.average.percent", metric["metric"])
+		require.True(t, metric["explicitTags"].(bool))
+	})
+
+	t.Run("Build metric with TSUIDs", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "cpu.average.percent",
+						"tsuids": ["000001000001000001", "000001000001000002"],
+						"aggregator": "sum",
+						"disableDownsampling": true
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		require.Len(t, metric, 2)
+		require.Nil(t, metric["metric"])
+		require.Equal(t, []string{"000001000001000001", "000001000001000002"}, metric["tsuids"])
+		require.Equal(t, "sum", metric["aggregator"])
+	})
+}
+
+func TestOpenTsdbAnnotations(t *testing.T) {
+	var received OpenTsdbQuery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`[{
+			"metric": "deploys",
+			"tags": {},
+			"dps": [],
+			"annotations": [{"tsuid": "000001", "description": "deploy", "startTime": 1405544146}],
+			"globalAnnotations": [{"description": "outage", "startTime": 1405544146, "endTime": 1405544746}]
+		}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: fakeInstanceManager{&datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}},
+	}
+	timeRange := backend.TimeRange{From: time.Unix(1405540000, 0), To: time.Unix(1405550000, 0)}
+
+	t.Run("should return the annotations of the metric", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "Anno",
+				QueryType: annotationsQueryType,
+				TimeRange: timeRange,
+				JSON:      []byte(`{"target": "deploys"}`),
+			}},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]any{{"aggregator": "sum", "metric": "deploys"}}, received.Queries)
+		assert.False(t, received.GlobalAnnotations)
+
+		require.NoError(t, res.Responses["Anno"].Error)
+		frame := res.Responses["Anno"].Frames[0]
+		assert.Equal(t, "Anno", frame.RefID)
+		require.Equal(t, 1, frame.Rows())
+		assert.Equal(t, time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC), frame.Fields[0].At(0))
+		assert.Nil(t, frame.Fields[1].At(0))
+		assert.Equal(t, "deploy", frame.Fields[2].At(0))
+	})
+
+	t.Run("should return the global annotations", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "Anno",
+				QueryType: annotationsQueryType,
+				TimeRange: timeRange,
+				JSON:      []byte(`{"target": "deploys", "isGlobal": true}`),
+			}},
+		})
+		require.NoError(t, err)
+		assert.True(t, received.GlobalAnnotations)
+
+		frame := res.Responses["Anno"].Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		end := time.Date(2014, 7, 16, 21, 5, 46, 0, time.UTC)
+		assert.Equal(t, &end, frame.Fields[1].At(0))
+		assert.Equal(t, "outage", frame.Fields[2].At(0))
+	})
+
+	t.Run("should fail without metric", func(t *testing.T) {
+		res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "Anno",
+				QueryType: annotationsQueryType,
+				TimeRange: timeRange,
+				JSON:      []byte(`{}`),
+			}},
+		})
+		require.NoError(t, err)
+		require.Error(t, res.Responses["Anno"].Error)
+	})
+}
+
+type fakeInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (f fakeInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.dsInfo, nil
+}
+
+func (f fakeInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
 }
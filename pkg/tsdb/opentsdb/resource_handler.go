@@ -0,0 +1,95 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// resourcePaths are the OpenTSDB endpoints used by the query editor and variable queries that can be called as
+// resources, with the methods the query editor calls them with.
+var resourcePaths = map[string][]string{
+	"api/suggest":        {http.MethodGet},
+	"api/search/lookup":  {http.MethodGet},
+	"api/aggregators":    {http.MethodGet},
+	"api/config/filters": {http.MethodGet},
+}
+
+// CallResource proxies the calls of the query editor and variable queries to OpenTSDB so that they are authenticated
+// the same way as queries.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath := strings.Trim(req.Path, "/")
+	methods, ok := resourcePaths[resourcePath]
+	if !ok {
+		logger.Error("Invalid resource path", "path", req.Path)
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf("invalid resource path: %s", req.Path)),
+		})
+	}
+	if req.Method != "" && !slices.Contains(methods, req.Method) {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusMethodNotAllowed,
+			Body:   []byte(fmt.Sprintf("method not allowed: %s", req.Method)),
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return err
+	}
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+	u.RawQuery = reqURL.RawQuery
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		logger.Error("Failed to create request", "error", err, "path", resourcePath)
+		return err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		logger.Error("Failed to send resource request", "error", err, "path", resourcePath)
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("Failed to read response body", "error", err)
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = []string{contentType}
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
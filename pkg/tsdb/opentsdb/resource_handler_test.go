@@ -0,0 +1,129 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallResource(t *testing.T) {
+	var received *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["cpu.average.percent"]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{im: fakeInstanceManager{&datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL + "/opentsdb"}}}
+
+	t.Run("should forward allowed paths to OpenTSDB", func(t *testing.T) {
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Path:   "api/suggest",
+			Method: http.MethodGet,
+			URL:    "api/suggest?type=metrics&q=cpu&max=1000",
+		}, sender)
+		require.NoError(t, err)
+
+		require.NotNil(t, received)
+		assert.Equal(t, "/opentsdb/api/suggest", received.URL.Path)
+		assert.Equal(t, "type=metrics&q=cpu&max=1000", received.URL.RawQuery)
+
+		require.NotNil(t, sender.res)
+		assert.Equal(t, http.StatusOK, sender.res.Status)
+		assert.Equal(t, []string{"application/json"}, sender.res.Headers["content-type"])
+		assert.JSONEq(t, `["cpu.average.percent"]`, string(sender.res.Body))
+	})
+
+	t.Run("should reject other paths and methods", func(t *testing.T) {
+		received = nil
+		sender := &fakeSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Path:   "api/put",
+			Method: http.MethodGet,
+			URL:    "api/put",
+		}, sender)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, sender.res.Status)
+
+		err = service.CallResource(context.Background(), &backend.CallResourceRequest{
+			Path:   "api/search/lookup",
+			Method: http.MethodDelete,
+			URL:    "api/search/lookup",
+		}, sender)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, sender.res.Status)
+		assert.Nil(t, received)
+	})
+}
+
+func TestForwardHTTPHeaders(t *testing.T) {
+	var received http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"version": "2.4.1"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	service := ProvideService(httpclient.NewProvider())
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL, JSONData: []byte(`{}`)},
+	}
+
+	t.Run("should forward the identity of the user to OpenTSDB in resource calls", func(t *testing.T) {
+		received = nil
+		req := &backend.CallResourceRequest{PluginContext: pluginCtx, Path: "api/aggregators", Method: http.MethodGet, URL: "api/aggregators"}
+		req.SetHTTPHeader(backend.OAuthIdentityTokenHeaderName, "Bearer token")
+		req.SetHTTPHeader(backend.OAuthIdentityIDTokenHeaderName, "id-token")
+		err := service.CallResource(forwardHeaders(context.Background(), req), req, &fakeSender{})
+		require.NoError(t, err)
+		require.NotNil(t, received)
+		assert.Equal(t, "Bearer token", received.Get("Authorization"))
+		assert.Equal(t, "id-token", received.Get("X-Id-Token"))
+	})
+
+	t.Run("should forward the identity of the user to OpenTSDB in health checks", func(t *testing.T) {
+		received = nil
+		req := &backend.CheckHealthRequest{PluginContext: pluginCtx}
+		req.SetHTTPHeader(backend.OAuthIdentityTokenHeaderName, "Bearer token")
+		req.SetHTTPHeader(backend.OAuthIdentityIDTokenHeaderName, "id-token")
+		res, err := service.CheckHealth(forwardHeaders(context.Background(), req), req)
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+		require.NotNil(t, received)
+		assert.Equal(t, "Bearer token", received.Get("Authorization"))
+		assert.Equal(t, "id-token", received.Get("X-Id-Token"))
+	})
+}
+
+// forwardHeaders forwards the HTTP headers of a request to the data source like the plugin middleware does, which
+// only applies to HTTP clients created with ForwardHTTPHeaders.
+func forwardHeaders(ctx context.Context, req backend.ForwardHTTPHeaders) context.Context {
+	return httpclient.WithContextualMiddleware(ctx, httpclient.MiddlewareFunc(func(opts httpclient.Options, next http.RoundTripper) http.RoundTripper {
+		if !opts.ForwardHTTPHeaders {
+			return next
+		}
+		return httpclient.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			for k, v := range req.GetHTTPHeaders() {
+				r.Header[k] = v
+			}
+			return next.RoundTrip(r)
+		})
+	}))
+}
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(res *backend.CallResourceResponse) error {
+	s.res = res
+	return nil
+}
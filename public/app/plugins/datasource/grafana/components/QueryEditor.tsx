@@ -70,6 +70,11 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
       value: GrafanaQueryType.List,
       description: 'Show directory listings for public resources',
     },
+    {
+      label: 'Recorded metric',
+      value: GrafanaQueryType.RecordedMetric,
+      description: 'Metrics that recording rules stored in the Grafana database',
+    },
   ];
 
   constructor(props: Props) {
@@ -354,6 +359,29 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
     );
   }
 
+  onMetricChanged = (e: React.FormEvent<HTMLInputElement>) => {
+    const { onChange, query } = this.props;
+    onChange({ ...query, metric: e.currentTarget.value });
+  };
+
+  renderRecordedMetricQuery() {
+    const { query, onRunQuery } = this.props;
+
+    return (
+      <InlineFieldRow>
+        <InlineField label="Metric" grow={true} labelWidth={labelWidth}>
+          <Input
+            defaultValue={query.metric ?? ''}
+            placeholder="Name of the metric of a recording rule"
+            onChange={this.onMetricChanged}
+            onBlur={onRunQuery}
+            spellCheck={false}
+          />
+        </InlineField>
+      </InlineFieldRow>
+    );
+  }
+
   // Skip rendering the file list as we're handling that in this component instead.
   fileListRenderer = (file: DropzoneFile, removeFile: (file: DropzoneFile) => void) => {
     return null;
@@ -488,6 +516,7 @@ export class UnthemedQueryEditor extends PureComponent<Props, State> {
         </InlineFieldRow>
         {queryType === GrafanaQueryType.LiveMeasurements && this.renderMeasurementsQuery()}
         {queryType === GrafanaQueryType.List && this.renderListPublicFiles()}
+        {queryType === GrafanaQueryType.RecordedMetric && this.renderRecordedMetricQuery()}
         {queryType === GrafanaQueryType.Snapshot && this.renderSnapshotQuery()}
         {queryType === GrafanaQueryType.Search && (
           <SearchEditor value={query.search ?? {}} onChange={this.onSearchChange} />